@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
 	"github.com/chibiegg/isucon9-final/bench/mock"
 	"github.com/jarcoal/httpmock"
@@ -39,13 +41,14 @@ func TestMock(t *testing.T) {
 
 	mock.Register()
 
-	client, err := isutrain.NewClient()
+	ctx := benchrun.New(config.NewTarget(config.DefaultTargetBaseURL, config.DefaultPaymentBaseURL)).Context(context.Background())
+	client, err := isutrain.NewClient(ctx)
 	assert.NoError(t, err)
 	client.ReplaceMockTransport()
 
 	assets, err := Load("testdata")
 	for _, asset := range assets {
-		b, err := client.DownloadAsset(context.TODO(), asset.Path)
+		b, err := client.DownloadAsset(ctx, asset.Path)
 		assert.NoError(t, err)
 
 		hash := sha256.Sum256(b)
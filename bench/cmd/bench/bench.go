@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/chibiegg/isucon9-final/bench/assets"
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
 	"github.com/chibiegg/isucon9-final/bench/internal/logger"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
 	"github.com/chibiegg/isucon9-final/bench/mock"
 	"github.com/chibiegg/isucon9-final/bench/payment"
@@ -21,7 +25,12 @@ import (
 )
 
 var (
-	assetDir string
+	assetDir     string
+	profileFile  string
+	arrivalRate  float64
+	timelineFile string
+	seed         int64
+	recordFile   string
 )
 
 type BenchResult struct {
@@ -30,31 +39,38 @@ type BenchResult struct {
 	Messages      []string `json:"messages"`
 	AvailableDays int      `json:"available_days"`
 	Language      string   `json:"language"`
-}
+	Seed          int64    `json:"seed"` // 同じシードを --seed に指定すると、同じ順序でリクエストを送ります
 
-// UniqueMsgs は重複除去したメッセージ配列を返します
-func uniqueMsgs(msgs []string) (uniqMsgs []string) {
-	dedup := map[string]struct{}{}
-	for _, msg := range msgs {
-		if _, ok := dedup[msg]; ok {
-			continue
-		}
-		dedup[msg] = struct{}{}
-		uniqMsgs = append(uniqMsgs, msg)
-	}
-	return
+	OpenLoop      *OpenLoopResult       `json:"open_loop,omitempty"`
+	EndpointStats []endpoint.Stats      `json:"endpoint_stats,omitempty"` // ベンチマーク中のエンドポイントごとのレイテンシとエラー率
+	ScoreReport   *benchrun.ScoreReport `json:"score_report,omitempty"`   // スコアとペナルティの内訳
 }
 
-func dumpFailedResult(messages []string) {
-	lgr := zap.S()
+// 参加者に見せるエラーメッセージの種類の最大数
+const maxErrorMessages = 20
 
-	b, err := json.Marshal(&BenchResult{
+// errorMsgs は、件数の多い順にエラーメッセージを件数付きで返します
+func errorMsgs(errs *bencherror.BenchErrors) []string {
+	return bencherror.FormatMessages(errs.TopMessages(maxErrorMessages))
+}
+
+// failedResult は失格した場合の結果を返します
+func failedResult(ctx context.Context, seed int64, messages []string) *BenchResult {
+	target := config.FromContext(ctx)
+	return &BenchResult{
 		Pass:          false,
 		Score:         0,
 		Messages:      messages,
-		AvailableDays: config.AvailableDays,
-		Language:      config.Language,
-	})
+		AvailableDays: target.AvailableDays,
+		Language:      target.Language,
+		Seed:          seed,
+	}
+}
+
+func dumpFailedResult(ctx context.Context, messages []string) {
+	lgr := zap.S()
+
+	b, err := json.Marshal(failedResult(ctx, seed, messages))
 	if err != nil {
 		lgr.Warnf("FAILEDな結果を書き出す際にエラーが発生. messagesが失われました: messages=%+v err=%+v", messages, err)
 		fmt.Println(fmt.Sprintf(`{"pass": false, "score": 0, "messages": ["%s"]}`, string(b)))
@@ -74,14 +90,24 @@ var run = cli.Command{
 		},
 		cli.StringFlag{
 			Name:        "payment",
-			Value:       "http://localhost:5000",
-			Destination: &config.PaymentBaseURL,
+			Value:       config.DefaultPaymentBaseURL,
+			Destination: &paymentURL,
 			EnvVar:      "BENCH_PAYMENT_URL",
 		},
+		cli.StringFlag{
+			Name:        "payment-api-key",
+			Destination: &paymentAPIKey,
+			EnvVar:      "BENCH_PAYMENT_API_KEY",
+		},
+		cli.StringFlag{
+			Name:        "payment-merchant",
+			Destination: &paymentMerchantID,
+			EnvVar:      "BENCH_PAYMENT_MERCHANT",
+		},
 		cli.StringFlag{
 			Name:        "target",
-			Value:       "http://localhost",
-			Destination: &config.TargetBaseURL,
+			Value:       config.DefaultTargetBaseURL,
+			Destination: &targetURL,
 			EnvVar:      "BENCH_TARGET_URL",
 		},
 		cli.StringFlag{
@@ -90,6 +116,36 @@ var run = cli.Command{
 			Destination: &assetDir,
 			EnvVar:      "BENCH_ASSETDIR",
 		},
+		cli.StringFlag{
+			Name:        "profile",
+			Usage:       "ワークロードプロファイル(YAML/JSON)のパス. 省略時は標準の負荷をかける",
+			Destination: &profileFile,
+			EnvVar:      "BENCH_PROFILE",
+		},
+		cli.Float64Flag{
+			Name:        "arrival-rate",
+			Usage:       "1秒あたりに開始するシナリオ数. 指定すると応答を待たずに一定の到着率で負荷をかける(オープンループ)",
+			Destination: &arrivalRate,
+			EnvVar:      "BENCH_ARRIVAL_RATE",
+		},
+		cli.StringFlag{
+			Name:        "timeline",
+			Usage:       "ベンチマーク中の1秒ごとの状況を書き出すファイルのパス. 拡張子が.csvならCSV、それ以外はJSON Lines",
+			Destination: &timelineFile,
+			EnvVar:      "BENCH_TIMELINE",
+		},
+		cli.Int64Flag{
+			Name:        "seed",
+			Usage:       "乱数のシード. 同じシードなら同じ順序で同じユーザ・区間・日付・人数を使う. 省略時は現在時刻から決める",
+			Destination: &seed,
+			EnvVar:      "BENCH_SEED",
+		},
+		cli.StringFlag{
+			Name:        "record",
+			Usage:       "webappへのリクエストとレスポンスをJSON Linesで記録するファイルのパス. bench replay で送り直せる",
+			Destination: &recordFile,
+			EnvVar:      "BENCH_RECORD",
+		},
 		cli.StringFlag{
 			Name:        "webhookurl",
 			Destination: &config.SlackWebhookURL,
@@ -97,153 +153,272 @@ var run = cli.Command{
 		},
 	},
 	Action: func(cliCtx *cli.Context) error {
-		ctx := context.Background()
+		benchRun := benchrun.New(newTarget(targetURL, paymentURL))
+		ctx := benchRun.Context(context.Background())
 
 		lgr, err := logger.InitZapLogger()
 		if err != nil {
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
 		lgr.Info("===== Prepare benchmarker =====")
 
+		seed = seedRandom(seed)
+		lgr.Infof("シード: %d", seed)
+
+		var profile *workload.Profile
+		if profileFile != "" {
+			profile, err = workload.LoadFile(profileFile)
+			if err == nil {
+				err = checkProfile(profile)
+			}
+			if err != nil {
+				lgr.Warnf("ワークロードプロファイルを読み込めませんでした: %+v", err)
+				dumpFailedResult(ctx, []string{})
+				return cli.NewExitError(err, 1)
+			}
+		}
+		var arrival *workload.Arrival
+		if profile != nil && profile.Arrival != nil {
+			arrival = profile.Arrival
+		}
+		if arrivalRate > 0 {
+			// フラグで指定した場合は、プロファイルの到着率を一定の到着率で上書きする
+			arrival = &workload.Arrival{Rate: arrivalRate}
+			if profile != nil && profile.Arrival != nil {
+				arrival.MaxInFlight = profile.Arrival.MaxInFlight
+				arrival.LateThreshold = profile.Arrival.LateThreshold
+			}
+			arrival.SetDefaults()
+		}
+
 		assets, err := assets.Load(assetDir)
 		if err != nil {
 			lgr.Warn("静的ファイルをローカルから読み出せませんでした: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
-		initClient, err := isutrain.NewClientForInitialize()
-		if err != nil {
-			lgr.Warn("isutrainクライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
-			return cli.NewExitError(err, 1)
+		if recordFile != "" {
+			rec, err := traffic.NewFileRecorder(recordFile, time.Now())
+			if err != nil {
+				lgr.Warnf("リクエストを記録できません: %+v", err)
+				dumpFailedResult(ctx, []string{})
+				return cli.NewExitError(err, 1)
+			}
+			// 記録はこの実行のcontextで作ったクライアントのリクエストだけを対象にする
+			benchRun.Traffic = rec
+			ctx = benchRun.Context(context.Background())
+			defer func() {
+				if err := rec.Stop(); err != nil {
+					lgr.Warnf("リクエストの記録に失敗しました: %+v", err)
+				}
+			}()
 		}
 
-		testClient, err := isutrain.NewClient()
+		result, err := benchmark(ctx, benchRun, &benchmarkOptions{
+			assets:       assets,
+			profile:      profile,
+			arrival:      arrival,
+			seed:         seed,
+			timelineFile: timelineFile,
+		})
 		if err != nil {
-			lgr.Warn("pretestクライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
-		paymentClient, err := payment.NewClient()
+		// 最終結果をstdoutへ書き出す
+		resultBytes, err := json.Marshal(result)
 		if err != nil {
-			lgr.Warn("課金クライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			lgr.Warn("ベンチマーク結果のMarshalに失敗しました: %+v", err)
 			return cli.NewExitError(err, 1)
 		}
+		fmt.Println(string(resultBytes))
 
-		if config.Debug {
-			lgr.Warn("!!!!! Debug enabled !!!!!")
-			httpmock.Activate()
-			defer httpmock.DeactivateAndReset()
+		return nil
+	},
+}
 
-			_, err = mock.Register()
-			if err != nil {
-				dumpFailedResult([]string{err.Error()})
-				return cli.NewExitError(err, 1)
-			}
-			initClient.ReplaceMockTransport()
-			testClient.ReplaceMockTransport()
-		}
+// benchmarkOptions はベンチマーク1回分の負荷のかけ方です
+type benchmarkOptions struct {
+	assets       []*assets.Asset
+	profile      *workload.Profile
+	arrival      *workload.Arrival
+	seed         int64
+	timelineFile string
+}
 
-		// initialize
-		lgr.Info("===== Initialize payment =====")
-		if err := paymentClient.Initialize(); err != nil {
-			lgr.Warnf("課金APIへの /initialize でエラーが発生: %s", err.Error())
-			dumpFailedResult(bencherror.InitializeErrs.Msgs)
-			return nil
-		}
-		lgr.Info("===== Initialize webapp =====")
-		initClient.Initialize(ctx)
-		if bencherror.InitializeErrs.IsError() {
-			lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs.InternalMsgs)
-			dumpFailedResult(bencherror.InitializeErrs.Msgs)
-			return nil
-		}
+// 同じプロファイルで何度もベンチマークできるよう、実行ごとに複製して使う
+func (opts *benchmarkOptions) copyProfile() *workload.Profile {
+	if opts.profile == nil {
+		return nil
+	}
+	profile := *opts.profile
+	return &profile
+}
 
-		// pretest (まず、正しく動作できているかチェック. エラーが見つかったら、採点しようがないのでFAILにする)
-		lgr.Info("===== Pretest webapp =====")
-		scenario.Pretest(ctx, testClient, paymentClient, assets)
-		if bencherror.PreTestErrs.IsError() {
-			lgr.Warnf("webappへの pretest でエラーが発生: %+v", bencherror.PreTestErrs.InternalMsgs)
-			dumpFailedResult(bencherror.PreTestErrs.Msgs)
-			return nil
-		}
+// benchmark は、benchRunの宛先に対して初期化からスコアの計算までを行います
+// 失格した場合もその結果を返します. ベンチマークを始められなかった場合はエラーを返します
+func benchmark(ctx context.Context, benchRun *benchrun.Run, opts *benchmarkOptions) (*BenchResult, error) {
+	lgr := zap.S()
 
-		// bench (ISUCOIN売り上げ計上と、減点カウントを行う)
-		lgr.Info("===== Benchmark webapp =====")
-		benchCtx, cancel := context.WithTimeout(context.Background(), config.BenchmarkTimeout)
-		defer cancel()
+	initClient, err := isutrain.NewClientForInitialize(ctx)
+	if err != nil {
+		lgr.Warn("isutrainクライアント生成に失敗しました: %+v", err)
+		return nil, err
+	}
 
-		bgCtx, bgCancel := context.WithCancel(ctx)
-		bgtester, err := newBgTester()
-		if err != nil {
-			dumpFailedResult(uniqueMsgs(bencherror.BenchmarkErrs.Msgs))
-			return nil
+	testClient, err := isutrain.NewClient(ctx)
+	if err != nil {
+		lgr.Warn("pretestクライアント生成に失敗しました: %+v", err)
+		return nil, err
+	}
+
+	paymentClient, err := payment.NewClient(ctx)
+	if err != nil {
+		lgr.Warn("課金クライアント生成に失敗しました: %+v", err)
+		return nil, err
+	}
+
+	if config.Debug {
+		lgr.Warn("!!!!! Debug enabled !!!!!")
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		if _, err := mock.Register(); err != nil {
+			return nil, err
 		}
-		go bgtester.run(bgCtx)
+		initClient.ReplaceMockTransport()
+		testClient.ReplaceMockTransport()
+	}
+
+	// initialize
+	lgr.Info("===== Wait for payment =====")
+	if err := paymentClient.WaitReady(ctx, 30*time.Second); err != nil {
+		lgr.Warnf("課金APIの起動待ちでエラーが発生: %s", err.Error())
+		return failedResult(ctx, opts.seed, bencherror.InitializeErrs(ctx).Msgs), nil
+	}
+	lgr.Info("===== Initialize payment =====")
+	if err := paymentClient.Initialize(ctx); err != nil {
+		lgr.Warnf("課金APIへの /initialize でエラーが発生: %s", err.Error())
+		return failedResult(ctx, opts.seed, bencherror.InitializeErrs(ctx).Msgs), nil
+	}
+	lgr.Info("===== Initialize webapp =====")
+	initClient.Initialize(traffic.WithScenario(ctx, "initialize"))
+	if bencherror.InitializeErrs(ctx).IsError() {
+		lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs(ctx).InternalMsgs)
+		return failedResult(ctx, opts.seed, bencherror.InitializeErrs(ctx).Msgs), nil
+	}
+
+	// pretest (まず、正しく動作できているかチェック. エラーが見つかったら、採点しようがないのでFAILにする)
+	lgr.Info("===== Pretest webapp =====")
+	scenario.Pretest(traffic.WithScenario(ctx, "pretest"), testClient, paymentClient, opts.assets)
+	if bencherror.PreTestErrs(ctx).IsError() {
+		lgr.Warnf("webappへの pretest でエラーが発生: %+v", bencherror.PreTestErrs(ctx).InternalMsgs)
+		return failedResult(ctx, opts.seed, bencherror.PreTestErrs(ctx).Msgs), nil
+	}
+
+	// bench (ISUCOIN売り上げ計上と、減点カウントを行う)
+	lgr.Info("===== Benchmark webapp =====")
+	benchmarkTimeout := config.BenchmarkTimeout
+	if opts.profile != nil && opts.profile.Duration > 0 {
+		benchmarkTimeout = opts.profile.Duration
+	}
+	benchCtx, cancel := context.WithTimeout(ctx, benchmarkTimeout)
+	defer cancel()
+
+	// ベンチマーク中のリクエストのレイテンシを記録する
+	benchRun.Recorder.StartRecording(time.Now())
 
-		benchmarker := newBenchmarker()
-		if err := benchmarker.run(benchCtx); err != nil {
-			lgr.Warnf("ベンチマークにてエラーが発生しました: %+v", err)
+	bgtester, err := newBgTester(ctx)
+	if err != nil {
+		return failedResult(ctx, opts.seed, errorMsgs(bencherror.BenchmarkErrs(ctx))), nil
+	}
+	bgCtx, bgCancel := context.WithCancel(traffic.WithScenario(ctx, "bgtest"))
+	defer bgCancel()
+	go bgtester.run(bgCtx)
+
+	benchmarker := newBenchmarker(ctx, opts.copyProfile(), opts.arrival, opts.seed)
+	var tl *timeline
+	if opts.timelineFile != "" {
+		tl, err = openTimeline(opts.timelineFile, benchRun, benchmarker.running)
+		if err != nil {
+			// タイムラインは補助的な情報なので、書き出せなくてもベンチマークは続ける
+			lgr.Warnf("タイムラインを書き出せません: %+v", err)
+		} else {
+			tl.start(time.Now())
 		}
-		bgCancel()
-		if bencherror.BenchmarkErrs.IsFailure() {
-			dumpFailedResult(uniqueMsgs(bencherror.BenchmarkErrs.Msgs))
-			return nil
+	}
+	if err := benchmarker.run(benchCtx); err != nil {
+		lgr.Warnf("ベンチマークにてエラーが発生しました: %+v", err)
+	}
+	bgCancel()
+	if tl != nil {
+		if err := tl.stop(time.Now()); err != nil {
+			lgr.Warnf("タイムラインの書き出しに失敗しました: %+v", err)
 		}
+	}
+	benchRun.Recorder.StopRecording(time.Now())
+	endpointStats := benchRun.Recorder.Report()
+	endpoint.WriteReport(os.Stderr, endpointStats)
+	if bencherror.BenchmarkErrs(ctx).IsFailure() {
+		return failedResult(ctx, opts.seed, errorMsgs(bencherror.BenchmarkErrs(ctx))), nil
+	}
 
-		lgr.Info("===== Final check =====")
-		// NOTE: bulkリクエストの遅延処理考慮で、５秒待つ
-		time.Sleep(5 * time.Second)
-		scenario.FinalCheck(ctx, testClient, paymentClient)
-		if bencherror.FinalCheckErrs.IsFailure() {
-			lgr.Warnf("webappへのfinalcheckで失格判定: %+v", bencherror.FinalCheckErrs.InternalMsgs)
-			msgs := append(uniqueMsgs(bencherror.BenchmarkErrs.Msgs), bencherror.FinalCheckErrs.Msgs...)
-			dumpFailedResult(msgs)
-			return nil
-		}
+	lgr.Info("===== Final check =====")
+	// NOTE: bulkリクエストの遅延処理考慮で、５秒待つ
+	time.Sleep(5 * time.Second)
+	scenario.FinalCheck(traffic.WithScenario(ctx, "finalcheck"), testClient, paymentClient)
+	if bencherror.FinalCheckErrs(ctx).IsFailure() {
+		lgr.Warnf("webappへのfinalcheckで失格判定: %+v", bencherror.FinalCheckErrs(ctx).InternalMsgs)
+		msgs := append(errorMsgs(bencherror.BenchmarkErrs(ctx)), bencherror.FinalCheckErrs(ctx).Msgs...)
+		return failedResult(ctx, opts.seed, msgs), nil
+	}
 
-		lgr.Info("===== System errors =====")
-		if bencherror.SystemErrs.IsError() {
-			for _, errMsg := range bencherror.SystemErrs.InternalMsgs {
-				lgr.Warn(errMsg)
-			}
+	lgr.Info("===== System errors =====")
+	if bencherror.SystemErrs(ctx).IsError() {
+		for _, errMsg := range bencherror.SystemErrs(ctx).InternalMsgs {
+			lgr.Warn(errMsg)
 		}
+	}
 
-		// posttest (ベンチ後の整合性チェックにより、減点カウントを行う)
-		lgr.Info("===== Calculate final score =====")
+	// posttest (ベンチ後の整合性チェックにより、減点カウントを行う)
+	lgr.Info("===== Calculate final score =====")
 
-		scoreMsgs := []string{
-			fmt.Sprintf("エンドポイント成功回数: %d", endpoint.CalcFinalEndpointCount()),
-		}
+	scoreMsgs := []string{
+		fmt.Sprintf("シード: %d", opts.seed),
+		fmt.Sprintf("エンドポイント成功回数: %d", benchRun.Recorder.CalcFinalEndpointCount()),
+	}
 
-		score := endpoint.CalcFinalScore()
-		lgr.Infof("Final score: %d", score)
-		scoreMsgs = append(scoreMsgs, fmt.Sprintf("スコア: %d", score))
+	score := benchRun.Recorder.CalcFinalScore()
+	lgr.Infof("Final score: %d", score)
 
-		// エラーカウントから、スコアを減点
-		score -= bencherror.BenchmarkErrs.Penalty()
-		lgr.Infof("Final score (with penalty): %d", score)
-		scoreMsgs = append(scoreMsgs, fmt.Sprintf("ペナルティ: %d", bencherror.BenchmarkErrs.Penalty()))
+	// エラーカウントから、スコアを減点
+	score -= bencherror.BenchmarkErrs(ctx).Penalty()
+	lgr.Infof("Final score (with penalty): %d", score)
 
-		// 最終結果をstdoutへ書き出す
-		resultBytes, err := json.Marshal(&BenchResult{
-			Pass:          true,
-			Score:         score,
-			Messages:      append(uniqueMsgs(bencherror.BenchmarkErrs.Msgs), scoreMsgs...),
-			AvailableDays: config.AvailableDays,
-			Language:      config.Language,
-		})
-		if err != nil {
-			lgr.Warn("ベンチマーク結果のMarshalに失敗しました: %+v", err)
-			return cli.NewExitError(err, 1)
-		}
-		fmt.Println(string(resultBytes))
+	// 参加者には、スコアの内訳とペナルティの計算式を見せる
+	scoreReport := benchRun.ScoreReport(maxErrorMessages)
+	benchrun.WriteScoreReport(os.Stderr, scoreReport)
 
-		return nil
-	},
+	var openLoopResult *OpenLoopResult
+	if benchmarker.openLoop != nil {
+		openLoopResult = benchmarker.openLoop.result()
+		scoreMsgs = append(scoreMsgs, fmt.Sprintf("オープンループ: 開始 %d, 完了 %d, ドロップ %d, 遅延開始 %d",
+			openLoopResult.Issued, openLoopResult.Completed, openLoopResult.Dropped, openLoopResult.Late))
+	}
+
+	return &BenchResult{
+		Pass:          true,
+		Score:         score,
+		Messages:      append(scoreReport.Messages(), scoreMsgs...),
+		AvailableDays: benchRun.Target.AvailableDays,
+		Language:      benchRun.Target.Language,
+		Seed:          opts.seed,
+		OpenLoop:      openLoopResult,
+		EndpointStats: endpointStats,
+		ScoreReport:   scoreReport,
+	}, nil
 }
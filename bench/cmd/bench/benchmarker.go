@@ -3,11 +3,18 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
 	"github.com/chibiegg/isucon9-final/bench/scenario"
 	"golang.org/x/sync/semaphore"
 )
@@ -18,72 +25,185 @@ var (
 
 type benchmarker struct {
 	sem *semaphore.Weighted
+
+	// profileが指定された場合は、load()の代わりにプロファイルのシナリオを重みに従って実行する
+	profile *workload.Profile
+	// ランプアップ中に確保しておく実行枠
+	held int64
+	// シナリオの選択と、シナリオごとの乱数源のシードに使う(runのゴルーチンからのみ使う)
+	rnd *rand.Rand
+
+	// arrivalが指定された場合は、並列数ではなく到着率でシナリオを開始する(オープンループ)
+	arrival  *workload.Arrival
+	openLoop *openLoopStats
+
+	// 実行中のシナリオ数(オープンループではopenLoopが数える)
+	inFlight int64
+	// 開始した負荷の単位の数. リクエストを記録する際のシナリオIDに使う
+	seq int64
+}
+
+// プロファイルのシナリオがすべて登録されているか確認する
+func checkProfile(profile *workload.Profile) error {
+	for _, s := range profile.Scenarios {
+		if _, ok := scenario.Lookup(s.Name); !ok {
+			return fmt.Errorf("不明なシナリオです: %s (%v)", s.Name, scenario.Names())
+		}
+	}
+	return nil
 }
 
-func newBenchmarker() *benchmarker {
+func newBenchmarker(ctx context.Context, profile *workload.Profile, arrival *workload.Arrival, seed int64) *benchmarker {
 	lgr := zap.S()
 
-	weight := int64(config.ReservationEndDate.Month())
+	weight := int64(config.FromContext(ctx).ReservationEndDate.Month())
 	lgr.Infof("負荷レベル Lv:%d", weight)
-	return &benchmarker{sem: semaphore.NewWeighted(weight)}
+	if arrival != nil {
+		lgr.Infof("オープンループ: rate=%.1f/s steps=%+v max_in_flight=%d late_threshold=%s",
+			arrival.Rate, arrival.Steps, arrival.MaxInFlight, arrival.LateThreshold)
+	}
+	if profile == nil {
+		return &benchmarker{
+			sem:      semaphore.NewWeighted(weight),
+			rnd:      rand.New(rand.NewSource(seed)),
+			arrival:  arrival,
+			openLoop: newOpenLoopStats(arrival),
+		}
+	}
+
+	if profile.Concurrency == 0 {
+		profile.Concurrency = int(weight)
+	}
+	lgr.Infof("ワークロードプロファイル %s: concurrency=%d ramp_up=%s(%s) scenarios=%+v",
+		profile.Name, profile.Concurrency, profile.RampUp.Duration, profile.RampUp.Curve, profile.Scenarios)
+
+	b := &benchmarker{
+		sem:     semaphore.NewWeighted(int64(profile.Concurrency)),
+		profile: profile,
+		held:    int64(profile.Concurrency - profile.ConcurrencyAt(0)),
+		rnd:     rand.New(rand.NewSource(seed)),
+
+		arrival:  arrival,
+		openLoop: newOpenLoopStats(arrival),
+	}
+	// ランプアップが終わるまで使わない枠を確保しておく
+	b.sem.Acquire(context.Background(), b.held)
+	return b
 }
 
 // ベンチ負荷の１単位. これの回転数を上げていく
-func (b *benchmarker) load(ctx context.Context) error {
-	defer b.sem.Release(1)
-
-	month := int(config.ReservationEndDate.Month())
+// シナリオごとに加点を集計できるよう、シナリオIDはワークロードプロファイルと同じシナリオ名にする
+func (b *benchmarker) load(ctx context.Context, seq int64) error {
+	month := int(config.FromContext(ctx).ReservationEndDate.Month())
+	withScenario := func(name string) context.Context {
+		return traffic.WithScenario(ctx, fmt.Sprintf("%s-%d", name, seq))
+	}
 
-	scenario.NormalScenario(ctx)
+	scenario.NormalScenario(withScenario("normal"))
 
-	scenario.NormalCancelScenario(ctx)
+	scenario.NormalCancelScenario(withScenario("normal_cancel"))
 
-	scenario.AttackReserveForOtherReservation(ctx)
+	scenario.AttackReserveForOtherReservation(withScenario("attack_reserve_for_other_reservation"))
 
-	scenario.AttackReserveRaceCondition(ctx)
+	scenario.AttackReserveRaceCondition(withScenario("attack_reserve_race_condition"))
 
-	scenario.AbnormalReserveWrongSection(ctx)
+	scenario.AbnormalReserveWrongSection(withScenario("abnormal_reserve_wrong_section"))
 
-	scenario.AbnormalReserveWrongSeat(ctx)
+	scenario.AbnormalReserveWrongSeat(withScenario("abnormal_reserve_wrong_seat"))
 
 	if month > 3 {
-		scenario.NormalManyAmbigiousSearchScenario(ctx, month*3)
+		scenario.NormalManyAmbigiousSearchScenario(withScenario("normal_many_ambiguous_search"), month*3)
 	}
 
 	if month > 3 {
-		scenario.NormalManyCancelScenario(ctx, month*3)
+		scenario.NormalManyCancelScenario(withScenario("normal_many_cancel"), month*3)
 	}
 
-	scenario.NormalVagueSearchScenario(ctx)
+	scenario.NormalVagueSearchScenario(withScenario("normal_vague_search"))
 
-	if config.IsGoldenweekStarted() {
-		scenario.SeasonGoldenWeekScenario(ctx, config.GoldenWeekStartDate, 5)
+	if config.FromContext(ctx).IsGoldenweekStarted() {
+		scenario.SeasonGoldenWeekScenario(withScenario("season_golden_week"), config.GoldenWeekStartDate, 5)
 	}
-	if config.IsGoldenweekEnded() {
-		scenario.SeasonGoldenWeekScenario(ctx, config.GoldenWeekEndDate, 5)
+	if config.FromContext(ctx).IsGoldenweekEnded() {
+		scenario.SeasonGoldenWeekScenario(withScenario("season_golden_week"), config.GoldenWeekEndDate, 5)
 	}
 
-	if config.IsOlympic() {
-		scenario.SeasonOlympicScenario(ctx, 5)
+	if config.FromContext(ctx).IsOlympic() {
+		scenario.SeasonOlympicScenario(withScenario("season_olympic"), 5)
 	}
 
 	return nil
 }
 
+// プロファイルから重みに従って選んだシナリオを1つ実行する
+func (b *benchmarker) loadProfile(ctx context.Context, s workload.Scenario) error {
+	f, _ := scenario.Lookup(s.Name)
+	return f(ctx, s.Scale)
+}
+
+// ランプアップの経過に合わせて確保していた枠を解放する
+func (b *benchmarker) rampUp(elapsed time.Duration) {
+	held := int64(b.profile.Concurrency - b.profile.ConcurrencyAt(elapsed))
+	if held < b.held {
+		b.sem.Release(b.held - held)
+		b.held = held
+	}
+}
+
+// 次に実行する負荷の単位を選ぶ(runのゴルーチンからのみ呼ぶ)
+// 負荷の単位ごとに乱数源を分けるので、同じシードなら同じ順序で同じユーザや区間が使われる
+func (b *benchmarker) next() (string, func(ctx context.Context) error) {
+	rnd := xrandom.New(b.rnd.Int63())
+	b.seq++
+	seq := b.seq
+	if b.profile == nil {
+		return "default", func(ctx context.Context) error {
+			return b.load(xrandom.NewContext(ctx, rnd), seq)
+		}
+	}
+	s := b.profile.Pick(b.rnd)
+	return s.Name, func(ctx context.Context) error {
+		ctx = traffic.WithScenario(xrandom.NewContext(ctx, rnd), fmt.Sprintf("%s-%d", s.Name, seq))
+		return b.loadProfile(ctx, s)
+	}
+}
+
+// 実行中のシナリオ数を返す
+func (b *benchmarker) running() int64 {
+	if b.openLoop != nil {
+		return atomic.LoadInt64(&b.openLoop.inFlight)
+	}
+	return atomic.LoadInt64(&b.inFlight)
+}
+
 func (b *benchmarker) run(ctx context.Context) error {
-	defer bencherror.BenchmarkErrs.DumpCounters()
+	defer bencherror.BenchmarkErrs(ctx).DumpCounters()
+	if b.arrival != nil {
+		return b.runOpenLoop(ctx)
+	}
+
+	startedAt := time.Now()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		default:
-			if bencherror.BenchmarkErrs.IsFailure() {
+			if bencherror.BenchmarkErrs(ctx).IsFailure() {
 				// 失格と分かれば、早々にベンチマークを終了
 				return ErrBenchmarkFailure
 			}
 
+			if b.held > 0 {
+				b.rampUp(time.Since(startedAt))
+			}
 			if isAcquired := b.sem.TryAcquire(1); isAcquired {
-				go b.load(ctx)
+				_, unit := b.next()
+				atomic.AddInt64(&b.inFlight, 1)
+				go func() {
+					defer b.sem.Release(1)
+					defer atomic.AddInt64(&b.inFlight, -1)
+					unit(ctx)
+				}()
 			}
 		}
 	}
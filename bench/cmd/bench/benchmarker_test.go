@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchmarkerSeed(t *testing.T) {
+	picks := func(seed int64) []string {
+		profile := &workload.Profile{
+			Concurrency: 1,
+			Scenarios:   []workload.Scenario{{Name: "normal", Weight: 1}, {Name: "normal_cancel", Weight: 1}},
+		}
+		ctx := benchrun.New(config.NewTarget(config.DefaultTargetBaseURL, config.DefaultPaymentBaseURL)).Context(context.Background())
+		b := newBenchmarker(ctx, profile, nil, seed)
+		names := []string{}
+		for i := 0; i < 20; i++ {
+			name, _ := b.next()
+			names = append(names, name)
+		}
+		return names
+	}
+	// 同じシードなら同じ順序でシナリオを選ぶ
+	assert.Equal(t, picks(1), picks(1))
+	assert.NotEqual(t, picks(1), picks(2))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/logger"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
@@ -16,38 +17,39 @@ var bgtest = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:        "target",
-			Value:       "http://localhost",
-			Destination: &config.TargetBaseURL,
+			Value:       config.DefaultTargetBaseURL,
+			Destination: &targetURL,
 			EnvVar:      "BENCH_TARGET_URL",
 		},
 	},
 	Action: func(cliCtx *cli.Context) error {
-		ctx := context.Background()
+		ctx := benchrun.New(newTarget(targetURL, paymentURL)).Context(context.Background())
 
 		lgr, err := logger.InitZapLogger()
 		if err != nil {
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
+		seedRandom(0)
 
 		lgr.Info("===== Prepare bgtester =====")
 
-		initClient, err := isutrain.NewClientForInitialize()
+		initClient, err := isutrain.NewClientForInitialize(ctx)
 		if err != nil {
 			lgr.Warn("isutrainクライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
 		lgr.Info("===== Initialize webapp =====")
 		initClient.Initialize(ctx)
-		if bencherror.InitializeErrs.IsError() {
-			lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs.Msgs)
-			dumpFailedResult(bencherror.InitializeErrs.Msgs)
+		if bencherror.InitializeErrs(ctx).IsError() {
+			lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs(ctx).Msgs)
+			dumpFailedResult(ctx, bencherror.InitializeErrs(ctx).Msgs)
 			return nil
 		}
 
-		tester, err := newBgTester()
+		tester, err := newBgTester(ctx)
 		if err != nil {
 			return cli.NewExitError(err, 1)
 		}
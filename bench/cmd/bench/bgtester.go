@@ -44,10 +44,8 @@ type BgTester struct {
 	reserveCarNum                    int
 }
 
-func newBgTester() (*BgTester, error) {
-	ctx := context.Background()
-
-	client, err := isutrain.NewClient()
+func newBgTester(ctx context.Context) (*BgTester, error) {
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +124,7 @@ func (t *BgTester) bgtestSeatAvailability(ctx context.Context, wantReservedSeatA
 
 	sa := targetTrain.SeatAvailability
 	if sa["reserved"] != wantReservedSeatAvailability {
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: seatAvailabilityが不正です: want=%s, got=%s", endpointPath, wantReservedSeatAvailability, sa["reserved"]))
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: seatAvailabilityが不正です: want=%s, got=%s", endpointPath, wantReservedSeatAvailability, sa["reserved"]))
 	}
 
 	return nil
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/chibiegg/isucon9-final/bench/assets"
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/compare"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/logger"
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+var (
+	compareTargetA    string
+	compareTargetB    string
+	comparePaymentA   string
+	comparePaymentB   string
+	compareIterations int
+	compareConcurrent bool
+	compareAlpha      float64
+	compareOutput     string
+)
+
+var compareCommand = cli.Command{
+	Name:  "compare",
+	Usage: "2つのwebappに同じシードで負荷をかけ、スコアとエンドポイントごとのレイテンシ、エラーを比較する",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "target-a",
+			Destination: &compareTargetA,
+			EnvVar:      "BENCH_TARGET_A_URL",
+		},
+		cli.StringFlag{
+			Name:        "target-b",
+			Destination: &compareTargetB,
+			EnvVar:      "BENCH_TARGET_B_URL",
+		},
+		cli.StringFlag{
+			Name:        "payment",
+			Value:       config.DefaultPaymentBaseURL,
+			Destination: &paymentURL,
+			EnvVar:      "BENCH_PAYMENT_URL",
+		},
+		cli.StringFlag{
+			Name:        "payment-a",
+			Usage:       "target-aが使う課金APIのURL. 省略時は --payment",
+			Destination: &comparePaymentA,
+		},
+		cli.StringFlag{
+			Name:        "payment-b",
+			Usage:       "target-bが使う課金APIのURL. 省略時は --payment",
+			Destination: &comparePaymentB,
+		},
+		cli.StringFlag{
+			Name:        "payment-api-key",
+			Destination: &paymentAPIKey,
+			EnvVar:      "BENCH_PAYMENT_API_KEY",
+		},
+		cli.StringFlag{
+			Name:        "payment-merchant",
+			Destination: &paymentMerchantID,
+			EnvVar:      "BENCH_PAYMENT_MERCHANT",
+		},
+		cli.StringFlag{
+			Name:        "assetdir",
+			Value:       "assets/testdata",
+			Destination: &assetDir,
+			EnvVar:      "BENCH_ASSETDIR",
+		},
+		cli.StringFlag{
+			Name:        "profile",
+			Usage:       "ワークロードプロファイル(YAML/JSON)のパス. 省略時は標準の負荷をかける",
+			Destination: &profileFile,
+			EnvVar:      "BENCH_PROFILE",
+		},
+		cli.Int64Flag{
+			Name:        "seed",
+			Usage:       "1回目の乱数のシード. n回目はseed+n-1を使い、AとBには同じシードで負荷をかける. 省略時は現在時刻から決める",
+			Destination: &seed,
+			EnvVar:      "BENCH_SEED",
+		},
+		cli.IntFlag{
+			Name:        "iterations",
+			Usage:       "AとBをそれぞれベンチマークする回数. 2以上でスコアの差を検定する",
+			Value:       1,
+			Destination: &compareIterations,
+		},
+		cli.BoolFlag{
+			Name:        "concurrent",
+			Usage:       "AとBを同時にベンチマークする. 別々のDBと課金APIを使う環境でのみ指定できる. 省略時は1つずつ初期化してから順番に実行する",
+			Destination: &compareConcurrent,
+		},
+		cli.Float64Flag{
+			Name:        "alpha",
+			Usage:       "差があると判定する有意水準",
+			Value:       compare.DefaultAlpha,
+			Destination: &compareAlpha,
+		},
+		cli.StringFlag{
+			Name:        "output",
+			Usage:       "比較結果をJSONで書き出すファイルのパス",
+			Destination: &compareOutput,
+		},
+	},
+	Action: func(cliCtx *cli.Context) error {
+		lgr, err := logger.InitZapLogger()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		if compareTargetA == "" || compareTargetB == "" {
+			return cli.NewExitError(errors.New("--target-a と --target-b を指定してください"), 1)
+		}
+		if compareIterations < 1 {
+			return cli.NewExitError(errors.New("--iterations は1以上にしてください"), 1)
+		}
+		if comparePaymentA == "" {
+			comparePaymentA = paymentURL
+		}
+		if comparePaymentB == "" {
+			comparePaymentB = paymentURL
+		}
+		if compareConcurrent && comparePaymentA == comparePaymentB {
+			// 課金APIの初期化や最終チェックが、もう一方のベンチマークの決済と混ざってしまう
+			return cli.NewExitError(errors.New("同時に実行する場合は --payment-a と --payment-b に別々の課金APIを指定してください"), 1)
+		}
+
+		seed = seedRandom(seed)
+		lgr.Infof("シード: %d", seed)
+
+		opts := &benchmarkOptions{}
+		if profileFile != "" {
+			opts.profile, err = workload.LoadFile(profileFile)
+			if err == nil {
+				err = checkProfile(opts.profile)
+			}
+			if err != nil {
+				lgr.Warnf("ワークロードプロファイルを読み込めませんでした: %+v", err)
+				return cli.NewExitError(err, 1)
+			}
+			opts.arrival = opts.profile.Arrival
+		}
+		opts.assets, err = assets.Load(assetDir)
+		if err != nil {
+			lgr.Warnf("静的ファイルをローカルから読み出せませんでした: %+v", err)
+			return cli.NewExitError(err, 1)
+		}
+
+		iterations := []*compare.Iteration{}
+		for i := 0; i < compareIterations; i++ {
+			it, err := compareOnce(opts, seed+int64(i), i)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			lgr.Infof("===== %d/%d: A=%d B=%d =====", i+1, compareIterations, it.A.Score, it.B.Score)
+			iterations = append(iterations, it)
+		}
+
+		report := compare.Analyze(compareTargetA, compareTargetB, iterations, compareAlpha)
+		compare.WriteReport(os.Stdout, report)
+
+		if compareOutput != "" {
+			b, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			if err := ioutil.WriteFile(compareOutput, b, 0644); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+
+		return nil
+	},
+}
+
+// compareOnce は、同じシードでAとBを1回ずつベンチマークします
+// 順番に実行する場合は、時間による環境の変化が片方に偏らないよう、回ごとに実行順を入れ替える
+func compareOnce(baseOpts *benchmarkOptions, seed int64, i int) (*compare.Iteration, error) {
+	opts := *baseOpts
+	opts.seed = seed
+
+	it := &compare.Iteration{Seed: seed}
+	a := func() (err error) {
+		it.A, err = benchmarkTarget("A", compareTargetA, comparePaymentA, &opts)
+		return
+	}
+	b := func() (err error) {
+		it.B, err = benchmarkTarget("B", compareTargetB, comparePaymentB, &opts)
+		return
+	}
+
+	if compareConcurrent {
+		var (
+			wg         sync.WaitGroup
+			errA, errB error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errA = a()
+		}()
+		go func() {
+			defer wg.Done()
+			errB = b()
+		}()
+		wg.Wait()
+		if errA != nil {
+			return nil, errA
+		}
+		return it, errB
+	}
+
+	first, second := a, b
+	if i%2 == 1 {
+		first, second = b, a
+	}
+	if err := first(); err != nil {
+		return nil, err
+	}
+	if err := second(); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// benchmarkTarget は、1つの宛先を初期化してからベンチマークし、比較に使う結果を返します
+func benchmarkTarget(name, target, payment string, opts *benchmarkOptions) (*compare.Result, error) {
+	zap.S().Infof("===== Benchmark %s: %s (seed=%d) =====", name, target, opts.seed)
+
+	benchRun := benchrun.New(newTarget(target, payment))
+	// pretestなど負荷の単位の外で使う乱数も、AとBで同じ順序にする
+	ctx := benchRun.Context(xrandom.NewContext(context.Background(), xrandom.New(opts.seed)))
+	result, err := benchmark(ctx, benchRun, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 初期化やpretestで失格した場合も、その理由を比較できるようにする
+	msgs := map[string]int{}
+	for _, errs := range []*bencherror.BenchErrors{
+		benchRun.Errors.Initialize,
+		benchRun.Errors.PreTest,
+		benchRun.Errors.Benchmark,
+		benchRun.Errors.FinalCheck,
+	} {
+		for _, msg := range errs.Msgs {
+			msgs[msg]++
+		}
+	}
+	return &compare.Result{
+		Pass:          result.Pass,
+		Score:         result.Score,
+		Errors:        benchRun.Errors.Benchmark.Counts(),
+		ErrorMessages: msgs,
+		EndpointStats: result.EndpointStats,
+	}, nil
+}
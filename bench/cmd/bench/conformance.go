@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chibiegg/isucon9-final/bench/assets"
+	"github.com/chibiegg/isucon9-final/bench/conformance"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/logger"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+	"github.com/urfave/cli"
+)
+
+var (
+	conformanceAssetDir string
+	conformanceFormat   string
+	conformanceOutput   string
+	conformanceRun      string
+)
+
+var conformanceCommand = cli.Command{
+	Name:  "conformance",
+	Usage: "webappのAPIの仕様を満たしているかチェックし、JUnit XMLかTAPで結果を出力する",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "target",
+			Value:       config.DefaultTargetBaseURL,
+			Destination: &targetURL,
+			EnvVar:      "BENCH_TARGET_URL",
+		},
+		cli.StringFlag{
+			Name:        "payment",
+			Usage:       "決済APIのURL. 空なら決済が必要なチェックはスキップする",
+			Destination: &paymentURL,
+			EnvVar:      "BENCH_PAYMENT_URL",
+		},
+		cli.StringFlag{
+			Name:        "payment-api-key",
+			Destination: &paymentAPIKey,
+			EnvVar:      "BENCH_PAYMENT_API_KEY",
+		},
+		cli.StringFlag{
+			Name:        "payment-merchant",
+			Destination: &paymentMerchantID,
+			EnvVar:      "BENCH_PAYMENT_MERCHANT",
+		},
+		cli.StringFlag{
+			Name:        "assetdir",
+			Usage:       "静的ファイルのディレクトリ. 空なら静的ファイルのチェックはスキップする",
+			Destination: &conformanceAssetDir,
+			EnvVar:      "BENCH_ASSETDIR",
+		},
+		cli.StringFlag{
+			Name:        "format",
+			Usage:       "結果の形式(junit, tap)",
+			Value:       conformance.FormatJUnit,
+			Destination: &conformanceFormat,
+		},
+		cli.StringFlag{
+			Name:        "output",
+			Usage:       "結果を書き出すファイル. 空なら標準出力",
+			Destination: &conformanceOutput,
+		},
+		cli.StringFlag{
+			Name:        "run",
+			Usage:       "名前か分類が正規表現にマッチするチェックだけを実行する",
+			Destination: &conformanceRun,
+		},
+	},
+	Action: func(cliCtx *cli.Context) error {
+		ctx := benchrun.New(newTarget(targetURL, paymentURL)).Context(context.Background())
+
+		lgr, err := logger.InitZapLogger()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		seedRandom(0)
+
+		checks, err := conformance.Filter(conformance.Checks(), conformanceRun)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		env := &conformance.Env{Target: targetURL}
+		if conformanceAssetDir != "" {
+			env.Assets, err = assets.Load(conformanceAssetDir)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		if paymentURL != "" {
+			env.Payment, err = payment.NewClient(ctx)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+
+		var w io.Writer = os.Stdout
+		if conformanceOutput != "" {
+			f, err := os.Create(conformanceOutput)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		lgr.Infof("===== Conformance %d checks =====", len(checks))
+		results := conformance.Run(ctx, env, checks)
+		for _, r := range results {
+			switch {
+			case r.Failed():
+				lgr.Warnf("FAIL %s/%s: %s", r.Check.Category, r.Check.Name, r.Message())
+			case r.Skipped():
+				lgr.Infof("SKIP %s/%s: %s", r.Check.Category, r.Check.Name, r.SkipReason)
+			default:
+				lgr.Infof("PASS %s/%s", r.Check.Category, r.Check.Name)
+			}
+		}
+
+		if err := conformance.WriteReport(w, conformanceFormat, results); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		if failures := conformance.Failures(results); failures > 0 {
+			return cli.NewExitError(fmt.Sprintf("%d/%d checks failed", failures, len(results)), 1)
+		}
+		return nil
+	},
+}
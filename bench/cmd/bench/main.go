@@ -6,17 +6,43 @@ import (
 	"os"
 	"time"
 
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
 	"github.com/urfave/cli"
 )
 
+// 各コマンドの --target, --payment, --payment-api-key, --payment-merchant
+var (
+	targetURL         string
+	paymentURL        string
+	paymentAPIKey     string
+	paymentMerchantID string
+)
+
 func init() {
 	loc, err := time.LoadLocation("Asia/Tokyo")
 	if err != nil {
 		log.Fatalln(err)
 	}
 	time.Local = loc
+}
 
-	rand.Seed(time.Now().UnixNano())
+// newTarget は、フラグで指定した課金APIの管理キーとマーチャントIDを使うベンチマーク対象を作ります
+func newTarget(target, payment string) *config.Target {
+	t := config.NewTarget(target, payment)
+	t.PaymentAPIKey = paymentAPIKey
+	t.PaymentMerchantID = paymentMerchantID
+	return t
+}
+
+// seedRandom は乱数源をseedで初期化し、使ったシードを返します. 0の場合は現在時刻から決めます
+func seedRandom(seed int64) int64 {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	xrandom.Seed(seed)
+	return seed
 }
 
 func main() {
@@ -34,6 +60,10 @@ func cliMain() int {
 		run,
 		pretest,
 		bgtest,
+		replay,
+		compareCommand,
+		conformanceCommand,
+		mockServerCommand,
 	}
 
 	app.Action = func(cliCtx *cli.Context) error {
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/logger"
+	"github.com/chibiegg/isucon9-final/bench/mock"
+	"github.com/urfave/cli"
+)
+
+var (
+	mockServerListen        string
+	mockServerPaymentListen string
+	mockServerPaymentURL    string
+	mockServerAvailableDays int
+	mockServerAssetDir      string
+	mockServerBugs          cli.StringSlice
+)
+
+var mockServerCommand = cli.Command{
+	Name:  "mockserver",
+	Usage: "予約の状態を持つisutrainと課金APIのモックサーバを起動する",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "listen",
+			Value:       ":8000",
+			Destination: &mockServerListen,
+		},
+		cli.StringFlag{
+			Name:        "payment-listen",
+			Usage:       "課金APIのモックがlistenするアドレス",
+			Value:       ":5000",
+			Destination: &mockServerPaymentListen,
+		},
+		cli.StringFlag{
+			Name:        "payment-url",
+			Usage:       "/api/settings で返す課金APIのURL",
+			Value:       "http://localhost:5000",
+			Destination: &mockServerPaymentURL,
+		},
+		cli.IntFlag{
+			Name:        "available-days",
+			Value:       30,
+			Destination: &mockServerAvailableDays,
+		},
+		cli.StringFlag{
+			Name:        "assetdir",
+			Usage:       "静的ファイルのディレクトリ. 空なら静的ファイルは配信しない",
+			Destination: &mockServerAssetDir,
+		},
+		cli.StringSliceFlag{
+			Name:  "bug",
+			Usage: "仕込む不具合(double-booking, wrong-fare, missing-seats, ignore-ownership, no-refund, wrong-availability). 複数指定できる",
+			Value: &mockServerBugs,
+		},
+	},
+	Action: func(cliCtx *cli.Context) error {
+		lgr, err := logger.InitZapLogger()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		bugs := []mock.Bug{}
+		for _, name := range mockServerBugs {
+			bug, err := mock.ParseBug(name)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			bugs = append(bugs, bug)
+		}
+
+		server := mock.NewServer(mockServerAvailableDays, mockServerPaymentURL)
+		server.AssetDir = mockServerAssetDir
+		server.SetBugs(bugs...)
+
+		errCh := make(chan error, 2)
+		go func() {
+			lgr.Infof("課金APIのモックを起動します: %s", mockServerPaymentListen)
+			errCh <- http.ListenAndServe(mockServerPaymentListen, server.PaymentHandler())
+		}()
+		go func() {
+			lgr.Infof("isutrainのモックを起動します: %s (bugs=%v)", mockServerListen, bugs)
+			errCh <- http.ListenAndServe(mockServerListen, server.Handler())
+		}()
+
+		return cli.NewExitError(<-errCh, 1)
+	},
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+)
+
+// OpenLoopResult はオープンループで実行したシナリオの集計です
+// レイテンシは予定していた開始時刻からシナリオの完了までの時間です
+type OpenLoopResult struct {
+	Issued     int64                    `json:"issued"`     // 開始したシナリオ数
+	Completed  int64                    `json:"completed"`  // 完了したシナリオ数
+	Dropped    int64                    `json:"dropped"`    // 実行中のシナリオが上限に達していたため開始しなかった数
+	Late       int64                    `json:"late"`       // 予定時刻から閾値以上遅れて開始した数
+	Unfinished int64                    `json:"unfinished"` // ベンチマーク終了時に実行中だった数
+	Scenarios  []OpenLoopScenarioResult `json:"scenarios"`
+}
+
+// OpenLoopScenarioResult はシナリオごとの集計です
+type OpenLoopScenarioResult struct {
+	Name      string  `json:"name"`
+	Issued    int64   `json:"issued"`
+	Completed int64   `json:"completed"`
+	Dropped   int64   `json:"dropped"`
+	Late      int64   `json:"late"`
+	P50       float64 `json:"p50_ms"`
+	P90       float64 `json:"p90_ms"`
+	P99       float64 `json:"p99_ms"`
+	Max       float64 `json:"max_ms"`
+}
+
+type openLoopScenarioStats struct {
+	issued    int64
+	dropped   int64
+	late      int64
+	latencies []time.Duration
+}
+
+type openLoopStats struct {
+	maxInFlight   int64
+	lateThreshold time.Duration
+	inFlight      int64
+
+	mu        sync.Mutex
+	scenarios map[string]*openLoopScenarioStats
+}
+
+func newOpenLoopStats(arrival *workload.Arrival) *openLoopStats {
+	if arrival == nil {
+		return nil
+	}
+	return &openLoopStats{
+		maxInFlight:   int64(arrival.MaxInFlight),
+		lateThreshold: arrival.LateThreshold,
+		scenarios:     map[string]*openLoopScenarioStats{},
+	}
+}
+
+func (s *openLoopStats) get(name string) *openLoopScenarioStats {
+	st, ok := s.scenarios[name]
+	if !ok {
+		st = &openLoopScenarioStats{}
+		s.scenarios[name] = st
+	}
+	return st
+}
+
+// 予定時刻intendedのシナリオを開始する. 実行中のシナリオが上限に達していれば開始しない
+func (s *openLoopStats) dispatch(ctx context.Context, name string, intended time.Time, unit func(ctx context.Context) error) {
+	s.mu.Lock()
+	st := s.get(name)
+	if atomic.LoadInt64(&s.inFlight) >= s.maxInFlight {
+		st.dropped++
+		s.mu.Unlock()
+		return
+	}
+	st.issued++
+	if time.Since(intended) > s.lateThreshold {
+		st.late++
+	}
+	s.mu.Unlock()
+
+	atomic.AddInt64(&s.inFlight, 1)
+	go func() {
+		defer atomic.AddInt64(&s.inFlight, -1)
+		unit(ctx)
+		latency := time.Since(intended)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		st.latencies = append(st.latencies, latency)
+	}()
+}
+
+// 昇順に並んだlatenciesのpパーセンタイル(0 < p <= 100)
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	i := int(float64(len(latencies))*p/100+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(latencies) {
+		i = len(latencies) - 1
+	}
+	return latencies[i]
+}
+
+func msec(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (s *openLoopStats) result() *OpenLoopResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.scenarios))
+	for name := range s.scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := &OpenLoopResult{Scenarios: []OpenLoopScenarioResult{}}
+	for _, name := range names {
+		st := s.scenarios[name]
+		l := make([]time.Duration, len(st.latencies))
+		copy(l, st.latencies)
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+
+		sr := OpenLoopScenarioResult{
+			Name:      name,
+			Issued:    st.issued,
+			Completed: int64(len(l)),
+			Dropped:   st.dropped,
+			Late:      st.late,
+			P50:       msec(percentile(l, 50)),
+			P90:       msec(percentile(l, 90)),
+			P99:       msec(percentile(l, 99)),
+		}
+		if len(l) > 0 {
+			sr.Max = msec(l[len(l)-1])
+		}
+		r.Issued += sr.Issued
+		r.Completed += sr.Completed
+		r.Dropped += sr.Dropped
+		r.Late += sr.Late
+		r.Scenarios = append(r.Scenarios, sr)
+	}
+	r.Unfinished = r.Issued - r.Completed
+	return r
+}
+
+// 到着率に従って、応答を待たずにシナリオを開始する
+func (b *benchmarker) runOpenLoop(ctx context.Context) error {
+	lgr := zap.S()
+
+	startedAt := time.Now()
+	intended := startedAt
+	for {
+		if bencherror.BenchmarkErrs(ctx).IsFailure() {
+			// 失格と分かれば、早々にベンチマークを終了
+			return ErrBenchmarkFailure
+		}
+
+		// 予定時刻を過ぎていれば、遅れを取り戻すためにすぐ開始する
+		timer := time.NewTimer(time.Until(intended))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r := b.openLoop.result()
+			lgr.Infof("オープンループ: issued=%d completed=%d dropped=%d late=%d unfinished=%d",
+				r.Issued, r.Completed, r.Dropped, r.Late, r.Unfinished)
+			for _, sr := range r.Scenarios {
+				lgr.Infof("  %-40s issued=%d completed=%d dropped=%d late=%d p50=%.1fms p90=%.1fms p99=%.1fms max=%.1fms",
+					sr.Name, sr.Issued, sr.Completed, sr.Dropped, sr.Late, sr.P50, sr.P90, sr.P99, sr.Max)
+			}
+			return nil
+		case <-timer.C:
+		}
+
+		name, unit := b.next()
+		b.openLoop.dispatch(ctx, name, intended, unit)
+
+		rate := b.arrival.RateAt(intended.Sub(startedAt))
+		intended = intended.Add(time.Duration(float64(time.Second) / rate))
+	}
+}
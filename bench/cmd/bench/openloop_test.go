@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenLoopStats(t *testing.T) {
+	s := newOpenLoopStats(&workload.Arrival{Rate: 1, MaxInFlight: 2, LateThreshold: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	blocking := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	now := time.Now()
+	s.dispatch(ctx, "slow", now, blocking)
+	s.dispatch(ctx, "slow", now.Add(-time.Second), blocking)
+	// 実行中が上限に達しているので開始しない
+	s.dispatch(ctx, "slow", now, blocking)
+
+	r := s.result()
+	assert.Equal(t, int64(2), r.Issued)
+	assert.Equal(t, int64(1), r.Dropped)
+	assert.Equal(t, int64(1), r.Late)
+	assert.Equal(t, int64(2), r.Unfinished)
+
+	close(release)
+	for i := 0; i < 100 && s.result().Completed < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	r = s.result()
+	assert.Equal(t, int64(0), r.Unfinished)
+	// レイテンシは予定時刻から計測する
+	assert.True(t, r.Scenarios[0].Max >= 1000, "max=%f", r.Scenarios[0].Max)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/chibiegg/isucon9-final/bench/assets"
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/logger"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
@@ -19,14 +20,24 @@ var pretest = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:        "payment",
-			Value:       "http://localhost:5000",
-			Destination: &config.PaymentBaseURL,
+			Value:       config.DefaultPaymentBaseURL,
+			Destination: &paymentURL,
 			EnvVar:      "BENCH_PAYMENT_URL",
 		},
+		cli.StringFlag{
+			Name:        "payment-api-key",
+			Destination: &paymentAPIKey,
+			EnvVar:      "BENCH_PAYMENT_API_KEY",
+		},
+		cli.StringFlag{
+			Name:        "payment-merchant",
+			Destination: &paymentMerchantID,
+			EnvVar:      "BENCH_PAYMENT_MERCHANT",
+		},
 		cli.StringFlag{
 			Name:        "target",
-			Value:       "http://localhost",
-			Destination: &config.TargetBaseURL,
+			Value:       config.DefaultTargetBaseURL,
+			Destination: &targetURL,
 			EnvVar:      "BENCH_TARGET_URL",
 		},
 		cli.StringFlag{
@@ -37,58 +48,59 @@ var pretest = cli.Command{
 		},
 	},
 	Action: func(cliCtx *cli.Context) error {
-		ctx := context.Background()
+		ctx := benchrun.New(newTarget(targetURL, paymentURL)).Context(context.Background())
 
 		lgr, err := logger.InitZapLogger()
 		if err != nil {
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
+		seedRandom(0)
 
 		lgr.Info("===== Prepare benchmarker =====")
 
 		assets, err := assets.Load(assetDir)
 		if err != nil {
 			lgr.Warn("静的ファイルをローカルから読み出せませんでした: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
-		initClient, err := isutrain.NewClientForInitialize()
+		initClient, err := isutrain.NewClientForInitialize(ctx)
 		if err != nil {
 			lgr.Warn("isutrainクライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
-		testClient, err := isutrain.NewClient()
+		testClient, err := isutrain.NewClient(ctx)
 		if err != nil {
 			lgr.Warn("pretestクライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
-		paymentClient, err := payment.NewClient()
+		paymentClient, err := payment.NewClient(ctx)
 		if err != nil {
 			lgr.Warn("課金クライアント生成に失敗しました: %+v", err)
-			dumpFailedResult([]string{})
+			dumpFailedResult(ctx, []string{})
 			return cli.NewExitError(err, 1)
 		}
 
 		lgr.Info("===== Initialize webapp =====")
 		initClient.Initialize(ctx)
-		if bencherror.InitializeErrs.IsError() {
-			lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs.Msgs)
-			dumpFailedResult(bencherror.InitializeErrs.Msgs)
+		if bencherror.InitializeErrs(ctx).IsError() {
+			lgr.Warnf("webappへの /initialize でエラーが発生: %+v", bencherror.InitializeErrs(ctx).Msgs)
+			dumpFailedResult(ctx, bencherror.InitializeErrs(ctx).Msgs)
 			return nil
 		}
 
 		// pretest (まず、正しく動作できているかチェック. エラーが見つかったら、採点しようがないのでFAILにする)
 		lgr.Info("===== Pretest webapp =====")
 		scenario.Pretest(ctx, testClient, paymentClient, assets)
-		if bencherror.PreTestErrs.IsError() {
-			lgr.Warnf("webappへの pretest でエラーが発生: %+v", bencherror.PreTestErrs.Msgs)
-			dumpFailedResult(bencherror.PreTestErrs.Msgs)
+		if bencherror.PreTestErrs(ctx).IsError() {
+			lgr.Warnf("webappへの pretest でエラーが発生: %+v", bencherror.PreTestErrs(ctx).Msgs)
+			dumpFailedResult(ctx, bencherror.PreTestErrs(ctx).Msgs)
 			return nil
 		}
 
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/logger"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/urfave/cli"
+)
+
+var (
+	replaySpeed     float64
+	replayDiffPaths = cli.StringSlice(traffic.DefaultDiffPaths)
+)
+
+var replay = cli.Command{
+	Name:      "replay",
+	Usage:     "run --record で記録したリクエストを送り直す",
+	ArgsUsage: "<記録したファイル>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "target",
+			Value:       config.DefaultTargetBaseURL,
+			Destination: &targetURL,
+			EnvVar:      "BENCH_TARGET_URL",
+		},
+		cli.Float64Flag{
+			Name:        "speed",
+			Usage:       "記録時の何倍の速さで送るか. 0なら待たずに送る",
+			Value:       1,
+			Destination: &replaySpeed,
+		},
+		cli.StringSliceFlag{
+			Name:  "diff-path",
+			Usage: "レスポンスのボディを記録と比較するAPIのパス(前方一致)を追加する. 静的ファイルは常に比較する",
+			Value: &replayDiffPaths,
+		},
+	},
+	Action: func(cliCtx *cli.Context) error {
+		ctx := context.Background()
+
+		lgr, err := logger.InitZapLogger()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		if cliCtx.NArg() != 1 {
+			return cli.NewExitError(errors.New("記録したファイルを1つ指定してください"), 1)
+		}
+		entries, err := traffic.LoadFile(cliCtx.Args().First())
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if len(entries) == 0 {
+			return cli.NewExitError(errors.New("記録したリクエストがありません"), 1)
+		}
+
+		replayer, err := traffic.NewReplayer(targetURL, replaySpeed, replayDiffPaths)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		lgr.Infof("===== Replay %d requests (speed=%v) =====", len(entries), replaySpeed)
+		result := replayer.Replay(ctx, entries)
+		lgr.Infof("replayed=%d errors=%d status_mismatches=%d body_mismatches=%d elapsed=%.1fs",
+			result.Replayed, result.Errors, result.StatusMismatches, result.BodyMismatches, result.Elapsed)
+		for _, d := range result.Diffs {
+			lgr.Warnf("[%s] %s %s (session=%d scenario=%s): recorded=%.200s replayed=%.200s",
+				d.Kind, d.Method, d.Path, d.Session, d.Scenario, d.Recorded, d.Replayed)
+		}
+
+		b, err := json.Marshal(result)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Println(string(b))
+
+		return nil
+	},
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+)
+
+// TimelineSample はベンチマーク中の一定間隔ごとの状況です
+// リクエスト数とエラー数は前回のサンプルからの増分、実行中のシナリオ数とスコアはサンプル時点の値です
+type TimelineSample struct {
+	Elapsed           float64 `json:"elapsed_sec"`
+	Requests          int64   `json:"requests"`
+	Successes         int64   `json:"successes"`
+	CriticalErrors    uint64  `json:"critical_errors"`
+	ApplicationErrors uint64  `json:"application_errors"`
+	TimeoutErrors     uint64  `json:"timeout_errors"`
+	TemporaryErrors   uint64  `json:"temporary_errors"`
+	InFlight          int64   `json:"in_flight"`
+	Score             int64   `json:"score"` // ペナルティを差し引いた累計のスコア
+}
+
+var timelineCSVHeader = []string{
+	"elapsed_sec", "requests", "successes",
+	"critical_errors", "application_errors", "timeout_errors", "temporary_errors",
+	"in_flight", "score",
+}
+
+func (s TimelineSample) csvRecord() []string {
+	return []string{
+		strconv.FormatFloat(s.Elapsed, 'f', 3, 64),
+		strconv.FormatInt(s.Requests, 10),
+		strconv.FormatInt(s.Successes, 10),
+		strconv.FormatUint(s.CriticalErrors, 10),
+		strconv.FormatUint(s.ApplicationErrors, 10),
+		strconv.FormatUint(s.TimeoutErrors, 10),
+		strconv.FormatUint(s.TemporaryErrors, 10),
+		strconv.FormatInt(s.InFlight, 10),
+		strconv.FormatInt(s.Score, 10),
+	}
+}
+
+// サンプル時点の累計値
+type timelineSnapshot struct {
+	requests  int64
+	successes int64
+	errs      bencherror.Counts
+	score     int64
+}
+
+func currentTimelineSnapshot(run *benchrun.Run) timelineSnapshot {
+	requests, successes := run.Recorder.Totals()
+	return timelineSnapshot{
+		requests:  requests,
+		successes: successes,
+		errs:      run.Errors.Benchmark.Counts(),
+		score:     run.CurrentScore(),
+	}
+}
+
+const (
+	timelineFormatCSV   = "csv"
+	timelineFormatJSONL = "jsonl"
+)
+
+// 拡張子が.csvならCSV、それ以外はJSON Lines
+func timelineFormatOf(path string) string {
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		return timelineFormatCSV
+	}
+	return timelineFormatJSONL
+}
+
+type timeline struct {
+	w        *bufio.Writer
+	csv      *csv.Writer
+	closer   io.Closer
+	interval time.Duration
+
+	snapshot func() timelineSnapshot
+	running  func() int64
+
+	startedAt time.Time
+	prev      timelineSnapshot
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newTimeline(w io.Writer, format string, run *benchrun.Run, running func() int64) *timeline {
+	t := &timeline{
+		w:        bufio.NewWriter(w),
+		interval: time.Second,
+		snapshot: func() timelineSnapshot { return currentTimelineSnapshot(run) },
+		running:  running,
+		stopCh:   make(chan struct{}),
+	}
+	if format == timelineFormatCSV {
+		t.csv = csv.NewWriter(t.w)
+	}
+	return t
+}
+
+// openTimeline はpathにタイムラインを書き出すtimelineを作ります
+func openTimeline(path string, run *benchrun.Run, running func() int64) (*timeline, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, xerrors.Errorf("タイムラインのファイルを作成できません: %w", err)
+	}
+	t := newTimeline(f, timelineFormatOf(path), run, running)
+	t.closer = f
+	return t, nil
+}
+
+func (t *timeline) write(s TimelineSample) error {
+	if t.csv != nil {
+		if err := t.csv.Write(s.csvRecord()); err != nil {
+			return err
+		}
+		t.csv.Flush()
+		if err := t.csv.Error(); err != nil {
+			return err
+		}
+	} else {
+		b, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if _, err := t.w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	// 実行中でもグラフにできるよう、サンプルごとに書き出す
+	return t.w.Flush()
+}
+
+func (t *timeline) sample(now time.Time) TimelineSample {
+	cur := t.snapshot()
+	s := TimelineSample{
+		Elapsed:           now.Sub(t.startedAt).Seconds(),
+		Requests:          cur.requests - t.prev.requests,
+		Successes:         cur.successes - t.prev.successes,
+		CriticalErrors:    cur.errs.Critical - t.prev.errs.Critical,
+		ApplicationErrors: cur.errs.Application - t.prev.errs.Application,
+		TimeoutErrors:     cur.errs.Timeout - t.prev.errs.Timeout,
+		TemporaryErrors:   cur.errs.Temporary - t.prev.errs.Temporary,
+		InFlight:          t.running(),
+		Score:             cur.score,
+	}
+	t.prev = cur
+	return s
+}
+
+// start はintervalごとのサンプルの書き出しを始めます
+// CSVのヘッダは最初のサンプルと一緒に書き出される
+func (t *timeline) start(startedAt time.Time) {
+	t.startedAt = startedAt
+	t.prev = t.snapshot()
+	if t.csv != nil {
+		t.csv.Write(timelineCSVHeader)
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		lgr := zap.S()
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stopCh:
+				return
+			case now := <-ticker.C:
+				if err := t.write(t.sample(now)); err != nil {
+					lgr.Warnf("タイムラインの書き出しに失敗しました: %+v", err)
+				}
+			}
+		}
+	}()
+}
+
+// stop は最後のサンプルを書き出して、ファイルを閉じます
+func (t *timeline) stop(now time.Time) error {
+	close(t.stopCh)
+	t.wg.Wait()
+
+	if err := t.write(t.sample(now)); err != nil {
+		return err
+	}
+	if t.closer != nil {
+		return t.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineSample(t *testing.T) {
+	snapshots := []timelineSnapshot{
+		{},
+		{requests: 10, successes: 8, errs: bencherror.Counts{Application: 2}, score: 30},
+		{requests: 25, successes: 20, errs: bencherror.Counts{Application: 3, Timeout: 2}, score: 50},
+	}
+	newTestTimeline := func(format string) (*timeline, *bytes.Buffer) {
+		var buf bytes.Buffer
+		tl := newTimeline(&buf, format, nil, func() int64 { return 4 })
+		i := 0
+		tl.snapshot = func() timelineSnapshot {
+			s := snapshots[i]
+			i++
+			return s
+		}
+		tl.interval = time.Hour
+		return tl, &buf
+	}
+
+	startedAt := time.Now()
+	tl, buf := newTestTimeline(timelineFormatJSONL)
+	tl.start(startedAt)
+	assert.NoError(t, tl.write(tl.sample(startedAt.Add(time.Second))))
+	assert.NoError(t, tl.stop(startedAt.Add(2*time.Second)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	var samples []TimelineSample
+	for _, line := range lines {
+		var s TimelineSample
+		assert.NoError(t, json.Unmarshal([]byte(line), &s))
+		samples = append(samples, s)
+	}
+	assert.Equal(t, TimelineSample{Elapsed: 1, Requests: 10, Successes: 8, ApplicationErrors: 2, InFlight: 4, Score: 30}, samples[0])
+	// 件数は前回からの増分、スコアは累計
+	assert.Equal(t, TimelineSample{Elapsed: 2, Requests: 15, Successes: 12, ApplicationErrors: 1, TimeoutErrors: 2, InFlight: 4, Score: 50}, samples[1])
+
+	tl, buf = newTestTimeline(timelineFormatCSV)
+	tl.start(startedAt)
+	assert.NoError(t, tl.stop(startedAt.Add(time.Second)))
+	assert.Equal(t, "elapsed_sec,requests,successes,critical_errors,application_errors,timeout_errors,temporary_errors,in_flight,score\n"+
+		"1.000,10,8,0,2,0,0,4,30\n", buf.String())
+}
+
+func TestTimelineFormatOf(t *testing.T) {
+	assert.Equal(t, timelineFormatCSV, timelineFormatOf("out/timeline.CSV"))
+	assert.Equal(t, timelineFormatJSONL, timelineFormatOf("timeline.jsonl"))
+	assert.Equal(t, timelineFormatJSONL, timelineFormatOf("timeline"))
+}
@@ -0,0 +1,633 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/internal/isutraindb"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+	"github.com/chibiegg/isucon9-final/bench/scenario"
+)
+
+const initializeCheckName = "initialize"
+
+// チェックで予約する列車
+// 号車ごとに予約するチェックを分け、チェック同士で座席が重ならないようにしている
+var (
+	checkUseAt      = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	checkTrainClass = "最速"
+	checkTrainName  = "49"
+)
+
+func init() {
+	Register(
+		// 正常系
+		&Check{Name: initializeCheckName, Category: CategoryRoute, Run: checkInitialize},
+		&Check{Name: "settings", Category: CategoryRoute, Run: checkSettings},
+		&Check{Name: "list_stations", Category: CategoryRoute, Run: checkListStations},
+		&Check{Name: "static_files", Category: CategoryRoute, Run: checkStaticFiles},
+		&Check{Name: "signup_login_logout", Category: CategoryRoute, Run: checkSignupLoginLogout},
+		&Check{Name: "search_trains", Category: CategoryRoute, Run: checkSearchTrains},
+		&Check{Name: "search_train_seats", Category: CategoryRoute, Run: checkSearchTrainSeats},
+		&Check{Name: "reserve_show_list", Category: CategoryRoute, Run: checkReserveShowList},
+		&Check{Name: "commit_and_cancel", Category: CategoryRoute, Run: checkCommitAndCancel},
+		&Check{Name: "cancel_unpaid", Category: CategoryRoute, Run: checkCancelUnpaid},
+		&Check{Name: "payment_webhook_unsigned", Category: CategoryRoute, Run: checkPaymentWebhookUnsigned},
+
+		// 異常系
+		&Check{Name: "login_wrong_password", Category: CategoryError, Run: checkLoginWrongPassword},
+		&Check{Name: "signup_duplicate", Category: CategoryError, Run: checkSignupDuplicate},
+		&Check{Name: "search_out_of_period", Category: CategoryError, Run: checkSearchOutOfPeriod},
+		&Check{Name: "seats_unknown_train", Category: CategoryError, Run: checkSeatsUnknownTrain},
+		&Check{Name: "reserve_non_stoppable_station", Category: CategoryError, Run: checkReserveNonStoppableStation},
+		&Check{Name: "reserve_invalid_section", Category: CategoryError, Run: checkReserveInvalidSection},
+		&Check{Name: "reserve_unknown_seat", Category: CategoryError, Run: checkReserveUnknownSeat},
+		&Check{Name: "show_unknown_reservation", Category: CategoryError, Run: checkShowUnknownReservation},
+		&Check{Name: "commit_unknown_reservation", Category: CategoryError, Run: checkCommitUnknownReservation},
+
+		// 認可
+		&Check{Name: "login_required", Category: CategoryAuth, Run: checkLoginRequired},
+		&Check{Name: "other_user_reservation", Category: CategoryAuth, Run: checkOtherUserReservation},
+	)
+
+	// 運賃
+	for _, fc := range fareChecks {
+		Register(&Check{Name: fc.name, Category: CategoryFare, Run: fc.run})
+	}
+
+	// 座席
+	Register(
+		&Check{Name: "seat_overlap_rejected", Category: CategorySeat, Run: checkSeatOverlapRejected},
+		&Check{Name: "seat_adjacent_section", Category: CategorySeat, Run: checkSeatAdjacentSection},
+	)
+}
+
+// 号車の空席からcount席を選ぶ
+func searchFreeSeats(ctx context.Context, client *isutrain.Client, carNum int, departure, arrival string, count int) (isutrain.TrainSeats, error) {
+	resp, err := client.SearchTrainSeats(ctx, checkUseAt, checkTrainClass, checkTrainName, carNum, departure, arrival)
+	if err != nil {
+		return nil, err
+	}
+	seats := scenario.FilterTrainSeats(resp, count)
+	if len(seats) < count {
+		return nil, bencherror.NewSimpleCriticalError("GET %s: %d号車に空席が%d席ありません", endpoint.GetPath(endpoint.ListTrainSeats), carNum, count)
+	}
+	return seats, nil
+}
+
+func reserveSeats(ctx context.Context, client *isutrain.Client, carNum int, seats isutrain.TrainSeats, departure, arrival string, adult, child int, opt ...isutrain.ClientOption) (*isutrain.ReserveResponse, error) {
+	seatClass := isutraindb.GetSeatClass(checkTrainClass, carNum)
+	return client.Reserve(ctx, checkTrainClass, checkTrainName, seatClass, seats, departure, arrival, checkUseAt, carNum, child, adult, opt...)
+}
+
+// 正常系
+
+func checkInitialize(ctx context.Context, env *Env) error {
+	client, err := isutrain.NewClientForInitializeWithBaseURL(ctx, env.Target)
+	if err != nil {
+		return err
+	}
+	if err := client.TryInitialize(ctx); err != nil {
+		return err
+	}
+	if env.Payment != nil {
+		return env.Payment.Initialize(ctx)
+	}
+	return nil
+}
+
+func checkSettings(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	settings, err := client.Settings(ctx)
+	if err != nil {
+		return err
+	}
+	if settings.PaymentAPI == "" {
+		return bencherror.NewSimpleCriticalError("GET %s: payment_apiが空です", endpoint.GetPath(endpoint.Settings))
+	}
+	return nil
+}
+
+func checkListStations(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	stations, err := client.ListStations(ctx)
+	if err != nil {
+		return err
+	}
+	if !isutrain.IsValidStations(stations) {
+		return bencherror.NewSimpleCriticalError("GET %s: 駅一覧が不正です", endpoint.GetPath(endpoint.ListStations))
+	}
+	return nil
+}
+
+func checkStaticFiles(ctx context.Context, env *Env) error {
+	if len(env.Assets) == 0 {
+		return Skip("静的ファイルが指定されていません")
+	}
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	for _, asset := range env.Assets {
+		b, err := client.DownloadAsset(ctx, asset.Path)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(b)
+		if !bytes.Equal(hash[:], asset.Hash[:]) {
+			return bencherror.NewSimpleApplicationError("GET %s: 静的ファイルのハッシュ値が異なります", asset.Path)
+		}
+	}
+	return nil
+}
+
+func checkSignupLoginLogout(ctx context.Context, env *Env) error {
+	client, user, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	auth, err := client.GetAuth(ctx)
+	if err != nil {
+		return err
+	}
+	if auth.Email != user.Email {
+		return bencherror.NewSimpleCriticalError("GET %s: ログイン中のユーザのメールアドレスが不正です: want=%s, got=%s", endpoint.GetPath(endpoint.Auth), user.Email, auth.Email)
+	}
+	if err := client.Logout(ctx); err != nil {
+		return err
+	}
+	// ログアウト後はセッションが無効になっている
+	_, err = client.GetAuth(ctx, isutrain.StatusCodeOpt(http.StatusUnauthorized))
+	return err
+}
+
+// 検索結果のseat_fareのキーと座席クラスの対応. 喫煙席も運賃は同じ
+var fareSeatClasses = map[string]string{
+	"premium":        "premium",
+	"premium_smoke":  "premium",
+	"reserved":       "reserved",
+	"reserved_smoke": "reserved",
+	"non_reserved":   "non-reserved",
+}
+
+func checkSearchTrains(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	trains, err := client.SearchTrains(ctx, checkUseAt, "東京", "大阪", checkTrainClass, 1, 1)
+	if err != nil {
+		return err
+	}
+	endpointPath := endpoint.GetPath(endpoint.SearchTrains)
+	if len(trains) == 0 {
+		return bencherror.NewSimpleCriticalError("GET %s: 検索結果を返すべき条件で返せておらず、空です", endpointPath)
+	}
+	for _, train := range trains {
+		if train.Class != checkTrainClass {
+			return bencherror.NewSimpleCriticalError("GET %s: 検索条件と異なる列車種別が含まれています: want=%s, got=%s", endpointPath, checkTrainClass, train.Class)
+		}
+		for fareKey, seatClass := range fareSeatClasses {
+			fare, err := isutraindb.GetFare(0, checkUseAt, "東京", "大阪", train.Class, seatClass)
+			if err != nil {
+				return err
+			}
+			// 大人1人、子供(半額)1人
+			want := fare + fare/2
+			if got := train.FareInformation[fareKey]; got != want {
+				return bencherror.NewSimpleCriticalError("GET %s: 列車 %sの %sの運賃が不正です: want=%d, got=%d", endpointPath, train.Name, fareKey, want, got)
+			}
+		}
+	}
+	return nil
+}
+
+func checkSearchTrainSeats(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 4
+	resp, err := client.SearchTrainSeats(ctx, checkUseAt, checkTrainClass, checkTrainName, carNum, "東京", "大阪")
+	if err != nil {
+		return err
+	}
+	endpointPath := endpoint.GetPath(endpoint.ListTrainSeats)
+	if resp.TrainName != checkTrainName || resp.CarNumber != carNum {
+		return bencherror.NewSimpleCriticalError("GET %s: 列車名もしくは号車が不正です: want=%s/%d, got=%s/%d", endpointPath, checkTrainName, carNum, resp.TrainName, resp.CarNumber)
+	}
+	if len(resp.Seats) == 0 {
+		return bencherror.NewSimpleCriticalError("GET %s: 座席が空です", endpointPath)
+	}
+	if len(resp.Cars) == 0 {
+		return bencherror.NewSimpleCriticalError("GET %s: 車両一覧が空です", endpointPath)
+	}
+	return nil
+}
+
+func checkReserveShowList(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 9
+	seats, err := searchFreeSeats(ctx, client, carNum, "東京", "大阪", 2)
+	if err != nil {
+		return err
+	}
+	reserveResp, err := reserveSeats(ctx, client, carNum, seats, "東京", "大阪", 1, 1, isutrain.DisableAssertOpt())
+	if err != nil {
+		return err
+	}
+
+	reservation, err := client.ShowReservation(ctx, reserveResp.ReservationID)
+	if err != nil {
+		return err
+	}
+	if reservation.ReservationID != reserveResp.ReservationID {
+		return bencherror.NewSimpleCriticalError("GET %s: 予約IDが異なります: want=%d, got=%d", endpoint.GetPath(endpoint.ShowReservation), reserveResp.ReservationID, reservation.ReservationID)
+	}
+	if len(reservation.Seats) != len(seats) {
+		return bencherror.NewSimpleCriticalError("GET %s: 予約した座席数が異なります: want=%d, got=%d", endpoint.GetPath(endpoint.ShowReservation), len(seats), len(reservation.Seats))
+	}
+
+	reservations, err := client.ListReservations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		if r.ReservationID == reserveResp.ReservationID {
+			return nil
+		}
+	}
+	return bencherror.NewSimpleCriticalError("GET %s: 予約一覧に、予約したはずの予約IDが含まれていません: want=%d", endpoint.GetPath(endpoint.ListReservations), reserveResp.ReservationID)
+}
+
+func checkCommitAndCancel(ctx context.Context, env *Env) error {
+	if env.Payment == nil {
+		return Skip("決済APIが指定されていません")
+	}
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 10
+	seats, err := searchFreeSeats(ctx, client, carNum, "東京", "大阪", 2)
+	if err != nil {
+		return err
+	}
+	reserveResp, err := reserveSeats(ctx, client, carNum, seats, "東京", "大阪", 2, 0, isutrain.DisableAssertOpt())
+	if err != nil {
+		return err
+	}
+	cardToken, err := env.Payment.RegistCard(ctx, "4111111111111111", "222", "10/50")
+	if err != nil {
+		return err
+	}
+	if err := client.CommitReservation(ctx, reserveResp.ReservationID, cardToken); err != nil {
+		return err
+	}
+	// キャンセルすると、予約一覧と予約詳細から消える
+	return client.CancelReservation(ctx, reserveResp.ReservationID)
+}
+
+func checkCancelUnpaid(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 11
+	seats, err := searchFreeSeats(ctx, client, carNum, "東京", "大阪", 1)
+	if err != nil {
+		return err
+	}
+	reserveResp, err := reserveSeats(ctx, client, carNum, seats, "東京", "大阪", 1, 0, isutrain.DisableAssertOpt())
+	if err != nil {
+		return err
+	}
+	return client.CancelReservation(ctx, reserveResp.ReservationID)
+}
+
+func checkPaymentWebhookUnsigned(ctx context.Context, env *Env) error {
+	endpointPath := "/api/payment/webhook"
+	u, err := url.Parse(env.Target)
+	if err != nil {
+		return err
+	}
+	u.Path = filepath.Join(u.Path, endpointPath)
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewBufferString(`{"reservation_id":1,"status":"paid"}`))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", config.UserAgent)
+
+	client := &http.Client{Timeout: config.APITimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return bencherror.NewApplicationError(err, "POST %s: リクエストに失敗しました", endpointPath)
+	}
+	defer resp.Body.Close()
+
+	// 署名の検証に失敗した場合は400、webhookが無効な場合は404を返す
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
+		return bencherror.NewSimpleCriticalError("POST %s: 署名のないwebhookを受け付けています: got=%d", endpointPath, resp.StatusCode)
+	}
+	return nil
+}
+
+// 異常系
+
+func checkLoginWrongPassword(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	user := env.NewUser()
+	if err := client.Signup(ctx, user.Email, user.Password); err != nil {
+		return err
+	}
+	return client.Login(ctx, user.Email, user.Password+"x", isutrain.StatusCodeOpt(http.StatusForbidden))
+}
+
+func checkSignupDuplicate(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	user := env.NewUser()
+	if err := client.Signup(ctx, user.Email, user.Password); err != nil {
+		return err
+	}
+	return client.Signup(ctx, user.Email, user.Password, isutrain.StatusCodeOpt(http.StatusBadRequest))
+}
+
+func checkSearchOutOfPeriod(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	d := config.ReservationStartDate.AddDate(0, 0, config.FromContext(ctx).AvailableDays+1)
+	_, err = client.SearchTrains(ctx, d, "東京", "大阪", checkTrainClass, 1, 1, isutrain.StatusCodeOpt(http.StatusNotFound))
+	return err
+}
+
+func checkSeatsUnknownTrain(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.SearchTrainSeats(ctx, checkUseAt, checkTrainClass, "99999", 1, "東京", "大阪", isutrain.StatusCodeOpt(http.StatusNotFound))
+	return err
+}
+
+func checkReserveNonStoppableStation(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	d := time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)
+	_, err = client.Reserve(ctx, "最速", "1", "premium", isutrain.TrainSeats{}, "古岡", "大阪", d, 8, 1, 1, isutrain.StatusCodeOpt(http.StatusBadRequest))
+	return err
+}
+
+func checkReserveInvalidSection(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	d := time.Date(2020, 1, 1, 6, 50, 0, 0, time.UTC)
+	_, err = client.Reserve(ctx, "最速", "12", "premium", isutrain.TrainSeats{}, "大阪", "東京", d, 8, 1, 1, isutrain.StatusCodeOpt(http.StatusBadRequest))
+	return err
+}
+
+func checkReserveUnknownSeat(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	seats := isutrain.TrainSeats{&isutrain.TrainSeat{Row: 30, Column: "G"}}
+	_, err = reserveSeats(ctx, client, 5, seats, "東京", "大阪", 1, 0, isutrain.StatusCodeOpt(http.StatusNotFound))
+	return err
+}
+
+func checkShowUnknownReservation(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.ShowReservation(ctx, 999999999, isutrain.StatusCodeOpt(http.StatusNotFound))
+	return err
+}
+
+func checkCommitUnknownReservation(ctx context.Context, env *Env) error {
+	client, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	return client.CommitReservation(ctx, 999999999, "dummy", isutrain.StatusCodeOpt(http.StatusNotFound))
+}
+
+// 認可
+
+func checkLoginRequired(ctx context.Context, env *Env) error {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := client.GetAuth(ctx, isutrain.StatusCodeOpt(http.StatusUnauthorized)); err != nil {
+		return err
+	}
+	if _, err := client.ListReservations(ctx, isutrain.StatusCodeOpt(http.StatusUnauthorized)); err != nil {
+		return err
+	}
+	seats := isutrain.TrainSeats{&isutrain.TrainSeat{Row: 1, Column: "A"}}
+	_, err = reserveSeats(ctx, client, 12, seats, "東京", "大阪", 1, 0, isutrain.StatusCodeOpt(http.StatusUnauthorized))
+	return err
+}
+
+func checkOtherUserReservation(ctx context.Context, env *Env) error {
+	owner, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 12
+	seats, err := searchFreeSeats(ctx, owner, carNum, "東京", "大阪", 1)
+	if err != nil {
+		return err
+	}
+	reserveResp, err := reserveSeats(ctx, owner, carNum, seats, "東京", "大阪", 1, 0, isutrain.DisableAssertOpt())
+	if err != nil {
+		return err
+	}
+
+	other, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := other.ShowReservation(ctx, reserveResp.ReservationID, isutrain.StatusCodeOpt(http.StatusNotFound)); err != nil {
+		return err
+	}
+	if err := other.CancelReservation(ctx, reserveResp.ReservationID, isutrain.StatusCodeOpt(http.StatusBadRequest)); err != nil {
+		return err
+	}
+	if err := other.CommitReservation(ctx, reserveResp.ReservationID, "dummy", isutrain.StatusCodeOpt(http.StatusForbidden)); err != nil {
+		return err
+	}
+
+	// 他のユーザの操作で、予約が消えていない
+	_, err = owner.ShowReservation(ctx, reserveResp.ReservationID)
+	return err
+}
+
+// 運賃
+
+type fareCheck struct {
+	name               string
+	carNum             int
+	departure, arrival string
+	adult, child       int
+}
+
+var fareChecks = []*fareCheck{
+	{name: "fare_reserved_adult", carNum: 5, departure: "東京", arrival: "大阪", adult: 1},
+	{name: "fare_reserved_child", carNum: 6, departure: "東京", arrival: "名古屋", adult: 1, child: 2},
+	{name: "fare_premium", carNum: 8, departure: "名古屋", arrival: "京都", adult: 2},
+}
+
+// 予約、予約詳細、予約一覧の運賃がisutraindbから求めた運賃と一致するか
+func (fc *fareCheck) run(ctx context.Context, env *Env) error {
+	client, user, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	seats, err := searchFreeSeats(ctx, client, fc.carNum, fc.departure, fc.arrival, fc.adult+fc.child)
+	if err != nil {
+		return err
+	}
+	reserveResp, err := reserveSeats(ctx, client, fc.carNum, seats, fc.departure, fc.arrival, fc.adult, fc.child, isutrain.DisableAssertOpt())
+	if err != nil {
+		return err
+	}
+
+	entry := &isutrain.ReservationCacheEntry{
+		User:       user,
+		ID:         reserveResp.ReservationID,
+		Date:       checkUseAt,
+		Departure:  fc.departure,
+		Arrival:    fc.arrival,
+		TrainClass: checkTrainClass,
+		TrainName:  checkTrainName,
+		CarNum:     fc.carNum,
+		SeatClass:  isutraindb.GetSeatClass(checkTrainClass, fc.carNum),
+		Seats:      seats,
+		Adult:      fc.adult,
+		Child:      fc.child,
+	}
+	want, err := entry.Amount()
+	if err != nil {
+		return err
+	}
+
+	reservePath := endpoint.GetPath(endpoint.Reserve)
+	if reserveResp.Amount != want {
+		return bencherror.NewSimpleCriticalError("POST %s: amountが不正です: seatClass=%s, %s→%s, adult=%d, child=%d: want=%d, got=%d", reservePath, entry.SeatClass, fc.departure, fc.arrival, fc.adult, fc.child, want, reserveResp.Amount)
+	}
+
+	reservation, err := client.ShowReservation(ctx, reserveResp.ReservationID)
+	if err != nil {
+		return err
+	}
+	if reservation.Amount != want {
+		return bencherror.NewSimpleCriticalError("GET %s: 予約 %dの amountが不正です: want=%d, got=%d", endpoint.GetPath(endpoint.ShowReservation), reserveResp.ReservationID, want, reservation.Amount)
+	}
+	return nil
+}
+
+// 座席
+
+// 一部でも区間が重なる座席は予約できない
+func checkSeatOverlapRejected(ctx context.Context, env *Env) error {
+	first, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 13
+	seats, err := searchFreeSeats(ctx, first, carNum, "東京", "京都", 1)
+	if err != nil {
+		return err
+	}
+	if _, err := reserveSeats(ctx, first, carNum, seats, "東京", "京都", 1, 0, isutrain.DisableAssertOpt()); err != nil {
+		return err
+	}
+
+	second, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	// 名古屋〜京都が重なる
+	_, err = reserveSeats(ctx, second, carNum, seats, "名古屋", "大阪", 1, 0, isutrain.StatusCodeOpt(http.StatusBadRequest))
+	if err != nil {
+		return err
+	}
+
+	// 重なる区間の座席検索では埋まっている
+	resp, err := second.SearchTrainSeats(ctx, checkUseAt, checkTrainClass, checkTrainName, carNum, "名古屋", "大阪")
+	if err != nil {
+		return err
+	}
+	for _, seat := range resp.Seats {
+		if seat.Row == seats[0].Row && seat.Column == seats[0].Column && !seat.IsOccupied {
+			return bencherror.NewSimpleCriticalError("GET %s: 予約済みの座席 %d%sが空席になっています", endpoint.GetPath(endpoint.ListTrainSeats), seat.Row, seat.Column)
+		}
+	}
+	return nil
+}
+
+// 区間が重ならなければ、同じ座席を予約できる
+func checkSeatAdjacentSection(ctx context.Context, env *Env) error {
+	first, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	carNum := 14
+	seats, err := searchFreeSeats(ctx, first, carNum, "東京", "大阪", 1)
+	if err != nil {
+		return err
+	}
+	if _, err := reserveSeats(ctx, first, carNum, seats, "東京", "京都", 1, 0, isutrain.DisableAssertOpt()); err != nil {
+		return err
+	}
+
+	second, _, err := env.NewLoggedInClient(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := second.SearchTrainSeats(ctx, checkUseAt, checkTrainClass, checkTrainName, carNum, "京都", "大阪")
+	if err != nil {
+		return err
+	}
+	for _, seat := range resp.Seats {
+		if seat.Row == seats[0].Row && seat.Column == seats[0].Column && seat.IsOccupied {
+			return bencherror.NewSimpleCriticalError("GET %s: 区間が重ならない座席 %d%sが埋まっています", endpoint.GetPath(endpoint.ListTrainSeats), seat.Row, seat.Column)
+		}
+	}
+	_, err = reserveSeats(ctx, second, carNum, seats, "京都", "大阪", 1, 0, isutrain.DisableAssertOpt())
+	return err
+}
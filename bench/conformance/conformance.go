@@ -0,0 +1,184 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/assets"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+	"github.com/morikuni/failure"
+	"golang.org/x/xerrors"
+)
+
+// チェックの分類
+const (
+	CategoryRoute = "route" // 各エンドポイントの正常系
+	CategoryError = "error" // 不正なリクエストに対するエラー
+	CategoryAuth  = "auth"  // ログインや他ユーザの予約に関する認可
+	CategoryFare  = "fare"  // 運賃の計算
+	CategorySeat  = "seat"  // 座席の重複予約
+)
+
+// Env はチェックを実行する対象のwebappと、チェックに必要な情報です
+type Env struct {
+	Target string
+	// Payment がnilなら、決済が必要なチェックはスキップします
+	Payment *payment.Client
+	// Assets が空なら、静的ファイルのチェックはスキップします
+	Assets []*assets.Asset
+
+	userSeq int64
+}
+
+// NewClient はTargetにリクエストを送るクライアントを作成します
+func (env *Env) NewClient(ctx context.Context) (*isutrain.Client, error) {
+	return isutrain.NewClientWithBaseURL(ctx, env.Target)
+}
+
+// NewUser はチェックごとに重複しないユーザを作ります. 登録はしません
+func (env *Env) NewUser() *isutrain.User {
+	n := atomic.AddInt64(&env.userSeq, 1)
+	return &isutrain.User{
+		Email:    fmt.Sprintf("conformance%d-%d@example.com", time.Now().UnixNano(), n),
+		Password: fmt.Sprintf("conformance%d", n),
+	}
+}
+
+// NewLoggedInClient は新しいユーザを登録し、ログインしたクライアントを返します
+func (env *Env) NewLoggedInClient(ctx context.Context) (*isutrain.Client, *isutrain.User, error) {
+	client, err := env.NewClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	user := env.NewUser()
+	if err := client.Signup(ctx, user.Email, user.Password); err != nil {
+		return nil, nil, err
+	}
+	if err := client.Login(ctx, user.Email, user.Password); err != nil {
+		return nil, nil, err
+	}
+	return client, user, nil
+}
+
+// Check はwebappが満たすべき仕様1つ分です
+type Check struct {
+	Name     string
+	Category string
+	Run      func(ctx context.Context, env *Env) error
+}
+
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return "skip: " + e.reason
+}
+
+// Skip はチェックを実行できない理由を返すためのエラーです
+func Skip(reason string) error {
+	return &skipError{reason: reason}
+}
+
+// 上から順に実行される
+var checks []*Check
+
+// Register はチェックを追加します. チェックは登録した順に実行されます
+func Register(cs ...*Check) {
+	checks = append(checks, cs...)
+}
+
+// Checks は登録されているチェックを返します
+func Checks() []*Check {
+	return append([]*Check{}, checks...)
+}
+
+// Filter は名前か分類がpatternにマッチするチェックを返します. patternが空なら全てのチェックを返します
+// 他のチェックの前提となる初期化は常に含めます
+func Filter(cs []*Check, pattern string) ([]*Check, error) {
+	if pattern == "" {
+		return cs, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, xerrors.Errorf("チェックの絞り込み条件が不正です: %w", err)
+	}
+	filtered := []*Check{}
+	for _, c := range cs {
+		if c.Name == initializeCheckName || re.MatchString(c.Name) || re.MatchString(c.Category) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// Result はチェック1つ分の結果です
+type Result struct {
+	Check      *Check
+	Duration   time.Duration
+	Err        error
+	SkipReason string
+}
+
+// Skipped はチェックがスキップされたか否かを返します
+func (r *Result) Skipped() bool {
+	return r.SkipReason != ""
+}
+
+// Failed はチェックが失敗したか否かを返します
+func (r *Result) Failed() bool {
+	return r.Err != nil
+}
+
+// Message は失敗の理由を、ベンチマーカーがwebappに示すメッセージで返します
+func (r *Result) Message() string {
+	if r.Err == nil {
+		return ""
+	}
+	if msg, ok := failure.MessageOf(r.Err); ok {
+		return msg
+	}
+	return r.Err.Error()
+}
+
+// Run はチェックを順に実行します. 予約の状態を変更するチェックがあるため並列には実行しません
+func Run(ctx context.Context, env *Env, cs []*Check) []*Result {
+	// 予約可能日数や予約キャッシュは、ほかのベンチマークの実行と共有しない
+	if _, ok := benchrun.FromContext(ctx); !ok {
+		ctx = benchrun.New(config.NewTarget(env.Target, "")).Context(ctx)
+	}
+	results := make([]*Result, 0, len(cs))
+	for _, c := range cs {
+		startedAt := time.Now()
+		err := c.Run(ctx, env)
+		result := &Result{
+			Check:    c,
+			Duration: time.Since(startedAt),
+		}
+		var skipErr *skipError
+		if xerrors.As(err, &skipErr) {
+			result.SkipReason = skipErr.reason
+		} else {
+			result.Err = err
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Failures は失敗したチェックの数を返します
+func Failures(results []*Result) int {
+	n := 0
+	for _, r := range results {
+		if r.Failed() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/stretchr/testify/assert"
+)
+
+func testChecks() []*Check {
+	return []*Check{
+		{Name: initializeCheckName, Category: CategoryRoute, Run: func(ctx context.Context, env *Env) error { return nil }},
+		{Name: "ok", Category: CategoryRoute, Run: func(ctx context.Context, env *Env) error { return nil }},
+		{Name: "fail", Category: CategoryFare, Run: func(ctx context.Context, env *Env) error {
+			return bencherror.NewSimpleCriticalError("POST /api/train/reserve: amountが不正です")
+		}},
+		{Name: "plain_fail", Category: CategoryError, Run: func(ctx context.Context, env *Env) error {
+			return errors.New("connection refused")
+		}},
+		{Name: "skip", Category: CategoryRoute, Run: func(ctx context.Context, env *Env) error {
+			return Skip("決済APIが指定されていません")
+		}},
+	}
+}
+
+func TestRun(t *testing.T) {
+	results := Run(context.Background(), &Env{}, testChecks())
+	assert.Len(t, results, 5)
+	assert.False(t, results[1].Failed())
+	assert.True(t, results[2].Failed())
+	assert.Equal(t, "POST /api/train/reserve: amountが不正です", results[2].Message())
+	assert.Equal(t, "connection refused", results[3].Message())
+	assert.True(t, results[4].Skipped())
+	assert.False(t, results[4].Failed())
+	assert.Equal(t, 2, Failures(results))
+}
+
+func TestFilter(t *testing.T) {
+	filtered, err := Filter(testChecks(), "^fare$")
+	assert.NoError(t, err)
+	// 初期化は常に含まれる
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, initializeCheckName, filtered[0].Name)
+	assert.Equal(t, "fail", filtered[1].Name)
+
+	filtered, err = Filter(testChecks(), "")
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 5)
+
+	_, err = Filter(testChecks(), "(")
+	assert.Error(t, err)
+}
+
+func TestRegisteredChecks(t *testing.T) {
+	names := map[string]bool{}
+	for _, c := range Checks() {
+		assert.False(t, names[c.Name], "duplicated check: %s", c.Name)
+		names[c.Name] = true
+		assert.NotNil(t, c.Run, c.Name)
+	}
+	assert.Equal(t, initializeCheckName, Checks()[0].Name)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := Run(context.Background(), &Env{}, testChecks())
+	var buf bytes.Buffer
+	assert.NoError(t, WriteReport(&buf, FormatJUnit, results))
+
+	var got junitTestSuites
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, 5, got.Tests)
+	assert.Equal(t, 2, got.Failures)
+	assert.Equal(t, 1, got.Skipped)
+	assert.Len(t, got.Suites, 3)
+	assert.Equal(t, CategoryRoute, got.Suites[0].Name)
+	assert.Equal(t, 3, got.Suites[0].Tests)
+	assert.Equal(t, "POST /api/train/reserve: amountが不正です", got.Suites[1].Cases[0].Failure.Message)
+}
+
+func TestWriteTAP(t *testing.T) {
+	results := Run(context.Background(), &Env{}, testChecks())
+	var buf bytes.Buffer
+	assert.NoError(t, WriteReport(&buf, FormatTAP, results))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "TAP version 13", lines[0])
+	assert.Equal(t, "1..5", lines[1])
+	assert.Equal(t, "ok 1 - route/initialize", lines[2])
+	assert.Equal(t, "not ok 3 - fare/fail", lines[4])
+	assert.True(t, strings.Contains(buf.String(), "ok 5 - route/skip # SKIP 決済APIが指定されていません\n"))
+
+	assert.Error(t, WriteReport(&buf, "xml", results))
+}
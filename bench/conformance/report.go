@@ -0,0 +1,144 @@
+package conformance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// レポートの形式
+const (
+	FormatJUnit = "junit"
+	FormatTAP   = "tap"
+)
+
+// WriteReport はformatの形式で結果をwに書き出します
+func WriteReport(w io.Writer, format string, results []*Result) error {
+	switch format {
+	case FormatJUnit:
+		return WriteJUnit(w, results)
+	case FormatTAP:
+		return WriteTAP(w, results)
+	default:
+		return fmt.Errorf("不明なレポート形式です: %s", format)
+	}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+
+	seconds float64
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Detail  string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func seconds(f float64) string {
+	return fmt.Sprintf("%.3f", f)
+}
+
+// WriteJUnit は分類ごとのtestsuiteにまとめたJUnit XMLを書き出します
+func WriteJUnit(w io.Writer, results []*Result) error {
+	root := &junitTestSuites{Name: "isutrain-conformance"}
+	suites := map[string]*junitTestSuite{}
+	var total float64
+	for _, r := range results {
+		suite, ok := suites[r.Check.Category]
+		if !ok {
+			suite = &junitTestSuite{Name: r.Check.Category}
+			suites[r.Check.Category] = suite
+			root.Suites = append(root.Suites, suite)
+		}
+
+		tc := &junitTestCase{
+			Name:      r.Check.Name,
+			ClassName: "conformance." + r.Check.Category,
+			Time:      seconds(r.Duration.Seconds()),
+		}
+		switch {
+		case r.Failed():
+			tc.Failure = &junitFailure{Message: r.Message(), Detail: r.Err.Error()}
+			suite.Failures++
+			root.Failures++
+		case r.Skipped():
+			tc.Skipped = &junitSkipped{Message: r.SkipReason}
+			suite.Skipped++
+			root.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		suite.seconds += r.Duration.Seconds()
+		root.Tests++
+		total += r.Duration.Seconds()
+	}
+	for _, suite := range root.Suites {
+		suite.Time = seconds(suite.seconds)
+	}
+	root.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTAP はTAP version 13の形式で書き出します. 失敗したチェックにはYAMLで理由を付けます
+func WriteTAP(w io.Writer, results []*Result) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+	for i, r := range results {
+		name := r.Check.Category + "/" + r.Check.Name
+		switch {
+		case r.Failed():
+			fmt.Fprintf(&b, "not ok %d - %s\n", i+1, name)
+			b.WriteString("  ---\n")
+			fmt.Fprintf(&b, "  message: %q\n", r.Message())
+			fmt.Fprintf(&b, "  error: %q\n", r.Err.Error())
+			fmt.Fprintf(&b, "  duration_ms: %.3f\n", r.Duration.Seconds()*1000)
+			b.WriteString("  ...\n")
+		case r.Skipped():
+			fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", i+1, name, r.SkipReason)
+		default:
+			fmt.Fprintf(&b, "ok %d - %s\n", i+1, name)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
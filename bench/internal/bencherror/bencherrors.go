@@ -1,19 +1,71 @@
 package bencherror
 
 import (
+	"context"
 	"sync"
 
-	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"go.uber.org/zap"
 )
 
-var (
-	SystemErrs     = NewBenchErrors()
-	InitializeErrs = NewBenchErrors()
-	PreTestErrs    = NewBenchErrors()
-	BenchmarkErrs  = NewBenchErrors()
-	FinalCheckErrs = NewBenchErrors()
-)
+// Errors は、ベンチマーク1回分のフェーズごとのエラーです
+type Errors struct {
+	System     *BenchErrors
+	Initialize *BenchErrors
+	PreTest    *BenchErrors
+	Benchmark  *BenchErrors
+	FinalCheck *BenchErrors
+}
+
+func NewErrors() *Errors {
+	return &Errors{
+		System:     NewBenchErrors(),
+		Initialize: NewBenchErrors(),
+		PreTest:    NewBenchErrors(),
+		Benchmark:  NewBenchErrors(),
+		FinalCheck: NewBenchErrors(),
+	}
+}
+
+type errorsKey struct{}
+
+// NewContext はエラーの集計先errsを持つcontextを返します
+func NewContext(ctx context.Context, errs *Errors) context.Context {
+	return context.WithValue(ctx, errorsKey{}, errs)
+}
+
+// FromContext はcontextのエラーの集計先を返します
+// 集計先のないcontextで呼び出すと、ほかの実行とエラーが混ざらないようpanicします
+func FromContext(ctx context.Context) *Errors {
+	if errs, ok := ctx.Value(errorsKey{}).(*Errors); ok {
+		return errs
+	}
+	panic("contextにエラーの集計先がありません. benchrun.Run.Context で作ったcontextを渡してください")
+}
+
+// SystemErrs は、ベンチマーカー自体のエラーの集計先を返します
+func SystemErrs(ctx context.Context) *BenchErrors {
+	return FromContext(ctx).System
+}
+
+// InitializeErrs は、初期化処理のエラーの集計先を返します
+func InitializeErrs(ctx context.Context) *BenchErrors {
+	return FromContext(ctx).Initialize
+}
+
+// PreTestErrs は、pretestのエラーの集計先を返します
+func PreTestErrs(ctx context.Context) *BenchErrors {
+	return FromContext(ctx).PreTest
+}
+
+// BenchmarkErrs は、負荷走行中のエラーの集計先を返します
+func BenchmarkErrs(ctx context.Context) *BenchErrors {
+	return FromContext(ctx).Benchmark
+}
+
+// FinalCheckErrs は、最終チェックのエラーの集計先を返します
+func FinalCheckErrs(ctx context.Context) *BenchErrors {
+	return FromContext(ctx).FinalCheck
+}
 
 type BenchErrors struct {
 	mu sync.RWMutex
@@ -56,23 +108,44 @@ func (errs *BenchErrors) IsFailure() bool {
 	return false
 }
 
+// Penalty は、負荷走行中のエラーによるペナルティを計算し、その内訳をログに出します
 func (errs *BenchErrors) Penalty() int64 {
-	errs.mu.RLock()
-	defer errs.mu.RUnlock()
-
 	lgr := zap.S()
 
-	penalty := config.ApplicationPenaltyWeight * errs.applicationCnt
-	lgr.Infof("アプリのエラーによるペナルティ: %d", penalty)
-
-	trivialCnt := errs.timeoutCnt + errs.temporaryCnt
-	if trivialCnt > config.TrivialPenaltyThreshold {
+	p := errs.PenaltyBreakdown()
+	lgr.Infof("アプリのエラーによるペナルティ: %s", p.ApplicationFormula())
+	if p.TrivialPenalty > 0 {
 		lgr.Warn("タイムアウトや一時的なエラーが閾値を超えています")
-		penalty += config.TrivialPenaltyWeight * (1 + (trivialCnt-config.TrivialPenaltyThreshold)/config.TrivialPenaltyPerCount)
-		lgr.Infof("タイムアウトや一時的なエラーによるペナルティ: %d", penalty)
 	}
+	lgr.Infof("タイムアウトや一時的なエラーによるペナルティ: %s", p.TrivialFormula())
+
+	return p.Total
+}
 
-	return int64(penalty)
+// CurrentPenalty は、ログを出さずにその時点のペナルティを返します
+func (errs *BenchErrors) CurrentPenalty() int64 {
+	return errs.PenaltyBreakdown().Total
+}
+
+// Counts は、エラーの種類ごとの件数です
+type Counts struct {
+	Critical    uint64 `json:"critical"`
+	Application uint64 `json:"application"`
+	Timeout     uint64 `json:"timeout"`
+	Temporary   uint64 `json:"temporary"`
+}
+
+// Counts は、その時点までのエラーの種類ごとの件数を返します
+func (errs *BenchErrors) Counts() Counts {
+	errs.mu.RLock()
+	defer errs.mu.RUnlock()
+
+	return Counts{
+		Critical:    errs.criticalCnt,
+		Application: errs.applicationCnt,
+		Timeout:     errs.timeoutCnt,
+		Temporary:   errs.temporaryCnt,
+	}
 }
 
 func (errs *BenchErrors) AddError(err error) error {
@@ -0,0 +1,57 @@
+package bencherror
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MessageCount は、同じエラーメッセージの件数です
+type MessageCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// TopMessages は、件数の多い順にn種類までのエラーメッセージと、エラーメッセージの種類の数を返します
+// 件数が同じ場合は、先に発生した順に並べます
+func (errs *BenchErrors) TopMessages(n int) ([]MessageCount, int) {
+	errs.mu.RLock()
+	defer errs.mu.RUnlock()
+
+	return topMessages(errs.Msgs, n)
+}
+
+func topMessages(msgs []string, n int) ([]MessageCount, int) {
+	var (
+		counts = []MessageCount{}
+		index  = map[string]int{}
+	)
+	for _, msg := range msgs {
+		if i, ok := index[msg]; ok {
+			counts[i].Count++
+			continue
+		}
+		index[msg] = len(counts)
+		counts = append(counts, MessageCount{Message: msg, Count: 1})
+	}
+	distinct := len(counts)
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts, distinct
+}
+
+// FormatMessages は、エラーメッセージを件数付きで返します. 表示しきれなかった種類があれば、その数を最後に加えます
+func FormatMessages(counts []MessageCount, distinct int) []string {
+	lines := make([]string, 0, len(counts)+1)
+	for _, c := range counts {
+		lines = append(lines, fmt.Sprintf("%s (%d件)", c.Message, c.Count))
+	}
+	if rest := distinct - len(counts); rest > 0 {
+		lines = append(lines, fmt.Sprintf("ほか%d種類のエラー", rest))
+	}
+	return lines
+}
@@ -0,0 +1,68 @@
+package bencherror
+
+import (
+	"fmt"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+)
+
+// PenaltyBreakdown は、エラーの種類ごとのペナルティの内訳です
+type PenaltyBreakdown struct {
+	ApplicationCount   uint64 `json:"application_count"`
+	ApplicationWeight  uint64 `json:"application_weight"`
+	ApplicationPenalty uint64 `json:"application_penalty"` // ApplicationWeight × ApplicationCount
+
+	// タイムアウトと一時的なエラーは、合わせて閾値を超えた分だけペナルティを課す
+	TimeoutCount     uint64 `json:"timeout_count"`
+	TemporaryCount   uint64 `json:"temporary_count"`
+	TrivialThreshold uint64 `json:"trivial_threshold"`
+	TrivialWeight    uint64 `json:"trivial_weight"`
+	TrivialPerCount  uint64 `json:"trivial_per_count"`
+	TrivialPenalty   uint64 `json:"trivial_penalty"` // TrivialWeight × (1 + (件数 - TrivialThreshold) / TrivialPerCount). 閾値以下なら0
+
+	Total int64 `json:"total"`
+}
+
+// PenaltyBreakdown は、その時点のペナルティの内訳を返します
+func (errs *BenchErrors) PenaltyBreakdown() PenaltyBreakdown {
+	errs.mu.RLock()
+	defer errs.mu.RUnlock()
+
+	p := PenaltyBreakdown{
+		ApplicationCount:   errs.applicationCnt,
+		ApplicationWeight:  config.ApplicationPenaltyWeight,
+		ApplicationPenalty: config.ApplicationPenaltyWeight * errs.applicationCnt,
+		TimeoutCount:       errs.timeoutCnt,
+		TemporaryCount:     errs.temporaryCnt,
+		TrivialThreshold:   config.TrivialPenaltyThreshold,
+		TrivialWeight:      config.TrivialPenaltyWeight,
+		TrivialPerCount:    config.TrivialPenaltyPerCount,
+	}
+	if trivialCnt := p.TrivialCount(); trivialCnt > config.TrivialPenaltyThreshold {
+		p.TrivialPenalty = config.TrivialPenaltyWeight * (1 + (trivialCnt-config.TrivialPenaltyThreshold)/config.TrivialPenaltyPerCount)
+	}
+	p.Total = int64(p.ApplicationPenalty + p.TrivialPenalty)
+	return p
+}
+
+// TrivialCount は、タイムアウトと一時的なエラーの件数の合計です
+func (p PenaltyBreakdown) TrivialCount() uint64 {
+	return p.TimeoutCount + p.TemporaryCount
+}
+
+// ApplicationFormula は、アプリケーションエラーのペナルティの計算式を返します
+func (p PenaltyBreakdown) ApplicationFormula() string {
+	return fmt.Sprintf("%d × %d件 = %d", p.ApplicationWeight, p.ApplicationCount, p.ApplicationPenalty)
+}
+
+// TrivialFormula は、タイムアウトと一時的なエラーのペナルティの計算式を返します
+func (p PenaltyBreakdown) TrivialFormula() string {
+	trivialCnt := p.TrivialCount()
+	if p.TrivialPenalty == 0 {
+		return fmt.Sprintf("%d件 (タイムアウト %d + 一時的なエラー %d) は閾値 %d 以下のため 0",
+			trivialCnt, p.TimeoutCount, p.TemporaryCount, p.TrivialThreshold)
+	}
+	return fmt.Sprintf("%d件 (タイムアウト %d + 一時的なエラー %d) が閾値 %d を超えたため %d × (1 + (%d - %d) / %d) = %d",
+		trivialCnt, p.TimeoutCount, p.TemporaryCount, p.TrivialThreshold,
+		p.TrivialWeight, trivialCnt, p.TrivialThreshold, p.TrivialPerCount, p.TrivialPenalty)
+}
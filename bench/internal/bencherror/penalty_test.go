@@ -0,0 +1,46 @@
+package bencherror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPenaltyBreakdown(t *testing.T) {
+	errs := NewBenchErrors()
+	p := errs.PenaltyBreakdown()
+	assert.Zero(t, p.Total)
+	assert.Equal(t, "0件 (タイムアウト 0 + 一時的なエラー 0) は閾値 1 以下のため 0", p.TrivialFormula())
+
+	for i := 0; i < 3; i++ {
+		errs.AddError(NewSimpleApplicationError("予約できません"))
+	}
+	errs.AddError(NewTimeoutError(errors.New("timeout"), "GET /api/stations"))
+	errs.AddError(NewTimeoutError(errors.New("timeout"), "GET /api/stations"))
+	errs.AddError(NewTemporaryError(errors.New("temporary"), "GET /api/stations"))
+
+	p = errs.PenaltyBreakdown()
+	assert.Equal(t, uint64(15), p.ApplicationPenalty)
+	assert.Equal(t, "5 × 3件 = 15", p.ApplicationFormula())
+	assert.Equal(t, uint64(3), p.TrivialCount())
+	// 5 × (1 + (3 - 1) / 1)
+	assert.Equal(t, uint64(15), p.TrivialPenalty)
+	assert.Equal(t, "3件 (タイムアウト 2 + 一時的なエラー 1) が閾値 1 を超えたため 5 × (1 + (3 - 1) / 1) = 15", p.TrivialFormula())
+	assert.Equal(t, int64(30), p.Total)
+	assert.Equal(t, p.Total, errs.CurrentPenalty())
+	assert.Equal(t, p.Total, errs.Penalty())
+}
+
+func TestTopMessages(t *testing.T) {
+	msgs := []string{"a", "b", "b", "c", "c", "d"}
+	counts, distinct := topMessages(msgs, 2)
+	assert.Equal(t, 4, distinct)
+	// 件数が同じなら先に発生した順
+	assert.Equal(t, []MessageCount{{Message: "b", Count: 2}, {Message: "c", Count: 2}}, counts)
+	assert.Equal(t, []string{"b (2件)", "c (2件)", "ほか2種類のエラー"}, FormatMessages(counts, distinct))
+
+	counts, distinct = topMessages(nil, 2)
+	assert.Empty(t, counts)
+	assert.Empty(t, FormatMessages(counts, distinct))
+}
@@ -0,0 +1,58 @@
+package benchrun
+
+import (
+	"context"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+)
+
+// Run はベンチマーク1回分の状態です
+// ベンチマーク対象の設定、エラー、予約キャッシュ、スコアをまとめて持ち、contextでシナリオに持ち回ります
+// Runを複数作れば、1つのプロセスで複数のwebappを同時にベンチマークできます
+type Run struct {
+	Target           *config.Target
+	Errors           *bencherror.Errors
+	ReservationCache *isutrain.ReservationCache
+	Recorder         *endpoint.Recorder
+	// リクエストの記録先. 記録しない場合はnil
+	Traffic *traffic.Recorder
+}
+
+// New はtargetをベンチマークする実行を作ります
+func New(target *config.Target) *Run {
+	return &Run{
+		Target:           target,
+		Errors:           bencherror.NewErrors(),
+		ReservationCache: isutrain.NewReservationCache(),
+		Recorder:         endpoint.NewRecorder(),
+	}
+}
+
+type runKey struct{}
+
+// Context は、Runの状態を持つcontextを返します
+// シナリオやクライアントは、各パッケージのFromContextでこのRunの状態を使います
+func (r *Run) Context(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, runKey{}, r)
+	ctx = config.NewContext(ctx, r.Target)
+	ctx = bencherror.NewContext(ctx, r.Errors)
+	ctx = isutrain.NewReservationCacheContext(ctx, r.ReservationCache)
+	ctx = endpoint.NewContext(ctx, r.Recorder)
+	ctx = traffic.NewContext(ctx, r.Traffic)
+	return ctx
+}
+
+// FromContext はcontextのRunを返します
+func FromContext(ctx context.Context) (*Run, bool) {
+	r, ok := ctx.Value(runKey{}).(*Run)
+	return r, ok
+}
+
+// CurrentScore は、ログを出さずにその時点のペナルティを差し引いたスコアを返します
+func (r *Run) CurrentScore() int64 {
+	return r.Recorder.CurrentScore() - r.Errors.Benchmark.CurrentPenalty()
+}
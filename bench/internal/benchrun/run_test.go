@@ -0,0 +1,117 @@
+package benchrun
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunIsolation(t *testing.T) {
+	runA := New(config.NewTarget("http://a.example.com", ""))
+	runB := New(config.NewTarget("http://b.example.com", ""))
+	ctxA := runA.Context(context.Background())
+	ctxB := runB.Context(context.Background())
+
+	got, ok := FromContext(ctxA)
+	assert.True(t, ok)
+	assert.Equal(t, runA, got)
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+
+	assert.Equal(t, "http://a.example.com", config.FromContext(ctxA).TargetBaseURL)
+	assert.Equal(t, "http://b.example.com", config.FromContext(ctxB).TargetBaseURL)
+
+	// 一方の実行のエラーやスコアは、もう一方に影響しない
+	bencherror.BenchmarkErrs(ctxA).AddError(bencherror.NewSimpleApplicationError("テスト用のエラーです"))
+	endpoint.IncPathCounter(ctxB, endpoint.Login)
+	endpoint.IncPathCounter(ctxB, endpoint.Login)
+
+	assert.Len(t, runA.Errors.Benchmark.Msgs, 1)
+	assert.Empty(t, runB.Errors.Benchmark.Msgs)
+	assert.Zero(t, runA.Recorder.CalcFinalEndpointCount())
+	assert.Equal(t, int64(2), runB.Recorder.CalcFinalEndpointCount())
+	assert.Equal(t, int64(2*endpoint.GetWeight(endpoint.Login)), runB.CurrentScore())
+
+	assert.True(t, isutrain.ReservationCacheFromContext(ctxA) == runA.ReservationCache)
+	assert.True(t, isutrain.ReservationCacheFromContext(ctxB) != runA.ReservationCache)
+}
+
+func TestRunTrafficIsolation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	// 2つの実行を同時に記録しても、リクエストやセッションのIDは混ざらない
+	var bufA, bufB bytes.Buffer
+	runA := New(config.NewTarget(ts.URL, ""))
+	runA.Traffic = traffic.NewRecorder(&bufA, time.Now())
+	runB := New(config.NewTarget(ts.URL, ""))
+	runB.Traffic = traffic.NewRecorder(&bufB, time.Now())
+	for _, run := range []*Run{runA, runA, runB} {
+		ctx := run.Context(context.Background())
+		client, err := isutrain.NewClient(ctx)
+		assert.NoError(t, err)
+		_, err = client.ListStations(ctx)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, runA.Traffic.Stop())
+	assert.NoError(t, runB.Traffic.Stop())
+
+	entriesA, err := traffic.Load(&bufA)
+	assert.NoError(t, err)
+	entriesB, err := traffic.Load(&bufB)
+	assert.NoError(t, err)
+	if assert.Len(t, entriesA, 2) && assert.Len(t, entriesB, 1) {
+		assert.Equal(t, []int64{1, 2}, []int64{entriesA[0].Session, entriesA[1].Session})
+		assert.Equal(t, int64(1), entriesB[0].Session)
+	}
+	assert.Equal(t, int64(2), runA.Recorder.CalcFinalEndpointCount())
+	assert.Equal(t, int64(1), runB.Recorder.CalcFinalEndpointCount())
+}
+
+func TestFromContextWithoutRun(t *testing.T) {
+	// 実行のないcontextでは、共有の集計先に黙って書き込まずにpanicする
+	ctx := context.Background()
+	assert.Panics(t, func() { bencherror.FromContext(ctx) })
+	assert.Panics(t, func() { endpoint.FromContext(ctx) })
+	assert.Panics(t, func() { config.FromContext(ctx) })
+	assert.Panics(t, func() { isutrain.ReservationCacheFromContext(ctx) })
+	assert.Nil(t, traffic.FromContext(ctx))
+}
+
+func TestScoreReport(t *testing.T) {
+	run := New(config.NewTarget("http://a.example.com", ""))
+	ctx := run.Context(context.Background())
+
+	endpoint.IncPathCounter(ctx, endpoint.SearchTrains)
+	endpoint.AddExtraScore(ctx, endpoint.Reserve, endpoint.ReservedSeatBonus, 10)
+	bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleApplicationError("空席状況が不正です"))
+	bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleApplicationError("空席状況が不正です"))
+
+	s := run.ScoreReport(10)
+	assert.Equal(t, int64(3), s.BaseScore)
+	assert.Equal(t, int64(10), s.ExtraScore)
+	assert.Equal(t, int64(10), s.Penalty.Total)
+	assert.Equal(t, int64(3), s.Score)
+	assert.Equal(t, run.CurrentScore(), s.Score)
+	assert.Equal(t, []bencherror.MessageCount{{Message: "空席状況が不正です", Count: 2}}, s.TopErrors)
+
+	msgs := s.Messages()
+	assert.Contains(t, msgs, "空席状況が不正です (2件)")
+	assert.Contains(t, msgs, "スコア: 基本 3 + 加点 10 - ペナルティ 10 = 3")
+	assert.Contains(t, msgs, "基本スコア /api/train/search: 3 × 1回 = 3")
+	assert.Contains(t, msgs, "加点 unknown (指定席): 1回 10")
+	assert.Contains(t, msgs, "ペナルティ(アプリケーションエラー): 5 × 2件 = 10")
+}
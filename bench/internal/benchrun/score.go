@@ -0,0 +1,94 @@
+package benchrun
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+)
+
+// 参加者に見せる加点の種類の名前
+var extraScoreKindNames = map[endpoint.ExtraScoreKind]string{
+	endpoint.ReservedSeatBonus:  "指定席",
+	endpoint.NeighborSeatsBonus: "隣り合った座席",
+}
+
+// ScoreReport は、スコアの内訳です
+// Score = BaseScore + ExtraScore - Penalty.Total
+type ScoreReport struct {
+	Endpoints   []endpoint.EndpointScore `json:"endpoints"`
+	BaseScore   int64                    `json:"base_score"` // エンドポイントごとの 重み × 成功回数 の合計
+	ExtraScores []endpoint.ExtraScore    `json:"extra_scores"`
+	ExtraScore  int64                    `json:"extra_score"`
+
+	Penalty bencherror.PenaltyBreakdown `json:"penalty"`
+	Score   int64                       `json:"score"`
+
+	TopErrors      []bencherror.MessageCount `json:"top_errors"` // 負荷走行中のエラーメッセージ. 件数の多い順
+	DistinctErrors int                       `json:"distinct_errors"`
+}
+
+// ScoreReport は、負荷走行のスコアの内訳と、件数の多い順にtopN種類までのエラーメッセージを返します
+func (r *Run) ScoreReport(topN int) *ScoreReport {
+	s := &ScoreReport{
+		Endpoints:   r.Recorder.EndpointScores(),
+		ExtraScores: r.Recorder.ExtraScores(),
+		Penalty:     r.Errors.Benchmark.PenaltyBreakdown(),
+	}
+	for _, e := range s.Endpoints {
+		s.BaseScore += e.BaseScore
+		s.ExtraScore += e.ExtraScore
+	}
+	s.Score = s.BaseScore + s.ExtraScore - s.Penalty.Total
+	s.TopErrors, s.DistinctErrors = r.Errors.Benchmark.TopMessages(topN)
+	return s
+}
+
+// Messages は、参加者に見せるスコアの内訳とエラーメッセージを返します
+func (s *ScoreReport) Messages() []string {
+	msgs := bencherror.FormatMessages(s.TopErrors, s.DistinctErrors)
+	msgs = append(msgs, fmt.Sprintf("スコア: 基本 %d + 加点 %d - ペナルティ %d = %d",
+		s.BaseScore, s.ExtraScore, s.Penalty.Total, s.Score))
+	for _, e := range s.Endpoints {
+		if e.Count == 0 {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("基本スコア %s: %d × %d回 = %d", e.Path, e.Weight, e.Count, e.BaseScore))
+	}
+	for _, e := range s.ExtraScores {
+		msgs = append(msgs, fmt.Sprintf("加点 %s (%s): %d回 %d", e.Scenario, extraScoreKindName(e.Kind), e.Count, e.Score))
+	}
+	msgs = append(msgs,
+		fmt.Sprintf("ペナルティ(アプリケーションエラー): %s", s.Penalty.ApplicationFormula()),
+		fmt.Sprintf("ペナルティ(タイムアウト・一時的なエラー): %s", s.Penalty.TrivialFormula()),
+	)
+	return msgs
+}
+
+func extraScoreKindName(kind endpoint.ExtraScoreKind) string {
+	if name, ok := extraScoreKindNames[kind]; ok {
+		return name
+	}
+	return string(kind)
+}
+
+// WriteScoreReport はスコアの内訳を表形式で書き出します
+func WriteScoreReport(w io.Writer, s *ScoreReport) {
+	fmt.Fprintf(w, "%-40s %6s %8s %10s %10s\n", "endpoint", "weight", "count", "base", "extra")
+	for _, e := range s.Endpoints {
+		fmt.Fprintf(w, "%-40s %6d %8d %10d %10d\n", e.Path, e.Weight, e.Count, e.BaseScore, e.ExtraScore)
+	}
+	fmt.Fprintf(w, "%-40s %6s %8s %10d %10d\n", "total", "", "", s.BaseScore, s.ExtraScore)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%-40s %-16s %8s %10s\n", "scenario", "extra", "count", "score")
+	for _, e := range s.ExtraScores {
+		fmt.Fprintf(w, "%-40s %-16s %8d %10d\n", e.Scenario, e.Kind, e.Count, e.Score)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "penalty (application): %s\n", s.Penalty.ApplicationFormula())
+	fmt.Fprintf(w, "penalty (timeout/temporary): %s\n", s.Penalty.TrivialFormula())
+	fmt.Fprintf(w, "score: %d + %d - %d = %d\n", s.BaseScore, s.ExtraScore, s.Penalty.Total, s.Score)
+}
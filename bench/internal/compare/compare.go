@@ -0,0 +1,316 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+)
+
+// DefaultAlpha は、差があると判定する有意水準です
+const DefaultAlpha = 0.05
+
+// 差のあるエラーメッセージとして報告する最大数
+const maxMessageDeltas = 20
+
+// Result は、1つの宛先に対するベンチマーク1回分の結果です
+type Result struct {
+	Pass          bool              `json:"pass"`
+	Score         int64             `json:"score"` // 失格した場合は0
+	Errors        bencherror.Counts `json:"errors"`
+	ErrorMessages map[string]int    `json:"error_messages"` // エラーメッセージごとの件数
+	EndpointStats []endpoint.Stats  `json:"endpoint_stats,omitempty"`
+}
+
+// Iteration は、同じシードで負荷をかけたA/Bの結果の組です
+type Iteration struct {
+	Seed int64   `json:"seed"`
+	A    *Result `json:"a"`
+	B    *Result `json:"b"`
+}
+
+// Verdict はA/Bどちらのスコアが高いかの判定です
+type Verdict string
+
+const (
+	VerdictA            Verdict = "a"
+	VerdictB            Verdict = "b"
+	VerdictNoDifference Verdict = "no_difference" // 有意な差がない
+	VerdictInconclusive Verdict = "inconclusive"  // 反復回数が足りず検定できない
+)
+
+// EndpointDelta は、エンドポイントごとのA/Bの比較です
+// レイテンシは各回の値の平均、件数は全回の合計です
+type EndpointDelta struct {
+	Endpoint   string  `json:"endpoint"`
+	CountA     int64   `json:"count_a"`
+	CountB     int64   `json:"count_b"`
+	ErrorRateA float64 `json:"error_rate_a"`
+	ErrorRateB float64 `json:"error_rate_b"`
+	P50A       float64 `json:"p50_ms_a"`
+	P50B       float64 `json:"p50_ms_b"`
+	P99A       float64 `json:"p99_ms_a"`
+	P99B       float64 `json:"p99_ms_b"`
+}
+
+// MessageDelta は、エラーメッセージごとのA/Bの件数(全回の合計)です
+type MessageDelta struct {
+	Message string `json:"message"`
+	A       int    `json:"a"`
+	B       int    `json:"b"`
+}
+
+// Report はA/B比較の結果です
+type Report struct {
+	TargetA    string       `json:"target_a"`
+	TargetB    string       `json:"target_b"`
+	Iterations []*Iteration `json:"iterations"`
+
+	ScoreA  Summary    `json:"score_a"`
+	ScoreB  Summary    `json:"score_b"`
+	Test    *WelchTest `json:"test,omitempty"`
+	Alpha   float64    `json:"alpha"`
+	Verdict Verdict    `json:"verdict"`
+
+	PassA int `json:"pass_a"` // 失格しなかった回数
+	PassB int `json:"pass_b"`
+
+	Endpoints []EndpointDelta   `json:"endpoints"`
+	ErrorsA   bencherror.Counts `json:"errors_a"` // 全回の合計
+	ErrorsB   bencherror.Counts `json:"errors_b"`
+	Messages  []MessageDelta    `json:"messages"` // 件数の差が大きい順
+}
+
+// Analyze は、各回の結果からスコアの差を検定し、エンドポイントとエラーの差をまとめます
+// 失格した回はスコア0として扱います
+func Analyze(targetA, targetB string, iterations []*Iteration, alpha float64) *Report {
+	r := &Report{
+		TargetA:    targetA,
+		TargetB:    targetB,
+		Iterations: iterations,
+		Alpha:      alpha,
+	}
+
+	var scoresA, scoresB []float64
+	for _, it := range iterations {
+		scoresA = append(scoresA, float64(it.A.Score))
+		scoresB = append(scoresB, float64(it.B.Score))
+		if it.A.Pass {
+			r.PassA++
+		}
+		if it.B.Pass {
+			r.PassB++
+		}
+		addCounts(&r.ErrorsA, it.A.Errors)
+		addCounts(&r.ErrorsB, it.B.Errors)
+	}
+	r.ScoreA = Summarize(scoresA)
+	r.ScoreB = Summarize(scoresB)
+
+	r.Verdict = VerdictInconclusive
+	if test, ok := Welch(r.ScoreA, r.ScoreB); ok {
+		r.Test = &test
+		switch {
+		case test.P >= alpha:
+			r.Verdict = VerdictNoDifference
+		case r.ScoreB.Mean > r.ScoreA.Mean:
+			r.Verdict = VerdictB
+		default:
+			r.Verdict = VerdictA
+		}
+	}
+
+	r.Endpoints = endpointDeltas(iterations)
+	r.Messages = messageDeltas(iterations)
+	return r
+}
+
+func addCounts(dst *bencherror.Counts, src bencherror.Counts) {
+	dst.Critical += src.Critical
+	dst.Application += src.Application
+	dst.Timeout += src.Timeout
+	dst.Temporary += src.Temporary
+}
+
+type endpointAcc struct {
+	count, errors int64
+	p50, p99      float64
+	samples       int
+}
+
+func (acc *endpointAcc) add(st endpoint.Stats) {
+	acc.count += st.Count
+	acc.errors += st.Errors
+	acc.p50 += st.P50
+	acc.p99 += st.P99
+	acc.samples++
+}
+
+func (acc *endpointAcc) errorRate() float64 {
+	if acc.count == 0 {
+		return 0
+	}
+	return float64(acc.errors) / float64(acc.count)
+}
+
+func (acc *endpointAcc) mean(v float64) float64 {
+	if acc.samples == 0 {
+		return 0
+	}
+	return v / float64(acc.samples)
+}
+
+func endpointDeltas(iterations []*Iteration) []EndpointDelta {
+	accA := map[string]*endpointAcc{}
+	accB := map[string]*endpointAcc{}
+	add := func(accs map[string]*endpointAcc, stats []endpoint.Stats) {
+		for _, st := range stats {
+			acc, ok := accs[st.Endpoint]
+			if !ok {
+				acc = &endpointAcc{}
+				accs[st.Endpoint] = acc
+			}
+			acc.add(st)
+		}
+	}
+	for _, it := range iterations {
+		add(accA, it.A.EndpointStats)
+		add(accB, it.B.EndpointStats)
+	}
+
+	names := []string{}
+	for name := range accA {
+		names = append(names, name)
+	}
+	for name := range accB {
+		if _, ok := accA[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	deltas := make([]EndpointDelta, 0, len(names))
+	for _, name := range names {
+		a, b := accA[name], accB[name]
+		if a == nil {
+			a = &endpointAcc{}
+		}
+		if b == nil {
+			b = &endpointAcc{}
+		}
+		deltas = append(deltas, EndpointDelta{
+			Endpoint:   name,
+			CountA:     a.count,
+			CountB:     b.count,
+			ErrorRateA: a.errorRate(),
+			ErrorRateB: b.errorRate(),
+			P50A:       a.mean(a.p50),
+			P50B:       b.mean(b.p50),
+			P99A:       a.mean(a.p99),
+			P99B:       b.mean(b.p99),
+		})
+	}
+	return deltas
+}
+
+func messageDeltas(iterations []*Iteration) []MessageDelta {
+	byMsg := map[string]*MessageDelta{}
+	get := func(msg string) *MessageDelta {
+		d, ok := byMsg[msg]
+		if !ok {
+			d = &MessageDelta{Message: msg}
+			byMsg[msg] = d
+		}
+		return d
+	}
+	for _, it := range iterations {
+		for msg, n := range it.A.ErrorMessages {
+			get(msg).A += n
+		}
+		for msg, n := range it.B.ErrorMessages {
+			get(msg).B += n
+		}
+	}
+
+	deltas := []MessageDelta{}
+	for _, d := range byMsg {
+		if d.A != d.B {
+			deltas = append(deltas, *d)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := abs(deltas[i].B-deltas[i].A), abs(deltas[j].B-deltas[j].A)
+		if di != dj {
+			return di > dj
+		}
+		return deltas[i].Message < deltas[j].Message
+	})
+	if len(deltas) > maxMessageDeltas {
+		deltas = deltas[:maxMessageDeltas]
+	}
+	return deltas
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func percentChange(a, b float64) string {
+	if a == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", (b-a)/a*100)
+}
+
+// WriteReport は比較結果を表形式で書き出します
+func WriteReport(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "A: %s\nB: %s\n\n", r.TargetA, r.TargetB)
+
+	fmt.Fprintf(w, "%-6s %20s %10s %10s\n", "iter", "seed", "score A", "score B")
+	for i, it := range r.Iterations {
+		fmt.Fprintf(w, "%-6d %20d %10d %10d\n", i+1, it.Seed, it.A.Score, it.B.Score)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "score A: mean=%.1f stddev=%.1f min=%.0f max=%.0f pass=%d/%d\n",
+		r.ScoreA.Mean, r.ScoreA.StdDev, r.ScoreA.Min, r.ScoreA.Max, r.PassA, r.ScoreA.N)
+	fmt.Fprintf(w, "score B: mean=%.1f stddev=%.1f min=%.0f max=%.0f pass=%d/%d\n",
+		r.ScoreB.Mean, r.ScoreB.StdDev, r.ScoreB.Min, r.ScoreB.Max, r.PassB, r.ScoreB.N)
+	fmt.Fprintf(w, "B - A: %+.1f (%s)\n", r.ScoreB.Mean-r.ScoreA.Mean, percentChange(r.ScoreA.Mean, r.ScoreB.Mean))
+	if r.Test != nil {
+		fmt.Fprintf(w, "Welch's t-test: t=%.3f df=%.1f p=%.4f (alpha=%.2f)\n", r.Test.T, r.Test.DF, r.Test.P, r.Alpha)
+	}
+	switch r.Verdict {
+	case VerdictA:
+		fmt.Fprintln(w, "判定: Aのスコアが有意に高い")
+	case VerdictB:
+		fmt.Fprintln(w, "判定: Bのスコアが有意に高い")
+	case VerdictNoDifference:
+		fmt.Fprintln(w, "判定: 有意な差はない")
+	default:
+		fmt.Fprintln(w, "判定: 反復回数が足りないため検定できません(--iterations を2以上にしてください)")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%-45s %8s %8s %9s %9s %10s %10s %8s %10s %10s %8s\n",
+		"endpoint", "count A", "count B", "err A", "err B", "p50 A", "p50 B", "Δp50", "p99 A", "p99 B", "Δp99")
+	for _, d := range r.Endpoints {
+		fmt.Fprintf(w, "%-45s %8d %8d %8.1f%% %8.1f%% %10.1f %10.1f %8s %10.1f %10.1f %8s\n",
+			d.Endpoint, d.CountA, d.CountB, d.ErrorRateA*100, d.ErrorRateB*100,
+			d.P50A, d.P50B, percentChange(d.P50A, d.P50B), d.P99A, d.P99B, percentChange(d.P99A, d.P99B))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "errors A: critical=%d application=%d timeout=%d temporary=%d\n",
+		r.ErrorsA.Critical, r.ErrorsA.Application, r.ErrorsA.Timeout, r.ErrorsA.Temporary)
+	fmt.Fprintf(w, "errors B: critical=%d application=%d timeout=%d temporary=%d\n",
+		r.ErrorsB.Critical, r.ErrorsB.Application, r.ErrorsB.Timeout, r.ErrorsB.Temporary)
+	for _, d := range r.Messages {
+		fmt.Fprintf(w, "  A=%-5d B=%-5d %s\n", d.A, d.B, d.Message)
+	}
+}
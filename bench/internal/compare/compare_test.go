@@ -0,0 +1,94 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	assert.Equal(t, 8, s.N)
+	assert.Equal(t, 5.0, s.Mean)
+	assert.InDelta(t, 2.138, s.StdDev, 1e-3)
+	assert.Equal(t, 2.0, s.Min)
+	assert.Equal(t, 9.0, s.Max)
+
+	assert.Equal(t, Summary{N: 1, Mean: 3, Min: 3, Max: 3}, Summarize([]float64{3}))
+}
+
+func TestStudentTwoSidedP(t *testing.T) {
+	assert.InDelta(t, 1.0, studentTwoSidedP(0, 10), 1e-9)
+	assert.InDelta(t, 0.07339, studentTwoSidedP(2, 10), 1e-4)
+	assert.InDelta(t, 0.03000, studentTwoSidedP(3, 5), 1e-4)
+	assert.InDelta(t, 0.04550, studentTwoSidedP(-2, 1e6), 1e-4)
+}
+
+func TestWelch(t *testing.T) {
+	_, ok := Welch(Summarize([]float64{1}), Summarize([]float64{1, 2}))
+	assert.False(t, ok)
+
+	test, ok := Welch(Summarize([]float64{10, 10}), Summarize([]float64{10, 10}))
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, test.P)
+	test, ok = Welch(Summarize([]float64{10, 10}), Summarize([]float64{20, 20}))
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, test.P)
+
+	test, ok = Welch(
+		Summarize([]float64{27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4}),
+		Summarize([]float64{27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4}),
+	)
+	assert.True(t, ok)
+	assert.InDelta(t, 2.46, test.T, 1e-2)
+	assert.InDelta(t, 24.99, test.DF, 1e-2)
+	assert.InDelta(t, 0.021, test.P, 1e-3)
+}
+
+func newResult(score int64, p50 float64, msgs map[string]int) *Result {
+	return &Result{
+		Pass:          score > 0,
+		Score:         score,
+		Errors:        bencherror.Counts{Application: uint64(len(msgs))},
+		ErrorMessages: msgs,
+		EndpointStats: []endpoint.Stats{{Endpoint: "GET /api/train/search", Count: 100, Errors: 1, P50: p50, P99: p50 * 4}},
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	iterations := []*Iteration{
+		{Seed: 1, A: newResult(1000, 10, nil), B: newResult(1500, 5, map[string]int{"遅い": 2})},
+		{Seed: 2, A: newResult(1100, 12, nil), B: newResult(1600, 7, map[string]int{"遅い": 1})},
+		{Seed: 3, A: newResult(1050, 14, map[string]int{"不正": 1}), B: newResult(1550, 6, nil)},
+	}
+	r := Analyze("http://a", "http://b", iterations, DefaultAlpha)
+	assert.Equal(t, VerdictB, r.Verdict)
+	assert.Equal(t, 1050.0, r.ScoreA.Mean)
+	assert.Equal(t, 1550.0, r.ScoreB.Mean)
+	assert.Equal(t, 3, r.PassA)
+
+	if assert.Len(t, r.Endpoints, 1) {
+		d := r.Endpoints[0]
+		assert.Equal(t, int64(300), d.CountA)
+		assert.Equal(t, 12.0, d.P50A)
+		assert.Equal(t, 6.0, d.P50B)
+		assert.InDelta(t, 0.01, d.ErrorRateB, 1e-9)
+	}
+	assert.Equal(t, []MessageDelta{{Message: "遅い", A: 0, B: 3}, {Message: "不正", A: 1, B: 0}}, r.Messages)
+	assert.Equal(t, uint64(2), r.ErrorsB.Application)
+
+	// 1回だけでは検定できない
+	r = Analyze("http://a", "http://b", iterations[:1], DefaultAlpha)
+	assert.Equal(t, VerdictInconclusive, r.Verdict)
+	assert.Nil(t, r.Test)
+
+	_, err := json.Marshal(r)
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	WriteReport(&buf, r)
+	assert.Contains(t, buf.String(), "GET /api/train/search")
+}
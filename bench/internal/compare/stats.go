@@ -0,0 +1,141 @@
+package compare
+
+import (
+	"math"
+)
+
+// Summary はスコアなどの標本の要約です
+type Summary struct {
+	N      int     `json:"n"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"` // 不偏標準偏差. N < 2 なら0
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Summarize は標本xsの要約を返します
+func Summarize(xs []float64) Summary {
+	s := Summary{N: len(xs)}
+	if len(xs) == 0 {
+		return s
+	}
+	s.Min, s.Max = xs[0], xs[0]
+	var sum float64
+	for _, x := range xs {
+		sum += x
+		s.Min = math.Min(s.Min, x)
+		s.Max = math.Max(s.Max, x)
+	}
+	s.Mean = sum / float64(len(xs))
+	if len(xs) < 2 {
+		return s
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - s.Mean) * (x - s.Mean)
+	}
+	s.StdDev = math.Sqrt(ss / float64(len(xs)-1))
+	return s
+}
+
+// WelchTest は、分散が等しいとは限らない2群の平均の差のt検定(Welchのt検定)の結果です
+type WelchTest struct {
+	T  float64 `json:"t"`
+	DF float64 `json:"df"`
+	P  float64 `json:"p"` // 両側検定のp値
+}
+
+// Welch は、標本aとbの平均に差がないという帰無仮説をWelchのt検定で検定します
+// どちらかの標本が2つ未満なら検定できないので、falseを返します
+func Welch(a, b Summary) (WelchTest, bool) {
+	if a.N < 2 || b.N < 2 {
+		return WelchTest{}, false
+	}
+	va := a.StdDev * a.StdDev / float64(a.N)
+	vb := b.StdDev * b.StdDev / float64(b.N)
+	diff := b.Mean - a.Mean
+	if va+vb == 0 {
+		// どちらも毎回同じスコアなら、平均が違えば明らかに差がある
+		// t値は無限大になるが、JSONに書き出せないので0のままにする
+		if diff == 0 {
+			return WelchTest{P: 1}, true
+		}
+		return WelchTest{DF: float64(a.N + b.N - 2), P: 0}, true
+	}
+
+	t := diff / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/float64(a.N-1) + vb*vb/float64(b.N-1))
+	return WelchTest{T: t, DF: df, P: studentTwoSidedP(t, df)}, true
+}
+
+// studentTwoSidedP は、自由度dfのt分布で|T| >= |t|となる確率を返します
+func studentTwoSidedP(t, df float64) float64 {
+	return regIncBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// regIncBeta は正則化不完全ベータ関数 I_x(a, b) です
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// 連分数が速く収束する側で計算する
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction は不完全ベータ関数の連分数展開をLentz法で評価します
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		// 偶数項
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		// 奇数項
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
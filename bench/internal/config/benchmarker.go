@@ -19,4 +19,3 @@ const (
 
 var Debug bool
 var SlackWebhookURL string
-var Language = "unknown"
@@ -13,9 +13,6 @@ var (
 )
 
 var (
-	// initializeで設定される、予約日数
-	AvailableDays int
-
 	// 予約可能日数
 	maxAvailableDays = 366
 )
@@ -23,12 +20,10 @@ var (
 var (
 	// 予約受付開始日
 	ReservationStartDate time.Time = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
-	// 予約受付終了日
-	ReservationEndDate time.Time
 )
 
 // 予約日数設定
-func SetAvailReserveDays(days int) error {
+func (t *Target) SetAvailReserveDays(days int) error {
 	lgr := zap.S()
 
 	if days == 0 {
@@ -37,18 +32,19 @@ func SetAvailReserveDays(days int) error {
 	}
 
 	if days > maxAvailableDays {
-		lgr.Warnf("予約日数が予約可能日数を超過: 予約日数=%d, 予約可能日数=%d", AvailableDays, maxAvailableDays)
+		lgr.Warnf("予約日数が予約可能日数を超過: 予約日数=%d, 予約可能日数=%d", days, maxAvailableDays)
 		return ErrAvailableDaysTooLarge
 	}
 
-	AvailableDays = days
-	ReservationEndDate = ReservationStartDate.Add(time.Duration(days) * 24 * time.Hour)
+	t.AvailableDays = days
+	t.ReservationEndDate = ReservationStartDate.Add(time.Duration(days) * 24 * time.Hour)
 
 	lgr.Infow("予約日数を設定",
-		"指定された予約日数", AvailableDays,
-		"予約可能日数", ReservationEndDate,
+		"対象", t.TargetBaseURL,
+		"指定された予約日数", t.AvailableDays,
+		"予約可能日数", t.ReservationEndDate,
 		"予約受付開始日", ReservationStartDate,
-		"予約受付終了日", ReservationEndDate,
+		"予約受付終了日", t.ReservationEndDate,
 	)
 
 	// TODO: 日数に応じた負荷レベルを設定
@@ -61,9 +57,9 @@ var (
 	OlympicEndDate   = time.Date(2020, 8, 9, 0, 0, 0, 0, time.UTC)
 )
 
-func IsOlympic() bool {
-	t := ReservationStartDate.Add(time.Duration(AvailableDays*24) * time.Hour)
-	return !t.Before(OlympicStartDate)
+func (t *Target) IsOlympic() bool {
+	end := ReservationStartDate.Add(time.Duration(t.AvailableDays*24) * time.Hour)
+	return !end.Before(OlympicStartDate)
 }
 
 var (
@@ -71,12 +67,12 @@ var (
 	GoldenWeekEndDate   time.Time = time.Date(2020, 5, 6, 15, 0, 0, 0, time.Local)
 )
 
-func IsGoldenweekStarted() bool {
-	t := ReservationStartDate.Add(time.Duration(AvailableDays*24) * time.Hour)
-	return !t.Before(GoldenWeekStartDate)
+func (t *Target) IsGoldenweekStarted() bool {
+	end := ReservationStartDate.Add(time.Duration(t.AvailableDays*24) * time.Hour)
+	return !end.Before(GoldenWeekStartDate)
 }
 
-func IsGoldenweekEnded() bool {
-	t := ReservationStartDate.Add(time.Duration(AvailableDays*24) * time.Hour)
-	return !t.Before(GoldenWeekEndDate)
+func (t *Target) IsGoldenweekEnded() bool {
+	end := ReservationStartDate.Add(time.Duration(t.AvailableDays*24) * time.Hour)
+	return !end.Before(GoldenWeekEndDate)
 }
@@ -1,6 +1,54 @@
 package config
 
-var (
-	TargetBaseURL  = "http://localhost"
-	PaymentBaseURL = "http://localhost:5000"
+import (
+	"context"
+	"time"
 )
+
+const (
+	DefaultTargetBaseURL  = "http://localhost"
+	DefaultPaymentBaseURL = "http://localhost:5000"
+)
+
+// Target は、ベンチマーク対象のwebappごとの設定です
+// 1つのプロセスで複数のwebappをベンチマークできるよう、contextで持ち回ります
+type Target struct {
+	TargetBaseURL  string
+	PaymentBaseURL string
+
+	// 課金APIの管理キー(課金APIでAPIキーによる認証が有効な場合に指定する)
+	PaymentAPIKey string
+	// 課金APIを複数のwebappで共有している場合の、ベンチマーク対象のマーチャントID
+	PaymentMerchantID string
+
+	// initializeで設定される、予約日数
+	AvailableDays int
+	// 予約受付終了日
+	ReservationEndDate time.Time
+	// initializeで返される、webappの実装言語
+	Language string
+}
+
+func NewTarget(targetBaseURL, paymentBaseURL string) *Target {
+	return &Target{
+		TargetBaseURL:  targetBaseURL,
+		PaymentBaseURL: paymentBaseURL,
+		Language:       "unknown",
+	}
+}
+
+type targetKey struct{}
+
+// NewContext はベンチマーク対象tを持つcontextを返します
+func NewContext(ctx context.Context, t *Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, t)
+}
+
+// FromContext はcontextのベンチマーク対象を返します
+// 対象のないcontextで呼び出すと、意図しない宛先にリクエストを送らないようpanicします
+func FromContext(ctx context.Context) *Target {
+	if t, ok := ctx.Value(targetKey{}).(*Target); ok {
+		return t
+	}
+	panic("contextにベンチマーク対象がありません. benchrun.Run.Context で作ったcontextを渡してください")
+}
@@ -20,5 +20,14 @@ func (e *Endpoint) addExtraScore(extraScore int64) {
 }
 
 func (e *Endpoint) score() int64 {
-	return int64(e.weight)*e.count + e.extraScore
+	return int64(e.weight)*atomic.LoadInt64(&e.count) + atomic.LoadInt64(&e.extraScore)
+}
+
+// copyEndpoints は、回数と加点を0にしたエンドポイントの一覧を返します
+func copyEndpoints(endpoints []*Endpoint) []*Endpoint {
+	copied := make([]*Endpoint, len(endpoints))
+	for i, e := range endpoints {
+		copied[i] = &Endpoint{path: e.path, weight: e.weight}
+	}
+	return copied
 }
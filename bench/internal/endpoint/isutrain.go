@@ -1,7 +1,10 @@
 package endpoint
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 )
@@ -19,6 +22,7 @@ const (
 	SearchTrains
 	ListTrainSeats
 	ListReservations
+	Auth
 )
 
 var isutrainEndpoints = []*Endpoint{
@@ -33,6 +37,7 @@ var isutrainEndpoints = []*Endpoint{
 	&Endpoint{path: "/api/train/search", weight: 3},
 	&Endpoint{path: "/api/train/seats", weight: 3},
 	&Endpoint{path: "/api/user/reservations", weight: 1},
+	&Endpoint{path: "/api/auth", weight: 0},
 }
 
 const (
@@ -57,41 +62,62 @@ func GetDynamicPath(idx EndpointIdx, args ...interface{}) string {
 	return fmt.Sprintf(isutrainDynamicEndpoints[idx].path, args...)
 }
 
-func IncPathCounter(idx EndpointIdx) {
-	isutrainEndpoints[idx].inc()
+// GetDynamicPathPattern は予約IDなどを :id に置き換えたパスを返します
+func GetDynamicPathPattern(idx EndpointIdx) string {
+	return strings.Replace(isutrainDynamicEndpoints[idx].path, "%d", ":id", -1)
 }
 
-func AddExtraScore(idx EndpointIdx, extraScore int64) {
-	isutrainEndpoints[idx].addExtraScore(extraScore)
+func IncPathCounter(ctx context.Context, idx EndpointIdx) {
+	FromContext(ctx).endpoints[idx].inc()
 }
 
-func IncDynamicPathCounter(idx EndpointIdx) {
-	isutrainDynamicEndpoints[idx].inc()
+// AddExtraScore は、エンドポイントとcontextのシナリオにkindの加点をします
+func AddExtraScore(ctx context.Context, idx EndpointIdx, kind ExtraScoreKind, extraScore int64) {
+	r := FromContext(ctx)
+	r.endpoints[idx].addExtraScore(extraScore)
+	r.recordExtraScore(ctx, kind, extraScore)
 }
 
-func AddDynamicPathExtraScore(idx EndpointIdx, extraScore int64) {
-	isutrainDynamicEndpoints[idx].addExtraScore(extraScore)
+func IncDynamicPathCounter(ctx context.Context, idx EndpointIdx) {
+	FromContext(ctx).dynamicEndpoints[idx].inc()
 }
 
-func CalcFinalScore() (score int64) {
+func AddDynamicPathExtraScore(ctx context.Context, idx EndpointIdx, kind ExtraScoreKind, extraScore int64) {
+	r := FromContext(ctx)
+	r.dynamicEndpoints[idx].addExtraScore(extraScore)
+	r.recordExtraScore(ctx, kind, extraScore)
+}
+
+func (r *Recorder) CalcFinalScore() (score int64) {
 	lgr := zap.S()
-	for _, endpoint := range isutrainEndpoints {
+	for _, endpoint := range r.endpoints {
 		score += endpoint.score()
 		lgr.Infof("[%s] score = %d", endpoint.path, endpoint.score())
 	}
-	for _, endpoint := range isutrainDynamicEndpoints {
+	for _, endpoint := range r.dynamicEndpoints {
 		score += endpoint.score()
 		lgr.Infof("[%s] score = %d", endpoint.path, endpoint.score())
 	}
 	return
 }
 
-func CalcFinalEndpointCount() (count int64) {
-	for _, endpoint := range isutrainEndpoints {
-		count += endpoint.count
+// CurrentScore は、ログを出さずにその時点までのスコアを返します
+func (r *Recorder) CurrentScore() (score int64) {
+	for _, endpoint := range r.endpoints {
+		score += endpoint.score()
+	}
+	for _, endpoint := range r.dynamicEndpoints {
+		score += endpoint.score()
+	}
+	return
+}
+
+func (r *Recorder) CalcFinalEndpointCount() (count int64) {
+	for _, endpoint := range r.endpoints {
+		count += atomic.LoadInt64(&endpoint.count)
 	}
-	for _, endpoint := range isutrainDynamicEndpoints {
-		count += endpoint.count
+	for _, endpoint := range r.dynamicEndpoints {
+		count += atomic.LoadInt64(&endpoint.count)
 	}
 	return
 }
@@ -3,5 +3,7 @@ package endpoint
 const (
 	PaymentInitializePath = "/initialize"
 	PaymentResultPath     = "/result"
+	PaymentStreamPath     = "/result/stream"
 	PaymentRegistCardPath = "/card"
+	PaymentHealthzPath    = "/healthz"
 )
@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+)
+
+// ExtraScoreKind は加点の種類です
+type ExtraScoreKind string
+
+const (
+	// ReservedSeatBonus は指定席の予約成功に対する加点です
+	ReservedSeatBonus ExtraScoreKind = "reserved_seat"
+	// NeighborSeatsBonus は複数人の予約で隣り合った座席が取れた場合の加点です
+	NeighborSeatsBonus ExtraScoreKind = "neighbor_seats"
+)
+
+// contextにシナリオがない場合の加点に使うシナリオ名
+const unknownScenario = "unknown"
+
+type extraScoreKey struct {
+	scenario string
+	kind     ExtraScoreKind
+}
+
+// EndpointScore はエンドポイントごとのスコアの内訳です
+type EndpointScore struct {
+	Path       string `json:"path"`
+	Weight     int    `json:"weight"`
+	Count      int64  `json:"count"`
+	BaseScore  int64  `json:"base_score"` // Weight × Count
+	ExtraScore int64  `json:"extra_score"`
+}
+
+// ExtraScore はシナリオと加点の種類ごとの加点の合計です
+type ExtraScore struct {
+	Scenario string         `json:"scenario"`
+	Kind     ExtraScoreKind `json:"kind"`
+	Count    int64          `json:"count"` // 加点した回数
+	Score    int64          `json:"score"`
+}
+
+func (r *Recorder) recordExtraScore(ctx context.Context, kind ExtraScoreKind, extraScore int64) {
+	scenario := traffic.ScenarioNameOf(ctx)
+	if scenario == "" {
+		scenario = unknownScenario
+	}
+
+	r.extraMu.Lock()
+	defer r.extraMu.Unlock()
+
+	key := extraScoreKey{scenario: scenario, kind: kind}
+	s, ok := r.extraScores[key]
+	if !ok {
+		s = &ExtraScore{Scenario: scenario, Kind: kind}
+		r.extraScores[key] = s
+	}
+	s.Count++
+	s.Score += extraScore
+}
+
+func endpointScore(e *Endpoint) EndpointScore {
+	count := atomic.LoadInt64(&e.count)
+	return EndpointScore{
+		Path:       strings.Replace(e.path, "%d", ":id", -1),
+		Weight:     e.weight,
+		Count:      count,
+		BaseScore:  int64(e.weight) * count,
+		ExtraScore: atomic.LoadInt64(&e.extraScore),
+	}
+}
+
+// EndpointScores は、定義した順にエンドポイントごとのスコアの内訳を返します
+func (r *Recorder) EndpointScores() []EndpointScore {
+	scores := make([]EndpointScore, 0, len(r.endpoints)+len(r.dynamicEndpoints))
+	for _, e := range r.endpoints {
+		scores = append(scores, endpointScore(e))
+	}
+	for _, e := range r.dynamicEndpoints {
+		scores = append(scores, endpointScore(e))
+	}
+	return scores
+}
+
+// ExtraScores は、シナリオ名と加点の種類の順に加点の内訳を返します
+func (r *Recorder) ExtraScores() []ExtraScore {
+	r.extraMu.Lock()
+	defer r.extraMu.Unlock()
+
+	scores := make([]ExtraScore, 0, len(r.extraScores))
+	for _, s := range r.extraScores {
+		scores = append(scores, *s)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Scenario != scores[j].Scenario {
+			return scores[i].Scenario < scores[j].Scenario
+		}
+		return scores[i].Kind < scores[j].Kind
+	})
+	return scores
+}
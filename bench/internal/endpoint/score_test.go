@@ -0,0 +1,39 @@
+package endpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraScores(t *testing.T) {
+	r := NewRecorder()
+	ctx := NewContext(context.Background(), r)
+
+	IncPathCounter(ctx, Reserve)
+	IncPathCounter(ctx, Reserve)
+	IncDynamicPathCounter(ctx, ShowReservation)
+	AddExtraScore(traffic.WithScenario(ctx, "normal-1"), Reserve, ReservedSeatBonus, 10)
+	AddExtraScore(traffic.WithScenario(ctx, "normal-2"), Reserve, ReservedSeatBonus, 10)
+	AddExtraScore(traffic.WithScenario(ctx, "normal-2"), Reserve, NeighborSeatsBonus, 25)
+	AddExtraScore(ctx, Reserve, ReservedSeatBonus, 10)
+
+	assert.Equal(t, []ExtraScore{
+		{Scenario: "normal", Kind: NeighborSeatsBonus, Count: 1, Score: 25},
+		{Scenario: "normal", Kind: ReservedSeatBonus, Count: 2, Score: 20},
+		{Scenario: unknownScenario, Kind: ReservedSeatBonus, Count: 1, Score: 10},
+	}, r.ExtraScores())
+
+	scores := r.EndpointScores()
+	assert.Len(t, scores, len(isutrainEndpoints)+len(isutrainDynamicEndpoints))
+	assert.Equal(t, EndpointScore{Path: "/api/train/reserve", Weight: 5, Count: 2, BaseScore: 10, ExtraScore: 55}, scores[Reserve])
+	assert.Equal(t, EndpointScore{Path: "/api/user/reservations/:id", Weight: 1, Count: 1, BaseScore: 1}, scores[len(isutrainEndpoints)+int(ShowReservation)])
+
+	var total int64
+	for _, s := range scores {
+		total += s.BaseScore + s.ExtraScore
+	}
+	assert.Equal(t, r.CurrentScore(), total)
+}
@@ -0,0 +1,264 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ヒストグラムは2のべき乗ごとの区間をさらに2^subBucketBits個に分割する(HDR Histogramと同じ方式)
+// 相対誤差は最大で1/2^subBucketBits(約3%)
+const (
+	subBucketBits    = 5
+	subBucketCount   = 1 << subBucketBits
+	histogramBuckets = subBucketCount * (64 - subBucketBits)
+)
+
+// histogram はレイテンシ(マイクロ秒)の分布です
+type histogram struct {
+	counts [histogramBuckets]int64
+	total  int64
+	max    int64
+}
+
+func bucketIndex(v int64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+	shift := uint(bits.Len64(uint64(v)) - subBucketBits - 1)
+	return subBucketCount + int(shift)*subBucketCount + int(v>>shift) - subBucketCount
+}
+
+// 区間に含まれる最大の値
+func bucketUpperBound(idx int) int64 {
+	if idx < subBucketCount {
+		return int64(idx)
+	}
+	shift := uint(idx/subBucketCount - 1)
+	sub := int64(idx%subBucketCount + subBucketCount)
+	return (sub+1)<<shift - 1
+}
+
+func (h *histogram) record(v int64) {
+	if v < 0 {
+		v = 0
+	}
+	h.counts[bucketIndex(v)]++
+	h.total++
+	if v > h.max {
+		h.max = v
+	}
+}
+
+// pパーセンタイル(0 < p <= 100)の値. 区間の最大値を返すが、記録された最大値は超えない
+func (h *histogram) percentile(p float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+	rank := int64(float64(h.total)*p/100 + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var cum int64
+	for idx, c := range h.counts {
+		cum += c
+		if cum >= rank {
+			if v := bucketUpperBound(idx); v < h.max {
+				return v
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+type endpointStats struct {
+	hist        histogram
+	errors      int64
+	statusCodes map[int]int64
+	throughput  []int64 // 記録開始からの1秒ごとのリクエスト数
+}
+
+// Recorder は、ベンチマーク1回分のエンドポイントごとの成功回数と加点、レイテンシを記録します
+type Recorder struct {
+	endpoints        []*Endpoint
+	dynamicEndpoints []*Endpoint
+
+	statsMu        sync.Mutex
+	recording      bool
+	statsStartedAt time.Time
+	statsStoppedAt time.Time
+	statsByLabel   map[string]*endpointStats
+
+	extraMu     sync.Mutex
+	extraScores map[extraScoreKey]*ExtraScore
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		endpoints:        copyEndpoints(isutrainEndpoints),
+		dynamicEndpoints: copyEndpoints(isutrainDynamicEndpoints),
+		statsByLabel:     map[string]*endpointStats{},
+		extraScores:      map[extraScoreKey]*ExtraScore{},
+	}
+}
+
+type recorderKey struct{}
+
+// NewContext は記録先rを持つcontextを返します
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext はcontextの記録先を返します
+// 記録先のないcontextで呼び出すと、ほかの実行とスコアが混ざらないようpanicします
+func FromContext(ctx context.Context) *Recorder {
+	if r, ok := ctx.Value(recorderKey{}).(*Recorder); ok {
+		return r
+	}
+	panic("contextにスコアの記録先がありません. benchrun.Run.Context で作ったcontextを渡してください")
+}
+
+// StartRecording はエンドポイントごとのレイテンシの記録を開始します. それまでの記録は破棄されます
+func (r *Recorder) StartRecording(now time.Time) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	r.recording = true
+	r.statsStartedAt = now
+	r.statsStoppedAt = time.Time{}
+	r.statsByLabel = map[string]*endpointStats{}
+}
+
+// StopRecording はレイテンシの記録を終了します
+func (r *Recorder) StopRecording(now time.Time) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	r.recording = false
+	r.statsStoppedAt = now
+}
+
+// Observe はcontextの記録先に、リクエスト1回のレイテンシとステータスコードを記録します
+func Observe(ctx context.Context, label string, latency time.Duration, statusCode int, ok bool) {
+	FromContext(ctx).Observe(label, latency, statusCode, ok)
+}
+
+// Observe はリクエスト1回のレイテンシとステータスコードを記録します
+// statusCodeは通信に失敗した場合は0、okは期待したステータスコードが返った場合にtrueです
+func (r *Recorder) Observe(label string, latency time.Duration, statusCode int, ok bool) {
+	now := time.Now()
+
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	if !r.recording {
+		return
+	}
+	s, found := r.statsByLabel[label]
+	if !found {
+		s = &endpointStats{statusCodes: map[int]int64{}}
+		r.statsByLabel[label] = s
+	}
+	s.hist.record(int64(latency / time.Microsecond))
+	if !ok {
+		s.errors++
+	}
+	s.statusCodes[statusCode]++
+
+	sec := int(now.Sub(r.statsStartedAt) / time.Second)
+	if sec < 0 {
+		sec = 0
+	}
+	for len(s.throughput) <= sec {
+		s.throughput = append(s.throughput, 0)
+	}
+	s.throughput[sec]++
+}
+
+// Totals は記録中のすべてのエンドポイントへのリクエスト数と、期待したステータスコードが返った数を返します
+func (r *Recorder) Totals() (requests, successes int64) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	for _, s := range r.statsByLabel {
+		requests += s.hist.total
+		successes += s.hist.total - s.errors
+	}
+	return
+}
+
+// Stats はエンドポイントごとのレイテンシとエラー率です. レイテンシの単位はミリ秒です
+type Stats struct {
+	Endpoint    string        `json:"endpoint"`
+	Count       int64         `json:"count"`
+	Errors      int64         `json:"errors"`
+	ErrorRate   float64       `json:"error_rate"`
+	Throughput  float64       `json:"throughput"` // 平均のリクエスト数/秒
+	P50         float64       `json:"p50_ms"`
+	P90         float64       `json:"p90_ms"`
+	P99         float64       `json:"p99_ms"`
+	Max         float64       `json:"max_ms"`
+	StatusCodes map[int]int64 `json:"status_codes"`      // 0は通信の失敗
+	Timeline    []int64       `json:"throughput_series"` // 記録開始からの1秒ごとのリクエスト数
+}
+
+func usecToMsec(v int64) float64 {
+	return float64(v) / 1000
+}
+
+// Report はエンドポイント名の順に集計結果を返します
+func (r *Recorder) Report() []Stats {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	end := r.statsStoppedAt
+	if r.recording || end.IsZero() {
+		end = time.Now()
+	}
+	elapsed := end.Sub(r.statsStartedAt).Seconds()
+	seconds := int(end.Sub(r.statsStartedAt)/time.Second) + 1
+
+	labels := make([]string, 0, len(r.statsByLabel))
+	for label := range r.statsByLabel {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	report := make([]Stats, 0, len(labels))
+	for _, label := range labels {
+		s := r.statsByLabel[label]
+		st := Stats{
+			Endpoint:    label,
+			Count:       s.hist.total,
+			Errors:      s.errors,
+			P50:         usecToMsec(s.hist.percentile(50)),
+			P90:         usecToMsec(s.hist.percentile(90)),
+			P99:         usecToMsec(s.hist.percentile(99)),
+			Max:         usecToMsec(s.hist.max),
+			StatusCodes: map[int]int64{},
+			Timeline:    make([]int64, seconds),
+		}
+		if st.Count > 0 {
+			st.ErrorRate = float64(st.Errors) / float64(st.Count)
+		}
+		if elapsed > 0 {
+			st.Throughput = float64(st.Count) / elapsed
+		}
+		for code, n := range s.statusCodes {
+			st.StatusCodes[code] = n
+		}
+		copy(st.Timeline, s.throughput)
+		report = append(report, st)
+	}
+	return report
+}
+
+// WriteReport は集計結果を表形式で書き出します
+func WriteReport(w io.Writer, report []Stats) {
+	fmt.Fprintf(w, "%-45s %8s %8s %8s %10s %10s %10s %10s\n", "endpoint", "count", "errors", "req/s", "p50(ms)", "p90(ms)", "p99(ms)", "max(ms)")
+	for _, st := range report {
+		fmt.Fprintf(w, "%-45s %8d %7.1f%% %8.1f %10.1f %10.1f %10.1f %10.1f\n",
+			st.Endpoint, st.Count, st.ErrorRate*100, st.Throughput, st.P50, st.P90, st.P99, st.Max)
+	}
+}
@@ -0,0 +1,71 @@
+package endpoint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, v := range []int64{0, 1, 31, 32, 33, 63, 64, 65, 1000, 123456, 60 * 1000 * 1000} {
+		idx := bucketIndex(v)
+		upper := bucketUpperBound(idx)
+		assert.True(t, v <= upper, "v=%d upper=%d", v, upper)
+		// 区間の幅は値の1/32以下
+		assert.True(t, float64(upper-v) <= float64(v)/subBucketCount, "v=%d upper=%d", v, upper)
+		if idx > 0 {
+			assert.True(t, bucketUpperBound(idx-1) < v, "v=%d", v)
+		}
+	}
+}
+
+func TestHistogramPercentile(t *testing.T) {
+	h := &histogram{}
+	for i := int64(1); i <= 1000; i++ {
+		h.record(i * 1000)
+	}
+	for _, tc := range []struct {
+		p    float64
+		want int64
+	}{
+		{50, 500 * 1000},
+		{90, 900 * 1000},
+		{99, 990 * 1000},
+		{100, 1000 * 1000},
+	} {
+		assert.InEpsilon(t, tc.want, h.percentile(tc.p), 0.04, "p%v", tc.p)
+	}
+	assert.Equal(t, int64(1000*1000), h.max)
+	assert.Equal(t, int64(0), (&histogram{}).percentile(50))
+}
+
+func TestObserve(t *testing.T) {
+	r := NewRecorder()
+	r.Observe("GET /api/stations", time.Millisecond, 200, true)
+
+	r.StartRecording(time.Now())
+	r.Observe("GET /api/stations", 10*time.Millisecond, 200, true)
+	r.Observe("GET /api/stations", 20*time.Millisecond, 500, false)
+	r.Observe("POST /api/train/reserve", 30*time.Millisecond, 0, false)
+	r.StopRecording(time.Now())
+	r.Observe("GET /api/stations", time.Millisecond, 200, true)
+
+	report := r.Report()
+	assert.Len(t, report, 2)
+	st := report[0]
+	assert.Equal(t, "GET /api/stations", st.Endpoint)
+	assert.Equal(t, int64(2), st.Count)
+	assert.Equal(t, int64(1), st.Errors)
+	assert.Equal(t, 0.5, st.ErrorRate)
+	assert.Equal(t, map[int]int64{200: 1, 500: 1}, st.StatusCodes)
+	assert.InEpsilon(t, 20.0, st.Max, 0.01)
+	assert.Equal(t, int64(2), st.Timeline[0])
+	assert.Equal(t, map[int]int64{0: 1}, report[1].StatusCodes)
+
+	var buf bytes.Buffer
+	WriteReport(&buf, report)
+	assert.True(t, strings.Contains(buf.String(), "POST /api/train/reserve"))
+}
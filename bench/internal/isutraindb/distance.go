@@ -3,6 +3,7 @@ package isutraindb
 import (
 	"fmt"
 	"math"
+	"sort"
 )
 
 var distanceMap = map[string]float64{
@@ -240,3 +241,26 @@ func GetStopInfo(station string) (isStopExpress, isStopSemiExpress, isStopLocal
 
 	return stopInfo.IsStopExpress, stopInfo.IsStopSemiExpress, stopInfo.IsStopLocal, nil
 }
+
+// Station は駅の、東京からの距離と停車する列車種別です
+type Station struct {
+	Name     string
+	Distance float64
+	StopInfo
+}
+
+// Stations は全ての駅を東京からの距離の順に返します
+func Stations() []*Station {
+	stations := make([]*Station, 0, len(distanceMap))
+	for name, distance := range distanceMap {
+		stations = append(stations, &Station{
+			Name:     name,
+			Distance: distance,
+			StopInfo: *stopInfoMap[name],
+		})
+	}
+	sort.Slice(stations, func(i, j int) bool {
+		return stations[i].Distance < stations[j].Distance
+	})
+	return stations
+}
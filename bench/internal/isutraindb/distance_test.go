@@ -34,3 +34,13 @@ func TestGetDistance(t *testing.T) {
 		}
 	}
 }
+
+func TestStations(t *testing.T) {
+	stations := Stations()
+	assert.Len(t, stations, len(distanceMap))
+	assert.Equal(t, "東京", stations[0].Name)
+	assert.Equal(t, "大阪", stations[len(stations)-1].Name)
+	for i := 1; i < len(stations); i++ {
+		assert.True(t, stations[i-1].Distance < stations[i].Distance)
+	}
+}
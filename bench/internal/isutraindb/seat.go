@@ -1,6 +1,6 @@
 package isutraindb
 
-import "github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+import "go.uber.org/zap"
 
 // GetSeatClass は、列車クラスと車両番号から座席クラスを解決します
 func GetSeatClass(trainClass string, carNum int) string {
@@ -102,7 +102,8 @@ func GetSeatClass(trainClass string, carNum int) string {
 	case trainClass == "遅いやつ" && carNum == 16:
 		return "reserved"
 	default:
-		bencherror.SystemErrs.AddError(bencherror.NewSimpleCriticalError("不正なtrainClass=%s, carNum=%d が指定されました", trainClass, carNum))
+		// ベンチマーカーの不具合なので、どのベンチマークの実行かによらずログに残す
+		zap.S().Warnf("不正なtrainClass=%s, carNum=%d が指定されました", trainClass, carNum)
 		return ""
 	}
 }
@@ -0,0 +1,309 @@
+package traffic
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+)
+
+// DefaultDiffPaths は、同じ状態から送れば同じレスポンスになるはずのAPIです
+// APIでないパス(静的ファイル)は常に比較します
+var DefaultDiffPaths = []string{
+	"/api/settings",
+	"/api/stations",
+}
+
+// 差分として結果に含める最大数
+const maxDiffs = 100
+
+// Diff は記録と再送でレスポンスが異なったリクエストです
+type Diff struct {
+	Session  int64  `json:"session"`
+	Scenario string `json:"scenario,omitempty"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"` // status, body, error
+	Recorded string `json:"recorded"`
+	Replayed string `json:"replayed"`
+}
+
+// ReplayResult は再送の結果です
+type ReplayResult struct {
+	Replayed         int64   `json:"replayed"`
+	Errors           int64   `json:"errors"` // 通信に失敗した数(記録でも失敗していたものを除く)
+	StatusMismatches int64   `json:"status_mismatches"`
+	BodyMismatches   int64   `json:"body_mismatches"`
+	Elapsed          float64 `json:"elapsed_sec"`
+	Diffs            []Diff  `json:"diffs"`
+}
+
+// Replayer は記録したリクエストをtargetに送り直します
+type Replayer struct {
+	target    *url.URL
+	speed     float64
+	diffPaths []string
+
+	// 記録時の予約IDと、再送時の予約IDの対応
+	idsMu sync.Mutex
+	ids   map[int64]int64
+
+	resultMu sync.Mutex
+	result   *ReplayResult
+}
+
+// NewReplayer はReplayerを作ります
+// speedは記録時の何倍の速さで送るか(0以下なら待たずに送る)、diffPathsはレスポンスのボディを比較するAPIのパスです
+func NewReplayer(target string, speed float64, diffPaths []string) (*Replayer, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		target:    u,
+		speed:     speed,
+		diffPaths: diffPaths,
+		ids:       map[int64]int64{},
+	}, nil
+}
+
+func newReplayClient() *http.Client {
+	jar, _ := cookiejar.New(&cookiejar.Options{})
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+		Jar:     jar,
+		Timeout: config.APITimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Replay は記録したリクエストを、セッションごとに記録した順序で送り直します
+// /initialize は他のリクエストより先に送ります
+func (r *Replayer) Replay(ctx context.Context, entries []*Entry) *ReplayResult {
+	r.result = &ReplayResult{Diffs: []Diff{}}
+	startedAt := time.Now()
+
+	sessions := map[int64][]*Entry{}
+	initClient := newReplayClient()
+	initClient.Timeout = config.InitializeTimeout
+	for _, e := range entries {
+		if strings.HasPrefix(e.Path, "/initialize") {
+			r.send(ctx, initClient, e)
+			continue
+		}
+		sessions[e.Session] = append(sessions[e.Session], e)
+	}
+
+	// /initialize にかかった時間はずらす
+	replayStartedAt := time.Now()
+	var wg sync.WaitGroup
+	for _, es := range sessions {
+		sort.SliceStable(es, func(i, j int) bool { return es[i].Offset < es[j].Offset })
+		wg.Add(1)
+		go func(es []*Entry) {
+			defer wg.Done()
+			client := newReplayClient()
+			for _, e := range es {
+				if !r.wait(ctx, replayStartedAt, e) {
+					return
+				}
+				r.send(ctx, client, e)
+			}
+		}(es)
+	}
+	wg.Wait()
+
+	r.result.Elapsed = time.Since(startedAt).Seconds()
+	return r.result
+}
+
+// 記録時の送信時刻まで待つ
+func (r *Replayer) wait(ctx context.Context, startedAt time.Time, e *Entry) bool {
+	if r.speed <= 0 {
+		return ctx.Err() == nil
+	}
+	at := startedAt.Add(time.Duration(e.Offset / r.speed * float64(time.Millisecond)))
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+var (
+	reservationPathRe = regexp.MustCompile(`^/api/user/reservations/(\d+)`)
+	reservationBodyRe = regexp.MustCompile(`"reservation_id"\s*:\s*(\d+)`)
+)
+
+func (r *Replayer) mapID(id int64) int64 {
+	r.idsMu.Lock()
+	defer r.idsMu.Unlock()
+	if replayed, ok := r.ids[id]; ok {
+		return replayed
+	}
+	return id
+}
+
+// 記録時の予約IDを再送時の予約IDに置き換える
+func (r *Replayer) rewrite(s string, re *regexp.Regexp) string {
+	return re.ReplaceAllStringFunc(s, func(m string) string {
+		sub := re.FindStringSubmatchIndex(m)
+		id, err := strconv.ParseInt(m[sub[2]:sub[3]], 10, 64)
+		if err != nil {
+			return m
+		}
+		return m[:sub[2]] + strconv.FormatInt(r.mapID(id), 10) + m[sub[3]:]
+	})
+}
+
+// 予約APIのレスポンスから、記録時と再送時の予約IDの対応を覚える
+func (r *Replayer) learnID(recorded, replayed string) {
+	var rec, rep struct {
+		ReservationID int64 `json:"reservation_id"`
+	}
+	if json.Unmarshal([]byte(recorded), &rec) != nil || json.Unmarshal([]byte(replayed), &rep) != nil {
+		return
+	}
+	if rec.ReservationID == 0 || rep.ReservationID == 0 {
+		return
+	}
+	r.idsMu.Lock()
+	defer r.idsMu.Unlock()
+	r.ids[rec.ReservationID] = rep.ReservationID
+}
+
+func (r *Replayer) shouldDiffBody(path string) bool {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if !strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/initialize") {
+		return true
+	}
+	for _, p := range r.diffPaths {
+		if path == p || strings.HasPrefix(path, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Replayer) addDiff(e *Entry, kind, recorded, replayed string) {
+	r.resultMu.Lock()
+	defer r.resultMu.Unlock()
+	switch kind {
+	case "status":
+		r.result.StatusMismatches++
+	case "body":
+		r.result.BodyMismatches++
+	case "error":
+		r.result.Errors++
+	}
+	if len(r.result.Diffs) < maxDiffs {
+		r.result.Diffs = append(r.result.Diffs, Diff{
+			Session:  e.Session,
+			Scenario: e.Scenario,
+			Method:   e.Method,
+			Path:     e.Path,
+			Kind:     kind,
+			Recorded: recorded,
+			Replayed: replayed,
+		})
+	}
+}
+
+func (r *Replayer) send(ctx context.Context, client *http.Client, e *Entry) {
+	u := *r.target
+	ref, err := url.Parse(r.rewrite(e.Path, reservationPathRe))
+	if err != nil {
+		r.addDiff(e, "error", "", err.Error())
+		return
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + ref.Path
+	u.RawQuery = ref.RawQuery
+
+	req, err := http.NewRequest(e.Method, u.String(), strings.NewReader(r.rewrite(e.Body, reservationBodyRe)))
+	if err != nil {
+		r.addDiff(e, "error", "", err.Error())
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", config.UserAgent)
+	if e.ContentType != "" {
+		req.Header.Set("Content-Type", e.ContentType)
+	}
+
+	atomic.AddInt64(&r.result.Replayed, 1)
+	resp, err := client.Do(req)
+	if err != nil {
+		if e.Status != 0 {
+			r.addDiff(e, "error", strconv.Itoa(e.Status), err.Error())
+		}
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		r.addDiff(e, "error", strconv.Itoa(e.Status), err.Error())
+		return
+	}
+
+	replayed := &Entry{}
+	replayed.SetResponse(resp.Header.Get("Content-Type"), body)
+	if e.ResponseBody != "" && replayed.ResponseBody != "" {
+		r.learnID(e.ResponseBody, replayed.ResponseBody)
+	}
+
+	if resp.StatusCode != e.Status {
+		r.addDiff(e, "status", strconv.Itoa(e.Status), strconv.Itoa(resp.StatusCode))
+		return
+	}
+	if !r.shouldDiffBody(e.Path) {
+		return
+	}
+	if e.ResponseHash != "" || replayed.ResponseHash != "" {
+		if e.ResponseHash != replayed.ResponseHash {
+			r.addDiff(e, "body", e.ResponseHash, replayed.ResponseHash)
+		}
+		return
+	}
+	if !equalJSON(e.ResponseBody, replayed.ResponseBody) {
+		r.addDiff(e, "body", e.ResponseBody, replayed.ResponseBody)
+	}
+}
+
+// キーの順序や空白の違いを無視して比較する
+func equalJSON(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
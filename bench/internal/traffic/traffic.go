@@ -0,0 +1,187 @@
+package traffic
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Entry は記録したリクエスト1回分です
+type Entry struct {
+	Offset       float64 `json:"offset_ms"` // 記録開始からリクエストを送るまでの時間
+	Session      int64   `json:"session"`   // 同じセッション(cookie)で送ったリクエストは同じ値
+	Scenario     string  `json:"scenario,omitempty"`
+	Method       string  `json:"method"`
+	Path         string  `json:"path"` // クエリ文字列を含む
+	ContentType  string  `json:"content_type,omitempty"`
+	Body         string  `json:"body,omitempty"`
+	Status       int     `json:"status"` // 0は通信の失敗
+	Latency      float64 `json:"latency_ms"`
+	ResponseBody string  `json:"response_body,omitempty"`   // JSONのレスポンスのみ記録する
+	ResponseHash string  `json:"response_sha256,omitempty"` // JSON以外のレスポンスはハッシュのみ記録する
+	Error        string  `json:"error,omitempty"`
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// SetResponse はレスポンスのボディを、Content-Typeに応じてそのままかハッシュで記録します
+func (e *Entry) SetResponse(contentType string, body []byte) {
+	if isJSON(contentType) {
+		e.ResponseBody = string(body)
+		return
+	}
+	sum := sha256.Sum256(body)
+	e.ResponseHash = hex.EncodeToString(sum[:])
+}
+
+// Recorder はベンチマーク1回分のリクエストを記録します
+// 記録する実行のcontextに持たせるので、複数の実行を同時に記録しても混ざりません
+type Recorder struct {
+	mu        sync.Mutex
+	w         *bufio.Writer
+	enc       *json.Encoder
+	closer    io.Closer
+	startedAt time.Time
+	err       error
+
+	// リクエストを記録する際に、同じセッションのリクエストをまとめるためのIDの連番
+	sessionSeq int64
+}
+
+// NewRecorder はwへのリクエストの記録を開始します
+func NewRecorder(w io.Writer, now time.Time) *Recorder {
+	return newRecorder(w, nil, now)
+}
+
+func newRecorder(w io.Writer, closer io.Closer, now time.Time) *Recorder {
+	bw := bufio.NewWriter(w)
+	return &Recorder{
+		w:         bw,
+		enc:       json.NewEncoder(bw),
+		closer:    closer,
+		startedAt: now,
+	}
+}
+
+// NewFileRecorder はpathのファイルへのリクエストの記録を開始します
+func NewFileRecorder(path string, now time.Time) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, xerrors.Errorf("記録するファイルを作成できません: %w", err)
+	}
+	return newRecorder(f, f, now), nil
+}
+
+// Stop は記録を終了し、書き出しに失敗していればそのエラーを返します
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+// NextSession は、新しいセッションのIDを返します
+func (r *Recorder) NextSession() int64 {
+	return atomic.AddInt64(&r.sessionSeq, 1)
+}
+
+// Record はstartedAtに送ったリクエストを記録します
+func (r *Recorder) Record(startedAt time.Time, e *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.Offset = msec(startedAt.Sub(r.startedAt))
+	if err := r.enc.Encode(e); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+type recorderKey struct{}
+
+// NewContext は記録先rを持つcontextを返します
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext はcontextの記録先を返します. リクエストを記録しない実行ではnilを返します
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+func msec(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Load はJSON Linesで記録したリクエストを読み込みます
+func Load(r io.Reader) ([]*Entry, error) {
+	entries := []*Entry{}
+	dec := json.NewDecoder(r)
+	for {
+		e := &Entry{}
+		if err := dec.Decode(e); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, xerrors.Errorf("%d件目の記録を読み込めません: %w", len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// LoadFile はpathから記録したリクエストを読み込みます
+func LoadFile(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("記録したファイルを開けません: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+type scenarioKey struct{}
+
+// WithScenario はリクエストを送るシナリオのIDを持つcontextを返します
+func WithScenario(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, scenarioKey{}, id)
+}
+
+// ScenarioOf はcontextのシナリオのIDを返します
+func ScenarioOf(ctx context.Context) string {
+	id, _ := ctx.Value(scenarioKey{}).(string)
+	return id
+}
+
+// ScenarioNameOf はcontextのシナリオのIDから、開始した順番を除いたシナリオ名を返します
+func ScenarioNameOf(ctx context.Context) string {
+	id := ScenarioOf(ctx)
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return id
+	}
+	if _, err := strconv.Atoi(id[i+1:]); err != nil {
+		return id
+	}
+	return id[:i]
+}
@@ -0,0 +1,109 @@
+package traffic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	startedAt := time.Now()
+	r := NewRecorder(&buf, startedAt)
+	assert.True(t, FromContext(NewContext(context.Background(), r)) == r)
+	assert.Equal(t, int64(1), r.NextSession())
+	assert.Equal(t, int64(2), r.NextSession())
+	// 別の記録とはセッションのIDを共有しない
+	assert.Equal(t, int64(1), NewRecorder(&bytes.Buffer{}, startedAt).NextSession())
+
+	e := &Entry{Session: 1, Scenario: "normal-1", Method: http.MethodGet, Path: "/api/stations", Status: 200}
+	e.SetResponse("application/json; charset=utf-8", []byte(`[{"id":1}]`))
+	r.Record(startedAt.Add(1500*time.Microsecond), e)
+	e = &Entry{Session: 1, Method: http.MethodGet, Path: "/js/app.js", Status: 200}
+	e.SetResponse("application/javascript", []byte("console.log(1)"))
+	r.Record(startedAt.Add(time.Second), e)
+	assert.NoError(t, r.Stop())
+
+	entries, err := Load(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 1.5, entries[0].Offset)
+	assert.Equal(t, "normal-1", entries[0].Scenario)
+	assert.Equal(t, `[{"id":1}]`, entries[0].ResponseBody)
+	assert.Empty(t, entries[1].ResponseBody)
+	assert.Len(t, entries[1].ResponseHash, 64)
+
+	assert.Equal(t, "normal-1", ScenarioOf(WithScenario(context.Background(), "normal-1")))
+	assert.Equal(t, "", ScenarioOf(context.Background()))
+	assert.Equal(t, "normal_cancel", ScenarioNameOf(WithScenario(context.Background(), "normal_cancel-12")))
+	assert.Equal(t, "pretest", ScenarioNameOf(WithScenario(context.Background(), "pretest")))
+}
+
+func TestReplay(t *testing.T) {
+	var (
+		initialized  int32
+		reservations int64
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/initialize", func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt32(&initialized, 1)
+	})
+	mux.HandleFunc("/api/stations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id": 1, "name": "東京"}]`)
+	})
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+	})
+	mux.HandleFunc("/api/train/reserve", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil || atomic.LoadInt32(&initialized) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"reservation_id": %d}`, 100+atomic.AddInt64(&reservations, 1))
+	})
+	mux.HandleFunc("/api/user/reservations/101/cancel", func(w http.ResponseWriter, r *http.Request) {})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	entries := []*Entry{
+		{Offset: 0, Session: 1, Method: http.MethodPost, Path: "/initialize", Status: 200},
+		{Offset: 1, Session: 2, Method: http.MethodPost, Path: "/api/auth/login", Status: 200},
+		{Offset: 2, Session: 2, Method: http.MethodPost, Path: "/api/train/reserve", Status: 200, ResponseBody: `{"reservation_id": 1}`},
+		// 記録時の予約ID 1は、再送時の予約ID 101に置き換えて送る
+		{Offset: 3, Session: 2, Method: http.MethodPost, Path: "/api/user/reservations/1/cancel", Status: 200},
+		{Offset: 4, Session: 3, Method: http.MethodGet, Path: "/api/stations", Status: 200, ResponseBody: `[{"name":"東京","id":1}]`},
+		{Offset: 5, Session: 3, Method: http.MethodGet, Path: "/api/stations", Status: 200, ResponseBody: `[{"name":"大阪","id":1}]`},
+		{Offset: 6, Session: 3, Method: http.MethodGet, Path: "/api/train/reserve", Status: 200},
+	}
+
+	r, err := NewReplayer(ts.URL, 0, DefaultDiffPaths)
+	assert.NoError(t, err)
+	result := r.Replay(context.Background(), entries)
+	assert.Equal(t, int64(7), result.Replayed)
+	assert.Equal(t, int64(0), result.Errors)
+	assert.Equal(t, int64(1), result.BodyMismatches)
+	assert.Equal(t, int64(1), result.StatusMismatches)
+	if assert.Len(t, result.Diffs, 2) {
+		for _, d := range result.Diffs {
+			switch d.Kind {
+			case "body":
+				assert.Equal(t, "/api/stations", d.Path)
+			case "status":
+				// cookieのないセッションでは予約できない
+				assert.Equal(t, int64(3), d.Session)
+				assert.Equal(t, "401", d.Replayed)
+			}
+		}
+	}
+}
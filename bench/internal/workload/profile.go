@@ -0,0 +1,224 @@
+package workload
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"time"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ランプアップの曲線
+const (
+	CurveLinear      = "linear"      // 開始時の並列数から線形に増やす
+	CurveStep        = "step"        // Steps段の階段状に増やす
+	CurveExponential = "exponential" // 指数的に増やす
+)
+
+// Profile はベンチマークで流すシナリオの比率と並列数を定義するワークロードプロファイルです
+type Profile struct {
+	Name        string        `yaml:"name"`
+	Duration    time.Duration `yaml:"duration"`    // 負荷をかける時間(0の場合はベンチマークのデフォルト)
+	Concurrency int           `yaml:"concurrency"` // 同時に実行するシナリオ数の上限(0の場合は負荷レベル)
+	RampUp      RampUp        `yaml:"ramp_up"`
+	Arrival     *Arrival      `yaml:"arrival"` // 指定した場合はconcurrency, ramp_upの代わりに到着率でシナリオを開始する
+	Scenarios   []Scenario    `yaml:"scenarios"`
+}
+
+// Scenario はプロファイルに含めるシナリオです
+type Scenario struct {
+	Name   string `yaml:"name"`
+	Weight int    `yaml:"weight"` // 選ばれる比率(省略時は1, 0の場合は選ばれない)
+	Scale  int    `yaml:"scale"`  // シナリオ内の繰り返し回数や並列数(0の場合はシナリオのデフォルト)
+}
+
+// UnmarshalYAML は weight が省略された場合に1とし、明示的に指定された0と区別します
+func (s *Scenario) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Scenario
+	v := plain{Weight: 1}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	*s = Scenario(v)
+	return nil
+}
+
+// RampUp は並列数をConcurrencyまで増やしていく方法です
+type RampUp struct {
+	Duration time.Duration `yaml:"duration"` // Concurrencyに達するまでの時間(0の場合は最初からConcurrency)
+	Curve    string        `yaml:"curve"`    // linear, step, exponential(省略時はlinear)
+	Start    int           `yaml:"start"`    // 開始時の並列数(省略時は1)
+	Steps    int           `yaml:"steps"`    // stepの段数(省略時は4)
+}
+
+// Arrival は応答を待たずに一定の到着率でシナリオを開始する(オープンループ)設定です
+type Arrival struct {
+	Rate          float64       `yaml:"rate"`           // 1秒あたりに開始するシナリオ数
+	Steps         []RateStep    `yaml:"steps"`          // 到着率を段階的に変える
+	MaxInFlight   int           `yaml:"max_in_flight"`  // 実行中のシナリオ数の上限. 超えた分は開始せずdroppedとして数える(省略時は1000)
+	LateThreshold time.Duration `yaml:"late_threshold"` // 予定時刻からこれ以上遅れて開始したシナリオをlateとして数える(省略時は100ms)
+}
+
+// RateStep は開始からAfter経過した後の到着率です
+type RateStep struct {
+	After time.Duration `yaml:"after"`
+	Rate  float64       `yaml:"rate"`
+}
+
+// SetDefaults は省略された項目にデフォルト値を設定します
+func (a *Arrival) SetDefaults() {
+	if a.MaxInFlight == 0 {
+		a.MaxInFlight = 1000
+	}
+	if a.LateThreshold == 0 {
+		a.LateThreshold = 100 * time.Millisecond
+	}
+}
+
+// Validate は到着率の設定が正しいか検証します
+func (a *Arrival) Validate() error {
+	if a.Rate <= 0 {
+		return xerrors.New("arrival.rate は0より大きい必要があります")
+	}
+	var after time.Duration
+	for _, step := range a.Steps {
+		if step.Rate <= 0 {
+			return xerrors.New("arrival.steps の rate は0より大きい必要があります")
+		}
+		if step.After <= after {
+			return xerrors.New("arrival.steps の after は昇順である必要があります")
+		}
+		after = step.After
+	}
+	if a.MaxInFlight < 0 || a.LateThreshold < 0 {
+		return xerrors.New("arrival.max_in_flight, arrival.late_threshold は0以上である必要があります")
+	}
+	return nil
+}
+
+// RateAt は開始からelapsed経過した時点の到着率を返します
+func (a *Arrival) RateAt(elapsed time.Duration) float64 {
+	rate := a.Rate
+	for _, step := range a.Steps {
+		if elapsed < step.After {
+			break
+		}
+		rate = step.Rate
+	}
+	return rate
+}
+
+// Load はYAMLまたはJSONのプロファイルを読み込みます
+func Load(b []byte) (*Profile, error) {
+	p := &Profile{}
+	// JSONはYAMLとして読み込める
+	if err := yaml.UnmarshalStrict(b, p); err != nil {
+		return nil, xerrors.Errorf("プロファイルの読み込みに失敗しました: %w", err)
+	}
+	if p.RampUp.Curve == "" {
+		p.RampUp.Curve = CurveLinear
+	}
+	if p.RampUp.Start == 0 {
+		p.RampUp.Start = 1
+	}
+	if p.RampUp.Steps == 0 {
+		p.RampUp.Steps = 4
+	}
+	if p.Arrival != nil {
+		p.Arrival.SetDefaults()
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadFile はプロファイルをファイルから読み込みます
+func LoadFile(filename string) (*Profile, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(b)
+}
+
+// Validate はプロファイルが正しいか検証します
+func (p *Profile) Validate() error {
+	if len(p.Scenarios) == 0 {
+		return xerrors.New("シナリオが指定されていません")
+	}
+	total := 0
+	for _, s := range p.Scenarios {
+		if s.Name == "" {
+			return xerrors.New("シナリオ名が指定されていません")
+		}
+		if s.Weight < 0 || s.Scale < 0 {
+			return xerrors.Errorf("シナリオ %s の weight, scale は0以上である必要があります", s.Name)
+		}
+		total += s.Weight
+	}
+	if total == 0 {
+		return xerrors.New("weight が1以上のシナリオが少なくとも1つ必要です")
+	}
+	if p.Duration < 0 || p.Concurrency < 0 || p.RampUp.Duration < 0 || p.RampUp.Start < 0 || p.RampUp.Steps < 0 {
+		return xerrors.New("duration, concurrency, ramp_up は0以上である必要があります")
+	}
+	switch p.RampUp.Curve {
+	case CurveLinear, CurveStep, CurveExponential:
+	default:
+		return xerrors.Errorf("不明なランプアップ曲線です: %s", p.RampUp.Curve)
+	}
+	if p.Arrival != nil {
+		return p.Arrival.Validate()
+	}
+	return nil
+}
+
+// Pick は重みに従ってシナリオを1つ選びます. weight が0のシナリオは選ばれません
+func (p *Profile) Pick(r *rand.Rand) Scenario {
+	total := 0
+	for _, s := range p.Scenarios {
+		total += s.Weight
+	}
+	n := r.Intn(total)
+	for _, s := range p.Scenarios {
+		if n < s.Weight {
+			return s
+		}
+		n -= s.Weight
+	}
+	return p.Scenarios[len(p.Scenarios)-1]
+}
+
+// ConcurrencyAt は開始からelapsed経過した時点の並列数を返します
+func (p *Profile) ConcurrencyAt(elapsed time.Duration) int {
+	max := p.Concurrency
+	start := p.RampUp.Start
+	if start > max {
+		start = max
+	}
+	d := p.RampUp.Duration
+	if d <= 0 || elapsed >= d {
+		return max
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	progress := float64(elapsed) / float64(d)
+	var c float64
+	switch p.RampUp.Curve {
+	case CurveStep:
+		c = float64(start) + float64(max-start)*math.Floor(progress*float64(p.RampUp.Steps))/float64(p.RampUp.Steps)
+	case CurveExponential:
+		c = float64(start) * math.Pow(float64(max)/float64(start), progress)
+	default:
+		c = float64(start) + float64(max-start)*progress
+	}
+	if n := int(c); n > start {
+		return n
+	}
+	return start
+}
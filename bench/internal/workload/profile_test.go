@@ -0,0 +1,121 @@
+package workload
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProfile(t *testing.T) {
+	p, err := Load([]byte(`
+name: search-heavy
+duration: 30s
+concurrency: 8
+ramp_up:
+  duration: 10s
+scenarios:
+  - name: normal_vague_search
+    weight: 3
+  - name: normal_many_ambiguous_search
+    scale: 20
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "search-heavy", p.Name)
+	assert.Equal(t, 30*time.Second, p.Duration)
+	assert.Equal(t, CurveLinear, p.RampUp.Curve)
+	assert.Equal(t, 1, p.RampUp.Start)
+	assert.Equal(t, []Scenario{{"normal_vague_search", 3, 0}, {"normal_many_ambiguous_search", 1, 20}}, p.Scenarios)
+
+	// JSON
+	p, err = Load([]byte(`{"ramp_up": {"duration": "5s", "curve": "step"}, "scenarios": [{"name": "normal"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, p.RampUp.Duration)
+	assert.Equal(t, CurveStep, p.RampUp.Curve)
+
+	// 明示的なweight: 0はシナリオを無効にする
+	p, err = Load([]byte(`{"scenarios": [{"name": "normal", "weight": 0}, {"name": "normal_cancel"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Scenario{{"normal", 0, 0}, {"normal_cancel", 1, 0}}, p.Scenarios)
+
+	for _, b := range []string{
+		`scenarios: []`,
+		`{"scenarios": [{"weight": 1}]}`,
+		`{"scenarios": [{"name": "normal", "weight": -1}]}`,
+		`{"scenarios": [{"name": "normal", "weight": 0}]}`,
+		`{"ramp_up": {"curve": "sigmoid"}, "scenarios": [{"name": "normal"}]}`,
+		`{"unknown": 1, "scenarios": [{"name": "normal"}]}`,
+		`{"scenarios": [{"name": "normal", "wieght": 2}]}`,
+	} {
+		_, err := Load([]byte(b))
+		assert.Error(t, err, b)
+	}
+}
+
+func TestConcurrencyAt(t *testing.T) {
+	p := &Profile{Concurrency: 9, RampUp: RampUp{Duration: 10 * time.Second, Start: 1, Steps: 4}}
+	for _, tc := range []struct {
+		curve   string
+		elapsed time.Duration
+		want    int
+	}{
+		{CurveLinear, 0, 1},
+		{CurveLinear, 5 * time.Second, 5},
+		{CurveLinear, 10 * time.Second, 9},
+		{CurveStep, 2 * time.Second, 1},
+		{CurveStep, 5 * time.Second, 5},
+		{CurveStep, 8 * time.Second, 7},
+		{CurveExponential, 5 * time.Second, 3},
+		{CurveExponential, time.Minute, 9},
+	} {
+		p.RampUp.Curve = tc.curve
+		assert.Equal(t, tc.want, p.ConcurrencyAt(tc.elapsed), "%s %s", tc.curve, tc.elapsed)
+	}
+
+	p.RampUp.Duration = 0
+	assert.Equal(t, 9, p.ConcurrencyAt(0))
+}
+
+func TestPick(t *testing.T) {
+	p := &Profile{Scenarios: []Scenario{{Name: "a", Weight: 1}, {Name: "off", Weight: 0}, {Name: "b", Weight: 3}}}
+	r := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		counts[p.Pick(r).Name]++
+	}
+	assert.InDelta(t, 1000, counts["a"], 150)
+	assert.InDelta(t, 3000, counts["b"], 150)
+	assert.Zero(t, counts["off"])
+}
+
+func TestArrival(t *testing.T) {
+	p, err := Load([]byte(`
+arrival:
+  rate: 2
+  steps:
+    - after: 10s
+      rate: 5
+    - after: 20s
+      rate: 10
+scenarios:
+  - name: normal
+`))
+	assert.NoError(t, err)
+	a := p.Arrival
+	assert.Equal(t, 1000, a.MaxInFlight)
+	assert.Equal(t, 100*time.Millisecond, a.LateThreshold)
+	assert.Equal(t, 2.0, a.RateAt(0))
+	assert.Equal(t, 2.0, a.RateAt(9*time.Second))
+	assert.Equal(t, 5.0, a.RateAt(10*time.Second))
+	assert.Equal(t, 10.0, a.RateAt(time.Minute))
+
+	for _, b := range []string{
+		`{"arrival": {}, "scenarios": [{"name": "normal"}]}`,
+		`{"arrival": {"rate": 1, "steps": [{"after": "10s", "rate": 0}]}, "scenarios": [{"name": "normal"}]}`,
+		`{"arrival": {"rate": 1, "steps": [{"after": "10s", "rate": 2}, {"after": "5s", "rate": 3}]}, "scenarios": [{"name": "normal"}]}`,
+	} {
+		_, err := Load([]byte(b))
+		assert.Error(t, err, b)
+	}
+}
@@ -1,110 +1,175 @@
 package xrandom
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
+	"sync"
 	"time"
 
-	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/isutraindb"
-	"github.com/chibiegg/isucon9-final/bench/internal/util"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
 )
 
-func GetRandomNumberOfPeople() (adult, child int) {
-	adult = util.RandRangeIntn(1, 4)
-	child = util.RandRangeIntn(1, 4)
+// Rand はシナリオごとの乱数源です. 同じシードからは同じ順序で値を返します
+// シナリオ内のゴルーチンから同時に使えます
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func New(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+var defaultRand = New(1)
+
+// Seed は、シナリオ用の乱数源が設定されていないcontextで使う乱数源を初期化します
+func Seed(seed int64) {
+	defaultRand = New(seed)
+}
+
+type randKey struct{}
+
+// NewContext は乱数源rを持つcontextを返します
+func NewContext(ctx context.Context, r *Rand) context.Context {
+	return context.WithValue(ctx, randKey{}, r)
+}
+
+// FromContext はcontextの乱数源を返します. 設定されていなければ、Seedで初期化した乱数源を返します
+func FromContext(ctx context.Context) *Rand {
+	if r, ok := ctx.Value(randKey{}).(*Rand); ok {
+		return r
+	}
+	return defaultRand
+}
+
+func (r *Rand) Int63() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Int63()
+}
+
+func (r *Rand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Intn(n)
+}
+
+func (r *Rand) Perm(n int) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Perm(n)
+}
+
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Shuffle(n, swap)
+}
+
+// RangeIntn は[min, max)の乱数を返します
+func (r *Rand) RangeIntn(min, max int) int {
+	return r.Intn(max-min) + min
+}
+
+func (r *Rand) hexStr(b int) string {
+	k := make([]byte, b)
+	r.mu.Lock()
+	r.r.Read(k)
+	r.mu.Unlock()
+	return fmt.Sprintf("%x", k)
+}
+
+func (r *Rand) GetRandomNumberOfPeople() (adult, child int) {
+	adult = r.RangeIntn(1, 4)
+	child = r.RangeIntn(1, 4)
 	return
 }
 
-func GetRandomStations() string {
-	idx := rand.Intn(len(stations))
+func (r *Rand) GetRandomStations() string {
+	idx := r.Intn(len(stations))
 	return stations[idx]
 }
 
-func GetRandomTrainClass() string {
-	idx := rand.Intn(len(trainClasses))
+func (r *Rand) GetRandomTrainClass() string {
+	idx := r.Intn(len(trainClasses))
 	return trainClasses[idx]
 }
 
-func GetRandomUseAtByOlympicDate() time.Time {
+func (r *Rand) GetRandomUseAtByOlympicDate() time.Time {
 	var (
 		diffDuration = config.OlympicEndDate.Sub(config.OlympicStartDate)
 		diffDays     = diffDuration.Hours() / 24
 
-		randDays  = rand.Intn(int(diffDays))
+		randDays  = r.Intn(int(diffDays))
 		randUseAt = config.OlympicStartDate.AddDate(0, 0, randDays)
 	)
 	var (
-		hour   = util.RandRangeIntn(6, 15)
-		minute = util.RandRangeIntn(0, 59)
-		sec    = util.RandRangeIntn(0, 59)
+		hour   = r.RangeIntn(6, 15)
+		minute = r.RangeIntn(0, 59)
+		sec    = r.RangeIntn(0, 59)
 	)
 
 	return randUseAt.Add(time.Duration(hour*60*60+minute*60+sec) * time.Second)
 }
 
-func GetRandomUseAt() time.Time {
+// GetRandomUseAt は、予約受付開始日からavailableDays日の間の乗車日時を返します
+func (r *Rand) GetRandomUseAt(availableDays int) time.Time {
 	var (
-		hour   = util.RandRangeIntn(6, 15)
-		minute = util.RandRangeIntn(0, 59)
-		sec    = util.RandRangeIntn(0, 59)
+		hour   = r.RangeIntn(6, 15)
+		minute = r.RangeIntn(0, 59)
+		sec    = r.RangeIntn(0, 59)
 	)
 	startTime := config.ReservationStartDate.Add(time.Duration(hour*60*60+minute*60+sec) * time.Second)
-	days := rand.Intn(config.AvailableDays - 1)
+	days := r.Intn(availableDays - 1)
 
 	useAt := startTime.AddDate(0, 0, days)
 	return useAt
 }
 
-func GetRandomSectionWithTokyo() (station1 string, station2 string) {
-	localStations := stations
+func (r *Rand) GetRandomSectionWithTokyo() (station1 string, station2 string) {
 	station1 = "東京"
 
-	for i := 0; i < len(localStations); i++ {
-		if localStations[i] == station1 {
-			localStations = append(localStations[:i], localStations[i+1:]...)
+	// stationsを書き換えないよう、コピーしてから取り除く
+	localStations := make([]string, 0, len(stations))
+	for _, station := range stations {
+		if station != station1 {
+			localStations = append(localStations, station)
 		}
 	}
 
-	randIndexes := rand.Perm(len(localStations))
+	randIndexes := r.Perm(len(localStations))
 	return station1, localStations[randIndexes[0]]
 }
 
-func GetRandomSection() (station1 string, station2 string) {
+func (r *Rand) GetRandomSection() (station1 string, station2 string) {
 	localStations := stations
-	randIndexes := rand.Perm(len(localStations))
+	randIndexes := r.Perm(len(localStations))
 
 	return localStations[randIndexes[0]], localStations[randIndexes[1]]
 }
 
-func GetTokaiRandomSection() (string, string) {
-	stations1 := tokaiStations
-	rand.Shuffle(len(stations1), func(i, j int) { stations1[i], stations1[j] = stations1[j], stations1[i] })
+func (r *Rand) GetTokaiRandomSection() (string, string) {
+	stations1 := append([]string{}, tokaiStations...)
+	r.Shuffle(len(stations1), func(i, j int) { stations1[i], stations1[j] = stations1[j], stations1[i] })
 	stations2 := stations1[1:]
-	rand.Shuffle(len(stations2), func(i, j int) { stations2[i], stations2[j] = stations2[j], stations2[i] })
+	r.Shuffle(len(stations2), func(i, j int) { stations2[i], stations2[j] = stations2[j], stations2[i] })
 
 	return stations1[0], stations2[0]
 }
 
-func GetRandomUser() (*isutrain.User, error) {
-	emailRandomStr, err := util.SecureRandomStr(20)
-	if err != nil {
-		return nil, bencherror.NewCriticalError(err, "ユーザを作成できません. 運営に確認をお願いいたします")
-	}
-	passwdRandomStr, err := util.SecureRandomStr(20)
-	if err != nil {
-		return nil, bencherror.NewCriticalError(err, "ユーザを作成できません. 運営に確認をお願いいたします")
-	}
+// GetRandomUser は、乱数源から決まるメールアドレスとパスワードのユーザを返します
+func (r *Rand) GetRandomUser() (*isutrain.User, error) {
 	return &isutrain.User{
-		Email:    fmt.Sprintf("%s@example.com", emailRandomStr),
-		Password: passwdRandomStr,
+		Email:    fmt.Sprintf("%s@example.com", r.hexStr(20)),
+		Password: r.hexStr(20),
 	}, nil
 }
 
-func GetRandomCarNumber(trainClass, seatClass string) int {
+func (r *Rand) GetRandomCarNumber(trainClass, seatClass string) int {
 	l := []int{}
 
 	for carNum := 1; carNum <= 16; carNum++ {
@@ -114,6 +179,6 @@ func GetRandomCarNumber(trainClass, seatClass string) int {
 	}
 
 	log.Println(len(l))
-	idx := rand.Intn(len(l))
+	idx := r.Intn(len(l))
 	return l[idx]
 }
@@ -1,46 +1,75 @@
 package xrandom
 
 import (
+	"context"
 	"log"
-	"math/rand"
 	"testing"
-	"time"
 
-	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetRandomNumberOfPeople(t *testing.T) {
+	r := New(1)
 	for i := 0; i < 10; i++ {
-		adult, child := GetRandomNumberOfPeople()
+		adult, child := r.GetRandomNumberOfPeople()
 		log.Printf("adult=%d, child=%d", adult, child)
 	}
 }
 
 func TestRandomUseAt(t *testing.T) {
-	rand.Seed(time.Now().UnixNano())
-	assert.NoError(t, config.SetAvailReserveDays(30))
+	r := New(1)
 	for i := 0; i < 10; i++ {
-		log.Println(GetRandomUseAt().String())
+		log.Println(r.GetRandomUseAt(30).String())
 	}
 }
 
 func TestRandomUseAtByOlympicDate(t *testing.T) {
+	r := New(1)
 	for i := 0; i < 10; i++ {
-		log.Println(GetRandomUseAtByOlympicDate().String())
+		log.Println(r.GetRandomUseAtByOlympicDate().String())
 	}
 }
 
 func TestRandomSection(t *testing.T) {
+	r := New(1)
 	for i := 0; i < 10; i++ {
-		s1, s2 := GetRandomSection()
+		s1, s2 := r.GetRandomSection()
 		log.Printf("[*] s1=%s, s2=%s\n", s1, s2)
 	}
 }
 
 func TestRandomSectionWithTokyo(t *testing.T) {
+	r := New(1)
 	for i := 0; i < 10; i++ {
-		s1, s2 := GetRandomSectionWithTokyo()
+		s1, s2 := r.GetRandomSectionWithTokyo()
 		log.Printf("[*] s1=%s, s2=%s\n", s1, s2)
 	}
 }
+
+func TestSeed(t *testing.T) {
+	draw := func(r *Rand) []interface{} {
+		user, err := r.GetRandomUser()
+		assert.NoError(t, err)
+		adult, child := r.GetRandomNumberOfPeople()
+		s1, s2 := r.GetRandomSection()
+		t1, t2 := r.GetTokaiRandomSection()
+		return []interface{}{*user, adult, child, r.GetRandomUseAt(30), s1, s2, t1, t2}
+	}
+	// 同じシードからは同じ値の列になる
+	assert.Equal(t, draw(New(42)), draw(New(42)))
+	assert.NotEqual(t, draw(New(42)), draw(New(43)))
+
+	r := New(1)
+	ctx := NewContext(context.Background(), r)
+	assert.True(t, FromContext(ctx) == r)
+	assert.True(t, FromContext(context.Background()) == defaultRand)
+
+	// stationsは書き換えない
+	before := append([]string{}, stations...)
+	for i := 0; i < 10; i++ {
+		s1, s2 := r.GetRandomSectionWithTokyo()
+		assert.Equal(t, "東京", s1)
+		assert.NotEqual(t, "東京", s2)
+	}
+	assert.Equal(t, before, stations)
+}
@@ -37,6 +37,21 @@ func assertSearchTrains(ctx context.Context, endpointPath string, resp SearchTra
 		if ok := IsValidStation(train.Arrival); !ok {
 			return bencherror.NewSimpleCriticalError("GET %s: 不正な駅です: %s", endpointPath, train.Arrival)
 		}
+
+		// 空席状況は座席クラスごとに ○, △, × のいずれかで返す
+		for _, sa := range SeatAvailabilities {
+			symbol, ok := train.SeatAvailability[sa.String()]
+			if !ok {
+				return bencherror.NewSimpleCriticalError("GET %s: 列車 %s の空席状況に %s が含まれていません", endpointPath, train.Name, sa)
+			}
+			if ok := IsValidSeatAvailabilitySymbol(symbol); !ok {
+				return bencherror.NewSimpleCriticalError("GET %s: 列車 %s の %s の空席状況が不正です: %s", endpointPath, train.Name, sa, symbol)
+			}
+		}
+		// 自由席は常に空いている
+		if symbol := train.SeatAvailability[SaNonReserved.String()]; symbol != "○" {
+			return bencherror.NewSimpleCriticalError("GET %s: 列車 %s の自由席の空席状況が不正です: %s", endpointPath, train.Name, symbol)
+		}
 	}
 
 	return nil
@@ -78,15 +93,15 @@ func assertReserve(ctx context.Context, endpointPath string, client *Client, req
 		return bencherror.NewSimpleCriticalError("POST %s: レスポンスが不正です: %+v", endpointPath, resp)
 	}
 
-	cache, ok := ReservationCache.Reservation(resp.ReservationID)
+	cache, ok := ReservationCacheFromContext(ctx).Reservation(resp.ReservationID)
 	if !ok {
-		bencherror.SystemErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: 予約キャッシュの取得に失敗: ReservationID=%d", endpointPath, resp.ReservationID))
+		bencherror.SystemErrs(ctx).AddError(bencherror.NewSimpleCriticalError("POST %s: 予約キャッシュの取得に失敗: ReservationID=%d", endpointPath, resp.ReservationID))
 		return nil
 	}
 
 	amount, err := cache.Amount()
 	if err != nil {
-		bencherror.SystemErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: 予約のamount取得に失敗: resp.IsOK=%v, resp.ReservationID=%d, resp.Amount=%d", endpointPath, resp.IsOk, resp.ReservationID, resp.Amount))
+		bencherror.SystemErrs(ctx).AddError(bencherror.NewSimpleCriticalError("POST %s: 予約のamount取得に失敗: resp.IsOK=%v, resp.ReservationID=%d, resp.Amount=%d", endpointPath, resp.IsOk, resp.ReservationID, resp.Amount))
 		return nil
 	}
 
@@ -144,9 +159,9 @@ func assertReserve(ctx context.Context, endpointPath string, client *Client, req
 func assertCanReserve(ctx context.Context, endpointPath string, req *ReserveRequest, resp *ReserveResponse) error {
 	lgr := zap.S()
 
-	canReserve, err := ReservationCache.CanReserve(req)
+	canReserve, err := ReservationCacheFromContext(ctx).CanReserve(req)
 	if err != nil {
-		bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "POST %s: 予約可能判定でエラーが発生しました", endpointPath))
+		bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err, "POST %s: 予約可能判定でエラーが発生しました", endpointPath))
 		return nil
 	}
 
@@ -18,7 +18,9 @@ func addBonusNeighborSeats(ctx context.Context, client *Client, reservationID in
 		seats = reservation.Seats
 		score = seats.GetNeighborSeatsBonus()
 	)
-	endpoint.AddExtraScore(endpoint.Reserve, int64(score))
+	if score > 0 {
+		endpoint.AddExtraScore(ctx, endpoint.Reserve, endpoint.NeighborSeatsBonus, int64(score))
+	}
 
 	return nil
 }
@@ -25,15 +25,26 @@ type Client struct {
 	loginUser *User
 }
 
-func NewClient() (*Client, error) {
-	sess, err := NewSession()
+// NewClient は、contextのベンチマーク対象のwebappにリクエストを送るクライアントを作成します
+func NewClient(ctx context.Context) (*Client, error) {
+	client, err := NewClientWithBaseURL(ctx, config.FromContext(ctx).TargetBaseURL)
 	if err != nil {
-		return nil, bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします"))
+		return nil, bencherror.SystemErrs(ctx).AddError(err)
+	}
+	return client, nil
+}
+
+// NewClientWithBaseURL は、baseURLのwebappにリクエストを送るクライアントを作成します
+// contextの実行でリクエストを記録している場合は、このクライアントのリクエストも記録します
+func NewClientWithBaseURL(ctx context.Context, baseURL string) (*Client, error) {
+	sess, err := NewSession(ctx)
+	if err != nil {
+		return nil, bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします")
 	}
 
-	u, err := url.Parse(config.TargetBaseURL)
+	u, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします"))
+		return nil, bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします")
 	}
 
 	return &Client{
@@ -42,15 +53,25 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
-func NewClientForInitialize() (*Client, error) {
-	sess, err := newSessionForInitialize()
+// NewClientForInitialize は、contextのベンチマーク対象のwebappを初期化するクライアントを作成します
+func NewClientForInitialize(ctx context.Context) (*Client, error) {
+	client, err := NewClientForInitializeWithBaseURL(ctx, config.FromContext(ctx).TargetBaseURL)
+	if err != nil {
+		return nil, bencherror.SystemErrs(ctx).AddError(err)
+	}
+	return client, nil
+}
+
+// NewClientForInitializeWithBaseURL は、baseURLのwebappを初期化するクライアントを作成します
+func NewClientForInitializeWithBaseURL(ctx context.Context, baseURL string) (*Client, error) {
+	sess, err := newSessionForInitialize(ctx)
 	if err != nil {
-		return nil, bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします"))
+		return nil, bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします")
 	}
 
-	u, err := url.Parse(config.TargetBaseURL)
+	u, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします"))
+		return nil, bencherror.NewCriticalError(err, "Isutrainクライアントが作成できません. 運営に確認をお願いいたします")
 	}
 
 	return &Client{
@@ -59,6 +80,18 @@ func NewClientForInitialize() (*Client, error) {
 	}, nil
 }
 
+// do はリクエストを送り、エンドポイントごとにレスポンスヘッダを受け取るまでのレイテンシとステータスコードを記録します
+func (c *Client) do(req *http.Request, path string, wantStatusCode int) (*http.Response, error) {
+	startedAt := time.Now()
+	resp, err := c.sess.do(req)
+	statusCode := 0
+	if err == nil {
+		statusCode = resp.StatusCode
+	}
+	endpoint.Observe(req.Context(), req.Method+" "+path, time.Since(startedAt), statusCode, statusCode == wantStatusCode)
+	return resp, err
+}
+
 // ReplaceMockTransport は、clientの利用するhttp.RoundTripperを、DefaultTransportに差し替えます
 // NOTE: httpmockはhttp.DefaultTransportを利用するため、モックテストの時この関数を利用する
 func (c *Client) ReplaceMockTransport() {
@@ -66,6 +99,13 @@ func (c *Client) ReplaceMockTransport() {
 }
 
 func (c *Client) Initialize(ctx context.Context) {
+	if err := c.TryInitialize(ctx); err != nil {
+		bencherror.InitializeErrs(ctx).AddError(err)
+	}
+}
+
+// TryInitialize は /initialize を呼び出し、失敗した場合はエラーを返します
+func (c *Client) TryInitialize(ctx context.Context) error {
 	var (
 		successCode  = http.StatusOK
 		u            = *c.baseURL
@@ -78,47 +118,43 @@ func (c *Client) Initialize(ctx context.Context) {
 
 	req, err := c.sess.newRequest(ctx, http.MethodPost, u.String(), nil)
 	if err != nil {
-		bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "POST %s: リクエストに失敗しました", endpointPath))
-		return
+		return bencherror.NewCriticalError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Initialize), successCode)
 	if err != nil {
-		bencherror.InitializeErrs.AddError(bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath))
-		return
+		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
 	defer resp.Body.Close()
 
 	var initializeResp *InitializeResponse
 	if resp.StatusCode == successCode {
 		if err := json.NewDecoder(resp.Body).Decode(&initializeResp); err != nil {
-			bencherror.BenchmarkErrs.AddError(bencherror.NewCriticalError(err, "POST %s: レスポンスの形式が不正です", endpointPath))
-			return
+			return bencherror.NewCriticalError(err, "POST %s: レスポンスの形式が不正です", endpointPath)
 		}
 
 		if initializeResp.AvailableDays <= 0 {
-			bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "POST %s: 予約可能日数は正の整数値でなければなりません: got=%d", endpointPath, initializeResp.AvailableDays))
-			return
+			return bencherror.NewSimpleCriticalError("POST %s: 予約可能日数は正の整数値でなければなりません: got=%d", endpointPath, initializeResp.AvailableDays)
 		}
 
-		config.Language = initializeResp.Language
+		target := config.FromContext(ctx)
+		target.Language = initializeResp.Language
 		if len(initializeResp.Language) == 0 {
-			bencherror.InitializeErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: languageが指定されていません", endpointPath))
-			return
+			return bencherror.NewSimpleCriticalError("POST %s: languageが指定されていません", endpointPath)
 		}
 
-		if err := config.SetAvailReserveDays(initializeResp.AvailableDays); err != nil {
-			bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "POST %s: 予約可能日数の設定に失敗しました", endpointPath))
-			return
+		if err := target.SetAvailReserveDays(initializeResp.AvailableDays); err != nil {
+			return bencherror.NewCriticalError(err, "POST %s: 予約可能日数の設定に失敗しました", endpointPath)
 		}
 	}
 
 	if err := bencherror.NewHTTPStatusCodeError(req, resp, successCode); err != nil {
-		bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, http.StatusOK))
-		return
+		return bencherror.NewCriticalError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, http.StatusOK)
 	}
 
-	endpoint.IncPathCounter(endpoint.Initialize)
+	endpoint.IncPathCounter(ctx, endpoint.Initialize)
+
+	return nil
 }
 
 func (c *Client) Settings(ctx context.Context) (*SettingsResponse, error) {
@@ -134,7 +170,7 @@ func (c *Client) Settings(ctx context.Context) (*SettingsResponse, error) {
 		return nil, bencherror.NewApplicationError(err, "GET %s: 設定情報の取得に失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Settings), successCode)
 	if err != nil {
 		return nil, bencherror.NewWrapError(err, "GET %s: 設定情報の取得に失敗しました", endpointPath)
 	}
@@ -178,7 +214,7 @@ func (c *Client) Signup(ctx context.Context, email, password string, opt ...Clie
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Signup), opts.wantStatusCode)
 	if err != nil {
 		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
@@ -188,7 +224,7 @@ func (c *Client) Signup(ctx context.Context, email, password string, opt ...Clie
 		return bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.Signup)
+	endpoint.IncPathCounter(ctx, endpoint.Signup)
 
 	return nil
 }
@@ -219,7 +255,7 @@ func (c *Client) Login(ctx context.Context, email, password string, opt ...Clien
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Login), opts.wantStatusCode)
 	if err != nil {
 		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
@@ -233,7 +269,7 @@ func (c *Client) Login(ctx context.Context, email, password string, opt ...Clien
 		return bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, http.StatusOK)
 	}
 
-	endpoint.IncPathCounter(endpoint.Login)
+	endpoint.IncPathCounter(ctx, endpoint.Login)
 
 	return nil
 }
@@ -251,7 +287,7 @@ func (c *Client) Logout(ctx context.Context, opt ...ClientOption) error {
 		return bencherror.NewApplicationError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Logout), opts.wantStatusCode)
 	if err != nil {
 		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
@@ -261,11 +297,48 @@ func (c *Client) Logout(ctx context.Context, opt ...ClientOption) error {
 		return bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, http.StatusOK)
 	}
 
-	endpoint.IncPathCounter(endpoint.Logout)
+	endpoint.IncPathCounter(ctx, endpoint.Logout)
 
 	return nil
 }
 
+// GetAuth は、ログイン中のユーザのメールアドレスを取得します
+func (c *Client) GetAuth(ctx context.Context, opt ...ClientOption) (*AuthResponse, error) {
+	var (
+		successCode  = http.StatusOK
+		opts         = newClientOptions(successCode, opt...)
+		endpointPath = endpoint.GetPath(endpoint.Auth)
+		u            = *c.baseURL
+	)
+	u.Path = filepath.Join(u.Path, endpointPath)
+
+	req, err := c.sess.newRequest(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, bencherror.NewApplicationError(err, "GET %s: リクエストに失敗しました", endpointPath)
+	}
+
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Auth), opts.wantStatusCode)
+	if err != nil {
+		return nil, bencherror.NewWrapError(err, "GET %s: リクエストに失敗しました", endpointPath)
+	}
+	defer resp.Body.Close()
+
+	var authResp *AuthResponse
+	if resp.StatusCode == successCode {
+		if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+			return nil, bencherror.NewApplicationError(err, "GET %s: レスポンスのUnmarshalに失敗しました", endpointPath)
+		}
+	}
+
+	if err := bencherror.NewHTTPStatusCodeError(req, resp, opts.wantStatusCode); err != nil {
+		return nil, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
+	}
+
+	endpoint.IncPathCounter(ctx, endpoint.Auth)
+
+	return authResp, nil
+}
+
 // ListStations は駅一覧列挙APIです
 func (c *Client) ListStations(ctx context.Context, opt ...ClientOption) (ListStationsResponse, error) {
 	var (
@@ -281,7 +354,7 @@ func (c *Client) ListStations(ctx context.Context, opt ...ClientOption) (ListSta
 		return ListStationsResponse{}, bencherror.NewApplicationError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.ListStations), opts.wantStatusCode)
 	if err != nil {
 		return ListStationsResponse{}, bencherror.NewWrapError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
@@ -298,7 +371,7 @@ func (c *Client) ListStations(ctx context.Context, opt ...ClientOption) (ListSta
 		return ListStationsResponse{}, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.ListStations)
+	endpoint.IncPathCounter(ctx, endpoint.ListStations)
 
 	return listStationsResp, nil
 }
@@ -327,7 +400,7 @@ func (c *Client) SearchTrains(ctx context.Context, useAt time.Time, from, to, tr
 	query.Set("child", strconv.Itoa(child))
 	req.URL.RawQuery = query.Encode()
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.SearchTrains), opts.wantStatusCode)
 	if err != nil {
 		return SearchTrainsResponse{}, bencherror.NewWrapError(err, "GET %s: 列車検索リクエストに失敗しました", endpointPath)
 	}
@@ -350,7 +423,7 @@ func (c *Client) SearchTrains(ctx context.Context, useAt time.Time, from, to, tr
 		return SearchTrainsResponse{}, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.SearchTrains)
+	endpoint.IncPathCounter(ctx, endpoint.SearchTrains)
 
 	return searchTrainsResp, nil
 }
@@ -390,7 +463,7 @@ func (c *Client) SearchTrainSeats(ctx context.Context, date time.Time, trainClas
 		"to", arrival,
 	)
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.ListTrainSeats), opts.wantStatusCode)
 	if err != nil {
 		lgr.Warnf("座席列挙リクエスト失敗: %+v", err)
 		return nil, bencherror.NewWrapError(err, "GET %s: リクエストに失敗しました", endpointPath)
@@ -417,7 +490,7 @@ func (c *Client) SearchTrainSeats(ctx context.Context, date time.Time, trainClas
 		return nil, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.ListTrainSeats)
+	endpoint.IncPathCounter(ctx, endpoint.ListTrainSeats)
 
 	return searchTrainSeatsResp, nil
 }
@@ -473,7 +546,7 @@ func (c *Client) Reserve(
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.Reserve), opts.wantStatusCode)
 	if err != nil {
 		lgr.Warnf("予約リクエスト失敗: %+v", err)
 		return nil, bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
@@ -495,7 +568,7 @@ func (c *Client) Reserve(
 	}
 
 	if resp.StatusCode == successCode {
-		ReservationCache.Add(c.loginUser, reserveReq, reserveResp.ReservationID)
+		ReservationCacheFromContext(ctx).Add(c.loginUser, reserveReq, reserveResp.ReservationID)
 	}
 	if opts.autoAssert && resp.StatusCode == successCode {
 		if err := assertReserve(ctx, endpointPath, c, reserveReq, reserveResp); err != nil {
@@ -504,7 +577,7 @@ func (c *Client) Reserve(
 	}
 	if resp.StatusCode == successCode {
 		if SeatAvailability(seatClass) != SaNonReserved {
-			endpoint.AddExtraScore(endpoint.Reserve, config.ReservedSeatExtraScore)
+			endpoint.AddExtraScore(ctx, endpoint.Reserve, endpoint.ReservedSeatBonus, config.ReservedSeatExtraScore)
 		}
 
 		// 予約詳細から座席を取得し、曖昧予約ボーナスがあれば加点する
@@ -515,7 +588,7 @@ func (c *Client) Reserve(
 		return nil, bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.Reserve)
+	endpoint.IncPathCounter(ctx, endpoint.Reserve)
 
 	return reserveResp, nil
 }
@@ -552,7 +625,7 @@ func (c *Client) CommitReservation(ctx context.Context, reservationID int, cardT
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.CommitReservation), opts.wantStatusCode)
 	if err != nil {
 		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
@@ -572,8 +645,8 @@ func (c *Client) CommitReservation(ctx context.Context, reservationID int, cardT
 	}
 
 	if resp.StatusCode == successCode {
-		if err := ReservationCache.Commit(reservationID); err != nil {
-			bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "POST %s: 存在しない予約へのCommitを行おうとしました", endpointPath))
+		if err := ReservationCacheFromContext(ctx).Commit(reservationID); err != nil {
+			bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err, "POST %s: 存在しない予約へのCommitを行おうとしました", endpointPath))
 		}
 	}
 
@@ -581,7 +654,7 @@ func (c *Client) CommitReservation(ctx context.Context, reservationID int, cardT
 		return bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.CommitReservation)
+	endpoint.IncPathCounter(ctx, endpoint.CommitReservation)
 
 	return nil
 }
@@ -600,7 +673,7 @@ func (c *Client) ListReservations(ctx context.Context, opt ...ClientOption) (Lis
 		return ListReservationsResponse{}, bencherror.NewApplicationError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetPath(endpoint.ListReservations), opts.wantStatusCode)
 	if err != nil {
 		return ListReservationsResponse{}, bencherror.NewWrapError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
@@ -617,7 +690,7 @@ func (c *Client) ListReservations(ctx context.Context, opt ...ClientOption) (Lis
 		return ListReservationsResponse{}, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncPathCounter(endpoint.ListReservations)
+	endpoint.IncPathCounter(ctx, endpoint.ListReservations)
 
 	return listReservationResp, nil
 }
@@ -636,7 +709,7 @@ func (c *Client) ShowReservation(ctx context.Context, reservationID int, opt ...
 		return nil, bencherror.NewApplicationError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetDynamicPathPattern(endpoint.ShowReservation), opts.wantStatusCode)
 	if err != nil {
 		return nil, bencherror.NewWrapError(err, "GET %s: リクエストに失敗しました", endpointPath)
 	}
@@ -652,7 +725,7 @@ func (c *Client) ShowReservation(ctx context.Context, reservationID int, opt ...
 		return nil, bencherror.NewApplicationError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncDynamicPathCounter(endpoint.ShowReservation)
+	endpoint.IncDynamicPathCounter(ctx, endpoint.ShowReservation)
 
 	return showReservationResp, nil
 }
@@ -677,7 +750,7 @@ func (c *Client) CancelReservation(ctx context.Context, reservationID int, opt .
 		return bencherror.NewApplicationError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, endpoint.GetDynamicPathPattern(endpoint.CancelReservation), opts.wantStatusCode)
 	if err != nil {
 		return bencherror.NewWrapError(err, "POST %s: リクエストに失敗しました", endpointPath)
 	}
@@ -697,9 +770,9 @@ func (c *Client) CancelReservation(ctx context.Context, reservationID int, opt .
 	}
 
 	if resp.StatusCode == successCode {
-		if err := ReservationCache.Cancel(reservationID); err != nil {
+		if err := ReservationCacheFromContext(ctx).Cancel(reservationID); err != nil {
 			// FIXME: こういうベンチマーカーの異常は、利用者向けには一般的なメッセージで運営に連絡して欲しいと書き、運営向けにSlackに通知する
-			bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "存在しない予約のCancelを実施しようとしました"))
+			bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err, "存在しない予約のCancelを実施しようとしました"))
 		}
 	}
 
@@ -707,7 +780,7 @@ func (c *Client) CancelReservation(ctx context.Context, reservationID int, opt .
 		return bencherror.NewApplicationError(err, "POST %s: ステータスコードが不正です: got=%d, want=%d", endpointPath, resp.StatusCode, opts.wantStatusCode)
 	}
 
-	endpoint.IncDynamicPathCounter(endpoint.CancelReservation)
+	endpoint.IncDynamicPathCounter(ctx, endpoint.CancelReservation)
 
 	return nil
 }
@@ -721,17 +794,17 @@ func (c *Client) DownloadAsset(ctx context.Context, path string) ([]byte, error)
 
 	req, err := c.sess.newRequest(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return []byte{}, bencherror.PreTestErrs.AddError(bencherror.NewCriticalError(err, "GET %s: 静的ファイルのダウンロードに失敗しました", path))
+		return []byte{}, bencherror.PreTestErrs(ctx).AddError(bencherror.NewCriticalError(err, "GET %s: 静的ファイルのダウンロードに失敗しました", path))
 	}
 
-	resp, err := c.sess.do(req)
+	resp, err := c.do(req, "(static)", successCode)
 	if err != nil {
-		return []byte{}, bencherror.PreTestErrs.AddError(bencherror.NewWrapError(err, "GET %s: 静的ファイルのダウンロードに失敗しました", path))
+		return []byte{}, bencherror.PreTestErrs(ctx).AddError(bencherror.NewWrapError(err, "GET %s: 静的ファイルのダウンロードに失敗しました", path))
 	}
 	defer resp.Body.Close()
 
 	if err := bencherror.NewHTTPStatusCodeError(req, resp, successCode); err != nil {
-		return []byte{}, bencherror.PreTestErrs.AddError(bencherror.NewCriticalError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", path, resp.StatusCode, http.StatusOK))
+		return []byte{}, bencherror.PreTestErrs(ctx).AddError(bencherror.NewCriticalError(err, "GET %s: ステータスコードが不正です: got=%d, want=%d", path, resp.StatusCode, http.StatusOK))
 	}
 
 	return ioutil.ReadAll(resp.Body)
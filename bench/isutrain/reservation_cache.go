@@ -1,6 +1,7 @@
 package isutrain
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -73,12 +74,8 @@ func (r *ReservationCacheEntry) Amount() (int, error) {
 	return adultFare + childFare, nil
 }
 
-var (
-	// RCache は、webappの予約に関する情報が適切か検証するために用いられるキャッシュです
-	ReservationCache = newReservationCache()
-)
-
-type reservationCache struct {
+// ReservationCache は、webappの予約に関する情報が適切か検証するために用いられるキャッシュです
+type ReservationCache struct {
 	mu sync.RWMutex
 	// reservationID -> ReservationCacheEntry
 	reservations         map[int]*ReservationCacheEntry
@@ -86,34 +83,50 @@ type reservationCache struct {
 	canceledReservations map[int]*ReservationCacheEntry
 }
 
-func newReservationCache() *reservationCache {
-	return &reservationCache{
+func NewReservationCache() *ReservationCache {
+	return &ReservationCache{
 		reservations:         map[int]*ReservationCacheEntry{},
 		commitedReservations: map[int]*ReservationCacheEntry{},
 		canceledReservations: map[int]*ReservationCacheEntry{},
 	}
 }
 
+type reservationCacheKey struct{}
+
+// NewReservationCacheContext は予約キャッシュcacheを持つcontextを返します
+func NewReservationCacheContext(ctx context.Context, cache *ReservationCache) context.Context {
+	return context.WithValue(ctx, reservationCacheKey{}, cache)
+}
+
+// ReservationCacheFromContext はcontextの予約キャッシュを返します
+// 予約キャッシュのないcontextで呼び出すと、ほかの実行と予約が混ざらないようpanicします
+func ReservationCacheFromContext(ctx context.Context) *ReservationCache {
+	if cache, ok := ctx.Value(reservationCacheKey{}).(*ReservationCache); ok {
+		return cache
+	}
+	panic("contextに予約キャッシュがありません. benchrun.Run.Context で作ったcontextを渡してください")
+}
+
 func (r *ReservationCacheEntry) SeatCount() int {
 	// webappは全ての座席が取れない場合エラーのステータスコードを返すので、前席が帰って来ることを期待
 	return r.Adult + r.Child
 }
 
-func (r *reservationCache) Len() int {
+func (r *ReservationCache) Len() int {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	return len(r.reservations)
 }
 
-func (r *reservationCache) CommitedLen() int {
+func (r *ReservationCache) CommitedLen() int {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	return len(r.commitedReservations)
 }
 
-func (r *reservationCache) Reservation(reservationID int) (*ReservationCacheEntry, bool) {
+func (r *ReservationCache) Reservation(reservationID int) (*ReservationCacheEntry, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -127,7 +140,7 @@ func (r *reservationCache) Reservation(reservationID int) (*ReservationCacheEntr
 
 // 予約可能判定
 // NOTE: この予約が可能か？を判定する必要があるので、リクエストを受け取り、複数のSeatのどれか１つでも含まれていればNGとする
-func (r *reservationCache) CanReserve(req *ReserveRequest) (bool, error) {
+func (r *ReservationCache) CanReserve(req *ReserveRequest) (bool, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -221,7 +234,7 @@ func (r *reservationCache) CanReserve(req *ReserveRequest) (bool, error) {
 	return true, nil
 }
 
-func (r *reservationCache) Add(user *User, req *ReserveRequest, reservationID int) error {
+func (r *ReservationCache) Add(user *User, req *ReserveRequest, reservationID int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -265,7 +278,7 @@ func (r *reservationCache) Add(user *User, req *ReserveRequest, reservationID in
 	return nil
 }
 
-func (r *reservationCache) Commit(reservationID int) error {
+func (r *ReservationCache) Commit(reservationID int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -282,7 +295,7 @@ func (r *reservationCache) Commit(reservationID int) error {
 	return nil
 }
 
-func (r *reservationCache) Cancel(reservationID int) error {
+func (r *ReservationCache) Cancel(reservationID int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -304,7 +317,7 @@ func (r *reservationCache) Cancel(reservationID int) error {
 	return nil
 }
 
-func (r *reservationCache) RangeCommited(f func(reservation *ReservationCacheEntry)) {
+func (r *ReservationCache) RangeCommited(f func(reservation *ReservationCacheEntry)) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -313,7 +326,7 @@ func (r *reservationCache) RangeCommited(f func(reservation *ReservationCacheEnt
 	}
 }
 
-func (r *reservationCache) RangeCanceled(f func(reservation *ReservationCacheEntry)) {
+func (r *ReservationCache) RangeCanceled(f func(reservation *ReservationCacheEntry)) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
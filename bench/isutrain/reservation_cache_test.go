@@ -11,7 +11,7 @@ import (
 
 func TestReservationMem_CanReserve_Kudari(t *testing.T) {
 	now := time.Now()
-	mem := newReservationCache()
+	mem := NewReservationCache()
 
 	gotTests := []struct {
 		reservationID int
@@ -232,7 +232,7 @@ func TestReservationMem_CanReserve_Kudari(t *testing.T) {
 
 func TestReservationMem_CanReserve_Nobori(t *testing.T) {
 	now := time.Now()
-	mem := newReservationCache()
+	mem := NewReservationCache()
 
 	gotTests := []struct {
 		reservationID int
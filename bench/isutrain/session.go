@@ -1,16 +1,20 @@
 package isutrain
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"time"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/traffic"
 	"golang.org/x/xerrors"
 )
 
@@ -20,15 +24,27 @@ var (
 
 type Session struct {
 	httpClient *http.Client
+
+	// リクエストの記録先(記録しない場合はnil)と、同じセッションのリクエストをまとめるためのID
+	rec *traffic.Recorder
+	id  int64
+}
+
+// startRecording は、contextの実行でリクエストを記録していれば、このセッションのリクエストも記録します
+func (sess *Session) startRecording(ctx context.Context) {
+	if rec := traffic.FromContext(ctx); rec != nil {
+		sess.rec = rec
+		sess.id = rec.NextSession()
+	}
 }
 
-func NewSession() (*Session, error) {
+func NewSession(ctx context.Context) (*Session, error) {
 	jar, err := cookiejar.New(&cookiejar.Options{})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Session{
+	sess := &Session{
 		httpClient: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
@@ -42,11 +58,13 @@ func NewSession() (*Session, error) {
 				return bencherror.NewApplicationError(ErrRedirect, "アプリケーションへのリクエストでリダイレクトを検出しました")
 			},
 		},
-	}, nil
+	}
+	sess.startRecording(ctx)
+	return sess, nil
 }
 
-func newSessionForInitialize() (*Session, error) {
-	return &Session{
+func newSessionForInitialize(ctx context.Context) (*Session, error) {
+	sess := &Session{
 		httpClient: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
@@ -59,7 +77,9 @@ func newSessionForInitialize() (*Session, error) {
 				return bencherror.NewApplicationError(ErrRedirect, "アプリケーションへのリクエストでリダイレクトを検出しました")
 			},
 		},
-	}, nil
+	}
+	sess.startRecording(ctx)
+	return sess, nil
 }
 
 // NOTE: GETクエリパラメータをURLにくっつける処理は、utilityなどのURLを扱う側で実装
@@ -77,6 +97,13 @@ func (sess *Session) newRequest(ctx context.Context, method, uri string, body io
 }
 
 func (sess *Session) do(req *http.Request) (*http.Response, error) {
+	if sess.rec != nil {
+		return sess.doWithRecord(req)
+	}
+	return sess.doRequest(req)
+}
+
+func (sess *Session) doRequest(req *http.Request) (*http.Response, error) {
 	resp, err := sess.httpClient.Do(req)
 	if err != nil {
 		var netErr net.Error
@@ -93,3 +120,53 @@ func (sess *Session) do(req *http.Request) (*http.Response, error) {
 
 	return resp, nil
 }
+
+// doWithRecord はリクエストを送り、リクエストとレスポンスを記録します
+// レスポンスのボディは読み出して記録するので、呼び出し側には読み直せるボディを返します
+func (sess *Session) doWithRecord(req *http.Request) (*http.Response, error) {
+	e := &traffic.Entry{
+		Session:     sess.id,
+		Scenario:    traffic.ScenarioOf(req.Context()),
+		Method:      req.Method,
+		Path:        req.URL.RequestURI(),
+		ContentType: req.Header.Get("Content-Type"),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			e.Body = string(b)
+		}
+	}
+
+	startedAt := time.Now()
+	resp, err := sess.doRequest(req)
+	e.Latency = float64(time.Since(startedAt)) / float64(time.Millisecond)
+	if err != nil {
+		e.Error = err.Error()
+		sess.rec.Record(startedAt, e)
+		return nil, err
+	}
+
+	b, readErr := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	e.Status = resp.StatusCode
+	if readErr != nil {
+		// 読み出しに失敗した場合は、呼び出し側にも同じエラーを返す
+		e.Error = readErr.Error()
+		resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(b), &errReader{readErr}))
+	} else {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+	e.SetResponse(resp.Header.Get("Content-Type"), b)
+	sess.rec.Record(startedAt, e)
+
+	return resp, nil
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -135,6 +135,9 @@ const (
 	SaNonReserved   SeatAvailability = "non_reserved"
 )
 
+// SeatAvailabilities は、列車検索で空席状況が返される座席クラスの一覧です
+var SeatAvailabilities = []SeatAvailability{SaPremium, SaPremiumSmoke, SaReserved, SaReservedSmoke, SaNonReserved}
+
 func (sa SeatAvailability) String() string {
 	return string(sa)
 }
@@ -192,6 +195,16 @@ func IsValidTrainClass(trainClass string) bool {
 	}
 }
 
+// IsValidSeatAvailabilitySymbol は、列車検索の空席状況の記号が正しいか否かを返します
+func IsValidSeatAvailabilitySymbol(symbol string) bool {
+	switch symbol {
+	case "○", "△", "×":
+		return true
+	default:
+		return false
+	}
+}
+
 func IsValidSeatClass(seatClass string) bool {
 	switch seatClass {
 	case "premium", "reserved", "non-reserved":
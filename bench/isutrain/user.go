@@ -4,3 +4,8 @@ type User struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// AuthResponse は GET /api/auth のレスポンスです
+type AuthResponse struct {
+	Email string `json:"email"`
+}
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"bytes"
 	"encoding/json"
 	"math/rand"
 	"net/http"
@@ -40,6 +41,10 @@ func (m *paymentMock) addPaymentInformation() {
 	})
 }
 
+func (m *paymentMock) Healthz() ([]byte, int) {
+	return []byte("ok\n"), http.StatusOK
+}
+
 func (m *paymentMock) Initialize() ([]byte, int) {
 	return []byte(http.StatusText(http.StatusOK)), http.StatusOK
 }
@@ -68,3 +73,29 @@ func (m *paymentMock) GetResult() ([]byte, int) {
 
 	return b, http.StatusOK
 }
+
+func (m *paymentMock) StreamResults() ([]byte, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// grpc-gatewayのサーバストリーミングと同じく、改行区切りのJSONで返す
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rawData := range m.result.RawData {
+		if err := enc.Encode(map[string]*payment.StreamResultsResponse{
+			"result": &payment.StreamResultsResponse{RawData: rawData},
+		}); err != nil {
+			return []byte(http.StatusText(http.StatusInternalServerError)), http.StatusInternalServerError
+		}
+	}
+	if err := enc.Encode(map[string]*payment.StreamResultsResponse{
+		"result": &payment.StreamResultsResponse{Summary: &payment.ResultSummary{
+			Count:      len(m.result.RawData),
+			Duplicates: []*payment.DuplicatePayment{},
+		}},
+	}); err != nil {
+		return []byte(http.StatusText(http.StatusInternalServerError)), http.StatusInternalServerError
+	}
+
+	return buf.Bytes(), http.StatusOK
+}
@@ -157,6 +157,10 @@ func Register() (*Mock, error) {
 	})
 
 	// 課金API
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s%s", paymentBaseURL, endpoint.PaymentHealthzPath), func(req *http.Request) (*http.Response, error) {
+		body, status := paymentMock.Healthz()
+		return httpmock.NewBytesResponse(status, body), nil
+	})
 	httpmock.RegisterResponder("POST", fmt.Sprintf("%s%s", paymentBaseURL, endpoint.PaymentInitializePath), func(req *http.Request) (*http.Response, error) {
 		body, status := paymentMock.Initialize()
 		return httpmock.NewBytesResponse(status, body), nil
@@ -165,6 +169,10 @@ func Register() (*Mock, error) {
 		body, status := paymentMock.GetResult()
 		return httpmock.NewBytesResponse(status, body), nil
 	})
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s%s", paymentBaseURL, endpoint.PaymentStreamPath), func(req *http.Request) (*http.Response, error) {
+		body, status := paymentMock.StreamResults()
+		return httpmock.NewBytesResponse(status, body), nil
+	})
 	httpmock.RegisterResponder("POST", fmt.Sprintf("%s%s", paymentBaseURL, endpoint.PaymentRegistCardPath), func(req *http.Request) (*http.Response, error) {
 		body, status := paymentMock.RegistCard()
 		return httpmock.NewBytesResponse(status, body), nil
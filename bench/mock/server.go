@@ -0,0 +1,358 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/internal/isutraindb"
+	"github.com/chibiegg/isucon9-final/bench/internal/util"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+)
+
+// Bug はモックサーバに仕込む不具合です
+// ベンチマーカーが不具合を検出できるか確かめるために使います
+type Bug string
+
+const (
+	// BugDoubleBooking は、予約済みの座席を重ねて予約できてしまう不具合です
+	BugDoubleBooking Bug = "double-booking"
+	// BugWrongFare は、消費税を上乗せして運賃を誤って計算する不具合です
+	BugWrongFare Bug = "wrong-fare"
+	// BugMissingSeats は、予約詳細や予約一覧で座席が1つ欠ける不具合です
+	BugMissingSeats Bug = "missing-seats"
+	// BugIgnoreOwnership は、他のユーザの予約を参照・確定・キャンセルできてしまう不具合です
+	BugIgnoreOwnership Bug = "ignore-ownership"
+	// BugNoRefund は、決済済みの予約をキャンセルしても決済を取り消さない不具合です
+	BugNoRefund Bug = "no-refund"
+	// BugWrongAvailability は、列車検索の空席状況を記号ではなく空席数で返す不具合です
+	BugWrongAvailability Bug = "wrong-availability"
+)
+
+var allBugs = []Bug{
+	BugDoubleBooking,
+	BugWrongFare,
+	BugMissingSeats,
+	BugIgnoreOwnership,
+	BugNoRefund,
+	BugWrongAvailability,
+}
+
+// Bugs は仕込める不具合の一覧を返します
+func Bugs() []Bug {
+	return append([]Bug{}, allBugs...)
+}
+
+// ParseBug は名前から不具合を返します
+func ParseBug(name string) (Bug, error) {
+	for _, bug := range allBugs {
+		if string(bug) == name {
+			return bug, nil
+		}
+	}
+	return "", fmt.Errorf("不明な不具合です: %s", name)
+}
+
+const serverSessionName = "session_isutrain"
+
+// Server は予約の状態を持ち、net/httpでisutrainと課金APIを提供するモックサーバです
+// httpmockで固定のレスポンスを返す Mock と異なり、座席の重複や運賃を isutraindb と同じ規則で扱います
+type Server struct {
+	// AvailableDays は予約可能日数です
+	AvailableDays int
+	// PaymentURL は /api/settings で返す課金APIのURLです
+	PaymentURL string
+	// AssetDir が指定されていれば、静的ファイルを配信します
+	AssetDir string
+
+	bugMu sync.RWMutex
+	bugs  map[Bug]bool
+
+	mu                sync.Mutex
+	users             map[string]string // email -> password
+	sessions          map[string]string // token -> email
+	reservations      map[int]*serverReservation
+	lastReservationID int
+
+	payment *paymentServer
+	tt      *timetable
+}
+
+// NewServer はモックサーバを作成します
+func NewServer(availableDays int, paymentURL string) *Server {
+	s := &Server{
+		AvailableDays: availableDays,
+		PaymentURL:    paymentURL,
+		bugs:          map[Bug]bool{},
+		payment:       newPaymentServer(),
+		tt:            newTimetable(),
+	}
+	s.reset()
+	return s
+}
+
+func (s *Server) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = map[string]string{}
+	s.sessions = map[string]string{}
+	s.reservations = map[int]*serverReservation{}
+	s.lastReservationID = 0
+}
+
+// SetBugs は仕込む不具合を置き換えます. 何も渡さなければ不具合のない状態に戻ります
+func (s *Server) SetBugs(bugs ...Bug) {
+	s.bugMu.Lock()
+	defer s.bugMu.Unlock()
+
+	s.bugs = map[Bug]bool{}
+	for _, bug := range bugs {
+		s.bugs[bug] = true
+	}
+}
+
+func (s *Server) hasBug(bug Bug) bool {
+	s.bugMu.RLock()
+	defer s.bugMu.RUnlock()
+
+	return s.bugs[bug]
+}
+
+// Handler はisutrainのAPIを提供するハンドラを返します
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(endpoint.GetPath(endpoint.Initialize), s.handle(http.MethodPost, s.initialize))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Settings), s.handle(http.MethodGet, s.settings))
+	mux.HandleFunc(endpoint.GetPath(endpoint.ListStations), s.handle(http.MethodGet, s.listStations))
+	mux.HandleFunc(endpoint.GetPath(endpoint.SearchTrains), s.handle(http.MethodGet, s.searchTrains))
+	mux.HandleFunc(endpoint.GetPath(endpoint.ListTrainSeats), s.handle(http.MethodGet, s.searchTrainSeats))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Reserve), s.handle(http.MethodPost, s.reserve))
+	mux.HandleFunc(endpoint.GetPath(endpoint.CommitReservation), s.handle(http.MethodPost, s.commitReservation))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Auth), s.handle(http.MethodGet, s.getAuth))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Signup), s.handle(http.MethodPost, s.signup))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Login), s.handle(http.MethodPost, s.login))
+	mux.HandleFunc(endpoint.GetPath(endpoint.Logout), s.handle(http.MethodPost, s.logout))
+	mux.HandleFunc(endpoint.GetPath(endpoint.ListReservations), s.handle(http.MethodGet, s.listReservations))
+	mux.HandleFunc(endpoint.GetPath(endpoint.ListReservations)+"/", s.userReservation)
+	if s.AssetDir != "" {
+		mux.HandleFunc("/", s.serveAsset)
+	}
+	return mux
+}
+
+// serveAsset は静的ファイルを返します
+// http.FileServer は /index.html を / にリダイレクトするため、使わずにファイルを直接返します
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+	if name == "/" {
+		name = "/index.html"
+	}
+	f, err := os.Open(filepath.Join(s.AssetDir, filepath.FromSlash(name)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, name, stat.ModTime(), f)
+}
+
+// PaymentHandler は課金APIを提供するハンドラを返します
+// webappと同じ /initialize を持つため、Handler とは別のポートで提供する必要があります
+func (s *Server) PaymentHandler() http.Handler {
+	return s.payment.handler()
+}
+
+func (s *Server) handle(method string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			errorResponse(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+			return
+		}
+		f(w, r)
+	}
+}
+
+// /api/user/reservations/:id と /api/user/reservations/:id/cancel
+func (s *Server) userReservation(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, endpoint.GetPath(endpoint.ListReservations)+"/")
+	if strings.HasSuffix(path, "/cancel") {
+		s.handle(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			s.cancelReservation(w, r, strings.TrimSuffix(path, "/cancel"))
+		})(w, r)
+		return
+	}
+	s.handle(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		s.showReservation(w, r, path)
+	})(w, r)
+}
+
+func jsonResponse(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.Write(b)
+}
+
+func errorResponse(w http.ResponseWriter, code int, message string) {
+	b, _ := json.Marshal(map[string]interface{}{
+		"is_error": true,
+		"message":  message,
+	})
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(b)
+}
+
+func messageResponse(w http.ResponseWriter, message string) {
+	jsonResponse(w, map[string]interface{}{
+		"is_error": false,
+		"message":  message,
+	})
+}
+
+func (s *Server) initialize(w http.ResponseWriter, r *http.Request) {
+	s.reset()
+	jsonResponse(w, &isutrain.InitializeResponse{
+		AvailableDays: s.AvailableDays,
+		Language:      "mock",
+	})
+}
+
+func (s *Server) settings(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, &isutrain.SettingsResponse{
+		PaymentAPI: s.PaymentURL,
+	})
+}
+
+func (s *Server) listStations(w http.ResponseWriter, r *http.Request) {
+	stations := isutrain.ListStationsResponse{}
+	for i, station := range s.tt.stations {
+		// webappと同じく、距離は返さない
+		stations = append(stations, &isutrain.Station{
+			ID:                i + 1,
+			Name:              station.Name,
+			IsStopExpress:     station.IsStopExpress,
+			IsStopSemiExpress: station.IsStopSemiExpress,
+			IsStopLocal:       station.IsStopLocal,
+		})
+	}
+	jsonResponse(w, stations)
+}
+
+// 認証
+
+// loginUser はセッションのユーザのメールアドレスを返します. s.mu を取得した状態で呼び出します
+func (s *Server) loginUser(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(serverSessionName)
+	if err != nil {
+		return "", false
+	}
+	email, ok := s.sessions[cookie.Value]
+	return email, ok
+}
+
+func (s *Server) getAuth(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	email, ok := s.loginUser(r)
+	s.mu.Unlock()
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+	jsonResponse(w, &isutrain.AuthResponse{Email: email})
+}
+
+func (s *Server) signup(w http.ResponseWriter, r *http.Request) {
+	user := &isutrain.User{}
+	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
+		errorResponse(w, http.StatusBadRequest, "JSON parseに失敗しました")
+		return
+	}
+	if user.Email == "" || user.Password == "" {
+		errorResponse(w, http.StatusBadRequest, "メールアドレスとパスワードを指定してください")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[user.Email]; ok {
+		errorResponse(w, http.StatusBadRequest, "user registration failed")
+		return
+	}
+	s.users[user.Email] = user.Password
+	messageResponse(w, "registration complete")
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	user := &isutrain.User{}
+	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
+		errorResponse(w, http.StatusBadRequest, "JSON parseに失敗しました")
+		return
+	}
+	token, err := util.SecureRandomStr(20)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	password, ok := s.users[user.Email]
+	if !ok || password != user.Password {
+		errorResponse(w, http.StatusForbidden, "authentication failed")
+		return
+	}
+	s.sessions[token] = user.Email
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     serverSessionName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+	})
+	messageResponse(w, "autheticated")
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(serverSessionName); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, cookie.Value)
+		s.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   serverSessionName,
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+	messageResponse(w, "logged out")
+}
+
+// stationIndex は駅の東京からの順番を返します
+func (s *Server) stationIndex(name string) (int, bool) {
+	idx, ok := s.tt.stationIdx[name]
+	return idx, ok
+}
+
+func (s *Server) station(name string) (*isutraindb.Station, bool) {
+	idx, ok := s.stationIndex(name)
+	if !ok {
+		return nil, false
+	}
+	return s.tt.stations[idx], true
+}
@@ -0,0 +1,240 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+)
+
+var (
+	errUnknownCardToken = errors.New("カードトークンが不正です")
+	errUnknownPayment   = errors.New("決済情報がみつかりません")
+)
+
+// paymentServer は決済の状態を持つ課金APIのモックです
+// webappと同じく、/payment で決済し、/payment/:id で決済をキャンセルします
+type paymentServer struct {
+	mu       sync.Mutex
+	cards    map[string]*payment.CardInformation
+	rawData  []*payment.RawData
+	payments map[string]*payment.RawData
+	seq      int
+}
+
+func newPaymentServer() *paymentServer {
+	p := &paymentServer{}
+	p.initialize()
+	return p
+}
+
+func (p *paymentServer) initialize() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cards = map[string]*payment.CardInformation{}
+	p.rawData = []*payment.RawData{}
+	p.payments = map[string]*payment.RawData{}
+	p.seq = 0
+}
+
+func (p *paymentServer) registCard(card *payment.CardInformation) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seq++
+	token := fmt.Sprintf("card-%d", p.seq)
+	p.cards[token] = card
+	return token
+}
+
+func (p *paymentServer) pay(cardToken string, reservationID, amount int) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	card, ok := p.cards[cardToken]
+	if !ok {
+		return "", errUnknownCardToken
+	}
+
+	p.seq++
+	rawData := &payment.RawData{
+		PaymentID: fmt.Sprintf("payment-%d", p.seq),
+		PaymentInfo: &payment.PaymentInformation{
+			CardToken:     cardToken,
+			ReservationID: reservationID,
+			Datetime:      time.Now(),
+			Amount:        int64(amount),
+		},
+		CardInfo: card,
+	}
+	p.rawData = append(p.rawData, rawData)
+	p.payments[rawData.PaymentID] = rawData
+	return rawData.PaymentID, nil
+}
+
+func (p *paymentServer) cancel(paymentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rawData, ok := p.payments[paymentID]
+	if !ok {
+		return errUnknownPayment
+	}
+	rawData.PaymentInfo.IsCanceled = true
+	return nil
+}
+
+// duplicates は、同じ予約に対してキャンセルされていない決済が複数ある予約を返します. p.mu を取得した状態で呼び出します
+func (p *paymentServer) duplicates() []*payment.DuplicatePayment {
+	var (
+		paymentIDs = map[int][]string{}
+		order      = []int{}
+	)
+	for _, rawData := range p.rawData {
+		if rawData.PaymentInfo.IsCanceled {
+			continue
+		}
+		reservationID := rawData.PaymentInfo.ReservationID
+		if _, ok := paymentIDs[reservationID]; !ok {
+			order = append(order, reservationID)
+		}
+		paymentIDs[reservationID] = append(paymentIDs[reservationID], rawData.PaymentID)
+	}
+
+	duplicates := []*payment.DuplicatePayment{}
+	for _, reservationID := range order {
+		if ids := paymentIDs[reservationID]; len(ids) > 1 {
+			duplicates = append(duplicates, &payment.DuplicatePayment{
+				ReservationID: reservationID,
+				PaymentIDs:    ids,
+			})
+		}
+	}
+	return duplicates
+}
+
+func (p *paymentServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(endpoint.PaymentHealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc(endpoint.PaymentInitializePath, p.handleInitialize)
+	mux.HandleFunc(endpoint.PaymentRegistCardPath, p.handleRegistCard)
+	mux.HandleFunc(endpoint.PaymentResultPath, p.handleResult)
+	mux.HandleFunc(endpoint.PaymentStreamPath, p.handleStreamResults)
+	mux.HandleFunc("/payment", p.handlePay)
+	mux.HandleFunc("/payment/", p.handleCancel)
+	return mux
+}
+
+func (p *paymentServer) handleInitialize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	p.initialize()
+	jsonResponse(w, map[string]bool{"is_ok": true})
+}
+
+func (p *paymentServer) handleRegistCard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	var req struct {
+		CardInformation *payment.CardInformation `json:"card_information"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CardInformation == nil {
+		errorResponse(w, http.StatusBadRequest, "カード情報が不正です")
+		return
+	}
+	jsonResponse(w, &payment.RegistCardResponse{
+		CardToken: p.registCard(req.CardInformation),
+		IsOK:      true,
+	})
+}
+
+func (p *paymentServer) handlePay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	var req struct {
+		PaymentInformation *payment.PaymentInformation `json:"payment_information"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PaymentInformation == nil {
+		errorResponse(w, http.StatusBadRequest, "決済情報が不正です")
+		return
+	}
+	info := req.PaymentInformation
+	paymentID, err := p.pay(info.CardToken, info.ReservationID, int(info.Amount))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	jsonResponse(w, map[string]interface{}{
+		"payment_id": paymentID,
+		"is_ok":      true,
+	})
+}
+
+func (p *paymentServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		errorResponse(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	paymentID := strings.TrimPrefix(r.URL.Path, "/payment/")
+	if err := p.cancel(paymentID); err != nil {
+		errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	jsonResponse(w, map[string]bool{"is_ok": true})
+}
+
+func (p *paymentServer) handleResult(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	jsonResponse(w, &payment.PaymentResult{
+		RawData:    p.rawData,
+		IsOK:       true,
+		Duplicates: p.duplicates(),
+	})
+}
+
+func (p *paymentServer) handleStreamResults(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// grpc-gatewayのサーバストリーミングと同じく、改行区切りのJSONで返す
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rawData := range p.rawData {
+		if err := enc.Encode(map[string]*payment.StreamResultsResponse{
+			"result": &payment.StreamResultsResponse{RawData: rawData},
+		}); err != nil {
+			errorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err := enc.Encode(map[string]*payment.StreamResultsResponse{
+		"result": &payment.StreamResultsResponse{Summary: &payment.ResultSummary{
+			Count:      len(p.rawData),
+			Duplicates: p.duplicates(),
+		}},
+	}); err != nil {
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
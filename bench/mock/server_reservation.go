@@ -0,0 +1,339 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/isutraindb"
+	"github.com/chibiegg/isucon9-final/bench/internal/util"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+)
+
+type serverReservation struct {
+	ID    int
+	Email string
+
+	Date                  time.Time
+	TrainClass, TrainName string
+	Departure, Arrival    string
+	CarNumber             int
+	SeatClass             string
+	Seats                 isutrain.TrainSeats
+	Adult, Child          int
+	Amount                int
+
+	// 決済が済んでいれば、課金APIの決済IDが入る
+	PaymentID string
+}
+
+func (s *Server) reservationResponse(reservation *serverReservation) *isutrain.Reservation {
+	t := s.tt.trainMap[trainKey(reservation.TrainClass, reservation.TrainName)]
+
+	seats := isutrain.ReservationSeats{}
+	for _, seat := range reservation.Seats {
+		seats = append(seats, &isutrain.ReservationSeat{
+			SeatRow:    seat.Row,
+			SeatColumn: seat.Column,
+		})
+	}
+	if s.hasBug(BugMissingSeats) && len(seats) > 0 {
+		seats = seats[:len(seats)-1]
+	}
+
+	return &isutrain.Reservation{
+		ReservationID: reservation.ID,
+		Date:          reservation.Date.Format("2006/01/02"),
+		TrainClass:    reservation.TrainClass,
+		TrainName:     reservation.TrainName,
+		CarNumber:     reservation.CarNumber,
+		SeatClass:     reservation.SeatClass,
+		Amount:        reservation.Amount,
+		Adult:         reservation.Adult,
+		Child:         reservation.Child,
+		Departure:     reservation.Departure,
+		Arrival:       reservation.Arrival,
+		DepartureTime: formatClock(t.departures[reservation.Departure]),
+		ArrivalTime:   formatClock(t.arrivals[reservation.Arrival]),
+		Seats:         seats,
+	}
+}
+
+// isOwner は、予約がログイン中のユーザのものか否かを返します
+func (s *Server) isOwner(reservation *serverReservation, email string) bool {
+	return reservation.Email == email || s.hasBug(BugIgnoreOwnership)
+}
+
+// 予約
+
+func (s *Server) reserve(w http.ResponseWriter, r *http.Request) {
+	req := &isutrain.ReserveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "JSON parseに失敗しました")
+		return
+	}
+	useAt, err := util.ParseISO8601(req.Date)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "時刻のparseに失敗しました")
+		return
+	}
+	date, ok := s.reservationDate(useAt)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "予約可能期間外です")
+		return
+	}
+
+	t, ok := s.tt.trainMap[trainKey(req.TrainClass, req.TrainName)]
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "列車データがみつかりません")
+		return
+	}
+	from, ok := s.station(req.Departure)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("乗車駅データがみつかりません %s", req.Departure))
+		return
+	}
+	to, ok := s.station(req.Arrival)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("降車駅データがみつかりません %s", req.Arrival))
+		return
+	}
+	if !t.class.stopsAt(from) || !t.class.stopsAt(to) {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("%sの止まらない駅です", t.class.name))
+		return
+	}
+	if !t.runs(s.tt, from.Name, to.Name) {
+		errorResponse(w, http.StatusBadRequest, "リクエストされた区間に列車が運行していない区間が含まれています")
+		return
+	}
+
+	fare, err := isutraindb.GetFare(0, date, from.Name, to.Name, t.class.name, req.SeatClass)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	amount := req.Adult*fare + (req.Child*fare)/2
+	if s.hasBug(BugWrongFare) {
+		amount = amount * 110 / 100
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	carNum, seats := req.CarNum, req.Seats
+	switch {
+	case len(seats) == 0 && req.SeatClass == "non-reserved":
+		// 自由席は座席を指定せずに予約する
+		carNum = 0
+	case len(seats) == 0:
+		// あいまい予約では、1両の中に人数分の空席がある号車を探す
+		carNum, seats = s.findVacantSeats(date, t, from.Name, to.Name, req.SeatClass, req.IsSmokingSeat, req.Adult+req.Child)
+		if len(seats) == 0 {
+			errorResponse(w, http.StatusNotFound, "あいまい座席予約ができませんでした。指定した席、もしくは1車両内に希望の席数をご用意できませんでした。")
+			return
+		}
+	default:
+		if !isValidCarNumber(carNum) || isutraindb.GetSeatClass(t.class.name, carNum) != req.SeatClass {
+			errorResponse(w, http.StatusNotFound, "リクエストされた座席情報は存在しません。号車・喫煙席・座席クラスなど組み合わせを見直してください")
+			return
+		}
+		carSeats := carSeats(t.class.name, carNum)
+		reserved := isutrain.TrainSeats{}
+		for _, seat := range seats {
+			carSeat, ok := findSeat(carSeats, seat.Row, seat.Column)
+			if !ok {
+				errorResponse(w, http.StatusNotFound, "リクエストされた座席情報は存在しません。号車・喫煙席・座席クラスなど組み合わせを見直してください")
+				return
+			}
+			if req.SeatClass != "non-reserved" && !s.hasBug(BugDoubleBooking) && s.isOccupied(date, t, carNum, seat.Row, seat.Column, from.Name, to.Name) {
+				errorResponse(w, http.StatusBadRequest, "リクエストに既に予約された席が含まれています")
+				return
+			}
+			reserved = append(reserved, carSeat)
+		}
+		seats = reserved
+	}
+
+	email, ok := s.loginUser(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+
+	s.lastReservationID++
+	reservation := &serverReservation{
+		ID:         s.lastReservationID,
+		Email:      email,
+		Date:       date,
+		TrainClass: t.class.name,
+		TrainName:  t.name,
+		Departure:  from.Name,
+		Arrival:    to.Name,
+		CarNumber:  carNum,
+		SeatClass:  req.SeatClass,
+		Seats:      seats,
+		Adult:      req.Adult,
+		Child:      req.Child,
+		Amount:     amount,
+	}
+	s.reservations[reservation.ID] = reservation
+
+	jsonResponse(w, &isutrain.ReserveResponse{
+		ReservationID: reservation.ID,
+		Amount:        reservation.Amount,
+		IsOk:          true,
+	})
+}
+
+// findVacantSeats は、count席の空席がある最初の号車と、その座席を返します. s.mu を取得した状態で呼び出します
+func (s *Server) findVacantSeats(date time.Time, t *train, from, to, seatClass string, isSmokingSeat bool, count int) (int, isutrain.TrainSeats) {
+	if count <= 0 {
+		return 0, nil
+	}
+	for carNum := 1; carNum <= carsPerTrain; carNum++ {
+		if isutraindb.GetSeatClass(t.class.name, carNum) != seatClass {
+			continue
+		}
+		vacant := isutrain.TrainSeats{}
+		for _, seat := range carSeats(t.class.name, carNum) {
+			if seat.IsSmokingSeat != isSmokingSeat {
+				continue
+			}
+			if s.isOccupied(date, t, carNum, seat.Row, seat.Column, from, to) {
+				continue
+			}
+			vacant = append(vacant, seat)
+			if len(vacant) == count {
+				return carNum, vacant
+			}
+		}
+	}
+	return 0, nil
+}
+
+// 予約確定
+
+func (s *Server) commitReservation(w http.ResponseWriter, r *http.Request) {
+	req := &isutrain.CommitReservationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "JSON parseに失敗しました")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservation, ok := s.reservations[req.ReservationID]
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "予約情報がみつかりません")
+		return
+	}
+	email, ok := s.loginUser(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+	if !s.isOwner(reservation, email) {
+		errorResponse(w, http.StatusForbidden, "他のユーザIDの支払いはできません")
+		return
+	}
+	if reservation.PaymentID != "" {
+		errorResponse(w, http.StatusForbidden, "既に支払いが完了している予約IDです")
+		return
+	}
+
+	paymentID, err := s.payment.pay(req.CardToken, reservation.ID, reservation.Amount)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, "決済に失敗しました。カードトークンや支払いIDが間違っている可能性があります")
+		return
+	}
+	reservation.PaymentID = paymentID
+
+	jsonResponse(w, &isutrain.CommitReservationResponse{IsOK: true})
+}
+
+// 予約キャンセル
+
+func (s *Server) cancelReservation(w http.ResponseWriter, r *http.Request, id string) {
+	reservationID, err := strconv.Atoi(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "incorrect item id")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.loginUser(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+	reservation, ok := s.reservations[reservationID]
+	if !ok || !s.isOwner(reservation, email) {
+		errorResponse(w, http.StatusBadRequest, "reservations naiyo")
+		return
+	}
+
+	if reservation.PaymentID != "" && !s.hasBug(BugNoRefund) {
+		if err := s.payment.cancel(reservation.PaymentID); err != nil {
+			errorResponse(w, http.StatusInternalServerError, "決済のキャンセルに失敗しました")
+			return
+		}
+	}
+	delete(s.reservations, reservationID)
+
+	messageResponse(w, "cancell complete")
+}
+
+// 予約詳細・一覧
+
+func (s *Server) showReservation(w http.ResponseWriter, r *http.Request, id string) {
+	reservationID, err := strconv.Atoi(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "incorrect item id")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.loginUser(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+	reservation, ok := s.reservations[reservationID]
+	if !ok || !s.isOwner(reservation, email) {
+		errorResponse(w, http.StatusNotFound, "Reservation not found")
+		return
+	}
+
+	jsonResponse(w, s.reservationResponse(reservation))
+}
+
+func (s *Server) listReservations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.loginUser(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "no session")
+		return
+	}
+
+	reservations := isutrain.ListReservationsResponse{}
+	for _, reservation := range s.reservations {
+		if reservation.Email == email {
+			reservations = append(reservations, s.reservationResponse(reservation))
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].ReservationID > reservations[j].ReservationID
+	})
+
+	jsonResponse(w, reservations)
+}
@@ -0,0 +1,118 @@
+package mock
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/conformance"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEnv(t *testing.T, bugs ...Bug) (*conformance.Env, func()) {
+	server := NewServer(30, "")
+	server.SetBugs(bugs...)
+	paymentTS := httptest.NewServer(server.PaymentHandler())
+	server.PaymentURL = paymentTS.URL
+	ts := httptest.NewServer(server.Handler())
+
+	paymentURL, err := url.Parse(paymentTS.URL)
+	assert.NoError(t, err)
+
+	env := &conformance.Env{
+		Target:  ts.URL,
+		Payment: &payment.Client{BaseURL: paymentURL},
+	}
+	return env, func() {
+		ts.Close()
+		paymentTS.Close()
+	}
+}
+
+func failedChecks(results []*conformance.Result) map[string]string {
+	failed := map[string]string{}
+	for _, r := range results {
+		if r.Failed() {
+			failed[r.Check.Name] = r.Message()
+		}
+	}
+	return failed
+}
+
+func TestServerConformance(t *testing.T) {
+	env, closeFn := newTestEnv(t)
+	defer closeFn()
+
+	results := conformance.Run(context.Background(), env, conformance.Checks())
+	assert.Empty(t, failedChecks(results))
+	for _, r := range results {
+		// 静的ファイルは配信しない
+		if r.Check.Name != "static_files" {
+			assert.False(t, r.Skipped(), r.Check.Name)
+		}
+	}
+}
+
+func TestServerBugs(t *testing.T) {
+	tests := []struct {
+		bug  Bug
+		want string
+	}{
+		{bug: BugDoubleBooking, want: "seat_overlap_rejected"},
+		{bug: BugWrongFare, want: "fare_reserved_adult"},
+		{bug: BugMissingSeats, want: "reserve_show_list"},
+	}
+	for _, tt := range tests {
+		env, closeFn := newTestEnv(t, tt.bug)
+		results := conformance.Run(context.Background(), env, conformance.Checks())
+		closeFn()
+
+		failed := failedChecks(results)
+		assert.Contains(t, failed, tt.want, "bug=%s", tt.bug)
+	}
+}
+
+func TestParseBug(t *testing.T) {
+	for _, bug := range Bugs() {
+		got, err := ParseBug(string(bug))
+		assert.NoError(t, err)
+		assert.Equal(t, bug, got)
+	}
+	_, err := ParseBug("unknown")
+	assert.Error(t, err)
+}
+
+func TestTimetable(t *testing.T) {
+	tt := newTimetable()
+	assert.Len(t, tt.trains, trainsPerDay)
+
+	// 奇数は下り、偶数は上り
+	kudari := tt.trainMap[trainKey("最速", "49")]
+	if assert.NotNil(t, kudari) {
+		assert.False(t, kudari.isNobori)
+		assert.True(t, kudari.runs(tt, "東京", "大阪"))
+		assert.False(t, kudari.runs(tt, "大阪", "東京"))
+		// 最速は止まらない
+		assert.False(t, kudari.runs(tt, "古岡", "大阪"))
+		assert.True(t, kudari.departures["東京"] < kudari.arrivals["名古屋"])
+		assert.True(t, kudari.departures["名古屋"] < kudari.arrivals["大阪"])
+	}
+
+	nobori := tt.trainMap[trainKey("最速", "12")]
+	if assert.NotNil(t, nobori) {
+		assert.True(t, nobori.isNobori)
+		assert.True(t, nobori.runs(tt, "名古屋", "東京"))
+		assert.False(t, nobori.runs(tt, "大阪", "東京"))
+		assert.True(t, nobori.departures["名古屋"] < nobori.arrivals["東京"])
+	}
+}
+
+func TestSectionOverlaps(t *testing.T) {
+	assert.True(t, sectionOverlaps(0, 10, 5, 15))
+	assert.True(t, sectionOverlaps(10, 0, 15, 5))
+	assert.True(t, sectionOverlaps(0, 10, 2, 3))
+	assert.False(t, sectionOverlaps(0, 10, 10, 15))
+	assert.False(t, sectionOverlaps(10, 15, 0, 10))
+}
@@ -0,0 +1,482 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/isutraindb"
+	"github.com/chibiegg/isucon9-final/bench/internal/util"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+)
+
+// 時刻表は webapp/sql/generators/fixture_generator.py に合わせて作る
+// ただし、乱数は使わずに毎日同じ時刻表とする
+
+const (
+	trainsPerDay      = 192
+	firstDepartureAt  = 6 * time.Hour
+	departureInterval = 10 * time.Minute
+
+	carsPerTrain = 16
+)
+
+// 列車種別ごとの速度(km/h)と停車時間
+type trainClass struct {
+	name     string
+	speed    float64
+	stopTime time.Duration
+}
+
+func (c *trainClass) stopsAt(station *isutraindb.Station) bool {
+	switch c.name {
+	case "最速":
+		return station.IsStopExpress
+	case "中間":
+		return station.IsStopSemiExpress
+	default:
+		return station.IsStopLocal
+	}
+}
+
+var (
+	expressClass     = &trainClass{name: "最速", speed: 500, stopTime: 1 * time.Minute}
+	semiExpressClass = &trainClass{name: "中間", speed: 480, stopTime: 2 * time.Minute}
+	localClass       = &trainClass{name: "遅いやつ", speed: 480, stopTime: 2 * time.Minute}
+
+	// 上下線の組ごとに、順番に割り当てる. 最速が半分になるようにしている
+	trainClassPattern = []*trainClass{localClass, expressClass, expressClass, semiExpressClass}
+	// 発駅と終点の組. 上りは逆向きになる
+	trainSectionPattern = [][2]string{
+		{"東京", "大阪"},
+		{"東京", "大阪"},
+		{"東京", "名古屋"},
+		{"東京", "京都"},
+	}
+)
+
+type train struct {
+	class    *trainClass
+	name     string
+	isNobori bool
+	start    string
+	last     string
+	// 停車駅ごとの、0時からの到着時刻と出発時刻
+	arrivals   map[string]time.Duration
+	departures map[string]time.Duration
+}
+
+// runs は、列車がfromからtoまで停車しながら走るか否かを返します
+func (t *train) runs(tt *timetable, from, to string) bool {
+	if _, ok := t.departures[from]; !ok {
+		return false
+	}
+	if _, ok := t.arrivals[to]; !ok {
+		return false
+	}
+	if t.isNobori {
+		return tt.stationIdx[from] > tt.stationIdx[to]
+	}
+	return tt.stationIdx[from] < tt.stationIdx[to]
+}
+
+type timetable struct {
+	stations   []*isutraindb.Station
+	stationIdx map[string]int
+	trains     []*train
+	trainMap   map[string]*train
+}
+
+func trainKey(trainClass, trainName string) string {
+	return trainClass + "/" + trainName
+}
+
+func newTimetable() *timetable {
+	tt := &timetable{
+		stations:   isutraindb.Stations(),
+		stationIdx: map[string]int{},
+		trainMap:   map[string]*train{},
+	}
+	for i, station := range tt.stations {
+		tt.stationIdx[station.Name] = i
+	}
+
+	for i := 1; i <= trainsPerDay; i++ {
+		var (
+			// 奇数が下り、偶数が上りで、同じ時刻に出発する
+			pair        = (i + 1) / 2
+			class       = trainClassPattern[pair%len(trainClassPattern)]
+			section     = trainSectionPattern[pair%len(trainSectionPattern)]
+			departureAt = firstDepartureAt + time.Duration(pair-1)*departureInterval
+			t           = &train{
+				class:      class,
+				name:       strconv.Itoa(i),
+				isNobori:   i%2 == 0,
+				start:      section[0],
+				last:       section[1],
+				arrivals:   map[string]time.Duration{},
+				departures: map[string]time.Duration{},
+			}
+		)
+		if t.isNobori {
+			t.start, t.last = t.last, t.start
+		}
+
+		var (
+			startIdx = tt.stationIdx[t.start]
+			lastIdx  = tt.stationIdx[t.last]
+			step     = 1
+		)
+		if t.isNobori {
+			step = -1
+		}
+		var (
+			now          = departureAt
+			lastDistance = tt.stations[startIdx].Distance
+		)
+		for idx := startIdx; ; idx += step {
+			station := tt.stations[idx]
+			if idx == startIdx {
+				t.arrivals[station.Name] = now
+				t.departures[station.Name] = now
+			} else if class.stopsAt(station) || idx == lastIdx {
+				distance := station.Distance - lastDistance
+				if distance < 0 {
+					distance = -distance
+				}
+				now += time.Duration(distance / class.speed * float64(time.Hour))
+				t.arrivals[station.Name] = now
+				if idx != lastIdx {
+					now += class.stopTime
+					t.departures[station.Name] = now
+				}
+				lastDistance = station.Distance
+			}
+			if idx == lastIdx {
+				break
+			}
+		}
+
+		tt.trains = append(tt.trains, t)
+		tt.trainMap[trainKey(class.name, t.name)] = t
+	}
+	return tt
+}
+
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+func clockOf(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// 座席
+
+// carLayout は号車の列数と座席の列の文字、喫煙席が始まる列を返します. 喫煙席がなければ0を返します
+func carLayout(carNum int) (rows int, columns string, smokingFrom int) {
+	switch carNum {
+	case 1, 16:
+		// 先頭車両
+		return 13, "ABCDE", 0
+	case 8, 9, 10:
+		// グリーン車は横に4座席
+		return 17, "ABCD", 0
+	case 3, 5, 7, 11, 13, 15:
+		// トイレ車両
+		return 16, "ABCDE", 11
+	default:
+		return 20, "ABCDE", 0
+	}
+}
+
+func isValidCarNumber(carNum int) bool {
+	return carNum >= 1 && carNum <= carsPerTrain
+}
+
+// carSeats は号車の全座席を返します
+func carSeats(trainClass string, carNum int) isutrain.TrainSeats {
+	var (
+		seatClass                  = isutraindb.GetSeatClass(trainClass, carNum)
+		rows, columns, smokingFrom = carLayout(carNum)
+		seats                      = isutrain.TrainSeats{}
+	)
+	for row := 1; row <= rows; row++ {
+		for _, column := range columns {
+			seats = append(seats, &isutrain.TrainSeat{
+				Row:           row,
+				Column:        string(column),
+				Class:         seatClass,
+				IsSmokingSeat: smokingFrom > 0 && row >= smokingFrom,
+			})
+		}
+	}
+	return seats
+}
+
+func findSeat(seats isutrain.TrainSeats, row int, column string) (*isutrain.TrainSeat, bool) {
+	for _, seat := range seats {
+		if seat.Row == row && seat.Column == column {
+			return seat, true
+		}
+	}
+	return nil, false
+}
+
+// sectionOverlaps は、東京からの順番で表した2つの乗車区間が重なるか否かを返します
+func sectionOverlaps(aFrom, aTo, bFrom, bTo int) bool {
+	if aFrom > aTo {
+		aFrom, aTo = aTo, aFrom
+	}
+	if bFrom > bTo {
+		bFrom, bTo = bTo, bFrom
+	}
+	return aFrom < bTo && bFrom < aTo
+}
+
+// isOccupied は座席がfromからtoの区間で予約済みか否かを返します. s.mu を取得した状態で呼び出します
+func (s *Server) isOccupied(date time.Time, t *train, carNum, row int, column, from, to string) bool {
+	var (
+		fromIdx = s.tt.stationIdx[from]
+		toIdx   = s.tt.stationIdx[to]
+	)
+	for _, reservation := range s.reservations {
+		if !reservation.Date.Equal(date) || reservation.TrainClass != t.class.name || reservation.TrainName != t.name {
+			continue
+		}
+		if reservation.CarNumber != carNum {
+			continue
+		}
+		if !sectionOverlaps(fromIdx, toIdx, s.tt.stationIdx[reservation.Departure], s.tt.stationIdx[reservation.Arrival]) {
+			continue
+		}
+		if _, ok := findSeat(reservation.Seats, row, column); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// availableSeatCount は、座席種別と喫煙の有無ごとに空席数を数えます. s.mu を取得した状態で呼び出します
+func (s *Server) availableSeatCount(date time.Time, t *train, from, to, seatClass string, isSmokingSeat bool) int {
+	count := 0
+	for carNum := 1; carNum <= carsPerTrain; carNum++ {
+		if isutraindb.GetSeatClass(t.class.name, carNum) != seatClass {
+			continue
+		}
+		for _, seat := range carSeats(t.class.name, carNum) {
+			if seat.IsSmokingSeat != isSmokingSeat {
+				continue
+			}
+			if !s.isOccupied(date, t, carNum, seat.Row, seat.Column, from, to) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// availability は、空席数から列車検索で返す空席状況を返します
+func (s *Server) availability(count int) string {
+	if s.hasBug(BugWrongAvailability) {
+		return strconv.Itoa(count)
+	}
+	return availabilitySymbol(count)
+}
+
+func availabilitySymbol(count int) string {
+	switch {
+	case count == 0:
+		return "×"
+	case count < 10:
+		return "△"
+	default:
+		return "○"
+	}
+}
+
+// 日付
+
+// reservationDate は日時から予約の日付を返します. 予約可能期間外ならfalseを返します
+func (s *Server) reservationDate(t time.Time) (time.Time, bool) {
+	var (
+		date  = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		start = config.ReservationStartDate
+		end   = start.AddDate(0, 0, s.AvailableDays)
+	)
+	if date.Before(start) || !date.Before(end) {
+		return date, false
+	}
+	return date, true
+}
+
+// 運賃
+
+// seatFare は大人と子供を考慮した運賃です. 子供は半額
+func seatFare(fare, adult, child int) int {
+	return fare*adult + fare/2*child
+}
+
+// 列車検索
+
+func (s *Server) searchTrains(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	useAt, err := util.ParseISO8601(query.Get("use_at"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	date, ok := s.reservationDate(useAt)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "予約可能期間外です")
+		return
+	}
+
+	var (
+		trainClass = query.Get("train_class")
+		from       = query.Get("from")
+		to         = query.Get("to")
+	)
+	adult, _ := strconv.Atoi(query.Get("adult"))
+	child, _ := strconv.Atoi(query.Get("child"))
+	if _, ok := s.station(from); !ok {
+		errorResponse(w, http.StatusBadRequest, "乗車駅が存在しません")
+		return
+	}
+	if _, ok := s.station(to); !ok {
+		errorResponse(w, http.StatusBadRequest, "降車駅が存在しません")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trains := isutrain.SearchTrainsResponse{}
+	for _, t := range s.tt.trains {
+		if trainClass != "" && t.class.name != trainClass {
+			continue
+		}
+		if !t.runs(s.tt, from, to) {
+			continue
+		}
+		if t.departures[from] <= clockOf(useAt) {
+			// 乗りたい時刻より出発時刻が前なので除外
+			continue
+		}
+
+		seatAvailability := map[string]string{
+			string(isutrain.SaPremium):       s.availability(s.availableSeatCount(date, t, from, to, "premium", false)),
+			string(isutrain.SaPremiumSmoke):  s.availability(s.availableSeatCount(date, t, from, to, "premium", true)),
+			string(isutrain.SaReserved):      s.availability(s.availableSeatCount(date, t, from, to, "reserved", false)),
+			string(isutrain.SaReservedSmoke): s.availability(s.availableSeatCount(date, t, from, to, "reserved", true)),
+			string(isutrain.SaNonReserved):   "○",
+		}
+
+		fareInformation := map[string]int{}
+		for key, seatClass := range map[isutrain.SeatAvailability]string{
+			isutrain.SaPremium:       "premium",
+			isutrain.SaPremiumSmoke:  "premium",
+			isutrain.SaReserved:      "reserved",
+			isutrain.SaReservedSmoke: "reserved",
+			isutrain.SaNonReserved:   "non-reserved",
+		} {
+			fare, err := isutraindb.GetFare(0, date, from, to, t.class.name, seatClass)
+			if err != nil {
+				errorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			fareInformation[string(key)] = seatFare(fare, adult, child)
+		}
+
+		trains = append(trains, &isutrain.Train{
+			Class:            t.class.name,
+			Name:             t.name,
+			Start:            t.start,
+			Last:             t.last,
+			Departure:        from,
+			Arrival:          to,
+			DepartedAt:       formatClock(t.departures[from]),
+			ArrivedAt:        formatClock(t.arrivals[to]),
+			SeatAvailability: seatAvailability,
+			FareInformation:  fareInformation,
+		})
+		if len(trains) >= 10 {
+			break
+		}
+	}
+
+	jsonResponse(w, trains)
+}
+
+// 座席検索
+
+func (s *Server) searchTrainSeats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	d, err := util.ParseISO8601(query.Get("date"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	date, ok := s.reservationDate(d)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "予約可能期間外です")
+		return
+	}
+
+	var (
+		trainClass = query.Get("train_class")
+		trainName  = query.Get("train_name")
+		from       = query.Get("from")
+		to         = query.Get("to")
+	)
+	carNum, _ := strconv.Atoi(query.Get("car_number"))
+
+	t, ok := s.tt.trainMap[trainKey(trainClass, trainName)]
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "列車が存在しません")
+		return
+	}
+	if _, ok := s.station(from); !ok {
+		errorResponse(w, http.StatusBadRequest, "乗車駅が存在しません")
+		return
+	}
+	if _, ok := s.station(to); !ok {
+		errorResponse(w, http.StatusBadRequest, "降車駅が存在しません")
+		return
+	}
+	if !t.runs(s.tt, from, to) {
+		errorResponse(w, http.StatusBadRequest, "リクエストされた区間に列車が運行していない区間が含まれています")
+		return
+	}
+	if !isValidCarNumber(carNum) {
+		errorResponse(w, http.StatusBadRequest, "号車が不正です")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seats := carSeats(t.class.name, carNum)
+	for _, seat := range seats {
+		seat.IsOccupied = s.isOccupied(date, t, carNum, seat.Row, seat.Column, from, to)
+	}
+	cars := isutrain.TrainCars{}
+	for i := 1; i <= carsPerTrain; i++ {
+		cars = append(cars, &isutrain.TrainCar{
+			CarNumber: i,
+			SeatClass: isutraindb.GetSeatClass(t.class.name, i),
+		})
+	}
+
+	jsonResponse(w, &isutrain.SearchTrainSeatsResponse{
+		Date:       date.Format("2006/01/02"),
+		TrainClass: t.class.name,
+		TrainName:  t.name,
+		CarNumber:  carNum,
+		Seats:      seats,
+		Cars:       cars,
+	})
+}
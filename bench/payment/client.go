@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"time"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
@@ -18,40 +20,96 @@ var (
 	ErrInitializePayment = errors.New("決済情報の初期化に失敗しました")
 	ErrPaymentResult     = errors.New("課金APIの処理結果を取得できませんでした")
 	ErrRegistCard        = errors.New("クレジットカードの登録及びトークン発行に失敗しました")
+	ErrPaymentNotReady   = errors.New("課金APIが起動していません")
 )
 
 type Client struct {
-	BaseURL *url.URL
+	BaseURL    *url.URL
+	APIKey     string
+	MerchantID string
 }
 
-func NewClient() (*Client, error) {
-	u, err := url.Parse(config.PaymentBaseURL)
+// NewClient は、contextのベンチマーク対象の課金APIのクライアントを作成します
+func NewClient(ctx context.Context) (*Client, error) {
+	target := config.FromContext(ctx)
+	u, err := url.Parse(target.PaymentBaseURL)
 	if err != nil {
-		return nil, bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "課金クライアントが作成できません"))
+		return nil, bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err, "課金クライアントが作成できません"))
 	}
 
 	return &Client{
-		BaseURL: u,
+		BaseURL:    u,
+		APIKey:     target.PaymentAPIKey,
+		MerchantID: target.PaymentMerchantID,
 	}, nil
 }
 
-func (c *Client) Initialize() error {
+// newRequest は課金APIへのリクエストを作成します. 管理キーが指定されていればヘッダに付与します
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
 	u := *c.BaseURL
-	u.Path = filepath.Join(u.Path, endpoint.PaymentInitializePath)
+	u.Path = filepath.Join(u.Path, path)
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	req, err := http.NewRequest(method, u.String(), body)
 	if err != nil {
-		return bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "課金APIへのinitializeリクエストが失敗しました. 運営に確認をお願いいたします"))
+		return nil, err
 	}
+	if c.APIKey != "" {
+		req.Header.Set("X-Api-Key", c.APIKey)
+	}
+	return req.WithContext(ctx), nil
+}
+
+// merchantQuery は、対象のマーチャントに絞り込むためのクエリを返します
+func (c *Client) merchantQuery() string {
+	if c.MerchantID == "" {
+		return ""
+	}
+	return url.Values{"merchant_id": []string{c.MerchantID}}.Encode()
+}
+
+// WaitReady は、課金APIの /healthz が200を返すまでtimeoutの間待ちます
+func (c *Client) WaitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		req, err := c.newRequest(ctx, http.MethodGet, endpoint.PaymentHealthzPath, nil)
+		if err != nil {
+			return bencherror.InitializeErrs(ctx).AddError(bencherror.NewCriticalError(err, "課金APIのヘルスチェックに失敗しました. 運営に確認をお願いいたします"))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return bencherror.InitializeErrs(ctx).AddError(bencherror.NewCriticalError(ErrPaymentNotReady, "課金APIが%s以内に起動しませんでした. 運営に確認をお願いいたします", timeout))
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) Initialize(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint.PaymentInitializePath, nil)
+	if err != nil {
+		return bencherror.InitializeErrs(ctx).AddError(bencherror.NewCriticalError(err, "課金APIへのinitializeリクエストが失敗しました. 運営に確認をお願いいたします"))
+	}
+	req.URL.RawQuery = c.merchantQuery()
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(err, "課金APIへのinitializeリクエストが失敗しました. 運営に確認をお願いいたします"))
+		return bencherror.InitializeErrs(ctx).AddError(bencherror.NewCriticalError(err, "課金APIへのinitializeリクエストが失敗しました. 運営に確認をお願いいたします"))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return bencherror.InitializeErrs.AddError(bencherror.NewCriticalError(
+		return bencherror.InitializeErrs(ctx).AddError(bencherror.NewCriticalError(
 			ErrPaymentResult,
 			"課金APIへのinitialize時、不正なステータスコードが返却されました(got=%d, want=%d). 運営に確認をお願いいたします",
 			resp.StatusCode,
@@ -63,9 +121,6 @@ func (c *Client) Initialize() error {
 }
 
 func (c *Client) RegistCard(ctx context.Context, cardNumber, cvv, expiryDate string) (string, error) {
-	u := *c.BaseURL
-	u.Path = filepath.Join(u.Path, endpoint.PaymentRegistCardPath)
-
 	b, err := json.Marshal(map[string]*CardInformation{
 		"card_information": &CardInformation{
 			CardNumber: cardNumber,
@@ -77,7 +132,7 @@ func (c *Client) RegistCard(ctx context.Context, cardNumber, cvv, expiryDate str
 		return "", bencherror.NewCriticalError(ErrRegistCard, "課金APIへのRegistCard時、Marshal処理で失敗しました. 運営に確認をお願いいたします")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewBuffer(b))
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint.PaymentRegistCardPath, bytes.NewBuffer(b))
 	if err != nil {
 		return "", bencherror.NewCriticalError(ErrRegistCard, "課金APIにクレジットカードを登録できませんでした. 運営に確認をお願いいたします")
 	}
@@ -110,13 +165,11 @@ func (c *Client) RegistCard(ctx context.Context, cardNumber, cvv, expiryDate str
 }
 
 func (c *Client) Result(ctx context.Context) (*PaymentResult, error) {
-	u := *c.BaseURL
-	u.Path = filepath.Join(u.Path, endpoint.PaymentResultPath)
-
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint.PaymentResultPath, nil)
 	if err != nil {
 		return nil, bencherror.NewCriticalError(err, "課金APIから決済結果を取得できませんでした. 運営に確認をお願いいたします")
 	}
+	req.URL.RawQuery = c.merchantQuery()
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -144,3 +197,55 @@ func (c *Client) Result(ctx context.Context) (*PaymentResult, error) {
 
 	return result, nil
 }
+
+// StreamResults は、課金APIから決済結果を1件ずつ受け取ってfnに渡し、最後に集計結果を返します
+func (c *Client) StreamResults(ctx context.Context, fn func(rawData *RawData) error) (*ResultSummary, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint.PaymentStreamPath, nil)
+	if err != nil {
+		return nil, bencherror.NewCriticalError(err, "課金APIから決済結果を取得できませんでした. 運営に確認をお願いいたします")
+	}
+	req.URL.RawQuery = c.merchantQuery()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, bencherror.NewCriticalError(err, "課金APIへのリクエストに失敗しました. 運営に確認をお願いいたします")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, bencherror.NewCriticalError(
+			ErrPaymentResult,
+			"課金APIから決済結果取得時、不正なステータスコード(got=%d, want=%d)が返却されました. 運営に確認をお願いいたします",
+			resp.StatusCode,
+			http.StatusOK,
+		)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var chunk struct {
+			Result *StreamResultsResponse `json:"result"`
+			Error  *StreamError           `json:"error"`
+		}
+		if err := dec.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				// 集計結果が返る前にストリームが終了した
+				return nil, bencherror.NewCriticalError(ErrPaymentResult, "課金APIの決済結果が途中で終了しました. 運営に確認をお願いいたします")
+			}
+			return nil, bencherror.NewCriticalError(err, "課金APIのレスポンスが不正です.運営に確認をお願いいたします")
+		}
+
+		switch {
+		case chunk.Error != nil:
+			return nil, bencherror.NewCriticalError(ErrPaymentResult, "課金APIから決済結果取得中にエラーが返却されました(%s). 運営に確認をお願いいたします", chunk.Error.Message)
+		case chunk.Result == nil:
+			return nil, bencherror.NewCriticalError(ErrPaymentResult, "課金APIのレスポンスが不正です.運営に確認をお願いいたします")
+		case chunk.Result.Summary != nil:
+			return chunk.Result.Summary, nil
+		case chunk.Result.RawData != nil:
+			if err := fn(chunk.Result.RawData); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
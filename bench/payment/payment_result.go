@@ -17,13 +17,44 @@ type CardInformation struct {
 }
 
 type RawData struct {
+	PaymentID   string              `json:"payment_id"`
 	PaymentInfo *PaymentInformation `json:"payment_information"`
 	CardInfo    *CardInformation    `json:"card_information"`
 }
 
+// DuplicatePayment は、同一予約に対して有効な決済が複数存在すること(多重課金)を表します
+type DuplicatePayment struct {
+	ReservationID int      `json:"reservation_id"`
+	PaymentIDs    []string `json:"payment_id"`
+}
+
 type PaymentResult struct {
-	RawData []*RawData `json:"raw_data"`
-	IsOK    bool       `json:"is_ok"`
+	RawData    []*RawData          `json:"raw_data"`
+	IsOK       bool                `json:"is_ok"`
+	Duplicates []*DuplicatePayment `json:"duplicates"`
+	Replayed   int                 `json:"replayed"`
+	NextCursor string              `json:"next_cursor"`
+}
+
+// ResultSummary は、決済結果のストリームの最後に返される集計です
+type ResultSummary struct {
+	Count      int                 `json:"count"`
+	Duplicates []*DuplicatePayment `json:"duplicates"`
+	Replayed   int                 `json:"replayed"`
+}
+
+// StreamResultsResponse は、決済結果のストリームの1メッセージです
+type StreamResultsResponse struct {
+	RawData *RawData       `json:"raw_data"`
+	Summary *ResultSummary `json:"summary"`
+}
+
+// StreamError は、決済結果のストリームの途中で発生したエラーです
+type StreamError struct {
+	GrpcCode   int    `json:"grpc_code"`
+	HTTPCode   int    `json:"http_code"`
+	Message    string `json:"message"`
+	HTTPStatus string `json:"http_status"`
 }
 
 type RegistCardResponse struct {
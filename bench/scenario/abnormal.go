@@ -2,7 +2,6 @@ package scenario
 
 import (
 	"context"
-	"math/rand"
 	"net/http"
 	"time"
 
@@ -20,22 +19,22 @@ func AbnormalLoginScenario(ctx context.Context) error {
 		password, err2 = util.SecureRandomStr(10)
 	)
 	if err1 != nil {
-		bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err1, "ランダム文字列生成でエラーが発生しました"))
+		bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err1, "ランダム文字列生成でエラーが発生しました"))
 		return nil
 	}
 	if err2 != nil {
-		bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err2, "ランダム文字列生成でエラーが発生しました"))
+		bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err2, "ランダム文字列生成でエラーが発生しました"))
 		return nil
 	}
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
 	err = client.Login(ctx, email, password, isutrain.StatusCodeOpt(http.StatusUnauthorized))
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -43,8 +42,9 @@ func AbnormalLoginScenario(ctx context.Context) error {
 
 // 指定列車の運用区間外で予約を取ろうとして、きちんと弾かれるかチェック
 func AbnormalReserveWrongSection(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -53,37 +53,37 @@ func AbnormalReserveWrongSection(ctx context.Context) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	useAt := xrandom.GetRandomUseAt()
-	adult, child := xrandom.GetRandomNumberOfPeople()
+	useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+	adult, child := rnd.GetRandomNumberOfPeople()
 	trains, err := client.SearchTrains(ctx, useAt, "東京", "大阪", "最速", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
 	carNum := 5
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, "東京", "大阪")
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -96,7 +96,7 @@ func AbnormalReserveWrongSection(ctx context.Context) error {
 		isutrain.StatusCodeOpt(http.StatusBadRequest))
 
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -104,42 +104,43 @@ func AbnormalReserveWrongSection(ctx context.Context) error {
 
 // 列車の指定号車に存在しない席を予約しようとし、エラーになるかチェック
 func AbnormalReserveWrongSeat(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if config.Debug {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	useAt := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
 	departure, arrival := "東京", "大阪"
-	adult, child := xrandom.GetRandomNumberOfPeople()
+	adult, child := rnd.GetRandomNumberOfPeople()
 	trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "最速", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
-	carNum := xrandom.GetRandomCarNumber(train.Class, "reserved")
+	carNum := rnd.GetRandomCarNumber(train.Class, "reserved")
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, departure, arrival)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -155,7 +156,7 @@ func AbnormalReserveWrongSeat(ctx context.Context) error {
 		carNum, 1, 1,
 		isutrain.StatusCodeOpt(http.StatusNotFound))
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -2,7 +2,6 @@ package scenario
 
 import (
 	"context"
-	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +20,8 @@ import (
 
 // 検索しまくる
 func AttackSearchScenario(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
+
 	var searchGrp sync.WaitGroup
 
 	// SearchTrains
@@ -31,9 +32,9 @@ func AttackSearchScenario(ctx context.Context) error {
 		go func() {
 			defer searchGrp.Done()
 
-			client, err := isutrain.NewClient()
+			client, err := isutrain.NewClient(ctx)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
@@ -41,14 +42,14 @@ func AttackSearchScenario(ctx context.Context) error {
 				client.ReplaceMockTransport()
 			}
 
-			user, err := xrandom.GetRandomUser()
+			user, err := rnd.GetRandomUser()
 			if err != nil {
-				bencherror.SystemErrs.AddError(err)
+				bencherror.SystemErrs(ctx).AddError(err)
 				return
 			}
 			err = client.Login(ctx, user.Email, user.Password)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
@@ -58,13 +59,13 @@ func AttackSearchScenario(ctx context.Context) error {
 					return
 				default:
 					var (
-						useAt        = xrandom.GetRandomUseAt()
-						from, to     = xrandom.GetRandomSection()
-						adult, child = xrandom.GetRandomNumberOfPeople()
+						useAt        = rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+						from, to     = rnd.GetRandomSection()
+						adult, child = rnd.GetRandomNumberOfPeople()
 					)
 					_, err := client.SearchTrains(searchTrainCtx, useAt, from, to, "", adult, child)
 					if err != nil {
-						bencherror.BenchmarkErrs.AddError(err)
+						bencherror.BenchmarkErrs(ctx).AddError(err)
 					}
 				}
 			}
@@ -79,9 +80,9 @@ func AttackSearchScenario(ctx context.Context) error {
 		go func() {
 			defer searchGrp.Done()
 
-			client, err := isutrain.NewClient()
+			client, err := isutrain.NewClient(ctx)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
@@ -89,14 +90,14 @@ func AttackSearchScenario(ctx context.Context) error {
 				client.ReplaceMockTransport()
 			}
 
-			user, err := xrandom.GetRandomUser()
+			user, err := rnd.GetRandomUser()
 			if err != nil {
-				bencherror.SystemErrs.AddError(bencherror.NewCriticalError(err, "ユーザを作成できません"))
+				bencherror.SystemErrs(ctx).AddError(bencherror.NewCriticalError(err, "ユーザを作成できません"))
 				return
 			}
 			err = client.Login(ctx, user.Email, user.Password)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
@@ -106,25 +107,25 @@ func AttackSearchScenario(ctx context.Context) error {
 					return
 				default:
 					var (
-						useAt              = xrandom.GetRandomUseAt()
-						departure, arrival = xrandom.GetRandomSection()
-						adult, child       = xrandom.GetRandomNumberOfPeople()
+						useAt              = rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+						departure, arrival = rnd.GetRandomSection()
+						adult, child       = rnd.GetRandomNumberOfPeople()
 					)
 					trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "", adult, child)
 					if err != nil {
-						bencherror.BenchmarkErrs.AddError(err)
+						bencherror.BenchmarkErrs(ctx).AddError(err)
 					}
 					if len(trains) == 0 {
 						break
 					}
 
-					trainIdx := rand.Intn(len(trains))
+					trainIdx := rnd.Intn(len(trains))
 					train := trains[trainIdx]
 					carNum := 8
 
 					_, err = client.SearchTrainSeats(listTrainSeatsCtx, useAt, train.Class, train.Name, carNum, train.Departure, train.Arrival)
 					if err != nil {
-						bencherror.BenchmarkErrs.AddError(err)
+						bencherror.BenchmarkErrs(ctx).AddError(err)
 					}
 				}
 			}
@@ -137,16 +138,18 @@ func AttackSearchScenario(ctx context.Context) error {
 
 // ログインしまくる (ログイン失敗もする. また、失敗するはずが成功したりしたら失格扱いにする)
 func AttackLoginScenario(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
+
 	var loginGrp sync.WaitGroup
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
 	err = client.Signup(ctx, "aluser@example.com", "aluser")
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	// 正常ログイン
@@ -169,11 +172,11 @@ func AttackLoginScenario(ctx context.Context) error {
 
 					err = client.Login(loginCtx, "aluser@example.com", "aluser")
 					if err != nil {
-						bencherror.BenchmarkErrs.AddError(err)
+						bencherror.BenchmarkErrs(ctx).AddError(err)
 						return
 					}
 
-					msecs := rand.Intn(1000)
+					msecs := rnd.Intn(1000)
 					time.Sleep(time.Duration(msecs) * time.Millisecond)
 				}
 			}
@@ -197,12 +200,14 @@ func AttackListReservationsScenario(ctx context.Context) error {
 
 // AttackReserveRaceCondition は、予約にて、一気にリクエストを送ることで競合が発生しないかチェックするシナリオ
 func AttackReserveRaceCondition(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
+
 	lgr := zap.S()
 
 	// ISUTRAIN APIのクライアントを作成
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		// 実行中のエラーは `bencherror.BenchmarkErrs.AddError(err)` に投げる
+		// 実行中のエラーは `bencherror.BenchmarkErrs(ctx).AddError(err)` に投げる
 		return err
 	}
 
@@ -213,43 +218,43 @@ func AttackReserveRaceCondition(ctx context.Context) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	useAt := xrandom.GetRandomUseAt()
-	departure, arrival := xrandom.GetRandomSection()
-	adult, child := xrandom.GetRandomNumberOfPeople()
+	useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+	departure, arrival := rnd.GetRandomSection()
+	adult, child := rnd.GetRandomNumberOfPeople()
 	trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "遅いやつ", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if len(trains) == 0 {
 		err := bencherror.NewSimpleCriticalError("GET %s: 列車が１件もヒットしませんでした", endpoint.GetPath(endpoint.SearchTrains))
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
 	carNum := 9
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, departure, arrival)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -276,10 +281,10 @@ func AttackReserveRaceCondition(ctx context.Context) error {
 	wg.Wait()
 
 	if successCount == 0 {
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleApplicationError("予約できませんでした"))
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleApplicationError("予約できませんでした"))
 	} else if successCount > 1 {
 		lgr.Info("多重発券されました")
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleCriticalError("多重発券されました"))
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleCriticalError("多重発券されました"))
 	}
 
 	return nil
@@ -288,13 +293,15 @@ func AttackReserveRaceCondition(ctx context.Context) error {
 // 他人の予約をキャンセルしようとする
 // ちゃんと弾けなかったら失格
 func AttackReserveForOtherReservation(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
+
 	// lgr := zap.S()
 
 	// ISUTRAIN APIのクライアントを作成
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		// 実行中のエラーは `bencherror.BenchmarkErrs.AddError(err)` に投げる
-		return bencherror.BenchmarkErrs.AddError(err)
+		// 実行中のエラーは `bencherror.BenchmarkErrs(ctx).AddError(err)` に投げる
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	// デバッグの場合はモックに差し替える
@@ -305,45 +312,45 @@ func AttackReserveForOtherReservation(ctx context.Context) error {
 	}
 
 	var (
-		user1, user1Err = xrandom.GetRandomUser()
-		user2, user2Err = xrandom.GetRandomUser()
+		user1, user1Err = rnd.GetRandomUser()
+		user2, user2Err = rnd.GetRandomUser()
 	)
 	if user1Err != nil {
-		bencherror.SystemErrs.AddError(user1Err)
+		bencherror.SystemErrs(ctx).AddError(user1Err)
 		return nil
 	}
 	if user2Err != nil {
-		bencherror.SystemErrs.AddError(user2Err)
+		bencherror.SystemErrs(ctx).AddError(user2Err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user1)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	useAt := xrandom.GetRandomUseAt()
-	departure, arrival := xrandom.GetRandomSection()
+	useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+	departure, arrival := rnd.GetRandomSection()
 	reservation, err := createSimpleReservation(ctx, client, user1, useAt, departure, arrival, "遅いやつ", 1, 1)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.Logout(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	// 異なるユーザーでログインする
 	err = registerUserAndLogin(ctx, client, user2)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.CancelReservation(ctx, reservation.ReservationID)
 	if err == nil {
 		err = bencherror.NewSimpleCriticalError("他のユーザーの予約がキャンセルできました")
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -18,6 +18,7 @@ var (
 	ErrInvalidReservationForBenchCache              = errors.New("予約における計算結果が")
 	ErrNoReservationPayments                        = errors.New("予約に紐づく課金情報がありません")
 	ErrCanceledReservationExistsPaymentInformations = errors.New("キャンセルされた予約が課金情報に含まれています")
+	ErrDuplicatePayment                             = errors.New("同一の予約に対して多重に課金されています")
 )
 
 // FinalCheck は、課金サービスとwebappとで決済情報を突き合わせ、売上を計上します
@@ -28,21 +29,40 @@ func FinalCheck(ctx context.Context, isutrainClient *isutrain.Client, paymentCli
 
 func finalcheckPayment(ctx context.Context, paymentClient *payment.Client) error {
 	lgr := zap.S()
-	//
-	paymentAPIResult, err := paymentClient.Result(ctx)
+
+	// 決済結果を1件ずつ受け取り、予約IDごとにまとめる
+	paymentsByReservation := map[int][]*payment.PaymentInformation{}
+	summary, err := paymentClient.StreamResults(ctx, func(rawData *payment.RawData) error {
+		if rawData.PaymentInfo == nil {
+			return nil
+		}
+		reservationID := rawData.PaymentInfo.ReservationID
+		paymentsByReservation[reservationID] = append(paymentsByReservation[reservationID], rawData.PaymentInfo)
+		return nil
+	})
 	if err != nil {
-		return bencherror.FinalCheckErrs.AddError(bencherror.NewCriticalError(err, "課金APIから決済結果を取得できませんでした"))
+		return bencherror.FinalCheckErrs(ctx).AddError(bencherror.NewCriticalError(err, "課金APIから決済結果を取得できませんでした"))
+	}
+
+	if isutrain.ReservationCacheFromContext(ctx).CommitedLen() != 0 && summary.Count == 0 {
+		lgr.Warnf("ReservationCacheと課金APIのRawDataが不一致: 予約キャッシュ件数=%d に対し、 課金APIのデータ件数が0", isutrain.ReservationCacheFromContext(ctx).Len())
+		return bencherror.FinalCheckErrs(ctx).AddError(bencherror.NewCriticalError(ErrInvalidReservationForPaymentAPI, "成功した予約が存在するはずですが、課金APIには予約が記録されていませんでした"))
+	}
+
+	if summary.Replayed > 0 {
+		lgr.Infof("課金APIへの決済リクエストの再送を検出: %d件", summary.Replayed)
 	}
 
-	if isutrain.ReservationCache.CommitedLen() != 0 && len(paymentAPIResult.RawData) == 0 {
-		lgr.Warnf("ReservationCacheと課金APIのRawDataが不一致: 予約キャッシュ件数=%d に対し、 課金APIのデータ件数が0", isutrain.ReservationCache.Len())
-		return bencherror.FinalCheckErrs.AddError(bencherror.NewCriticalError(ErrInvalidReservationForPaymentAPI, "成功した予約が存在するはずですが、課金APIには予約が記録されていませんでした"))
+	// 同一予約への多重課金がないことをチェック
+	for _, duplicate := range summary.Duplicates {
+		lgr.Warnf("予約 %d に対して多重課金されています: payment_ids=%v", duplicate.ReservationID, duplicate.PaymentIDs)
+		bencherror.FinalCheckErrs(ctx).AddError(bencherror.NewCriticalError(ErrDuplicatePayment, "予約 %d に対して %d 件の決済が行われています", duplicate.ReservationID, len(duplicate.PaymentIDs)))
 	}
 
 	eg := &errgroup.Group{}
 
 	// commitされた予約について整合性チェック
-	isutrain.ReservationCache.RangeCommited(func(reservation *isutrain.ReservationCacheEntry) {
+	isutrain.ReservationCacheFromContext(ctx).RangeCommited(func(reservation *isutrain.ReservationCacheEntry) {
 		var (
 			reservationID = reservation.ID
 			amount, err   = reservation.Amount()
@@ -50,24 +70,18 @@ func finalcheckPayment(ctx context.Context, paymentClient *payment.Client) error
 		if err != nil {
 			// FIXME: Slack通知
 			lgr.Warnf("決済情報の整合性チェックでエラー: %s", err.Error())
-			bencherror.FinalCheckErrs.AddError(bencherror.NewCriticalError(err, "予約の運賃取得に失敗しました"))
+			bencherror.FinalCheckErrs(ctx).AddError(bencherror.NewCriticalError(err, "予約の運賃取得に失敗しました"))
 			return
 		}
 
 		eg.Go(func() error {
-			for _, rawData := range paymentAPIResult.RawData {
-				if rawData.PaymentInfo == nil {
-					continue
-				}
-				if rawData.PaymentInfo.ReservationID != reservationID {
-					continue
-				}
-				if rawData.PaymentInfo.IsCanceled {
+			for _, paymentInfo := range paymentsByReservation[reservationID] {
+				if paymentInfo.IsCanceled {
 					// Commitしたものだけ見るので、Cancelされたものは無視する
 					continue
 				}
-				if rawData.PaymentInfo.Amount != int64(amount) {
-					lgr.Warnf("reservation_id (payment=%d, cache=%d): not same amount %d != %d", rawData.PaymentInfo.ReservationID, reservationID, rawData.PaymentInfo.Amount, amount)
+				if paymentInfo.Amount != int64(amount) {
+					lgr.Warnf("reservation_id (payment=%d, cache=%d): not same amount %d != %d", paymentInfo.ReservationID, reservationID, paymentInfo.Amount, amount)
 					return ErrInvalidReservationForPaymentAPI
 				}
 				return nil
@@ -79,16 +93,13 @@ func finalcheckPayment(ctx context.Context, paymentClient *payment.Client) error
 	})
 
 	// cancelされた予約が存在しないことをチェック
-	isutrain.ReservationCache.RangeCanceled(func(reservation *isutrain.ReservationCacheEntry) {
+	isutrain.ReservationCacheFromContext(ctx).RangeCanceled(func(reservation *isutrain.ReservationCacheEntry) {
 		var (
 			reservationID = reservation.ID
 		)
 		eg.Go(func() error {
-			for _, rawData := range paymentAPIResult.RawData {
-				if rawData.PaymentInfo == nil {
-					continue
-				}
-				if rawData.PaymentInfo.ReservationID == reservationID && !rawData.PaymentInfo.IsCanceled {
+			for _, paymentInfo := range paymentsByReservation[reservationID] {
+				if !paymentInfo.IsCanceled {
 					lgr.Warnf("キャンセルされた予約 %d が課金情報に含まれてる", reservationID)
 					return ErrCanceledReservationExistsPaymentInformations
 				}
@@ -99,7 +110,7 @@ func finalcheckPayment(ctx context.Context, paymentClient *payment.Client) error
 	})
 
 	if err := eg.Wait(); err != nil {
-		return bencherror.FinalCheckErrs.AddError(bencherror.NewCriticalError(err, err.Error()))
+		return bencherror.FinalCheckErrs(ctx).AddError(bencherror.NewCriticalError(err, err.Error()))
 	}
 
 	return nil
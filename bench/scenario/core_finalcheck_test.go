@@ -1,7 +1,73 @@
 package scenario
 
-import "testing"
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
+	"github.com/chibiegg/isucon9-final/bench/isutrain"
+	"github.com/chibiegg/isucon9-final/bench/mock"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
 
 func TestPosttest(t *testing.T) {
 
 }
+
+func TestFinalcheckPayment(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	_, err := mock.Register()
+	assert.NoError(t, err)
+
+	ctx := newTestRunContext()
+	paymentClient, err := payment.NewClient(ctx)
+	assert.NoError(t, err)
+
+	summary, err := paymentClient.StreamResults(ctx, func(rawData *payment.RawData) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Count)
+
+	assert.NoError(t, finalcheckPayment(ctx, paymentClient))
+}
+
+func TestFinalcheckPaymentWithReservations(t *testing.T) {
+	run, closeFn := startMutationServer(t)
+	defer closeFn()
+	ctx := run.Context(xrandom.NewContext(context.Background(), xrandom.New(mutationSeed)))
+
+	// 予約して決済したものと、予約して決済したあとにキャンセルしたものを作る
+	assert.NoError(t, NormalScenario(ctx))
+	assert.NoError(t, NormalCancelScenario(ctx))
+	assert.NotZero(t, isutrain.ReservationCacheFromContext(ctx).CommitedLen())
+
+	paymentClient, err := payment.NewClient(ctx)
+	assert.NoError(t, err)
+
+	var payments, canceled int
+	summary, err := paymentClient.StreamResults(ctx, func(rawData *payment.RawData) error {
+		payments++
+		if rawData.PaymentInfo.IsCanceled {
+			canceled++
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, payments, summary.Count)
+	assert.NotZero(t, payments-canceled)
+	assert.NotZero(t, canceled)
+
+	assert.NoError(t, finalcheckPayment(ctx, paymentClient))
+	assert.Empty(t, run.Errors.FinalCheck.Msgs)
+
+	// 課金APIから決済が消えると、成功した予約と突き合わせられない
+	assert.NoError(t, paymentClient.Initialize(ctx))
+	assert.Error(t, finalcheckPayment(ctx, paymentClient))
+	assert.Contains(t, strings.Join(run.Errors.FinalCheck.Msgs, "\n"), "課金APIには予約が記録されていませんでした")
+}
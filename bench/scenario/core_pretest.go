@@ -6,7 +6,6 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"reflect"
 	"time"
@@ -15,6 +14,7 @@ import (
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
 	"github.com/chibiegg/isucon9-final/bench/payment"
 	"golang.org/x/sync/errgroup"
@@ -84,7 +84,7 @@ func pretestStaticFiles(ctx context.Context, client *isutrain.Client, assets []*
 		hash := sha256.Sum256(b)
 
 		if !bytes.Equal(hash[:], asset.Hash[:]) {
-			return bencherror.PreTestErrs.AddError(bencherror.NewApplicationError(ErrInvalidAssetHash, "GET /%s: 静的ファイルのハッシュ値が異なります", asset.Path))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewApplicationError(ErrInvalidAssetHash, "GET /%s: 静的ファイルのハッシュ値が異なります", asset.Path))
 		}
 	}
 	return nil
@@ -96,9 +96,9 @@ func pretestStaticFiles(ctx context.Context, client *isutrain.Client, assets []*
 func pretestListStations(ctx context.Context, client *isutrain.Client) error {
 	endpointPath := endpoint.GetPath(endpoint.ListStations)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	err = registerUserAndLogin(ctx, client, &isutrain.User{
@@ -106,16 +106,16 @@ func pretestListStations(ctx context.Context, client *isutrain.Client) error {
 		Password: "puser1",
 	})
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	gotStations, err := client.ListStations(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if ok := isutrain.IsValidStations(gotStations); !ok {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: 駅一覧が不正です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: 駅一覧が不正です", endpointPath))
 	}
 
 	return nil
@@ -124,6 +124,8 @@ func pretestListStations(ctx context.Context, client *isutrain.Client) error {
 // 列車検索
 
 func pretestSearchTrains(ctx context.Context, client *isutrain.Client) error {
+	rnd := xrandom.FromContext(ctx)
+
 	// 初期状態で、いくつか試す
 	// 必ずこれは空にならないというパターンを試す
 	endpointPath := endpoint.GetPath(endpoint.SearchTrains)
@@ -133,43 +135,43 @@ func pretestSearchTrains(ctx context.Context, client *isutrain.Client) error {
 		Password: "puser2",
 	})
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
-	randIdx := rand.Intn(len(pretestSearchTrainsTests))
+	randIdx := rnd.Intn(len(pretestSearchTrainsTests))
 	randTest := pretestSearchTrainsTests[randIdx]
 
 	resp, err := client.SearchTrains(ctx, randTest.useAt, randTest.from, randTest.to, randTest.trainClass, randTest.adult, randTest.child)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if len(resp) == 0 {
-		bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: 検索結果を返すべき条件で返せておらず、空です", endpointPath))
+		bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: 検索結果を返すべき条件で返せておらず、空です", endpointPath))
 	}
 
 	for _, train := range resp {
 		wantSeatAvailability, ok := randTest.wantSeatAvailability[train.Name]
 		if !ok {
-			return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: 検索条件に対し、不正な列車名がレスポンスに含まれています: got=%s", endpointPath, train.Name))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: 検索条件に対し、不正な列車名がレスポンスに含まれています: got=%s", endpointPath, train.Name))
 		}
 		if !reflect.DeepEqual(train.SeatAvailability, wantSeatAvailability) {
-			return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: seat_availabilityが不正です: want=%+v, got=%v", endpointPath, wantSeatAvailability, train.SeatAvailability))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: seat_availabilityが不正です: want=%+v, got=%v", endpointPath, wantSeatAvailability, train.SeatAvailability))
 		}
 
 		wantFareInformation := randTest.wantFareInformation[train.Name]
 		if !reflect.DeepEqual(train.FareInformation, wantFareInformation) {
-			return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: fare_informationが不正です: want=%+v, got=%v", endpointPath, wantFareInformation, train.FareInformation))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: fare_informationが不正です: want=%+v, got=%v", endpointPath, wantFareInformation, train.FareInformation))
 		}
 
 		wantDepartedAt := randTest.wantDepartedAt[train.Name]
 		if train.DepartedAt != wantDepartedAt {
-			return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: DepartedAtが不正です: want=%s, got=%s", endpointPath, wantDepartedAt, train.DepartedAt))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: DepartedAtが不正です: want=%s, got=%s", endpointPath, wantDepartedAt, train.DepartedAt))
 		}
 
 		wantArrivedAt := randTest.wantArrivedAt[train.Name]
 		if train.ArrivedAt != wantArrivedAt {
-			return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: ArrivedAtが不正です: want=%s, got=%s", endpointPath, wantArrivedAt, train.ArrivedAt))
+			return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: ArrivedAtが不正です: want=%s, got=%s", endpointPath, wantArrivedAt, train.ArrivedAt))
 		}
 	}
 
@@ -184,6 +186,8 @@ func pretestSearchTrainsEmptyTrainClass(ctx context.Context, client *isutrain.Cl
 // 座席検索
 
 func pretestSearchTrainSeats(ctx context.Context, client *isutrain.Client) error {
+	rnd := xrandom.FromContext(ctx)
+
 	endpointPath := endpoint.GetPath(endpoint.SearchTrains)
 
 	err := registerUserAndLogin(ctx, client, &isutrain.User{
@@ -191,37 +195,37 @@ func pretestSearchTrainSeats(ctx context.Context, client *isutrain.Client) error
 		Password: "puser4",
 	})
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
-	randIdx := rand.Intn(len(pretestSearchTrainSeatsTests))
+	randIdx := rnd.Intn(len(pretestSearchTrainSeatsTests))
 	randTest := pretestSearchTrainSeatsTests[randIdx]
 
 	resp, err := client.SearchTrainSeats(ctx,
 		randTest.date, randTest.trainClass, randTest.trainName,
 		randTest.carNum, randTest.departure, randTest.arrival)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	wantDate := randTest.date.Format("2006/01/02")
 	if resp.Date != wantDate {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる日付が不正です: want=%s, got=%s", endpointPath, wantDate, resp.Date))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる日付が不正です: want=%s, got=%s", endpointPath, wantDate, resp.Date))
 	}
 	if resp.TrainClass != randTest.trainClass {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる列車種別が不正です: want=%s, got=%s", endpointPath, randTest.trainClass, resp.TrainClass))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる列車種別が不正です: want=%s, got=%s", endpointPath, randTest.trainClass, resp.TrainClass))
 	}
 	if resp.TrainName != randTest.trainName {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる列車名が不正です: want=%s, got=%s", endpointPath, randTest.trainName, resp.TrainName))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる列車名が不正です: want=%s, got=%s", endpointPath, randTest.trainName, resp.TrainName))
 	}
 	if resp.CarNumber != randTest.carNum {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる車両番号が不正です: want=%d, got=%d", endpointPath, randTest.carNum, resp.CarNumber))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる車両番号が不正です: want=%d, got=%d", endpointPath, randTest.carNum, resp.CarNumber))
 	}
 	if !randTest.wantSeats.IsSame(resp.Seats) {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる座席が不正です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる座席が不正です", endpointPath))
 	}
 	if !randTest.wantCars.IsSame(resp.Cars) {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる車両が不正です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: レスポンスに含まれる車両が不正です", endpointPath))
 	}
 
 	return nil
@@ -238,16 +242,16 @@ func pretestNormalReservation(ctx context.Context, client *isutrain.Client, paym
 	}
 
 	if err := client.Signup(ctx, user.Email, user.Password); err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if err := client.Login(ctx, user.Email, user.Password); err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	_, err := client.ListStations(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	var (
@@ -261,7 +265,7 @@ func pretestNormalReservation(ctx context.Context, client *isutrain.Client, paym
 	)
 	_, err = client.SearchTrains(ctx, useAt, departure, arrival, "最速", adult, child)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	// FIXME: 日付、列車クラス、名前、車両番号、乗車駅降車駅を指定
@@ -269,7 +273,7 @@ func pretestNormalReservation(ctx context.Context, client *isutrain.Client, paym
 		useAt,
 		trainClass, trainName, carNum, departure, arrival)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(searchTrainSeatsResp, 2)
@@ -278,23 +282,23 @@ func pretestNormalReservation(ctx context.Context, client *isutrain.Client, paym
 		useAt,
 		carNum, child, adult)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	// 予約が、予約詳細に反映されているか
 	showReservationResp, err := client.ShowReservation(ctx, reserveResp.ReservationID)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if reserveResp.ReservationID != showReservationResp.ReservationID {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: 予約レスポンスに含まれる予約IDと、予約詳細に含まれる予約IDが異なります: want=%d, got=%d", endpoint.GetPath(endpoint.ShowReservation), reserveResp.ReservationID, showReservationResp.ReservationID))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: 予約レスポンスに含まれる予約IDと、予約詳細に含まれる予約IDが異なります: want=%d, got=%d", endpoint.GetPath(endpoint.ShowReservation), reserveResp.ReservationID, showReservationResp.ReservationID))
 	}
 
 	// 予約が、予約一覧に反映されているか
 	listReservationResp, err := client.ListReservations(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	var found bool
@@ -306,20 +310,20 @@ func pretestNormalReservation(ctx context.Context, client *isutrain.Client, paym
 	}
 
 	if !found {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("GET %s: 予約一覧に、予約したはずの予約IDが含まれていません: want=%d", endpoint.GetPath(endpoint.ListReservations), reserveResp.ReservationID))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("GET %s: 予約一覧に、予約したはずの予約IDが含まれていません: want=%d", endpoint.GetPath(endpoint.ListReservations), reserveResp.ReservationID))
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if err = client.CommitReservation(ctx, reserveResp.ReservationID, cardToken); err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	if err := client.CancelReservation(ctx, reserveResp.ReservationID); err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -334,18 +338,18 @@ func pretestReserveVagueSeats(ctx context.Context, client *isutrain.Client) erro
 		Password: "puser5",
 	})
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	d := time.Date(2020, 1, 1, 6, 50, 0, 0, time.UTC)
 	_, err = client.Reserve(ctx, "最速", "1", "premium", isutrain.TrainSeats{}, "東京", "大阪", d, 8, 1, 1)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	err = client.Logout(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -355,13 +359,13 @@ func pretestReserveVagueSeats(ctx context.Context, client *isutrain.Client) erro
 
 func pretestAbnormalDate(ctx context.Context, client *isutrain.Client) error {
 	var (
-		availDate = config.ReservationStartDate.AddDate(0, 0, config.AvailableDays)
+		availDate = config.ReservationStartDate.AddDate(0, 0, config.FromContext(ctx).AvailableDays)
 		d         = availDate.AddDate(0, 0, 1)
 	)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	err = registerUserAndLogin(ctx, client, &isutrain.User{
@@ -369,13 +373,13 @@ func pretestAbnormalDate(ctx context.Context, client *isutrain.Client) error {
 		Password: "puser6",
 	})
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	_, err = client.SearchTrains(ctx, d, "東京", "大阪", "最速", 1, 1, isutrain.StatusCodeOpt(http.StatusNotFound))
 	if err != nil {
 		fmt.Println(err.Error())
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -386,7 +390,7 @@ func pretestAbnormalDate(ctx context.Context, client *isutrain.Client) error {
 // PreTestAbnormalLogin は不正なパスワードでのログインを試みます
 func pretestAbnormalLogin(ctx context.Context, client *isutrain.Client) error {
 	if err := client.Login(ctx, "FikyavwocZear@example.com", "jieldirAwsabyonsInd", isutrain.StatusCodeOpt(http.StatusForbidden)); err != nil {
-		return bencherror.PreTestErrs.AddError(err)
+		return bencherror.PreTestErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -399,13 +403,13 @@ func pretestAbnormalReserveNonStoppableStation(ctx context.Context, client *isut
 	// Express
 	_, err := client.Reserve(ctx, "最速", "1", "premium", isutrain.TrainSeats{}, "古岡", "大阪", d, 8, 1, 1, isutrain.StatusCodeOpt(http.StatusBadRequest))
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: 最速が止まらない駅で予約可能です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("POST %s: 最速が止まらない駅で予約可能です", endpointPath))
 	}
 
 	// SemiExpress
 	_, err = client.Reserve(ctx, "中間", "3", "reserved", isutrain.TrainSeats{}, "東京", "絵寒町", d, 8, 1, 1, isutrain.StatusCodeOpt(http.StatusBadRequest))
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: 中間が止まらない駅で予約可能です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("POST %s: 中間が止まらない駅で予約可能です", endpointPath))
 	}
 
 	return nil
@@ -418,7 +422,7 @@ func pretestAbnormalReserveInvalidSection(ctx context.Context, client *isutrain.
 	// 適当に選んだ列車が走らない区間を選ぶ
 	_, err := client.Reserve(ctx, "最速", "12", "premium", isutrain.TrainSeats{}, "大阪", "東京", d, 8, 1, 1, isutrain.StatusCodeOpt(http.StatusBadRequest))
 	if err != nil {
-		return bencherror.PreTestErrs.AddError(bencherror.NewSimpleCriticalError("POST %s: 列車が運行してない区間の予約が可能です", endpointPath))
+		return bencherror.PreTestErrs(ctx).AddError(bencherror.NewSimpleCriticalError("POST %s: 列車が運行してない区間の予約が可能です", endpointPath))
 	}
 
 	return nil
@@ -0,0 +1,117 @@
+package scenario
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+	"github.com/chibiegg/isucon9-final/bench/internal/xrandom"
+	"github.com/chibiegg/isucon9-final/bench/mock"
+	"github.com/chibiegg/isucon9-final/bench/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+// 不具合を1つだけ仕込んだモックサーバに対してシナリオを実行し、
+// ベンチマーカーが期待するエラーを報告できるか(検出器が機能しているか)を確かめる
+
+const (
+	mutationAvailableDays = 30
+	mutationSeed          = 1
+)
+
+// startMutationServer は、不具合を仕込んだモックサーバを起動し、そこを宛先とする実行を返します
+// 実行ごとにエラーや予約を持つので、前のケースの結果は残らない
+func startMutationServer(t *testing.T, bugs ...mock.Bug) (*benchrun.Run, func()) {
+	server := mock.NewServer(mutationAvailableDays, "")
+	server.SetBugs(bugs...)
+	paymentTS := httptest.NewServer(server.PaymentHandler())
+	server.PaymentURL = paymentTS.URL
+	ts := httptest.NewServer(server.Handler())
+
+	run := benchrun.New(config.NewTarget(ts.URL, paymentTS.URL))
+	assert.NoError(t, run.Target.SetAvailReserveDays(mutationAvailableDays))
+
+	return run, func() {
+		ts.Close()
+		paymentTS.Close()
+	}
+}
+
+// cancelAndFinalCheck は、予約をキャンセルしたあとに課金APIと突き合わせます
+func cancelAndFinalCheck(ctx context.Context) error {
+	if err := NormalCancelScenario(ctx); err != nil {
+		return err
+	}
+	paymentClient, err := payment.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	return finalcheckPayment(ctx, paymentClient)
+}
+
+func TestMutation(t *testing.T) {
+	tests := []struct {
+		bug  mock.Bug
+		run  func(ctx context.Context) error
+		errs func(run *benchrun.Run) *bencherror.BenchErrors
+		// 報告されるべきエラーメッセージの一部
+		want string
+	}{
+		{
+			bug:  mock.BugDoubleBooking,
+			run:  AttackReserveRaceCondition,
+			errs: func(run *benchrun.Run) *bencherror.BenchErrors { return run.Errors.Benchmark },
+			want: "多重発券されました",
+		},
+		{
+			bug:  mock.BugIgnoreOwnership,
+			run:  AttackReserveForOtherReservation,
+			errs: func(run *benchrun.Run) *bencherror.BenchErrors { return run.Errors.Benchmark },
+			want: "他のユーザーの予約がキャンセルできました",
+		},
+		{
+			bug:  mock.BugWrongFare,
+			run:  NormalScenario,
+			errs: func(run *benchrun.Run) *bencherror.BenchErrors { return run.Errors.Benchmark },
+			want: "amountが不正です",
+		},
+		{
+			bug:  mock.BugNoRefund,
+			run:  cancelAndFinalCheck,
+			errs: func(run *benchrun.Run) *bencherror.BenchErrors { return run.Errors.FinalCheck },
+			want: ErrCanceledReservationExistsPaymentInformations.Error(),
+		},
+		{
+			bug:  mock.BugWrongAvailability,
+			run:  NormalScenario,
+			errs: func(run *benchrun.Run) *bencherror.BenchErrors { return run.Errors.Benchmark },
+			want: "空席状況が不正です",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.bug), func(t *testing.T) {
+			// 不具合がなければ、同じシナリオでエラーにならない
+			run, closeFn := startMutationServer(t)
+			ctx := run.Context(xrandom.NewContext(context.Background(), xrandom.New(mutationSeed)))
+			assert.NoError(t, tt.run(ctx))
+			closeFn()
+			for _, errs := range []*bencherror.BenchErrors{run.Errors.System, run.Errors.Benchmark, run.Errors.FinalCheck} {
+				assert.Empty(t, errs.Msgs)
+			}
+
+			// 不具合を仕込むと、致命的なエラーとして報告される
+			run, closeFn = startMutationServer(t, tt.bug)
+			ctx = run.Context(xrandom.NewContext(context.Background(), xrandom.New(mutationSeed)))
+			tt.run(ctx)
+			closeFn()
+			errs := tt.errs(run)
+			assert.NotZero(t, errs.Counts().Critical, "msgs=%v", errs.Msgs)
+			assert.Contains(t, strings.Join(errs.Msgs, "\n"), tt.want)
+		})
+	}
+}
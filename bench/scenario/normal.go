@@ -2,7 +2,6 @@ package scenario
 
 import (
 	"context"
-	"math/rand"
 	"time"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
@@ -15,12 +14,14 @@ import (
 
 // NormalScenario は基本的な予約フローのシナリオです
 func NormalScenario(ctx context.Context) error {
-	client, err := isutrain.NewClient()
+	rnd := xrandom.FromContext(ctx)
+
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	paymentClient, err := payment.NewClient()
+	paymentClient, err := payment.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -29,42 +30,42 @@ func NormalScenario(ctx context.Context) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	useAt := xrandom.GetRandomUseAt()
-	departure, arrival := xrandom.GetRandomSection()
-	adult, child := xrandom.GetRandomNumberOfPeople()
+	useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+	departure, arrival := rnd.GetRandomSection()
+	adult, child := rnd.GetRandomNumberOfPeople()
 	trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if len(trains) == 0 {
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleApplicationError("列車検索の結果が空です"))
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleApplicationError("列車検索の結果が空です"))
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
-	carNum := xrandom.GetRandomCarNumber(train.Class, "premium")
+	carNum := rnd.GetRandomCarNumber(train.Class, "premium")
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, departure, arrival)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -75,35 +76,35 @@ func NormalScenario(ctx context.Context) error {
 		departure, arrival, useAt,
 		carNum, 1, 1)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.CommitReservation(ctx, reserveResp.ReservationID, cardToken)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListReservations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	reservation2, err := client.ShowReservation(ctx, reserveResp.ReservationID)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if reserveResp.ReservationID != reservation2.ReservationID {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if err := client.Logout(ctx); err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -111,12 +112,14 @@ func NormalScenario(ctx context.Context) error {
 
 // 予約キャンセル含む(Commit後にキャンセル)
 func NormalCancelScenario(ctx context.Context) error {
-	client, err := isutrain.NewClient()
+	rnd := xrandom.FromContext(ctx)
+
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	paymentClient, err := payment.NewClient()
+	paymentClient, err := payment.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -125,45 +128,45 @@ func NormalCancelScenario(ctx context.Context) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	var (
-		useAt              = xrandom.GetRandomUseAt()
-		departure, arrival = xrandom.GetRandomSection()
-		adult, child       = xrandom.GetRandomNumberOfPeople()
+		useAt              = rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+		departure, arrival = rnd.GetRandomSection()
+		adult, child       = rnd.GetRandomNumberOfPeople()
 	)
 	trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if len(trains) == 0 {
 		err := bencherror.NewSimpleCriticalError("列車検索結果が空でした")
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
-	carNum := xrandom.GetRandomCarNumber(train.Class, "reserved")
+	carNum := rnd.GetRandomCarNumber(train.Class, "reserved")
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, departure, arrival)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -175,40 +178,40 @@ func NormalCancelScenario(ctx context.Context) error {
 		carNum, 1, 1,
 	)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.CommitReservation(ctx, reserveResp.ReservationID, cardToken)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListReservations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	reservation2, err := client.ShowReservation(ctx, reserveResp.ReservationID)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if reserveResp.ReservationID != reservation2.ReservationID {
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleCriticalError("予約確認で得られる予約IDが一致していません: got=%d, want=%d", reservation2.ReservationID, reserveResp.ReservationID))
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleCriticalError("予約確認で得られる予約IDが一致していません: got=%d, want=%d", reservation2.ReservationID, reserveResp.ReservationID))
 	}
 
 	err = client.CancelReservation(ctx, reserveResp.ReservationID)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if err := client.Logout(ctx); err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
@@ -216,7 +219,9 @@ func NormalCancelScenario(ctx context.Context) error {
 
 // 曖昧検索シナリオ
 func NormalVagueSearchScenario(ctx context.Context) error {
-	client, err := isutrain.NewClient()
+	rnd := xrandom.FromContext(ctx)
+
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -225,27 +230,27 @@ func NormalVagueSearchScenario(ctx context.Context) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	user, err = xrandom.GetRandomUser()
+	user, err = rnd.GetRandomUser()
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 	if err = client.Signup(ctx, user.Email, user.Password); err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if err := client.Login(ctx, user.Email, user.Password); err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.Reserve(ctx,
@@ -253,15 +258,16 @@ func NormalVagueSearchScenario(ctx context.Context) error {
 		"東京", "大阪", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
 		1, 1, 1)
 	if err != nil {
-		bencherror.BenchmarkErrs.AddError(err)
+		bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
 }
 
 func NormalManyCancelScenario(ctx context.Context, counter int) error {
+	rnd := xrandom.FromContext(ctx)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -270,19 +276,19 @@ func NormalManyCancelScenario(ctx context.Context, counter int) error {
 		client.ReplaceMockTransport()
 	}
 
-	user, err := xrandom.GetRandomUser()
+	user, err := rnd.GetRandomUser()
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	var retErr error
@@ -291,11 +297,11 @@ func NormalManyCancelScenario(ctx context.Context, counter int) error {
 
 	// たくさん予約を作る
 	for i := 0; i < counter; i++ {
-		useAt := xrandom.GetRandomUseAt()
-		departure, arrival := xrandom.GetRandomSection()
+		useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+		departure, arrival := rnd.GetRandomSection()
 		reservation, err := createSimpleReservation(ctx, client, user, useAt, departure, arrival, "遅いやつ", 3, 3)
 		if err != nil {
-			bencherror.BenchmarkErrs.AddError(err)
+			bencherror.BenchmarkErrs(ctx).AddError(err)
 			retErr = err
 			continue
 		}
@@ -306,13 +312,13 @@ func NormalManyCancelScenario(ctx context.Context, counter int) error {
 	for _, reservationId := range cancelIds {
 		err = client.CancelReservation(ctx, reservationId)
 		if err != nil {
-			bencherror.BenchmarkErrs.AddError(err)
+			bencherror.BenchmarkErrs(ctx).AddError(err)
 			retErr = err
 		}
 	}
 
 	if err := client.Logout(ctx); err != nil {
-		bencherror.BenchmarkErrs.AddError(err)
+		bencherror.BenchmarkErrs(ctx).AddError(err)
 		retErr = err
 	}
 
@@ -320,38 +326,40 @@ func NormalManyCancelScenario(ctx context.Context, counter int) error {
 }
 
 func NormalManyAmbigiousSearchScenario(ctx context.Context, counter int) error {
-	client, err := isutrain.NewClient()
+	rnd := xrandom.FromContext(ctx)
+
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if config.Debug {
 		client.ReplaceMockTransport()
 	}
 
-	useAt := xrandom.GetRandomUseAt()
-	departure, arrival := xrandom.GetRandomSection()
+	useAt := rnd.GetRandomUseAt(config.FromContext(ctx).AvailableDays)
+	departure, arrival := rnd.GetRandomSection()
 
 	var retErr error
 
 	for i := 0; i < counter; i++ {
-		user, err := xrandom.GetRandomUser()
+		user, err := rnd.GetRandomUser()
 		if err != nil {
-			return bencherror.BenchmarkErrs.AddError(err)
+			return bencherror.BenchmarkErrs(ctx).AddError(err)
 		}
 		err = registerUserAndLogin(ctx, client, user)
 		if err != nil {
-			return bencherror.BenchmarkErrs.AddError(err)
+			return bencherror.BenchmarkErrs(ctx).AddError(err)
 		}
 
 		_, err = client.ListStations(ctx)
 		if err != nil {
-			return bencherror.BenchmarkErrs.AddError(err)
+			return bencherror.BenchmarkErrs(ctx).AddError(err)
 		}
 
 		_, err = createSimpleReservation(ctx, client, user, useAt, departure, arrival, "遅いやつ", 3, 3)
 		if err != nil {
-			bencherror.BenchmarkErrs.AddError(err)
+			bencherror.BenchmarkErrs(ctx).AddError(err)
 			retErr = err
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
+	"github.com/chibiegg/isucon9-final/bench/internal/benchrun"
 	"github.com/chibiegg/isucon9-final/bench/internal/config"
 	"github.com/chibiegg/isucon9-final/bench/internal/endpoint"
 	"github.com/chibiegg/isucon9-final/bench/isutrain"
@@ -14,19 +15,26 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// newTestRunContext は、他のテストとエラーや予約を共有しない実行のコンテキストを返します
+func newTestRunContext() context.Context {
+	target := config.NewTarget(config.DefaultTargetBaseURL, config.DefaultPaymentBaseURL)
+	return benchrun.New(target).Context(context.Background())
+}
+
 func TestScenario(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
 	mock.Register()
 
-	initClient, err := isutrain.NewClientForInitialize()
+	ctx := newTestRunContext()
+	initClient, err := isutrain.NewClientForInitialize(ctx)
 	assert.NoError(t, err)
 	initClient.ReplaceMockTransport()
-	initClient.Initialize(context.Background())
+	initClient.Initialize(ctx)
 
 	config.Debug = true
-	assert.NoError(t, NormalScenario(context.Background()))
+	assert.NoError(t, NormalScenario(ctx))
 }
 
 func TestInitializeBenchError(t *testing.T) {
@@ -42,12 +50,13 @@ func TestInitializeBenchError(t *testing.T) {
 		return nil
 	})
 
-	initClient, err := isutrain.NewClientForInitialize()
+	ctx := newTestRunContext()
+	initClient, err := isutrain.NewClientForInitialize(ctx)
 	assert.NoError(t, err)
 	initClient.ReplaceMockTransport()
-	initClient.Initialize(context.Background())
+	initClient.Initialize(ctx)
 
-	assert.True(t, bencherror.InitializeErrs.IsError())
+	assert.True(t, bencherror.InitializeErrs(ctx).IsError())
 }
 
 func TestScenarioBenchError(t *testing.T) {
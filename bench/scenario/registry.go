@@ -0,0 +1,94 @@
+package scenario
+
+import (
+	"context"
+	"sort"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/config"
+)
+
+// Func はワークロードプロファイルから名前で呼び出すシナリオです
+// scaleはシナリオ内の繰り返し回数や並列数で、0の場合はシナリオごとのデフォルト値を使います
+type Func func(ctx context.Context, scale int) error
+
+func withDefault(scale, defaultScale int) int {
+	if scale > 0 {
+		return scale
+	}
+	return defaultScale
+}
+
+// シナリオ名とシナリオの対応
+var registry = map[string]Func{
+	"normal": func(ctx context.Context, _ int) error {
+		return NormalScenario(ctx)
+	},
+	"normal_cancel": func(ctx context.Context, _ int) error {
+		return NormalCancelScenario(ctx)
+	},
+	"normal_vague_search": func(ctx context.Context, _ int) error {
+		return NormalVagueSearchScenario(ctx)
+	},
+	"normal_many_ambiguous_search": func(ctx context.Context, scale int) error {
+		return NormalManyAmbigiousSearchScenario(ctx, withDefault(scale, int(config.FromContext(ctx).ReservationEndDate.Month())*3))
+	},
+	"normal_many_cancel": func(ctx context.Context, scale int) error {
+		return NormalManyCancelScenario(ctx, withDefault(scale, int(config.FromContext(ctx).ReservationEndDate.Month())*3))
+	},
+	"attack_search": func(ctx context.Context, _ int) error {
+		return AttackSearchScenario(ctx)
+	},
+	"attack_login": func(ctx context.Context, _ int) error {
+		return AttackLoginScenario(ctx)
+	},
+	"attack_reserve_for_other_reservation": func(ctx context.Context, _ int) error {
+		return AttackReserveForOtherReservation(ctx)
+	},
+	"attack_reserve_race_condition": func(ctx context.Context, _ int) error {
+		return AttackReserveRaceCondition(ctx)
+	},
+	"abnormal_login": func(ctx context.Context, _ int) error {
+		return AbnormalLoginScenario(ctx)
+	},
+	"abnormal_reserve_wrong_section": func(ctx context.Context, _ int) error {
+		return AbnormalReserveWrongSection(ctx)
+	},
+	"abnormal_reserve_wrong_seat": func(ctx context.Context, _ int) error {
+		return AbnormalReserveWrongSeat(ctx)
+	},
+	// 繁忙期のシナリオは、予約可能な期間に該当の日付が含まれる場合のみ実行されます
+	"season_golden_week": func(ctx context.Context, scale int) error {
+		parallel := withDefault(scale, 5)
+		if config.FromContext(ctx).IsGoldenweekStarted() {
+			if err := SeasonGoldenWeekScenario(ctx, config.GoldenWeekStartDate, parallel); err != nil {
+				return err
+			}
+		}
+		if config.FromContext(ctx).IsGoldenweekEnded() {
+			return SeasonGoldenWeekScenario(ctx, config.GoldenWeekEndDate, parallel)
+		}
+		return nil
+	},
+	"season_olympic": func(ctx context.Context, scale int) error {
+		if !config.FromContext(ctx).IsOlympic() {
+			return nil
+		}
+		return SeasonOlympicScenario(ctx, withDefault(scale, 5))
+	},
+}
+
+// Lookup は名前に対応するシナリオを返します
+func Lookup(name string) (Func, bool) {
+	f, ok := registry[name]
+	return f, ok
+}
+
+// Names は登録されているシナリオ名を昇順で返します
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
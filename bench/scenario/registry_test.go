@@ -0,0 +1,25 @@
+package scenario
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/chibiegg/isucon9-final/bench/internal/workload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfilesUseRegisteredScenarios(t *testing.T) {
+	files, err := filepath.Glob("../profiles/*")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+	for _, file := range files {
+		p, err := workload.LoadFile(file)
+		if !assert.NoError(t, err, file) {
+			continue
+		}
+		for _, s := range p.Scenarios {
+			_, ok := Lookup(s.Name)
+			assert.True(t, ok, "%s: %s", file, s.Name)
+		}
+	}
+}
@@ -15,6 +15,8 @@ import (
 )
 
 func SeasonGoldenWeekScenario(ctx context.Context, goldenweekDate time.Time, parallel int) error {
+	rnd := xrandom.FromContext(ctx)
+
 	// zapロガー取得 (参考: https://qiita.com/emonuh/items/28dbee9bf2fe51d28153#sugaredlogger )
 	lgr := zap.S()
 
@@ -30,9 +32,9 @@ func SeasonGoldenWeekScenario(ctx context.Context, goldenweekDate time.Time, par
 			defer wg.Done()
 			defer lgr.Infof("[season:GoldenWeekScenario] Done %d", i)
 
-			departure, arrival := xrandom.GetTokaiRandomSection()
+			departure, arrival := rnd.GetTokaiRandomSection()
 
-			client, err := isutrain.NewClient()
+			client, err := isutrain.NewClient(ctx)
 			if err != nil {
 				return
 			}
@@ -41,27 +43,27 @@ func SeasonGoldenWeekScenario(ctx context.Context, goldenweekDate time.Time, par
 				client.ReplaceMockTransport()
 			}
 
-			user, err := xrandom.GetRandomUser()
+			user, err := rnd.GetRandomUser()
 			if err != nil {
-				bencherror.SystemErrs.AddError(err)
+				bencherror.SystemErrs(ctx).AddError(err)
 				return
 			}
 
 			err = registerUserAndLogin(ctx, client, user)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
 			_, err = client.ListStations(ctx)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				return
 			}
 
 			_, err = createSimpleReservation(ctx, client, user, goldenweekDate, departure, arrival, "最速", 1, 1)
 			if err != nil {
-				bencherror.BenchmarkErrs.AddError(err)
+				bencherror.BenchmarkErrs(ctx).AddError(err)
 				totalErr = err
 			}
 		}()
@@ -72,15 +74,17 @@ func SeasonGoldenWeekScenario(ctx context.Context, goldenweekDate time.Time, par
 }
 
 func reserveForOlympic(ctx context.Context, scenarioIdx int, user *isutrain.User, departure, arrival string) error {
+	rnd := xrandom.FromContext(ctx)
+
 	lgr := zap.S()
 	defer lgr.Infof("[season:SeasonOlympicScenario] Done %d", scenarioIdx)
 
 	var (
-		useAt        = xrandom.GetRandomUseAtByOlympicDate()
-		adult, child = xrandom.GetRandomNumberOfPeople()
+		useAt        = rnd.GetRandomUseAtByOlympicDate()
+		adult, child = rnd.GetRandomNumberOfPeople()
 	)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -91,32 +95,34 @@ func reserveForOlympic(ctx context.Context, scenarioIdx int, user *isutrain.User
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = createSpecifiedReservation(ctx, client, user, useAt, departure, arrival, adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
 }
 
 func vagueReserveForOlympic(ctx context.Context, scenarioIdx int, user *isutrain.User, departure, arrival string) error {
+	rnd := xrandom.FromContext(ctx)
+
 	lgr := zap.S()
 	defer lgr.Infof("[season:SeasonOlympicScenario] Done %d", scenarioIdx)
 
 	var (
-		useAt        = xrandom.GetRandomUseAtByOlympicDate()
-		adult, child = xrandom.GetRandomNumberOfPeople()
+		useAt        = rnd.GetRandomUseAtByOlympicDate()
+		adult, child = rnd.GetRandomNumberOfPeople()
 	)
 
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -127,12 +133,12 @@ func vagueReserveForOlympic(ctx context.Context, scenarioIdx int, user *isutrain
 
 	err = registerUserAndLogin(ctx, client, user)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	lgr.Infow("指定検索: ",
@@ -145,13 +151,15 @@ func vagueReserveForOlympic(ctx context.Context, scenarioIdx int, user *isutrain
 	)
 	_, err = createSimpleReservation(ctx, client, user, useAt, departure, arrival, "遅いやつ", adult, child)
 	if err != nil {
-		return bencherror.BenchmarkErrs.AddError(err)
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return nil
 }
 
 func SeasonOlympicScenario(ctx context.Context, parallel int) error {
+	rnd := xrandom.FromContext(ctx)
+
 	// zapロガー取得 (参考: https://qiita.com/emonuh/items/28dbee9bf2fe51d28153#sugaredlogger )
 	lgr := zap.S()
 
@@ -164,11 +172,11 @@ func SeasonOlympicScenario(ctx context.Context, parallel int) error {
 	for i := 0; i < parallel; i++ {
 		var (
 			scenarioIdx       = i
-			tokyo, anyStation = xrandom.GetRandomSectionWithTokyo()
-			user, err         = xrandom.GetRandomUser()
+			tokyo, anyStation = rnd.GetRandomSectionWithTokyo()
+			user, err         = rnd.GetRandomUser()
 		)
 		if err != nil {
-			return bencherror.SystemErrs.AddError(err)
+			return bencherror.SystemErrs(ctx).AddError(err)
 		}
 		eg.Go(func() error {
 			return reserveForOlympic(ctx, scenarioIdx, user, anyStation, tokyo)
@@ -179,11 +187,11 @@ func SeasonOlympicScenario(ctx context.Context, parallel int) error {
 	for i := parallel; i < parallel*2; i++ {
 		var (
 			scenarioIdx       = i
-			tokyo, anyStation = xrandom.GetRandomSectionWithTokyo()
-			user, err         = xrandom.GetRandomUser()
+			tokyo, anyStation = rnd.GetRandomSectionWithTokyo()
+			user, err         = rnd.GetRandomUser()
 		)
 		if err != nil {
-			return bencherror.SystemErrs.AddError(err)
+			return bencherror.SystemErrs(ctx).AddError(err)
 		}
 		eg.Go(func() error {
 			return vagueReserveForOlympic(ctx, scenarioIdx, user, tokyo, anyStation)
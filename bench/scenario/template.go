@@ -18,19 +18,21 @@ func DoSomething() (int, error) {
 }
 
 func AwesomeScenario(ctx context.Context) error {
+	rnd := xrandom.FromContext(ctx)
+
 	// zapロガー取得 (参考: https://qiita.com/emonuh/items/28dbee9bf2fe51d28153#sugaredlogger )
 	lgr := zap.S()
 
 	// ISUTRAIN APIのクライアントを作成
 	// isutrain.Clientでエラーが発生したらシステムエラーに追加してくれる
-	client, err := isutrain.NewClient()
+	client, err := isutrain.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
 	// 決済サービスのクライアントを作成
 	// isutrain.Clientでエラーが発生したらシステムエラーに追加してくれる
-	paymentClient, err := payment.NewClient()
+	paymentClient, err := payment.NewClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -49,39 +51,39 @@ func AwesomeScenario(ctx context.Context) error {
 
 	// ユーザー作成とログイン
 	// ベンチマーカーのランダム生成に問題があって、webappに問題はないので、ベンチマークのシステムエラーとして追加
-	user, err := xrandom.GetRandomUser() // ランダムデータ生成系は xrandom に作成するかあるものを使う
+	user, err := rnd.GetRandomUser() // ランダムデータ生成系は xrandom に作成するかあるものを使う
 	if err != nil {
-		bencherror.SystemErrs.AddError(err)
+		bencherror.SystemErrs(ctx).AddError(err)
 		return nil
 	}
 
 	err = client.Signup(ctx, user.Email, user.Password)
 	if err != nil {
-		// 実行中のエラーは `bencherror.BenchmarkErrs.AddError(err)` に投げる
-		return bencherror.BenchmarkErrs.AddError(err)
+		// 実行中のエラーは `bencherror.BenchmarkErrs(ctx).AddError(err)` に投げる
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.Login(ctx, user.Email, user.Password)
 	if err != nil {
-		// `bencherror.BenchmarkErrs.AddError(err)` も忘れずに
-		return bencherror.BenchmarkErrs.AddError(err)
+		// `bencherror.BenchmarkErrs(ctx).AddError(err)` も忘れずに
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
-		// `bencherror.BenchmarkErrs.AddError(err)` も忘れずに
-		return bencherror.BenchmarkErrs.AddError(err)
+		// `bencherror.BenchmarkErrs(ctx).AddError(err)` も忘れずに
+		return bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	// 例) cardTokenが不正な場合に失格にしたい場合
 	if cardToken != "XXXXXXXX" {
-		// `bencherror.BenchmarkErrs.AddError(err)` も忘れずに
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewSimpleCriticalError("カードトークンが不正: %s", cardToken))
+		// `bencherror.BenchmarkErrs(ctx).AddError(err)` も忘れずに
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewSimpleCriticalError("カードトークンが不正: %s", cardToken))
 	}
 	// 例) Client以外からエラーを得たが、これを減点要素にしたい場合
 	if num, err := DoSomething(); err != nil {
-		// `bencherror.BenchmarkErrs.AddError(err)` も忘れずに
-		return bencherror.BenchmarkErrs.AddError(bencherror.NewApplicationError(err, "エラー発生: %d", num))
+		// `bencherror.BenchmarkErrs(ctx).AddError(err)` も忘れずに
+		return bencherror.BenchmarkErrs(ctx).AddError(bencherror.NewApplicationError(err, "エラー発生: %d", num))
 	}
 
 	lgr.Infof("[template:AwesomeScenario] カードのトークン %s", cardToken)
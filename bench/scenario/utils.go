@@ -2,7 +2,6 @@ package scenario
 
 import (
 	"context"
-	"math/rand"
 	"time"
 
 	"github.com/chibiegg/isucon9-final/bench/internal/bencherror"
@@ -35,7 +34,7 @@ func createSimpleReservation(ctx context.Context, client *isutrain.Client, user
 	// lgr := zap.S()
 
 	// 決済サービスのクライアントを作成
-	paymentClient, err := payment.NewClient()
+	paymentClient, err := payment.NewClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +65,7 @@ func createSimpleReservation(ctx context.Context, client *isutrain.Client, user
 		return nil, err
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
 		return nil, err
 	}
@@ -81,35 +80,36 @@ func createSimpleReservation(ctx context.Context, client *isutrain.Client, user
 
 // 列車種別以外指定で予約
 func createSpecifiedReservation(ctx context.Context, client *isutrain.Client, user *isutrain.User, useAt time.Time, departure, arrival string, adult, child int) (*isutrain.ReserveResponse, error) {
+	rnd := xrandom.FromContext(ctx)
 
-	paymentClient, err := payment.NewClient()
+	paymentClient, err := payment.NewClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	_, err = client.ListStations(ctx)
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	trains, err := client.SearchTrains(ctx, useAt, departure, arrival, "", adult, child)
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	if len(trains) == 0 {
 		err := bencherror.NewSimpleCriticalError("列車検索結果が空でした")
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	trainIdx := rand.Intn(len(trains))
+	trainIdx := rnd.Intn(len(trains))
 	train := trains[trainIdx]
-	carNum := xrandom.GetRandomCarNumber(train.Class, "reserved")
+	carNum := rnd.GetRandomCarNumber(train.Class, "reserved")
 	listTrainSeatsResp, err := client.SearchTrainSeats(ctx,
 		useAt,
 		train.Class, train.Name, carNum, departure, arrival)
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	availSeats := FilterTrainSeats(listTrainSeatsResp, 2)
@@ -121,17 +121,17 @@ func createSpecifiedReservation(ctx context.Context, client *isutrain.Client, us
 		carNum, 1, 1,
 	)
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
-	cardToken, err := paymentClient.RegistCard(ctx, "11111111", "222", "10/50")
+	cardToken, err := paymentClient.RegistCard(ctx, "4111111111111111", "222", "10/50")
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	err = client.CommitReservation(ctx, reserveResp.ReservationID, cardToken)
 	if err != nil {
-		return nil, bencherror.BenchmarkErrs.AddError(err)
+		return nil, bencherror.BenchmarkErrs(ctx).AddError(err)
 	}
 
 	return reserveResp, nil
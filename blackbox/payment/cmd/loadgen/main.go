@@ -0,0 +1,316 @@
+// loadgen は決済サービスのgRPC APIに負荷をかけ、スループットとレイテンシを計測するコマンドです
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "payment/pb"
+	"payment/server"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 負荷をかける操作
+const (
+	opRegistCard     = "RegistCard"
+	opExecutePayment = "ExecutePayment"
+	opCancelPayment  = "CancelPayment"
+	opBulkCancel     = "BulkCancelPayment"
+	opGetPayment     = "GetPaymentInformation"
+)
+
+// -mix で指定する名前と操作の対応
+var opNames = map[string]string{
+	"regist":  opRegistCard,
+	"execute": opExecutePayment,
+	"cancel":  opCancelPayment,
+	"bulk":    opBulkCancel,
+	"get":     opGetPayment,
+}
+
+var (
+	addr        = flag.String("addr", "localhost:5001", "gRPC address of the payment service")
+	apiKey      = flag.String("api-key", "", "API key sent as X-Api-Key")
+	adminKey    = flag.String("admin-key", "", "API key used for Initialize (defaults to -api-key)")
+	duration    = flag.Duration("duration", 30*time.Second, "how long to generate load")
+	rate        = flag.Float64("rate", 0, "total requests per second (0 means as fast as the workers can go)")
+	concurrency = flag.Int("concurrency", 16, "number of concurrent workers")
+	mix         = flag.String("mix", "regist=1,execute=5,cancel=2,bulk=1,get=1", "relative weights of operations (regist, execute, cancel, bulk, get)")
+	cards       = flag.Int("cards", 100, "number of cards registered before the run")
+	bulkSize    = flag.Int("bulk-size", 3, "number of payments per BulkCancelPayment")
+	initialize  = flag.Bool("initialize", false, "call Initialize before the run")
+	seed        = flag.Int64("seed", 0, "random seed (0 means the current time)")
+)
+
+// 重みつきの操作
+type weightedOp struct {
+	op     string
+	weight int
+}
+
+//"execute=5,cancel=2"のような指定を解釈する
+func parseMix(s string) ([]weightedOp, error) {
+	ops := []weightedOp{}
+	for _, kv := range strings.Split(s, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		op, ok := opNames[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation: %s", parts[0])
+		}
+		weight := 1
+		if len(parts) == 2 {
+			w, err := strconv.Atoi(parts[1])
+			if err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid weight for %s: %s", parts[0], parts[1])
+			}
+			weight = w
+		}
+		if weight > 0 {
+			ops = append(ops, weightedOp{op: op, weight: weight})
+		}
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no operations in mix: %q", s)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].op < ops[j].op })
+	return ops, nil
+}
+
+func pick(ops []weightedOp, r *rand.Rand) string {
+	total := 0
+	for _, o := range ops {
+		total += o.weight
+	}
+	n := r.Intn(total)
+	for _, o := range ops {
+		if n < o.weight {
+			return o.op
+		}
+		n -= o.weight
+	}
+	return ops[len(ops)-1].op
+}
+
+// 実行中に作成したカードトークンと決済ID
+type pool struct {
+	mu       sync.RWMutex
+	tokens   []string
+	payments []string
+}
+
+func (p *pool) addToken(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens = append(p.tokens, token)
+}
+
+func (p *pool) addPayment(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.payments = append(p.payments, id)
+}
+
+func (p *pool) randomToken(r *rand.Rand) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	return p.tokens[r.Intn(len(p.tokens))]
+}
+
+func (p *pool) randomPayments(r *rand.Rand, n int) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.payments) == 0 {
+		return nil
+	}
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = p.payments[r.Intn(len(p.payments))]
+	}
+	return ids
+}
+
+type generator struct {
+	client        pb.PaymentServiceClient
+	pool          *pool
+	stats         *stats
+	reservationID int32
+}
+
+func (g *generator) registCard(ctx context.Context) error {
+	card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+	res, err := g.client.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
+	if err != nil {
+		return err
+	}
+	g.pool.addToken(res.CardToken)
+	return nil
+}
+
+//opを1回実行して計測する. 前提となるカードや決済がなければ何もしない
+func (g *generator) do(ctx context.Context, op string, r *rand.Rand) {
+	var call func() error
+	switch op {
+	case opRegistCard:
+		call = func() error { return g.registCard(ctx) }
+	case opExecutePayment:
+		token := g.pool.randomToken(r)
+		if token == "" {
+			return
+		}
+		pay := &pb.PaymentInformation{
+			CardToken:     token,
+			ReservationId: atomic.AddInt32(&g.reservationID, 1),
+			Amount:        int32(r.Intn(10000) + 1),
+		}
+		call = func() error {
+			res, err := g.client.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+			if err == nil {
+				g.pool.addPayment(res.PaymentId)
+			}
+			return err
+		}
+	case opCancelPayment:
+		ids := g.pool.randomPayments(r, 1)
+		if ids == nil {
+			return
+		}
+		call = func() error {
+			_, err := g.client.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: ids[0]})
+			return err
+		}
+	case opBulkCancel:
+		ids := g.pool.randomPayments(r, *bulkSize)
+		if ids == nil {
+			return
+		}
+		call = func() error {
+			_, err := g.client.BulkCancelPayment(ctx, &pb.BulkCancelPaymentRequest{PaymentId: ids})
+			return err
+		}
+	case opGetPayment:
+		ids := g.pool.randomPayments(r, 1)
+		if ids == nil {
+			return
+		}
+		call = func() error {
+			_, err := g.client.GetPaymentInformation(ctx, &pb.GetPaymentInformationRequest{PaymentId: ids[0]})
+			return err
+		}
+	}
+
+	start := time.Now()
+	err := call()
+	g.stats.record(op, time.Since(start), status.Code(err))
+}
+
+func withKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, server.APIKeyHeader, key)
+}
+
+func main() {
+	flag.Parse()
+
+	ops, err := parseMix(*mix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("failed to connect: %s", err)
+	}
+	defer conn.Close()
+	g := &generator{client: pb.NewPaymentServiceClient(conn), pool: &pool{}, stats: newStats()}
+	ctx := withKey(context.Background(), *apiKey)
+
+	if *initialize {
+		key := *adminKey
+		if key == "" {
+			key = *apiKey
+		}
+		if _, err := g.client.Initialize(withKey(context.Background(), key), &pb.InitializeRequest{}); err != nil {
+			log.Fatalf("failed to initialize: %s", err)
+		}
+	}
+	for i := 0; i < *cards; i++ {
+		if err := g.registCard(ctx); err != nil {
+			log.Fatalf("failed to register card: %s", err)
+		}
+	}
+	log.Printf("addr=%s duration=%s rate=%.1f concurrency=%d mix=%s seed=%d\n", *addr, *duration, *rate, *concurrency, *mix, *seed)
+
+	runCtx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	// rateが指定されていれば一定間隔で実行権を配る. ワーカーが足りない分は捨てる
+	var tickets chan struct{}
+	if *rate > 0 {
+		tickets = make(chan struct{}, *concurrency)
+		go func() {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+			defer ticker.Stop()
+			for {
+				select {
+				case <-runCtx.Done():
+					return
+				case <-ticker.C:
+					select {
+					case tickets <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			for {
+				if tickets != nil {
+					select {
+					case <-runCtx.Done():
+						return
+					case <-tickets:
+					}
+				} else if runCtx.Err() != nil {
+					return
+				}
+				// 終了時刻をまたいだリクエストも計測する
+				g.do(ctx, pick(ops, r), r)
+			}
+		}(rand.New(rand.NewSource(*seed + int64(w))))
+	}
+	wg.Wait()
+
+	g.stats.report(os.Stdout, time.Since(start))
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// 操作ごとのレイテンシとエラーコードを集計する
+type stats struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	codes     map[string]map[codes.Code]int
+}
+
+func newStats() *stats {
+	return &stats{
+		latencies: map[string][]time.Duration{},
+		codes:     map[string]map[codes.Code]int{},
+	}
+}
+
+func (s *stats) record(op string, d time.Duration, code codes.Code) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies[op] = append(s.latencies[op], d)
+	if s.codes[op] == nil {
+		s.codes[op] = map[codes.Code]int{}
+	}
+	s.codes[op][code]++
+}
+
+//昇順に並んだlatenciesのpパーセンタイル(0 < p <= 100)
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	i := int(float64(len(latencies))*p/100+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(latencies) {
+		i = len(latencies) - 1
+	}
+	return latencies[i]
+}
+
+//操作ごとのスループット、レイテンシのパーセンタイル、エラーコードを書き出す
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ops := make([]string, 0, len(s.latencies))
+	total := 0
+	for op, l := range s.latencies {
+		ops = append(ops, op)
+		total += len(l)
+	}
+	sort.Strings(ops)
+
+	fmt.Fprintf(w, "elapsed: %s, requests: %d, throughput: %.1f req/s\n", elapsed.Round(time.Millisecond), total, float64(total)/elapsed.Seconds())
+	fmt.Fprintf(w, "%-20s %8s %10s %10s %10s %10s %10s  %s\n", "op", "count", "req/s", "p50", "p90", "p99", "max", "errors")
+	for _, op := range ops {
+		l := s.latencies[op]
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+
+		errs := ""
+		codeList := make([]codes.Code, 0, len(s.codes[op]))
+		for code := range s.codes[op] {
+			if code != codes.OK {
+				codeList = append(codeList, code)
+			}
+		}
+		sort.Slice(codeList, func(i, j int) bool { return codeList[i] < codeList[j] })
+		for _, code := range codeList {
+			errs += fmt.Sprintf("%s=%d ", code, s.codes[op][code])
+		}
+
+		fmt.Fprintf(w, "%-20s %8d %10.1f %10s %10s %10s %10s  %s\n",
+			op, len(l), float64(len(l))/elapsed.Seconds(),
+			percentile(l, 50).Round(time.Microsecond),
+			percentile(l, 90).Round(time.Microsecond),
+			percentile(l, 99).Round(time.Microsecond),
+			l[len(l)-1].Round(time.Microsecond),
+			errs)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	for _, tc := range []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+		{0.1, 1 * time.Millisecond},
+	} {
+		if got := percentile(latencies, tc.p); got != tc.want {
+			t.Errorf("p%v: Expected:%s but %s\n", tc.p, tc.want, got)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("empty: Expected:0 but %s\n", got)
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	ops, err := parseMix("execute=5, cancel=2,get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weightedOp{{opCancelPayment, 2}, {opExecutePayment, 5}, {opGetPayment, 1}}
+	if len(ops) != len(want) {
+		t.Fatalf("Failed. Expected:%v but %v\n", want, ops)
+	}
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Fatalf("Failed. Expected:%v but %v\n", want, ops)
+		}
+	}
+
+	for _, s := range []string{"", "refund=1", "execute=-1", "execute=0"} {
+		if _, err := parseMix(s); err == nil {
+			t.Errorf("%q: Expected error", s)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"io/ioutil"
+	"time"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -14,6 +16,29 @@ func Load(s string) (*Config, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load")
 	}
+	for rpc, l := range cfg.Latency {
+		if err := l.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid latency for %s", rpc)
+		}
+	}
+	for name, p := range cfg.FaultProfiles {
+		for rpc, f := range p {
+			if err := f.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "invalid fault profile %s for %s", name, rpc)
+			}
+		}
+	}
+	if _, ok := cfg.FaultProfiles[cfg.FaultProfile]; cfg.FaultProfile != "" && !ok {
+		return nil, errors.Errorf("fault profile not found: %s", cfg.FaultProfile)
+	}
+	for code, c := range cfg.Currencies {
+		if err := c.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid currency %s", code)
+		}
+	}
+	if err := validateMerchants(cfg.AdminKey, cfg.Merchants); err != nil {
+		return nil, errors.Wrap(err, "invalid merchants")
+	}
 	return cfg, nil
 }
 
@@ -31,6 +56,200 @@ func LoadFile(filename string) (*Config, error) {
 }
 
 type Config struct {
-	HttpPort string `yaml:"http_port,omitempty"` // HTTP Port
-	GrpcPort string `yaml:"grpc_port,omitempty"` // gRPC Port
+	HttpPort string             `yaml:"http_port,omitempty"` // HTTP Port
+	GrpcPort string             `yaml:"grpc_port,omitempty"` // gRPC Port
+	Latency  map[string]Latency `yaml:"latency,omitempty"`   // RPC名(例: CancelPayment)ごとに注入する遅延
+
+	SinglePort      bool          `yaml:"single_port,omitempty"`      // gRPCとREST APIをhttp_portの1つのポートで提供する(grpc_portは使わない)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout,omitempty"` // 終了時に処理中のリクエストを待つ時間(デフォルト10s)
+	SnapshotFile    string        `yaml:"snapshot_file,omitempty"`    // 終了時に決済とカードの情報を書き出すファイル
+
+	FaultProfile  string                  `yaml:"fault_profile,omitempty"`  // 起動時に有効にする障害注入プロファイル
+	FaultProfiles map[string]FaultProfile `yaml:"fault_profiles,omitempty"` // 障害注入プロファイル(管理APIで切り替えられる)
+
+	AdminKey  string     `yaml:"admin_key,omitempty"` // Initialize, GetResultなどの管理用RPCに使うキー
+	Merchants []Merchant `yaml:"merchants,omitempty"` // マーチャント. admin_keyもmerchantsも未設定の場合は認証しない
+
+	Webhook Webhook `yaml:"webhook,omitempty"` // Webhookの配信設定
+
+	CardTokenTTL time.Duration `yaml:"card_token_ttl,omitempty"` // RegistCardでttl_secondsを指定しなかった場合のカードトークンの有効期間(0の場合は期限なし)
+
+	Currencies map[string]Currency `yaml:"currencies,omitempty"` // 通貨コードごとの円への換算規則(JPYは常に受け付ける)
+}
+
+// 円から換算するときの端数の扱い
+const (
+	RoundingRound = "round" // 四捨五入(0.5は0から遠い方へ)
+	RoundingFloor = "floor" // 切り捨て
+	RoundingCeil  = "ceil"  // 切り上げ
+)
+
+// Currency は通貨と円の換算規則です. webappの ISUTRAIN_CURRENCY_RULES と同じ項目と意味を持ちます
+type Currency struct {
+	JPYPerUnit float64 `yaml:"jpy_per_unit"`       // 主単位(USDなら1ドル)あたりの円
+	Exponent   int     `yaml:"exponent,omitempty"` // 最小単位の桁数(USDなら2)
+	Rounding   string  `yaml:"rounding,omitempty"` // 円から換算するときの端数の扱い(round, floor, ceil). 省略時はround
+}
+
+// Validate は換算規則が正しいか検証します
+func (c Currency) Validate() error {
+	if c.JPYPerUnit <= 0 {
+		return errors.New("jpy_per_unit must be positive")
+	}
+	if c.Exponent < 0 || 4 < c.Exponent {
+		return errors.New("exponent must be between 0 and 4")
+	}
+	switch c.Rounding {
+	case "", RoundingRound, RoundingFloor, RoundingCeil:
+	default:
+		return errors.Errorf("unknown rounding: %q", c.Rounding)
+	}
+	return nil
+}
+
+// Webhook はWebhookの配信設定です. 0の項目はデフォルト値が使われます
+type Webhook struct {
+	MaxAttempts    int           `yaml:"max_attempts,omitempty"`    // 最大配信回数(デフォルト5回)
+	InitialBackoff time.Duration `yaml:"initial_backoff,omitempty"` // 最初の再送までの待ち時間. 再送ごとに倍になる(デフォルト500ms)
+	MaxBackoff     time.Duration `yaml:"max_backoff,omitempty"`     // 再送までの待ち時間の上限(デフォルト30s)
+	Timeout        time.Duration `yaml:"timeout,omitempty"`         // 1回の配信のタイムアウト(デフォルト5s)
+}
+
+// Merchant は決済サービスを利用するマーチャント(webapp)です
+type Merchant struct {
+	ID             string `yaml:"id"`
+	APIKey         string `yaml:"api_key"`                   // すべての決済用RPCに使えるキー
+	PublishableKey string `yaml:"publishable_key,omitempty"` // カード登録(RegistCard)にのみ使えるキー(ブラウザから利用する)
+}
+
+func validateMerchants(adminKey string, merchants []Merchant) error {
+	if len(merchants) == 0 {
+		return nil
+	}
+	if adminKey == "" {
+		return errors.New("admin_key is required when merchants are configured")
+	}
+	ids := map[string]bool{}
+	keys := map[string]bool{adminKey: true}
+	for _, m := range merchants {
+		if m.ID == "" || m.APIKey == "" {
+			return errors.New("id and api_key are required")
+		}
+		if ids[m.ID] {
+			return errors.Errorf("duplicate merchant id: %s", m.ID)
+		}
+		ids[m.ID] = true
+		for _, key := range []string{m.APIKey, m.PublishableKey} {
+			if key == "" {
+				continue
+			}
+			if keys[key] {
+				return errors.Errorf("duplicate key for merchant %s", m.ID)
+			}
+			keys[key] = true
+		}
+	}
+	return nil
+}
+
+// 遅延の分布
+const (
+	DistributionConstant    = "constant"    // 常にDurationだけ遅延する
+	DistributionUniform     = "uniform"     // MinからMaxの一様分布
+	DistributionNormal      = "normal"      // 平均Duration、標準偏差Stddevの正規分布
+	DistributionExponential = "exponential" // 平均Durationの指数分布
+)
+
+// DefaultLatency は、設定ファイルでlatencyを指定しなかった場合に注入する遅延です
+// 返金系のRPCは常に1秒かかる
+func DefaultLatency() map[string]Latency {
+	return map[string]Latency{
+		"CancelPayment":     {Distribution: DistributionConstant, Duration: time.Second},
+		"BulkCancelPayment": {Distribution: DistributionConstant, Duration: time.Second},
+	}
+}
+
+// Latency はRPCに注入する遅延の設定です
+type Latency struct {
+	Distribution string        `yaml:"distribution"`       // 分布(constant, uniform, normal, exponential)
+	Duration     time.Duration `yaml:"duration,omitempty"` // constantの遅延、normal/exponentialの平均
+	Min          time.Duration `yaml:"min,omitempty"`      // uniformの下限、その他の分布では遅延の下限
+	Max          time.Duration `yaml:"max,omitempty"`      // uniformの上限、その他の分布では遅延の上限(0なら無制限)
+	Stddev       time.Duration `yaml:"stddev,omitempty"`   // normalの標準偏差
+}
+
+// Validate は遅延の設定が正しいか検証します
+func (l Latency) Validate() error {
+	switch l.Distribution {
+	case DistributionConstant, DistributionNormal, DistributionExponential:
+	case DistributionUniform:
+		if l.Max < l.Min {
+			return errors.New("max must be greater than or equal to min")
+		}
+	default:
+		return errors.Errorf("unknown distribution: %q", l.Distribution)
+	}
+	if l.Duration < 0 || l.Min < 0 || l.Max < 0 || l.Stddev < 0 {
+		return errors.New("durations must not be negative")
+	}
+	return nil
+}
+
+// FaultProfile はRPC名ごとの障害注入の設定です
+type FaultProfile map[string]Fault
+
+// Fault はRPCに注入する障害の設定です. 確率は0から1で指定します
+type Fault struct {
+	ErrorRate              float64       `yaml:"error_rate,omitempty"`                // ErrorCodeのエラーを返す確率
+	ErrorCode              string        `yaml:"error_code,omitempty"`                // gRPCのステータスコード名(例: InvalidArgument). 省略時はInternal
+	ServerErrorRate        float64       `yaml:"server_error_rate,omitempty"`         // 断続的な5xx(InternalまたはUnavailable)を返す確率
+	TimeoutAfterCommitRate float64       `yaml:"timeout_after_commit_rate,omitempty"` // 処理を確定させた後にDeadlineExceededを返す確率
+	Timeout                time.Duration `yaml:"timeout,omitempty"`                   // DeadlineExceededを返すまでに待つ時間
+	Latency                Percentiles   `yaml:"latency,omitempty"`                   // 処理前に注入する遅延
+}
+
+// Percentiles はパーセンタイルで指定する遅延の分布です. 各区間は線形に補間されます
+type Percentiles struct {
+	P50 time.Duration `yaml:"p50,omitempty"`
+	P90 time.Duration `yaml:"p90,omitempty"`
+	P99 time.Duration `yaml:"p99,omitempty"`
+	Max time.Duration `yaml:"max,omitempty"` // 遅延の上限(0ならP99)
+}
+
+var codeNames = func() map[string]codes.Code {
+	m := map[string]codes.Code{}
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+// Code はErrorCodeをgRPCのステータスコードに変換します
+func (f Fault) Code() codes.Code {
+	if c, ok := codeNames[f.ErrorCode]; ok {
+		return c
+	}
+	return codes.Internal
+}
+
+// Validate は障害注入の設定が正しいか検証します
+func (f Fault) Validate() error {
+	for _, rate := range []float64{f.ErrorRate, f.ServerErrorRate, f.TimeoutAfterCommitRate} {
+		if rate < 0 || 1 < rate {
+			return errors.New("rates must be between 0 and 1")
+		}
+	}
+	if f.ErrorCode != "" {
+		if c, ok := codeNames[f.ErrorCode]; !ok || c == codes.OK {
+			return errors.Errorf("unknown error code: %q", f.ErrorCode)
+		}
+	}
+	if f.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	p := f.Latency
+	if p.P50 < 0 || p.P90 < p.P50 || p.P99 < p.P90 || (p.Max != 0 && p.Max < p.P99) {
+		return errors.New("latency percentiles must satisfy 0 <= p50 <= p90 <= p99 <= max")
+	}
+	return nil
 }
@@ -3,6 +3,9 @@ package config
 import (
 	"flag"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -13,3 +16,124 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("Error parsing %s: %s", "testdata/conf.good.yml", err)
 	}
 }
+
+func TestLoadLatency(t *testing.T) {
+	cfg, err := LoadFile("testdata/conf.latency.yml")
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", "testdata/conf.latency.yml", err)
+	}
+	l, ok := cfg.Latency["CancelPayment"]
+	if !ok {
+		t.Fatal("latency for CancelPayment is not loaded")
+	}
+	if l.Distribution != DistributionUniform || l.Min != 100*time.Millisecond || l.Max != 300*time.Millisecond {
+		t.Fatalf("unexpected latency: %+v", l)
+	}
+
+	_, err = LoadFile("testdata/conf.bad_latency.yml")
+	if err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestLoadFaultProfiles(t *testing.T) {
+	cfg, err := LoadFile("testdata/conf.fault.yml")
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", "testdata/conf.fault.yml", err)
+	}
+	if cfg.FaultProfile != "flaky" || len(cfg.FaultProfiles) != 2 {
+		t.Fatalf("unexpected fault profiles: %s %+v", cfg.FaultProfile, cfg.FaultProfiles)
+	}
+	f := cfg.FaultProfiles["flaky"]["ExecutePayment"]
+	if f.ServerErrorRate != 0.1 || f.Timeout != 3*time.Second || f.Latency.P99 != 500*time.Millisecond {
+		t.Fatalf("unexpected fault: %+v", f)
+	}
+	if c := cfg.FaultProfiles["flaky"]["CancelPayment"].Code(); c != codes.Unavailable {
+		t.Fatalf("Failed. Expected:%s but %s\n", codes.Unavailable, c)
+	}
+	if c := cfg.FaultProfiles["down"]["ExecutePayment"].Code(); c != codes.Internal {
+		t.Fatalf("Failed. Expected:%s but %s\n", codes.Internal, c)
+	}
+
+	_, err = LoadFile("testdata/conf.bad_fault.yml")
+	if err == nil {
+		t.Fatal("should fail")
+	}
+	_, err = Load("fault_profile: missing\n")
+	if err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestLoadMerchants(t *testing.T) {
+	cfg, err := LoadFile("testdata/conf.merchants.yml")
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", "testdata/conf.merchants.yml", err)
+	}
+	if cfg.AdminKey != "admin-secret" || len(cfg.Merchants) != 2 {
+		t.Fatalf("unexpected merchants: %+v", cfg)
+	}
+	if m := cfg.Merchants[0]; m.ID != "team1" || m.APIKey != "sk_team1" || m.PublishableKey != "pk_team1" {
+		t.Fatalf("unexpected merchant: %+v", m)
+	}
+
+	_, err = LoadFile("testdata/conf.bad_merchants.yml")
+	if err == nil {
+		t.Fatal("should fail")
+	}
+	_, err = Load("merchants:\n  - id: team1\n    api_key: sk_team1\n")
+	if err == nil {
+		t.Fatal("should fail without admin_key")
+	}
+}
+
+func TestLoadWebhook(t *testing.T) {
+	cfg, err := Load("webhook:\n  max_attempts: 3\n  initial_backoff: 100ms\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Webhook.MaxAttempts != 3 || cfg.Webhook.InitialBackoff != 100*time.Millisecond || cfg.Webhook.MaxBackoff != 0 {
+		t.Fatalf("unexpected webhook: %+v", cfg.Webhook)
+	}
+}
+
+func TestLoadCurrencies(t *testing.T) {
+	cfg, err := Load("currencies:\n  USD:\n    jpy_per_unit: 150\n    exponent: 2\n    rounding: floor\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := cfg.Currencies["USD"]; c.JPYPerUnit != 150 || c.Exponent != 2 || c.Rounding != RoundingFloor {
+		t.Fatalf("unexpected currency: %+v", c)
+	}
+
+	if _, err := Load("currencies:\n  USD:\n    exponent: 2\n"); err == nil {
+		t.Fatal("should fail without jpy_per_unit")
+	}
+	if _, err := Load("currencies:\n  USD:\n    jpy_per_unit: 150\n    rounding: half_even\n"); err == nil {
+		t.Fatal("should fail with unknown rounding")
+	}
+}
+
+func TestDefaultLatency(t *testing.T) {
+	cfg, err := Load("http_port: 0.0.0.0:5000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Latency != nil {
+		t.Fatalf("latency should be nil when omitted: %+v", cfg.Latency)
+	}
+	for _, rpc := range []string{"CancelPayment", "BulkCancelPayment"} {
+		l, ok := DefaultLatency()[rpc]
+		if !ok || l.Distribution != DistributionConstant || l.Duration != time.Second {
+			t.Fatalf("unexpected default latency for %s: %+v", rpc, l)
+		}
+	}
+
+	cfg, err = Load("latency: {}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Latency == nil || len(cfg.Latency) != 0 {
+		t.Fatalf("empty latency should disable the default: %+v", cfg.Latency)
+	}
+}
@@ -2,11 +2,12 @@ module payment
 
 require (
 	github.com/golang/protobuf v1.3.2
-	github.com/gorilla/handlers v1.4.2
 	github.com/grpc-ecosystem/grpc-gateway v1.9.5
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d
 	github.com/pkg/errors v0.8.1
+	github.com/prometheus/client_golang v1.2.1
 	github.com/rs/xid v1.2.1
+	github.com/soheilhy/cmux v0.1.4
 	google.golang.org/genproto v0.0.0-20190801165951-fa694d86fc64
 	google.golang.org/grpc v1.22.1
 	gopkg.in/yaml.v2 v2.2.2
@@ -1,17 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net"
-	_ "net/http/pprof"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"payment/config"
-	pb "payment/pb"
 	"payment/server"
-
-	"google.golang.org/grpc"
 )
 
 var (
@@ -26,51 +24,78 @@ type PaymentService struct{}
 func main() {
 	fmt.Println(banner)
 
-	httpPort := os.Getenv("PAYMENT_HTTP_PORT")
-	if httpPort == "" {
-		httpPort = "0.0.0.0:5000"
+	//setup config
+	c := config.Config{}
+	if configFile := os.Getenv("PAYMENT_CONFIG_FILE"); configFile != "" {
+		cfg, err := config.LoadFile(configFile)
+		if err != nil {
+			log.Fatalf("failed to load config:%s", err)
+		}
+		c = *cfg
 	}
-	grpcPort := os.Getenv("PAYMENT_GRPC_PORT")
-	if grpcPort == "" {
-		grpcPort = "0.0.0.0:5001"
+	if c.Latency == nil {
+		c.Latency = config.DefaultLatency()
 	}
-
-	//setup config
-	c := config.Config{
-		HttpPort: httpPort,
-		GrpcPort: grpcPort,
+	if httpPort := os.Getenv("PAYMENT_HTTP_PORT"); httpPort != "" {
+		c.HttpPort = httpPort
 	}
-	log.Printf("HTTP Port%s, gRPC Port%s\n", c.HttpPort, c.GrpcPort)
-
-	//setup grpc server
-	lis, err := net.Listen("tcp", c.GrpcPort)
-	if err != nil {
-		log.Fatalf("listen error: %s\n", err)
+	if c.HttpPort == "" {
+		c.HttpPort = "0.0.0.0:5000"
+	}
+	if grpcPort := os.Getenv("PAYMENT_GRPC_PORT"); grpcPort != "" {
+		c.GrpcPort = grpcPort
+	}
+	if c.GrpcPort == "" {
+		c.GrpcPort = "0.0.0.0:5001"
+	}
+	if adminKey := os.Getenv("PAYMENT_ADMIN_KEY"); adminKey != "" {
+		c.AdminKey = adminKey
+	}
+	if os.Getenv("PAYMENT_SINGLE_PORT") == "1" {
+		c.SinglePort = true
+	}
+	if snapshotFile := os.Getenv("PAYMENT_SNAPSHOT_FILE"); snapshotFile != "" {
+		c.SnapshotFile = snapshotFile
+	}
+	if c.SinglePort {
+		log.Printf("HTTP/gRPC Port%s\n", c.HttpPort)
+	} else {
+		log.Printf("HTTP Port%s, gRPC Port%s\n", c.HttpPort, c.GrpcPort)
+	}
+	for rpc, l := range c.Latency {
+		log.Printf("Latency %s: %+v\n", rpc, l)
+	}
+	if c.FaultProfile != "" {
+		log.Printf("Fault profile: %s\n", c.FaultProfile)
+	}
+	if c.AdminKey != "" || len(c.Merchants) > 0 {
+		log.Printf("API Key authentication enabled: %d merchants\n", len(c.Merchants))
 	}
-	g := grpc.NewServer()
 
-	s, err := server.NewNetworkServer()
+	s, err := server.NewNetworkServer(
+		server.WithLatency(c.Latency),
+		server.WithFaultProfiles(c.FaultProfiles, c.FaultProfile),
+		server.WithAuth(c.AdminKey, c.Merchants),
+		server.WithWebhook(c.Webhook),
+		server.WithCardTokenTTL(c.CardTokenTTL),
+		server.WithCurrencies(c.Currencies),
+		server.WithSnapshotFile(c.SnapshotFile),
+	)
 	if err != nil {
 		log.Fatalf("failed to create new server:%s", err)
 	}
 
-	pb.RegisterPaymentServiceServer(g, s)
-	done := make(chan struct{})
-	go func() {
-		err = g.Serve(lis)
-		if err != nil {
-			log.Fatal(err)
-		}
-		done <- struct{}{}
-	}()
-
+	//SIGINT, SIGTERMで処理中のリクエストを待ってから終了する
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		if err := server.StartGRPCGateway(c); err != nil {
-			log.Fatal(err)
-		}
-		done <- struct{}{}
+		log.Printf("Received %s\n", <-sig)
+		cancel()
 	}()
-	<-done // waiting finish goroutine
 
-	log.Fatal("Program exit")
+	if err := server.Serve(ctx, c, s); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Program exit")
 }
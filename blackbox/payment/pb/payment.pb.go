@@ -9,6 +9,7 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -26,6 +27,115 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// カードトークンを使える回数
+type CardTokenUsage int32
+
+const (
+	CardTokenUsage_MULTI_USE CardTokenUsage = 0
+	// 決済に1回使うと使えなくなる
+	CardTokenUsage_SINGLE_USE CardTokenUsage = 1
+)
+
+var CardTokenUsage_name = map[int32]string{
+	0: "MULTI_USE",
+	1: "SINGLE_USE",
+}
+
+var CardTokenUsage_value = map[string]int32{
+	"MULTI_USE":  0,
+	"SINGLE_USE": 1,
+}
+
+func (x CardTokenUsage) String() string {
+	return proto.EnumName(CardTokenUsage_name, int32(x))
+}
+
+func (CardTokenUsage) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{0}
+}
+
+type CardTokenStatus int32
+
+const (
+	CardTokenStatus_ACTIVE  CardTokenStatus = 0
+	CardTokenStatus_EXPIRED CardTokenStatus = 1
+	CardTokenStatus_REVOKED CardTokenStatus = 2
+	// SINGLE_USEのトークンが決済に使われた
+	CardTokenStatus_USED CardTokenStatus = 3
+)
+
+var CardTokenStatus_name = map[int32]string{
+	0: "ACTIVE",
+	1: "EXPIRED",
+	2: "REVOKED",
+	3: "USED",
+}
+
+var CardTokenStatus_value = map[string]int32{
+	"ACTIVE":  0,
+	"EXPIRED": 1,
+	"REVOKED": 2,
+	"USED":    3,
+}
+
+func (x CardTokenStatus) String() string {
+	return proto.EnumName(CardTokenStatus_name, int32(x))
+}
+
+func (CardTokenStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{1}
+}
+
+type ExportFormat int32
+
+const (
+	ExportFormat_JSON ExportFormat = 0
+	ExportFormat_CSV  ExportFormat = 1
+)
+
+var ExportFormat_name = map[int32]string{
+	0: "JSON",
+	1: "CSV",
+}
+
+var ExportFormat_value = map[string]int32{
+	"JSON": 0,
+	"CSV":  1,
+}
+
+func (x ExportFormat) String() string {
+	return proto.EnumName(ExportFormat_name, int32(x))
+}
+
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{2}
+}
+
+type SettlementReportRequest_GroupBy int32
+
+const (
+	SettlementReportRequest_DAY         SettlementReportRequest_GroupBy = 0
+	SettlementReportRequest_RESERVATION SettlementReportRequest_GroupBy = 1
+)
+
+var SettlementReportRequest_GroupBy_name = map[int32]string{
+	0: "DAY",
+	1: "RESERVATION",
+}
+
+var SettlementReportRequest_GroupBy_value = map[string]int32{
+	"DAY":         0,
+	"RESERVATION": 1,
+}
+
+func (x SettlementReportRequest_GroupBy) String() string {
+	return proto.EnumName(SettlementReportRequest_GroupBy_name, int32(x))
+}
+
+func (SettlementReportRequest_GroupBy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{34, 0}
+}
+
 type CardInformation struct {
 	CardNumber           string   `protobuf:"bytes,1,opt,name=card_number,json=cardNumber,proto3" json:"card_number,omitempty"`
 	Cvv                  string   `protobuf:"bytes,2,opt,name=cvv,proto3" json:"cvv,omitempty"`
@@ -82,10 +192,13 @@ func (m *CardInformation) GetExpiryDate() string {
 }
 
 type RegistCardRequest struct {
-	CardInformation      *CardInformation `protobuf:"bytes,1,opt,name=card_information,json=cardInformation,proto3" json:"card_information,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	CardInformation *CardInformation `protobuf:"bytes,1,opt,name=card_information,json=cardInformation,proto3" json:"card_information,omitempty"`
+	// トークンの有効期間(秒). 0の場合はサーバの設定(card_token_ttl)に従う
+	TtlSeconds           int64          `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	Usage                CardTokenUsage `protobuf:"varint,3,opt,name=usage,proto3,enum=paymentpb.CardTokenUsage" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *RegistCardRequest) Reset()         { *m = RegistCardRequest{} }
@@ -120,12 +233,33 @@ func (m *RegistCardRequest) GetCardInformation() *CardInformation {
 	return nil
 }
 
+func (m *RegistCardRequest) GetTtlSeconds() int64 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
+func (m *RegistCardRequest) GetUsage() CardTokenUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return CardTokenUsage_MULTI_USE
+}
+
 type RegistCardResponse struct {
-	CardToken            string   `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
-	IsOk                 bool     `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	CardToken string `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
+	IsOk      bool   `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	// カードブランド(visa, mastercard, amex, jcb, diners, discover)
+	Brand string `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	// カード番号の下4桁
+	Last4 string `protobuf:"bytes,4,opt,name=last4,proto3" json:"last4,omitempty"`
+	// トークンの有効期限. 期限がない場合は省略される
+	ExpiresAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Usage                CardTokenUsage       `protobuf:"varint,6,opt,name=usage,proto3,enum=paymentpb.CardTokenUsage" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *RegistCardResponse) Reset()         { *m = RegistCardResponse{} }
@@ -167,604 +301,2184 @@ func (m *RegistCardResponse) GetIsOk() bool {
 	return false
 }
 
-type PaymentInformation struct {
-	CardToken            string               `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
-	ReservationId        int32                `protobuf:"varint,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
-	Datetime             *timestamp.Timestamp `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
-	Amount               int32                `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	IsCanceled           bool                 `protobuf:"varint,5,opt,name=is_canceled,json=isCanceled,proto3" json:"is_canceled,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
-}
-
-func (m *PaymentInformation) Reset()         { *m = PaymentInformation{} }
-func (m *PaymentInformation) String() string { return proto.CompactTextString(m) }
-func (*PaymentInformation) ProtoMessage()    {}
-func (*PaymentInformation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{3}
-}
-
-func (m *PaymentInformation) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PaymentInformation.Unmarshal(m, b)
-}
-func (m *PaymentInformation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PaymentInformation.Marshal(b, m, deterministic)
-}
-func (m *PaymentInformation) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PaymentInformation.Merge(m, src)
-}
-func (m *PaymentInformation) XXX_Size() int {
-	return xxx_messageInfo_PaymentInformation.Size(m)
-}
-func (m *PaymentInformation) XXX_DiscardUnknown() {
-	xxx_messageInfo_PaymentInformation.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_PaymentInformation proto.InternalMessageInfo
-
-func (m *PaymentInformation) GetCardToken() string {
+func (m *RegistCardResponse) GetBrand() string {
 	if m != nil {
-		return m.CardToken
+		return m.Brand
 	}
 	return ""
 }
 
-func (m *PaymentInformation) GetReservationId() int32 {
+func (m *RegistCardResponse) GetLast4() string {
 	if m != nil {
-		return m.ReservationId
+		return m.Last4
 	}
-	return 0
+	return ""
 }
 
-func (m *PaymentInformation) GetDatetime() *timestamp.Timestamp {
+func (m *RegistCardResponse) GetExpiresAt() *timestamp.Timestamp {
 	if m != nil {
-		return m.Datetime
+		return m.ExpiresAt
 	}
 	return nil
 }
 
-func (m *PaymentInformation) GetAmount() int32 {
+func (m *RegistCardResponse) GetUsage() CardTokenUsage {
 	if m != nil {
-		return m.Amount
+		return m.Usage
 	}
-	return 0
+	return CardTokenUsage_MULTI_USE
 }
 
-func (m *PaymentInformation) GetIsCanceled() bool {
+type RevokeCardTokenRequest struct {
+	CardToken            string   `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeCardTokenRequest) Reset()         { *m = RevokeCardTokenRequest{} }
+func (m *RevokeCardTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeCardTokenRequest) ProtoMessage()    {}
+func (*RevokeCardTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{3}
+}
+
+func (m *RevokeCardTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeCardTokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeCardTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeCardTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeCardTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeCardTokenRequest.Merge(m, src)
+}
+func (m *RevokeCardTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeCardTokenRequest.Size(m)
+}
+func (m *RevokeCardTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeCardTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeCardTokenRequest proto.InternalMessageInfo
+
+func (m *RevokeCardTokenRequest) GetCardToken() string {
 	if m != nil {
-		return m.IsCanceled
+		return m.CardToken
 	}
-	return false
+	return ""
 }
 
-type ExecutePaymentRequest struct {
-	PaymentInformation   *PaymentInformation `protobuf:"bytes,1,opt,name=payment_information,json=paymentInformation,proto3" json:"payment_information,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+type RevokeCardTokenResponse struct {
+	IsOk                 bool     `protobuf:"varint,1,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ExecutePaymentRequest) Reset()         { *m = ExecutePaymentRequest{} }
-func (m *ExecutePaymentRequest) String() string { return proto.CompactTextString(m) }
-func (*ExecutePaymentRequest) ProtoMessage()    {}
-func (*ExecutePaymentRequest) Descriptor() ([]byte, []int) {
+func (m *RevokeCardTokenResponse) Reset()         { *m = RevokeCardTokenResponse{} }
+func (m *RevokeCardTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeCardTokenResponse) ProtoMessage()    {}
+func (*RevokeCardTokenResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_595799929d632654, []int{4}
 }
 
-func (m *ExecutePaymentRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ExecutePaymentRequest.Unmarshal(m, b)
+func (m *RevokeCardTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeCardTokenResponse.Unmarshal(m, b)
 }
-func (m *ExecutePaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ExecutePaymentRequest.Marshal(b, m, deterministic)
+func (m *RevokeCardTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeCardTokenResponse.Marshal(b, m, deterministic)
 }
-func (m *ExecutePaymentRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExecutePaymentRequest.Merge(m, src)
+func (m *RevokeCardTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeCardTokenResponse.Merge(m, src)
 }
-func (m *ExecutePaymentRequest) XXX_Size() int {
-	return xxx_messageInfo_ExecutePaymentRequest.Size(m)
+func (m *RevokeCardTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeCardTokenResponse.Size(m)
 }
-func (m *ExecutePaymentRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExecutePaymentRequest.DiscardUnknown(m)
+func (m *RevokeCardTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeCardTokenResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExecutePaymentRequest proto.InternalMessageInfo
+var xxx_messageInfo_RevokeCardTokenResponse proto.InternalMessageInfo
 
-func (m *ExecutePaymentRequest) GetPaymentInformation() *PaymentInformation {
+func (m *RevokeCardTokenResponse) GetIsOk() bool {
 	if m != nil {
-		return m.PaymentInformation
+		return m.IsOk
 	}
-	return nil
+	return false
 }
 
-type ExecutePaymentResponse struct {
-	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	IsOk                 bool     `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+type ListCardTokensRequest struct {
+	// 管理キーの場合のみ指定できる. マーチャントのAPIキーの場合は自身のものに限られる
+	MerchantId string `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	// 期限切れ、失効済み、使用済みのトークンも含める
+	IncludeInactive      bool     `protobuf:"varint,2,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ExecutePaymentResponse) Reset()         { *m = ExecutePaymentResponse{} }
-func (m *ExecutePaymentResponse) String() string { return proto.CompactTextString(m) }
-func (*ExecutePaymentResponse) ProtoMessage()    {}
-func (*ExecutePaymentResponse) Descriptor() ([]byte, []int) {
+func (m *ListCardTokensRequest) Reset()         { *m = ListCardTokensRequest{} }
+func (m *ListCardTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCardTokensRequest) ProtoMessage()    {}
+func (*ListCardTokensRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_595799929d632654, []int{5}
 }
 
-func (m *ExecutePaymentResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ExecutePaymentResponse.Unmarshal(m, b)
+func (m *ListCardTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCardTokensRequest.Unmarshal(m, b)
 }
-func (m *ExecutePaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ExecutePaymentResponse.Marshal(b, m, deterministic)
+func (m *ListCardTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCardTokensRequest.Marshal(b, m, deterministic)
 }
-func (m *ExecutePaymentResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExecutePaymentResponse.Merge(m, src)
+func (m *ListCardTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCardTokensRequest.Merge(m, src)
 }
-func (m *ExecutePaymentResponse) XXX_Size() int {
-	return xxx_messageInfo_ExecutePaymentResponse.Size(m)
+func (m *ListCardTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListCardTokensRequest.Size(m)
 }
-func (m *ExecutePaymentResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExecutePaymentResponse.DiscardUnknown(m)
+func (m *ListCardTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCardTokensRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExecutePaymentResponse proto.InternalMessageInfo
+var xxx_messageInfo_ListCardTokensRequest proto.InternalMessageInfo
 
-func (m *ExecutePaymentResponse) GetPaymentId() string {
+func (m *ListCardTokensRequest) GetMerchantId() string {
 	if m != nil {
-		return m.PaymentId
+		return m.MerchantId
 	}
 	return ""
 }
 
-func (m *ExecutePaymentResponse) GetIsOk() bool {
+func (m *ListCardTokensRequest) GetIncludeInactive() bool {
 	if m != nil {
-		return m.IsOk
+		return m.IncludeInactive
 	}
 	return false
 }
 
-type CancelPaymentRequest struct {
-	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type CardToken struct {
+	CardToken            string               `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
+	MerchantId           string               `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	Brand                string               `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	Last4                string               `protobuf:"bytes,4,opt,name=last4,proto3" json:"last4,omitempty"`
+	Usage                CardTokenUsage       `protobuf:"varint,5,opt,name=usage,proto3,enum=paymentpb.CardTokenUsage" json:"usage,omitempty"`
+	Status               CardTokenStatus      `protobuf:"varint,6,opt,name=status,proto3,enum=paymentpb.CardTokenStatus" json:"status,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt            *timestamp.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *CancelPaymentRequest) Reset()         { *m = CancelPaymentRequest{} }
-func (m *CancelPaymentRequest) String() string { return proto.CompactTextString(m) }
-func (*CancelPaymentRequest) ProtoMessage()    {}
-func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
+func (m *CardToken) Reset()         { *m = CardToken{} }
+func (m *CardToken) String() string { return proto.CompactTextString(m) }
+func (*CardToken) ProtoMessage()    {}
+func (*CardToken) Descriptor() ([]byte, []int) {
 	return fileDescriptor_595799929d632654, []int{6}
 }
 
-func (m *CancelPaymentRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CancelPaymentRequest.Unmarshal(m, b)
+func (m *CardToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CardToken.Unmarshal(m, b)
 }
-func (m *CancelPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CancelPaymentRequest.Marshal(b, m, deterministic)
+func (m *CardToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CardToken.Marshal(b, m, deterministic)
 }
-func (m *CancelPaymentRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CancelPaymentRequest.Merge(m, src)
+func (m *CardToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CardToken.Merge(m, src)
 }
-func (m *CancelPaymentRequest) XXX_Size() int {
-	return xxx_messageInfo_CancelPaymentRequest.Size(m)
+func (m *CardToken) XXX_Size() int {
+	return xxx_messageInfo_CardToken.Size(m)
 }
-func (m *CancelPaymentRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CancelPaymentRequest.DiscardUnknown(m)
+func (m *CardToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_CardToken.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CancelPaymentRequest proto.InternalMessageInfo
+var xxx_messageInfo_CardToken proto.InternalMessageInfo
 
-func (m *CancelPaymentRequest) GetPaymentId() string {
+func (m *CardToken) GetCardToken() string {
 	if m != nil {
-		return m.PaymentId
+		return m.CardToken
 	}
 	return ""
 }
 
-type CancelPaymentResponse struct {
-	IsOk                 bool     `protobuf:"varint,1,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CardToken) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
 }
 
-func (m *CancelPaymentResponse) Reset()         { *m = CancelPaymentResponse{} }
-func (m *CancelPaymentResponse) String() string { return proto.CompactTextString(m) }
-func (*CancelPaymentResponse) ProtoMessage()    {}
-func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{7}
+func (m *CardToken) GetBrand() string {
+	if m != nil {
+		return m.Brand
+	}
+	return ""
 }
 
-func (m *CancelPaymentResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CancelPaymentResponse.Unmarshal(m, b)
-}
-func (m *CancelPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CancelPaymentResponse.Marshal(b, m, deterministic)
-}
-func (m *CancelPaymentResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CancelPaymentResponse.Merge(m, src)
+func (m *CardToken) GetLast4() string {
+	if m != nil {
+		return m.Last4
+	}
+	return ""
 }
-func (m *CancelPaymentResponse) XXX_Size() int {
-	return xxx_messageInfo_CancelPaymentResponse.Size(m)
+
+func (m *CardToken) GetUsage() CardTokenUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return CardTokenUsage_MULTI_USE
 }
-func (m *CancelPaymentResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_CancelPaymentResponse.DiscardUnknown(m)
+
+func (m *CardToken) GetStatus() CardTokenStatus {
+	if m != nil {
+		return m.Status
+	}
+	return CardTokenStatus_ACTIVE
 }
 
-var xxx_messageInfo_CancelPaymentResponse proto.InternalMessageInfo
+func (m *CardToken) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
 
-func (m *CancelPaymentResponse) GetIsOk() bool {
+func (m *CardToken) GetExpiresAt() *timestamp.Timestamp {
 	if m != nil {
-		return m.IsOk
+		return m.ExpiresAt
 	}
-	return false
+	return nil
 }
 
-type BulkCancelPaymentRequest struct {
-	PaymentId            []string `protobuf:"bytes,1,rep,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type ListCardTokensResponse struct {
+	CardTokens           []*CardToken `protobuf:"bytes,1,rep,name=card_tokens,json=cardTokens,proto3" json:"card_tokens,omitempty"`
+	IsOk                 bool         `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func (m *BulkCancelPaymentRequest) Reset()         { *m = BulkCancelPaymentRequest{} }
-func (m *BulkCancelPaymentRequest) String() string { return proto.CompactTextString(m) }
-func (*BulkCancelPaymentRequest) ProtoMessage()    {}
-func (*BulkCancelPaymentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{8}
+func (m *ListCardTokensResponse) Reset()         { *m = ListCardTokensResponse{} }
+func (m *ListCardTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCardTokensResponse) ProtoMessage()    {}
+func (*ListCardTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{7}
 }
 
-func (m *BulkCancelPaymentRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BulkCancelPaymentRequest.Unmarshal(m, b)
+func (m *ListCardTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCardTokensResponse.Unmarshal(m, b)
 }
-func (m *BulkCancelPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BulkCancelPaymentRequest.Marshal(b, m, deterministic)
+func (m *ListCardTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCardTokensResponse.Marshal(b, m, deterministic)
 }
-func (m *BulkCancelPaymentRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BulkCancelPaymentRequest.Merge(m, src)
+func (m *ListCardTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCardTokensResponse.Merge(m, src)
 }
-func (m *BulkCancelPaymentRequest) XXX_Size() int {
-	return xxx_messageInfo_BulkCancelPaymentRequest.Size(m)
+func (m *ListCardTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCardTokensResponse.Size(m)
 }
-func (m *BulkCancelPaymentRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_BulkCancelPaymentRequest.DiscardUnknown(m)
+func (m *ListCardTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCardTokensResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BulkCancelPaymentRequest proto.InternalMessageInfo
+var xxx_messageInfo_ListCardTokensResponse proto.InternalMessageInfo
 
-func (m *BulkCancelPaymentRequest) GetPaymentId() []string {
+func (m *ListCardTokensResponse) GetCardTokens() []*CardToken {
 	if m != nil {
-		return m.PaymentId
+		return m.CardTokens
 	}
 	return nil
 }
 
-type BulkCancelPaymentResponse struct {
-	Deleted              int32    `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+func (m *ListCardTokensResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+// 通貨つきの金額
+type Money struct {
+	// ISO 4217の通貨コード(例: JPY, USD). 空の場合はJPY
+	Currency string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// 通貨の最小単位(JPYは円、USDはセント)での金額
+	Amount               int64    `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *BulkCancelPaymentResponse) Reset()         { *m = BulkCancelPaymentResponse{} }
-func (m *BulkCancelPaymentResponse) String() string { return proto.CompactTextString(m) }
-func (*BulkCancelPaymentResponse) ProtoMessage()    {}
-func (*BulkCancelPaymentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{9}
+func (m *Money) Reset()         { *m = Money{} }
+func (m *Money) String() string { return proto.CompactTextString(m) }
+func (*Money) ProtoMessage()    {}
+func (*Money) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{8}
 }
 
-func (m *BulkCancelPaymentResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BulkCancelPaymentResponse.Unmarshal(m, b)
+func (m *Money) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Money.Unmarshal(m, b)
 }
-func (m *BulkCancelPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BulkCancelPaymentResponse.Marshal(b, m, deterministic)
+func (m *Money) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Money.Marshal(b, m, deterministic)
 }
-func (m *BulkCancelPaymentResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BulkCancelPaymentResponse.Merge(m, src)
+func (m *Money) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Money.Merge(m, src)
 }
-func (m *BulkCancelPaymentResponse) XXX_Size() int {
-	return xxx_messageInfo_BulkCancelPaymentResponse.Size(m)
+func (m *Money) XXX_Size() int {
+	return xxx_messageInfo_Money.Size(m)
 }
-func (m *BulkCancelPaymentResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_BulkCancelPaymentResponse.DiscardUnknown(m)
+func (m *Money) XXX_DiscardUnknown() {
+	xxx_messageInfo_Money.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BulkCancelPaymentResponse proto.InternalMessageInfo
+var xxx_messageInfo_Money proto.InternalMessageInfo
 
-func (m *BulkCancelPaymentResponse) GetDeleted() int32 {
+func (m *Money) GetCurrency() string {
 	if m != nil {
-		return m.Deleted
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Money) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
 	}
 	return 0
 }
 
-type GetPaymentInformationRequest struct {
-	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+type PaymentInformation struct {
+	CardToken     string               `protobuf:"bytes,1,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
+	ReservationId int32                `protobuf:"varint,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	Datetime      *timestamp.Timestamp `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	// 円での決済金額(税込). priceがJPY以外の場合は換算した金額が記録される
+	Amount     int32 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	IsCanceled bool  `protobuf:"varint,5,opt,name=is_canceled,json=isCanceled,proto3" json:"is_canceled,omitempty"`
+	// 決済した通貨での金額(税込). 省略した場合はamountをJPYとして扱う
+	Price *Money `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	// priceに含まれる消費税額(priceと同じ通貨)
+	Tax                  *Money   `protobuf:"bytes,7,opt,name=tax,proto3" json:"tax,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetPaymentInformationRequest) Reset()         { *m = GetPaymentInformationRequest{} }
-func (m *GetPaymentInformationRequest) String() string { return proto.CompactTextString(m) }
-func (*GetPaymentInformationRequest) ProtoMessage()    {}
-func (*GetPaymentInformationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{10}
+func (m *PaymentInformation) Reset()         { *m = PaymentInformation{} }
+func (m *PaymentInformation) String() string { return proto.CompactTextString(m) }
+func (*PaymentInformation) ProtoMessage()    {}
+func (*PaymentInformation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{9}
 }
 
-func (m *GetPaymentInformationRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetPaymentInformationRequest.Unmarshal(m, b)
+func (m *PaymentInformation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PaymentInformation.Unmarshal(m, b)
 }
-func (m *GetPaymentInformationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetPaymentInformationRequest.Marshal(b, m, deterministic)
+func (m *PaymentInformation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PaymentInformation.Marshal(b, m, deterministic)
 }
-func (m *GetPaymentInformationRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetPaymentInformationRequest.Merge(m, src)
+func (m *PaymentInformation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PaymentInformation.Merge(m, src)
 }
-func (m *GetPaymentInformationRequest) XXX_Size() int {
-	return xxx_messageInfo_GetPaymentInformationRequest.Size(m)
+func (m *PaymentInformation) XXX_Size() int {
+	return xxx_messageInfo_PaymentInformation.Size(m)
 }
-func (m *GetPaymentInformationRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetPaymentInformationRequest.DiscardUnknown(m)
+func (m *PaymentInformation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PaymentInformation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetPaymentInformationRequest proto.InternalMessageInfo
+var xxx_messageInfo_PaymentInformation proto.InternalMessageInfo
 
-func (m *GetPaymentInformationRequest) GetPaymentId() string {
+func (m *PaymentInformation) GetCardToken() string {
 	if m != nil {
-		return m.PaymentId
+		return m.CardToken
 	}
 	return ""
 }
 
-type GetPaymentInformationResponse struct {
-	PaymentInformation   *PaymentInformation `protobuf:"bytes,1,opt,name=payment_information,json=paymentInformation,proto3" json:"payment_information,omitempty"`
-	IsOk                 bool                `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+func (m *PaymentInformation) GetReservationId() int32 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
 }
 
-func (m *GetPaymentInformationResponse) Reset()         { *m = GetPaymentInformationResponse{} }
-func (m *GetPaymentInformationResponse) String() string { return proto.CompactTextString(m) }
-func (*GetPaymentInformationResponse) ProtoMessage()    {}
-func (*GetPaymentInformationResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{11}
+func (m *PaymentInformation) GetDatetime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Datetime
+	}
+	return nil
 }
 
-func (m *GetPaymentInformationResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetPaymentInformationResponse.Unmarshal(m, b)
-}
-func (m *GetPaymentInformationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetPaymentInformationResponse.Marshal(b, m, deterministic)
-}
-func (m *GetPaymentInformationResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetPaymentInformationResponse.Merge(m, src)
-}
-func (m *GetPaymentInformationResponse) XXX_Size() int {
-	return xxx_messageInfo_GetPaymentInformationResponse.Size(m)
-}
-func (m *GetPaymentInformationResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetPaymentInformationResponse.DiscardUnknown(m)
+func (m *PaymentInformation) GetAmount() int32 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
 }
 
-var xxx_messageInfo_GetPaymentInformationResponse proto.InternalMessageInfo
+func (m *PaymentInformation) GetIsCanceled() bool {
+	if m != nil {
+		return m.IsCanceled
+	}
+	return false
+}
 
-func (m *GetPaymentInformationResponse) GetPaymentInformation() *PaymentInformation {
+func (m *PaymentInformation) GetPrice() *Money {
 	if m != nil {
-		return m.PaymentInformation
+		return m.Price
 	}
 	return nil
 }
 
-func (m *GetPaymentInformationResponse) GetIsOk() bool {
+func (m *PaymentInformation) GetTax() *Money {
 	if m != nil {
-		return m.IsOk
+		return m.Tax
 	}
-	return false
+	return nil
 }
 
-type InitializeRequest struct {
+type ExecutePaymentRequest struct {
+	PaymentInformation *PaymentInformation `protobuf:"bytes,1,opt,name=payment_information,json=paymentInformation,proto3" json:"payment_information,omitempty"`
+	// 冪等キー。未指定の場合は card_token と reservation_id から生成される
+	IdempotencyKey       string   `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *InitializeRequest) Reset()         { *m = InitializeRequest{} }
-func (m *InitializeRequest) String() string { return proto.CompactTextString(m) }
-func (*InitializeRequest) ProtoMessage()    {}
-func (*InitializeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{12}
+func (m *ExecutePaymentRequest) Reset()         { *m = ExecutePaymentRequest{} }
+func (m *ExecutePaymentRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecutePaymentRequest) ProtoMessage()    {}
+func (*ExecutePaymentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{10}
 }
 
-func (m *InitializeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_InitializeRequest.Unmarshal(m, b)
+func (m *ExecutePaymentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutePaymentRequest.Unmarshal(m, b)
 }
-func (m *InitializeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_InitializeRequest.Marshal(b, m, deterministic)
+func (m *ExecutePaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutePaymentRequest.Marshal(b, m, deterministic)
 }
-func (m *InitializeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_InitializeRequest.Merge(m, src)
+func (m *ExecutePaymentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutePaymentRequest.Merge(m, src)
 }
-func (m *InitializeRequest) XXX_Size() int {
-	return xxx_messageInfo_InitializeRequest.Size(m)
+func (m *ExecutePaymentRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecutePaymentRequest.Size(m)
 }
-func (m *InitializeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_InitializeRequest.DiscardUnknown(m)
+func (m *ExecutePaymentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutePaymentRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_InitializeRequest proto.InternalMessageInfo
+var xxx_messageInfo_ExecutePaymentRequest proto.InternalMessageInfo
 
-type InitializeResponse struct {
-	IsOk                 bool     `protobuf:"varint,1,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+func (m *ExecutePaymentRequest) GetPaymentInformation() *PaymentInformation {
+	if m != nil {
+		return m.PaymentInformation
+	}
+	return nil
+}
+
+func (m *ExecutePaymentRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type ExecutePaymentResponse struct {
+	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	IsOk                 bool     `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *InitializeResponse) Reset()         { *m = InitializeResponse{} }
-func (m *InitializeResponse) String() string { return proto.CompactTextString(m) }
-func (*InitializeResponse) ProtoMessage()    {}
-func (*InitializeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{13}
+func (m *ExecutePaymentResponse) Reset()         { *m = ExecutePaymentResponse{} }
+func (m *ExecutePaymentResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecutePaymentResponse) ProtoMessage()    {}
+func (*ExecutePaymentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{11}
 }
 
-func (m *InitializeResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_InitializeResponse.Unmarshal(m, b)
+func (m *ExecutePaymentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutePaymentResponse.Unmarshal(m, b)
 }
-func (m *InitializeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_InitializeResponse.Marshal(b, m, deterministic)
+func (m *ExecutePaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutePaymentResponse.Marshal(b, m, deterministic)
 }
-func (m *InitializeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_InitializeResponse.Merge(m, src)
+func (m *ExecutePaymentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutePaymentResponse.Merge(m, src)
 }
-func (m *InitializeResponse) XXX_Size() int {
-	return xxx_messageInfo_InitializeResponse.Size(m)
+func (m *ExecutePaymentResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecutePaymentResponse.Size(m)
 }
-func (m *InitializeResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_InitializeResponse.DiscardUnknown(m)
+func (m *ExecutePaymentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutePaymentResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_InitializeResponse proto.InternalMessageInfo
+var xxx_messageInfo_ExecutePaymentResponse proto.InternalMessageInfo
 
-func (m *InitializeResponse) GetIsOk() bool {
+func (m *ExecutePaymentResponse) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *ExecutePaymentResponse) GetIsOk() bool {
 	if m != nil {
 		return m.IsOk
 	}
 	return false
 }
 
-type GetResultRequest struct {
+type CancelPaymentRequest struct {
+	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetResultRequest) Reset()         { *m = GetResultRequest{} }
-func (m *GetResultRequest) String() string { return proto.CompactTextString(m) }
-func (*GetResultRequest) ProtoMessage()    {}
-func (*GetResultRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{14}
+func (m *CancelPaymentRequest) Reset()         { *m = CancelPaymentRequest{} }
+func (m *CancelPaymentRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelPaymentRequest) ProtoMessage()    {}
+func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{12}
 }
 
-func (m *GetResultRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetResultRequest.Unmarshal(m, b)
-}
-func (m *GetResultRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetResultRequest.Marshal(b, m, deterministic)
+func (m *CancelPaymentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelPaymentRequest.Unmarshal(m, b)
 }
-func (m *GetResultRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetResultRequest.Merge(m, src)
+func (m *CancelPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelPaymentRequest.Marshal(b, m, deterministic)
 }
-func (m *GetResultRequest) XXX_Size() int {
-	return xxx_messageInfo_GetResultRequest.Size(m)
+func (m *CancelPaymentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelPaymentRequest.Merge(m, src)
 }
-func (m *GetResultRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetResultRequest.DiscardUnknown(m)
+func (m *CancelPaymentRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelPaymentRequest.Size(m)
+}
+func (m *CancelPaymentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelPaymentRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetResultRequest proto.InternalMessageInfo
+var xxx_messageInfo_CancelPaymentRequest proto.InternalMessageInfo
 
-type RawData struct {
+func (m *CancelPaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type CancelPaymentResponse struct {
+	IsOk                 bool     `protobuf:"varint,1,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelPaymentResponse) Reset()         { *m = CancelPaymentResponse{} }
+func (m *CancelPaymentResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelPaymentResponse) ProtoMessage()    {}
+func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{13}
+}
+
+func (m *CancelPaymentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelPaymentResponse.Unmarshal(m, b)
+}
+func (m *CancelPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelPaymentResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelPaymentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelPaymentResponse.Merge(m, src)
+}
+func (m *CancelPaymentResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelPaymentResponse.Size(m)
+}
+func (m *CancelPaymentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelPaymentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelPaymentResponse proto.InternalMessageInfo
+
+func (m *CancelPaymentResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type BulkCancelPaymentRequest struct {
+	PaymentId            []string `protobuf:"bytes,1,rep,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkCancelPaymentRequest) Reset()         { *m = BulkCancelPaymentRequest{} }
+func (m *BulkCancelPaymentRequest) String() string { return proto.CompactTextString(m) }
+func (*BulkCancelPaymentRequest) ProtoMessage()    {}
+func (*BulkCancelPaymentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{14}
+}
+
+func (m *BulkCancelPaymentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkCancelPaymentRequest.Unmarshal(m, b)
+}
+func (m *BulkCancelPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkCancelPaymentRequest.Marshal(b, m, deterministic)
+}
+func (m *BulkCancelPaymentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkCancelPaymentRequest.Merge(m, src)
+}
+func (m *BulkCancelPaymentRequest) XXX_Size() int {
+	return xxx_messageInfo_BulkCancelPaymentRequest.Size(m)
+}
+func (m *BulkCancelPaymentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkCancelPaymentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkCancelPaymentRequest proto.InternalMessageInfo
+
+func (m *BulkCancelPaymentRequest) GetPaymentId() []string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return nil
+}
+
+type BulkCancelPaymentResponse struct {
+	Deleted              int32    `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkCancelPaymentResponse) Reset()         { *m = BulkCancelPaymentResponse{} }
+func (m *BulkCancelPaymentResponse) String() string { return proto.CompactTextString(m) }
+func (*BulkCancelPaymentResponse) ProtoMessage()    {}
+func (*BulkCancelPaymentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{15}
+}
+
+func (m *BulkCancelPaymentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkCancelPaymentResponse.Unmarshal(m, b)
+}
+func (m *BulkCancelPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkCancelPaymentResponse.Marshal(b, m, deterministic)
+}
+func (m *BulkCancelPaymentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkCancelPaymentResponse.Merge(m, src)
+}
+func (m *BulkCancelPaymentResponse) XXX_Size() int {
+	return xxx_messageInfo_BulkCancelPaymentResponse.Size(m)
+}
+func (m *BulkCancelPaymentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkCancelPaymentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkCancelPaymentResponse proto.InternalMessageInfo
+
+func (m *BulkCancelPaymentResponse) GetDeleted() int32 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+type GetPaymentInformationRequest struct {
+	PaymentId            string   `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPaymentInformationRequest) Reset()         { *m = GetPaymentInformationRequest{} }
+func (m *GetPaymentInformationRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPaymentInformationRequest) ProtoMessage()    {}
+func (*GetPaymentInformationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{16}
+}
+
+func (m *GetPaymentInformationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPaymentInformationRequest.Unmarshal(m, b)
+}
+func (m *GetPaymentInformationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPaymentInformationRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPaymentInformationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPaymentInformationRequest.Merge(m, src)
+}
+func (m *GetPaymentInformationRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPaymentInformationRequest.Size(m)
+}
+func (m *GetPaymentInformationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPaymentInformationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPaymentInformationRequest proto.InternalMessageInfo
+
+func (m *GetPaymentInformationRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type GetPaymentInformationResponse struct {
 	PaymentInformation   *PaymentInformation `protobuf:"bytes,1,opt,name=payment_information,json=paymentInformation,proto3" json:"payment_information,omitempty"`
-	CardInformation      *CardInformation    `protobuf:"bytes,2,opt,name=card_information,json=cardInformation,proto3" json:"card_information,omitempty"`
+	IsOk                 bool                `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
 	XXX_sizecache        int32               `json:"-"`
 }
 
-func (m *RawData) Reset()         { *m = RawData{} }
-func (m *RawData) String() string { return proto.CompactTextString(m) }
-func (*RawData) ProtoMessage()    {}
-func (*RawData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{15}
+func (m *GetPaymentInformationResponse) Reset()         { *m = GetPaymentInformationResponse{} }
+func (m *GetPaymentInformationResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPaymentInformationResponse) ProtoMessage()    {}
+func (*GetPaymentInformationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{17}
 }
 
-func (m *RawData) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RawData.Unmarshal(m, b)
+func (m *GetPaymentInformationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPaymentInformationResponse.Unmarshal(m, b)
 }
-func (m *RawData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RawData.Marshal(b, m, deterministic)
+func (m *GetPaymentInformationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPaymentInformationResponse.Marshal(b, m, deterministic)
 }
-func (m *RawData) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RawData.Merge(m, src)
+func (m *GetPaymentInformationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPaymentInformationResponse.Merge(m, src)
 }
-func (m *RawData) XXX_Size() int {
-	return xxx_messageInfo_RawData.Size(m)
+func (m *GetPaymentInformationResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPaymentInformationResponse.Size(m)
 }
-func (m *RawData) XXX_DiscardUnknown() {
-	xxx_messageInfo_RawData.DiscardUnknown(m)
+func (m *GetPaymentInformationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPaymentInformationResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RawData proto.InternalMessageInfo
+var xxx_messageInfo_GetPaymentInformationResponse proto.InternalMessageInfo
 
-func (m *RawData) GetPaymentInformation() *PaymentInformation {
+func (m *GetPaymentInformationResponse) GetPaymentInformation() *PaymentInformation {
 	if m != nil {
 		return m.PaymentInformation
 	}
 	return nil
 }
 
-func (m *RawData) GetCardInformation() *CardInformation {
+func (m *GetPaymentInformationResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type InitializeRequest struct {
+	// 指定した場合はそのマーチャントのデータのみ初期化する
+	MerchantId           string   `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitializeRequest) Reset()         { *m = InitializeRequest{} }
+func (m *InitializeRequest) String() string { return proto.CompactTextString(m) }
+func (*InitializeRequest) ProtoMessage()    {}
+func (*InitializeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{18}
+}
+
+func (m *InitializeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitializeRequest.Unmarshal(m, b)
+}
+func (m *InitializeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitializeRequest.Marshal(b, m, deterministic)
+}
+func (m *InitializeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitializeRequest.Merge(m, src)
+}
+func (m *InitializeRequest) XXX_Size() int {
+	return xxx_messageInfo_InitializeRequest.Size(m)
+}
+func (m *InitializeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitializeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitializeRequest proto.InternalMessageInfo
+
+func (m *InitializeRequest) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+type InitializeResponse struct {
+	IsOk                 bool     `protobuf:"varint,1,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitializeResponse) Reset()         { *m = InitializeResponse{} }
+func (m *InitializeResponse) String() string { return proto.CompactTextString(m) }
+func (*InitializeResponse) ProtoMessage()    {}
+func (*InitializeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{19}
+}
+
+func (m *InitializeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitializeResponse.Unmarshal(m, b)
+}
+func (m *InitializeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitializeResponse.Marshal(b, m, deterministic)
+}
+func (m *InitializeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitializeResponse.Merge(m, src)
+}
+func (m *InitializeResponse) XXX_Size() int {
+	return xxx_messageInfo_InitializeResponse.Size(m)
+}
+func (m *InitializeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitializeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitializeResponse proto.InternalMessageInfo
+
+func (m *InitializeResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type GetResultRequest struct {
+	// 1ページあたりの件数(0の場合は全件). StreamResultsでは無視される
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// 前のページのnext_cursor. この決済IDより後の決済を返す
+	Cursor string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// 予約IDの範囲(両端を含む. 0の場合は制限なし)
+	ReservationIdFrom int32 `protobuf:"varint,3,opt,name=reservation_id_from,json=reservationIdFrom,proto3" json:"reservation_id_from,omitempty"`
+	ReservationIdTo   int32 `protobuf:"varint,4,opt,name=reservation_id_to,json=reservationIdTo,proto3" json:"reservation_id_to,omitempty"`
+	// 決済日時の範囲(since以上until未満. 未指定の場合は制限なし)
+	Since *timestamp.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamp.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	// 指定した場合はそのマーチャントの決済のみ返す
+	MerchantId           string   `protobuf:"bytes,7,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResultRequest) Reset()         { *m = GetResultRequest{} }
+func (m *GetResultRequest) String() string { return proto.CompactTextString(m) }
+func (*GetResultRequest) ProtoMessage()    {}
+func (*GetResultRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{20}
+}
+
+func (m *GetResultRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResultRequest.Unmarshal(m, b)
+}
+func (m *GetResultRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResultRequest.Marshal(b, m, deterministic)
+}
+func (m *GetResultRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResultRequest.Merge(m, src)
+}
+func (m *GetResultRequest) XXX_Size() int {
+	return xxx_messageInfo_GetResultRequest.Size(m)
+}
+func (m *GetResultRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResultRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResultRequest proto.InternalMessageInfo
+
+func (m *GetResultRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetResultRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *GetResultRequest) GetReservationIdFrom() int32 {
+	if m != nil {
+		return m.ReservationIdFrom
+	}
+	return 0
+}
+
+func (m *GetResultRequest) GetReservationIdTo() int32 {
+	if m != nil {
+		return m.ReservationIdTo
+	}
+	return 0
+}
+
+func (m *GetResultRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *GetResultRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *GetResultRequest) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+type RawData struct {
+	PaymentInformation   *PaymentInformation `protobuf:"bytes,1,opt,name=payment_information,json=paymentInformation,proto3" json:"payment_information,omitempty"`
+	CardInformation      *CardInformation    `protobuf:"bytes,2,opt,name=card_information,json=cardInformation,proto3" json:"card_information,omitempty"`
+	PaymentId            string              `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	MerchantId           string              `protobuf:"bytes,4,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *RawData) Reset()         { *m = RawData{} }
+func (m *RawData) String() string { return proto.CompactTextString(m) }
+func (*RawData) ProtoMessage()    {}
+func (*RawData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{21}
+}
+
+func (m *RawData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RawData.Unmarshal(m, b)
+}
+func (m *RawData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RawData.Marshal(b, m, deterministic)
+}
+func (m *RawData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawData.Merge(m, src)
+}
+func (m *RawData) XXX_Size() int {
+	return xxx_messageInfo_RawData.Size(m)
+}
+func (m *RawData) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RawData proto.InternalMessageInfo
+
+func (m *RawData) GetPaymentInformation() *PaymentInformation {
+	if m != nil {
+		return m.PaymentInformation
+	}
+	return nil
+}
+
+func (m *RawData) GetCardInformation() *CardInformation {
+	if m != nil {
+		return m.CardInformation
+	}
+	return nil
+}
+
+func (m *RawData) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *RawData) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+// 同一予約に対して有効な決済が複数存在する(多重課金)
+type DuplicatePayment struct {
+	ReservationId        int32    `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	PaymentId            []string `protobuf:"bytes,2,rep,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	MerchantId           string   `protobuf:"bytes,3,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DuplicatePayment) Reset()         { *m = DuplicatePayment{} }
+func (m *DuplicatePayment) String() string { return proto.CompactTextString(m) }
+func (*DuplicatePayment) ProtoMessage()    {}
+func (*DuplicatePayment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{22}
+}
+
+func (m *DuplicatePayment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DuplicatePayment.Unmarshal(m, b)
+}
+func (m *DuplicatePayment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DuplicatePayment.Marshal(b, m, deterministic)
+}
+func (m *DuplicatePayment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DuplicatePayment.Merge(m, src)
+}
+func (m *DuplicatePayment) XXX_Size() int {
+	return xxx_messageInfo_DuplicatePayment.Size(m)
+}
+func (m *DuplicatePayment) XXX_DiscardUnknown() {
+	xxx_messageInfo_DuplicatePayment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DuplicatePayment proto.InternalMessageInfo
+
+func (m *DuplicatePayment) GetReservationId() int32 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+func (m *DuplicatePayment) GetPaymentId() []string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return nil
+}
+
+func (m *DuplicatePayment) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+type GetResultResponse struct {
+	RawData    []*RawData          `protobuf:"bytes,1,rep,name=raw_data,json=rawData,proto3" json:"raw_data,omitempty"`
+	IsOk       bool                `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	Duplicates []*DuplicatePayment `protobuf:"bytes,3,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	// 冪等キーにより再送と判定された決済リクエストの件数
+	Replayed int32 `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	// 続きのページがある場合に次のリクエストのcursorに指定する
+	NextCursor           string   `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResultResponse) Reset()         { *m = GetResultResponse{} }
+func (m *GetResultResponse) String() string { return proto.CompactTextString(m) }
+func (*GetResultResponse) ProtoMessage()    {}
+func (*GetResultResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{23}
+}
+
+func (m *GetResultResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResultResponse.Unmarshal(m, b)
+}
+func (m *GetResultResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResultResponse.Marshal(b, m, deterministic)
+}
+func (m *GetResultResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResultResponse.Merge(m, src)
+}
+func (m *GetResultResponse) XXX_Size() int {
+	return xxx_messageInfo_GetResultResponse.Size(m)
+}
+func (m *GetResultResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResultResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResultResponse proto.InternalMessageInfo
+
+func (m *GetResultResponse) GetRawData() []*RawData {
+	if m != nil {
+		return m.RawData
+	}
+	return nil
+}
+
+func (m *GetResultResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+func (m *GetResultResponse) GetDuplicates() []*DuplicatePayment {
+	if m != nil {
+		return m.Duplicates
+	}
+	return nil
+}
+
+func (m *GetResultResponse) GetReplayed() int32 {
+	if m != nil {
+		return m.Replayed
+	}
+	return 0
+}
+
+func (m *GetResultResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type ResultSummary struct {
+	// 条件に合う決済の件数
+	Count                int32               `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Duplicates           []*DuplicatePayment `protobuf:"bytes,2,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Replayed             int32               `protobuf:"varint,3,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ResultSummary) Reset()         { *m = ResultSummary{} }
+func (m *ResultSummary) String() string { return proto.CompactTextString(m) }
+func (*ResultSummary) ProtoMessage()    {}
+func (*ResultSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{24}
+}
+
+func (m *ResultSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResultSummary.Unmarshal(m, b)
+}
+func (m *ResultSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResultSummary.Marshal(b, m, deterministic)
+}
+func (m *ResultSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResultSummary.Merge(m, src)
+}
+func (m *ResultSummary) XXX_Size() int {
+	return xxx_messageInfo_ResultSummary.Size(m)
+}
+func (m *ResultSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResultSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResultSummary proto.InternalMessageInfo
+
+func (m *ResultSummary) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ResultSummary) GetDuplicates() []*DuplicatePayment {
+	if m != nil {
+		return m.Duplicates
+	}
+	return nil
+}
+
+func (m *ResultSummary) GetReplayed() int32 {
+	if m != nil {
+		return m.Replayed
+	}
+	return 0
+}
+
+type StreamResultsResponse struct {
+	RawData *RawData `protobuf:"bytes,1,opt,name=raw_data,json=rawData,proto3" json:"raw_data,omitempty"`
+	// 最後のメッセージにのみ設定される
+	Summary              *ResultSummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *StreamResultsResponse) Reset()         { *m = StreamResultsResponse{} }
+func (m *StreamResultsResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamResultsResponse) ProtoMessage()    {}
+func (*StreamResultsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{25}
+}
+
+func (m *StreamResultsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamResultsResponse.Unmarshal(m, b)
+}
+func (m *StreamResultsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamResultsResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamResultsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamResultsResponse.Merge(m, src)
+}
+func (m *StreamResultsResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamResultsResponse.Size(m)
+}
+func (m *StreamResultsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamResultsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamResultsResponse proto.InternalMessageInfo
+
+func (m *StreamResultsResponse) GetRawData() *RawData {
+	if m != nil {
+		return m.RawData
+	}
+	return nil
+}
+
+func (m *StreamResultsResponse) GetSummary() *ResultSummary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+type SetFaultProfileRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetFaultProfileRequest) Reset()         { *m = SetFaultProfileRequest{} }
+func (m *SetFaultProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*SetFaultProfileRequest) ProtoMessage()    {}
+func (*SetFaultProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{26}
+}
+
+func (m *SetFaultProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetFaultProfileRequest.Unmarshal(m, b)
+}
+func (m *SetFaultProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetFaultProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *SetFaultProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetFaultProfileRequest.Merge(m, src)
+}
+func (m *SetFaultProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_SetFaultProfileRequest.Size(m)
+}
+func (m *SetFaultProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetFaultProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetFaultProfileRequest proto.InternalMessageInfo
+
+func (m *SetFaultProfileRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetFaultProfileRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetFaultProfileRequest) Reset()         { *m = GetFaultProfileRequest{} }
+func (m *GetFaultProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFaultProfileRequest) ProtoMessage()    {}
+func (*GetFaultProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{27}
+}
+
+func (m *GetFaultProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFaultProfileRequest.Unmarshal(m, b)
+}
+func (m *GetFaultProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFaultProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFaultProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFaultProfileRequest.Merge(m, src)
+}
+func (m *GetFaultProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFaultProfileRequest.Size(m)
+}
+func (m *GetFaultProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFaultProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFaultProfileRequest proto.InternalMessageInfo
+
+type FaultProfileResponse struct {
+	// 有効な障害注入プロファイル名(無効の場合は空文字)
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 設定ファイルに定義されたプロファイル名
+	Profiles             []string `protobuf:"bytes,2,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	IsOk                 bool     `protobuf:"varint,3,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FaultProfileResponse) Reset()         { *m = FaultProfileResponse{} }
+func (m *FaultProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*FaultProfileResponse) ProtoMessage()    {}
+func (*FaultProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{28}
+}
+
+func (m *FaultProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FaultProfileResponse.Unmarshal(m, b)
+}
+func (m *FaultProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FaultProfileResponse.Marshal(b, m, deterministic)
+}
+func (m *FaultProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FaultProfileResponse.Merge(m, src)
+}
+func (m *FaultProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_FaultProfileResponse.Size(m)
+}
+func (m *FaultProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FaultProfileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FaultProfileResponse proto.InternalMessageInfo
+
+func (m *FaultProfileResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FaultProfileResponse) GetProfiles() []string {
+	if m != nil {
+		return m.Profiles
+	}
+	return nil
+}
+
+func (m *FaultProfileResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type RegisterWebhookRequest struct {
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// 署名に使う秘密鍵. 省略した場合は生成される
+	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterWebhookRequest) Reset()         { *m = RegisterWebhookRequest{} }
+func (m *RegisterWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterWebhookRequest) ProtoMessage()    {}
+func (*RegisterWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{29}
+}
+
+func (m *RegisterWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterWebhookRequest.Unmarshal(m, b)
+}
+func (m *RegisterWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterWebhookRequest.Merge(m, src)
+}
+func (m *RegisterWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterWebhookRequest.Size(m)
+}
+func (m *RegisterWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterWebhookRequest proto.InternalMessageInfo
+
+func (m *RegisterWebhookRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *RegisterWebhookRequest) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type RegisterWebhookResponse struct {
+	Secret               string   `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	IsOk                 bool     `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterWebhookResponse) Reset()         { *m = RegisterWebhookResponse{} }
+func (m *RegisterWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterWebhookResponse) ProtoMessage()    {}
+func (*RegisterWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{30}
+}
+
+func (m *RegisterWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterWebhookResponse.Unmarshal(m, b)
+}
+func (m *RegisterWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *RegisterWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterWebhookResponse.Merge(m, src)
+}
+func (m *RegisterWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_RegisterWebhookResponse.Size(m)
+}
+func (m *RegisterWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterWebhookResponse proto.InternalMessageInfo
+
+func (m *RegisterWebhookResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *RegisterWebhookResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	EventId   string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Url       string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// 何回目の配信か(1始まり)
+	Attempt int32 `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// 受信側が返したHTTPステータスコード(接続できなかった場合は0)
+	StatusCode           int32                `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Error                string               `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Datetime             *timestamp.Timestamp `protobuf:"bytes,7,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Delivered            bool                 `protobuf:"varint,8,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	MerchantId           string               `protobuf:"bytes,9,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	PaymentId            string               `protobuf:"bytes,10,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WebhookDelivery) Reset()         { *m = WebhookDelivery{} }
+func (m *WebhookDelivery) String() string { return proto.CompactTextString(m) }
+func (*WebhookDelivery) ProtoMessage()    {}
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{31}
+}
+
+func (m *WebhookDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookDelivery.Unmarshal(m, b)
+}
+func (m *WebhookDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookDelivery.Marshal(b, m, deterministic)
+}
+func (m *WebhookDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookDelivery.Merge(m, src)
+}
+func (m *WebhookDelivery) XXX_Size() int {
+	return xxx_messageInfo_WebhookDelivery.Size(m)
+}
+func (m *WebhookDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookDelivery proto.InternalMessageInfo
+
+func (m *WebhookDelivery) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetEventType() string {
+	if m != nil {
+		return m.EventType
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetDatetime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Datetime
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetDelivered() bool {
+	if m != nil {
+		return m.Delivered
+	}
+	return false
+}
+
+func (m *WebhookDelivery) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesRequest struct {
+	// 指定した場合はそのイベントの配信履歴のみ返す
+	EventId              string   `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesRequest) Reset()         { *m = ListWebhookDeliveriesRequest{} }
+func (m *ListWebhookDeliveriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesRequest) ProtoMessage()    {}
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{32}
+}
+
+func (m *ListWebhookDeliveriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Size(m)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesRequest proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesRequest) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries           []*WebhookDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	IsOk                 bool               `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesResponse) Reset()         { *m = ListWebhookDeliveriesResponse{} }
+func (m *ListWebhookDeliveriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesResponse) ProtoMessage()    {}
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{33}
+}
+
+func (m *ListWebhookDeliveriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Size(m)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesResponse proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+func (m *ListWebhookDeliveriesResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type SettlementReportRequest struct {
+	// 集計期間(YYYY-MM-DD, JST, 両端を含む). 省略した場合は制限しない
+	DateFrom string                          `protobuf:"bytes,1,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo   string                          `protobuf:"bytes,2,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	GroupBy  SettlementReportRequest_GroupBy `protobuf:"varint,3,opt,name=group_by,json=groupBy,proto3,enum=paymentpb.SettlementReportRequest_GroupBy" json:"group_by,omitempty"`
+	// 管理キーの場合のみ指定できる. マーチャントのAPIキーの場合は自身のものに限られる
+	MerchantId string `protobuf:"bytes,4,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	// ExportSettlementReportの出力形式
+	Format               ExportFormat `protobuf:"varint,5,opt,name=format,proto3,enum=paymentpb.ExportFormat" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SettlementReportRequest) Reset()         { *m = SettlementReportRequest{} }
+func (m *SettlementReportRequest) String() string { return proto.CompactTextString(m) }
+func (*SettlementReportRequest) ProtoMessage()    {}
+func (*SettlementReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{34}
+}
+
+func (m *SettlementReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SettlementReportRequest.Unmarshal(m, b)
+}
+func (m *SettlementReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SettlementReportRequest.Marshal(b, m, deterministic)
+}
+func (m *SettlementReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SettlementReportRequest.Merge(m, src)
+}
+func (m *SettlementReportRequest) XXX_Size() int {
+	return xxx_messageInfo_SettlementReportRequest.Size(m)
+}
+func (m *SettlementReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SettlementReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SettlementReportRequest proto.InternalMessageInfo
+
+func (m *SettlementReportRequest) GetDateFrom() string {
+	if m != nil {
+		return m.DateFrom
+	}
+	return ""
+}
+
+func (m *SettlementReportRequest) GetDateTo() string {
+	if m != nil {
+		return m.DateTo
+	}
+	return ""
+}
+
+func (m *SettlementReportRequest) GetGroupBy() SettlementReportRequest_GroupBy {
+	if m != nil {
+		return m.GroupBy
+	}
+	return SettlementReportRequest_DAY
+}
+
+func (m *SettlementReportRequest) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+func (m *SettlementReportRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_JSON
+}
+
+type SettlementRow struct {
+	MerchantId string `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	// group_byがDAYの場合の日付(YYYY-MM-DD, JST)
+	Date string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	// group_byがRESERVATIONの場合の予約ID
+	ReservationId int32 `protobuf:"varint,3,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	CaptureCount  int64 `protobuf:"varint,4,opt,name=capture_count,json=captureCount,proto3" json:"capture_count,omitempty"`
+	CaptureAmount int64 `protobuf:"varint,5,opt,name=capture_amount,json=captureAmount,proto3" json:"capture_amount,omitempty"`
+	// 決済と同じ日のキャンセル(取消)
+	CancelCount  int64 `protobuf:"varint,6,opt,name=cancel_count,json=cancelCount,proto3" json:"cancel_count,omitempty"`
+	CancelAmount int64 `protobuf:"varint,7,opt,name=cancel_amount,json=cancelAmount,proto3" json:"cancel_amount,omitempty"`
+	// 決済より後の日のキャンセル(返金)
+	RefundCount  int64 `protobuf:"varint,8,opt,name=refund_count,json=refundCount,proto3" json:"refund_count,omitempty"`
+	RefundAmount int64 `protobuf:"varint,9,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"`
+	// capture_amount - cancel_amount - refund_amount
+	NetAmount            int64    `protobuf:"varint,10,opt,name=net_amount,json=netAmount,proto3" json:"net_amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SettlementRow) Reset()         { *m = SettlementRow{} }
+func (m *SettlementRow) String() string { return proto.CompactTextString(m) }
+func (*SettlementRow) ProtoMessage()    {}
+func (*SettlementRow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{35}
+}
+
+func (m *SettlementRow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SettlementRow.Unmarshal(m, b)
+}
+func (m *SettlementRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SettlementRow.Marshal(b, m, deterministic)
+}
+func (m *SettlementRow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SettlementRow.Merge(m, src)
+}
+func (m *SettlementRow) XXX_Size() int {
+	return xxx_messageInfo_SettlementRow.Size(m)
+}
+func (m *SettlementRow) XXX_DiscardUnknown() {
+	xxx_messageInfo_SettlementRow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SettlementRow proto.InternalMessageInfo
+
+func (m *SettlementRow) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+func (m *SettlementRow) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *SettlementRow) GetReservationId() int32 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetCaptureCount() int64 {
+	if m != nil {
+		return m.CaptureCount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetCaptureAmount() int64 {
+	if m != nil {
+		return m.CaptureAmount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetCancelCount() int64 {
+	if m != nil {
+		return m.CancelCount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetCancelAmount() int64 {
+	if m != nil {
+		return m.CancelAmount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetRefundCount() int64 {
+	if m != nil {
+		return m.RefundCount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetRefundAmount() int64 {
+	if m != nil {
+		return m.RefundAmount
+	}
+	return 0
+}
+
+func (m *SettlementRow) GetNetAmount() int64 {
+	if m != nil {
+		return m.NetAmount
+	}
+	return 0
+}
+
+type LedgerBalance struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	// 借方を正、貸方を負とした残高
+	Balance              int64    `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LedgerBalance) Reset()         { *m = LedgerBalance{} }
+func (m *LedgerBalance) String() string { return proto.CompactTextString(m) }
+func (*LedgerBalance) ProtoMessage()    {}
+func (*LedgerBalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{36}
+}
+
+func (m *LedgerBalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerBalance.Unmarshal(m, b)
+}
+func (m *LedgerBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerBalance.Marshal(b, m, deterministic)
+}
+func (m *LedgerBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerBalance.Merge(m, src)
+}
+func (m *LedgerBalance) XXX_Size() int {
+	return xxx_messageInfo_LedgerBalance.Size(m)
+}
+func (m *LedgerBalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerBalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerBalance proto.InternalMessageInfo
+
+func (m *LedgerBalance) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *LedgerBalance) GetBalance() int64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+type SettlementReportResponse struct {
+	Rows  []*SettlementRow `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	Total *SettlementRow   `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	// 集計対象の仕訳から求めた勘定ごとの残高. 合計は常に0になる
+	Balances             []*LedgerBalance `protobuf:"bytes,3,rep,name=balances,proto3" json:"balances,omitempty"`
+	IsOk                 bool             `protobuf:"varint,4,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SettlementReportResponse) Reset()         { *m = SettlementReportResponse{} }
+func (m *SettlementReportResponse) String() string { return proto.CompactTextString(m) }
+func (*SettlementReportResponse) ProtoMessage()    {}
+func (*SettlementReportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{37}
+}
+
+func (m *SettlementReportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SettlementReportResponse.Unmarshal(m, b)
+}
+func (m *SettlementReportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SettlementReportResponse.Marshal(b, m, deterministic)
+}
+func (m *SettlementReportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SettlementReportResponse.Merge(m, src)
+}
+func (m *SettlementReportResponse) XXX_Size() int {
+	return xxx_messageInfo_SettlementReportResponse.Size(m)
+}
+func (m *SettlementReportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SettlementReportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SettlementReportResponse proto.InternalMessageInfo
+
+func (m *SettlementReportResponse) GetRows() []*SettlementRow {
+	if m != nil {
+		return m.Rows
+	}
+	return nil
+}
+
+func (m *SettlementReportResponse) GetTotal() *SettlementRow {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *SettlementReportResponse) GetBalances() []*LedgerBalance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+func (m *SettlementReportResponse) GetIsOk() bool {
+	if m != nil {
+		return m.IsOk
+	}
+	return false
+}
+
+type ExportLedgerRequest struct {
+	DateFrom             string       `protobuf:"bytes,1,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo               string       `protobuf:"bytes,2,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	MerchantId           string       `protobuf:"bytes,3,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	Format               ExportFormat `protobuf:"varint,4,opt,name=format,proto3,enum=paymentpb.ExportFormat" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ExportLedgerRequest) Reset()         { *m = ExportLedgerRequest{} }
+func (m *ExportLedgerRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportLedgerRequest) ProtoMessage()    {}
+func (*ExportLedgerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{38}
+}
+
+func (m *ExportLedgerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportLedgerRequest.Unmarshal(m, b)
+}
+func (m *ExportLedgerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportLedgerRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportLedgerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportLedgerRequest.Merge(m, src)
+}
+func (m *ExportLedgerRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportLedgerRequest.Size(m)
+}
+func (m *ExportLedgerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportLedgerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportLedgerRequest proto.InternalMessageInfo
+
+func (m *ExportLedgerRequest) GetDateFrom() string {
+	if m != nil {
+		return m.DateFrom
+	}
+	return ""
+}
+
+func (m *ExportLedgerRequest) GetDateTo() string {
+	if m != nil {
+		return m.DateTo
+	}
+	return ""
+}
+
+func (m *ExportLedgerRequest) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+func (m *ExportLedgerRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_JSON
+}
+
+type LedgerEntry struct {
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// capture, cancel, refund
+	Type          string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	PaymentId     string `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	ReservationId int32  `protobuf:"varint,4,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	MerchantId    string `protobuf:"bytes,5,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	Account       string `protobuf:"bytes,6,opt,name=account,proto3" json:"account,omitempty"`
+	// 借方を正、貸方を負とした金額
+	Amount               int64                `protobuf:"varint,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Datetime             *timestamp.Timestamp `protobuf:"bytes,8,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *LedgerEntry) Reset()         { *m = LedgerEntry{} }
+func (m *LedgerEntry) String() string { return proto.CompactTextString(m) }
+func (*LedgerEntry) ProtoMessage()    {}
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{39}
+}
+
+func (m *LedgerEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerEntry.Unmarshal(m, b)
+}
+func (m *LedgerEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerEntry.Marshal(b, m, deterministic)
+}
+func (m *LedgerEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerEntry.Merge(m, src)
+}
+func (m *LedgerEntry) XXX_Size() int {
+	return xxx_messageInfo_LedgerEntry.Size(m)
+}
+func (m *LedgerEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerEntry proto.InternalMessageInfo
+
+func (m *LedgerEntry) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *LedgerEntry) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LedgerEntry) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *LedgerEntry) GetReservationId() int32 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+func (m *LedgerEntry) GetMerchantId() string {
+	if m != nil {
+		return m.MerchantId
+	}
+	return ""
+}
+
+func (m *LedgerEntry) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *LedgerEntry) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *LedgerEntry) GetDatetime() *timestamp.Timestamp {
 	if m != nil {
-		return m.CardInformation
+		return m.Datetime
 	}
 	return nil
 }
 
-type GetResultResponse struct {
-	RawData              []*RawData `protobuf:"bytes,1,rep,name=raw_data,json=rawData,proto3" json:"raw_data,omitempty"`
-	IsOk                 bool       `protobuf:"varint,2,opt,name=is_ok,json=isOk,proto3" json:"is_ok,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+type ExportLedgerResponse struct {
+	Entries              []*LedgerEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func (m *GetResultResponse) Reset()         { *m = GetResultResponse{} }
-func (m *GetResultResponse) String() string { return proto.CompactTextString(m) }
-func (*GetResultResponse) ProtoMessage()    {}
-func (*GetResultResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_595799929d632654, []int{16}
+func (m *ExportLedgerResponse) Reset()         { *m = ExportLedgerResponse{} }
+func (m *ExportLedgerResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportLedgerResponse) ProtoMessage()    {}
+func (*ExportLedgerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_595799929d632654, []int{40}
 }
 
-func (m *GetResultResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetResultResponse.Unmarshal(m, b)
+func (m *ExportLedgerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportLedgerResponse.Unmarshal(m, b)
 }
-func (m *GetResultResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetResultResponse.Marshal(b, m, deterministic)
+func (m *ExportLedgerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportLedgerResponse.Marshal(b, m, deterministic)
 }
-func (m *GetResultResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetResultResponse.Merge(m, src)
+func (m *ExportLedgerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportLedgerResponse.Merge(m, src)
 }
-func (m *GetResultResponse) XXX_Size() int {
-	return xxx_messageInfo_GetResultResponse.Size(m)
+func (m *ExportLedgerResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportLedgerResponse.Size(m)
 }
-func (m *GetResultResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetResultResponse.DiscardUnknown(m)
+func (m *ExportLedgerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportLedgerResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetResultResponse proto.InternalMessageInfo
+var xxx_messageInfo_ExportLedgerResponse proto.InternalMessageInfo
 
-func (m *GetResultResponse) GetRawData() []*RawData {
+func (m *ExportLedgerResponse) GetEntries() []*LedgerEntry {
 	if m != nil {
-		return m.RawData
+		return m.Entries
 	}
 	return nil
 }
 
-func (m *GetResultResponse) GetIsOk() bool {
-	if m != nil {
-		return m.IsOk
-	}
-	return false
-}
-
 func init() {
+	proto.RegisterEnum("paymentpb.CardTokenUsage", CardTokenUsage_name, CardTokenUsage_value)
+	proto.RegisterEnum("paymentpb.CardTokenStatus", CardTokenStatus_name, CardTokenStatus_value)
+	proto.RegisterEnum("paymentpb.ExportFormat", ExportFormat_name, ExportFormat_value)
+	proto.RegisterEnum("paymentpb.SettlementReportRequest_GroupBy", SettlementReportRequest_GroupBy_name, SettlementReportRequest_GroupBy_value)
 	proto.RegisterType((*CardInformation)(nil), "paymentpb.CardInformation")
 	proto.RegisterType((*RegistCardRequest)(nil), "paymentpb.RegistCardRequest")
 	proto.RegisterType((*RegistCardResponse)(nil), "paymentpb.RegistCardResponse")
+	proto.RegisterType((*RevokeCardTokenRequest)(nil), "paymentpb.RevokeCardTokenRequest")
+	proto.RegisterType((*RevokeCardTokenResponse)(nil), "paymentpb.RevokeCardTokenResponse")
+	proto.RegisterType((*ListCardTokensRequest)(nil), "paymentpb.ListCardTokensRequest")
+	proto.RegisterType((*CardToken)(nil), "paymentpb.CardToken")
+	proto.RegisterType((*ListCardTokensResponse)(nil), "paymentpb.ListCardTokensResponse")
+	proto.RegisterType((*Money)(nil), "paymentpb.Money")
 	proto.RegisterType((*PaymentInformation)(nil), "paymentpb.PaymentInformation")
 	proto.RegisterType((*ExecutePaymentRequest)(nil), "paymentpb.ExecutePaymentRequest")
 	proto.RegisterType((*ExecutePaymentResponse)(nil), "paymentpb.ExecutePaymentResponse")
@@ -778,62 +2492,182 @@ func init() {
 	proto.RegisterType((*InitializeResponse)(nil), "paymentpb.InitializeResponse")
 	proto.RegisterType((*GetResultRequest)(nil), "paymentpb.GetResultRequest")
 	proto.RegisterType((*RawData)(nil), "paymentpb.RawData")
+	proto.RegisterType((*DuplicatePayment)(nil), "paymentpb.DuplicatePayment")
 	proto.RegisterType((*GetResultResponse)(nil), "paymentpb.GetResultResponse")
+	proto.RegisterType((*ResultSummary)(nil), "paymentpb.ResultSummary")
+	proto.RegisterType((*StreamResultsResponse)(nil), "paymentpb.StreamResultsResponse")
+	proto.RegisterType((*SetFaultProfileRequest)(nil), "paymentpb.SetFaultProfileRequest")
+	proto.RegisterType((*GetFaultProfileRequest)(nil), "paymentpb.GetFaultProfileRequest")
+	proto.RegisterType((*FaultProfileResponse)(nil), "paymentpb.FaultProfileResponse")
+	proto.RegisterType((*RegisterWebhookRequest)(nil), "paymentpb.RegisterWebhookRequest")
+	proto.RegisterType((*RegisterWebhookResponse)(nil), "paymentpb.RegisterWebhookResponse")
+	proto.RegisterType((*WebhookDelivery)(nil), "paymentpb.WebhookDelivery")
+	proto.RegisterType((*ListWebhookDeliveriesRequest)(nil), "paymentpb.ListWebhookDeliveriesRequest")
+	proto.RegisterType((*ListWebhookDeliveriesResponse)(nil), "paymentpb.ListWebhookDeliveriesResponse")
+	proto.RegisterType((*SettlementReportRequest)(nil), "paymentpb.SettlementReportRequest")
+	proto.RegisterType((*SettlementRow)(nil), "paymentpb.SettlementRow")
+	proto.RegisterType((*LedgerBalance)(nil), "paymentpb.LedgerBalance")
+	proto.RegisterType((*SettlementReportResponse)(nil), "paymentpb.SettlementReportResponse")
+	proto.RegisterType((*ExportLedgerRequest)(nil), "paymentpb.ExportLedgerRequest")
+	proto.RegisterType((*LedgerEntry)(nil), "paymentpb.LedgerEntry")
+	proto.RegisterType((*ExportLedgerResponse)(nil), "paymentpb.ExportLedgerResponse")
 }
 
 func init() { proto.RegisterFile("pb/payment.proto", fileDescriptor_595799929d632654) }
 
 var fileDescriptor_595799929d632654 = []byte{
-	// 774 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x51, 0x6b, 0xd3, 0x50,
-	0x14, 0x26, 0xdb, 0xba, 0xb6, 0xa7, 0xac, 0x6b, 0x6f, 0xed, 0xec, 0x62, 0xcb, 0x66, 0x54, 0x9c,
-	0x43, 0x1b, 0x98, 0x4c, 0x50, 0xf0, 0xc5, 0x6d, 0xcc, 0x81, 0x4c, 0x89, 0x83, 0x81, 0x82, 0xe5,
-	0xb6, 0xb9, 0x2b, 0xd7, 0xb6, 0x49, 0x96, 0xdc, 0x74, 0x9b, 0xe2, 0x8b, 0xf8, 0x22, 0xf8, 0xe6,
-	0x1f, 0xf0, 0xff, 0xf8, 0xe8, 0x5f, 0xf0, 0x87, 0xc8, 0xbd, 0xb9, 0x49, 0x93, 0x36, 0xed, 0x26,
-	0xec, 0xad, 0xf7, 0xdc, 0x73, 0xbf, 0xef, 0x7c, 0xdf, 0xc9, 0x39, 0x85, 0x92, 0xd3, 0xd6, 0x1d,
-	0x7c, 0x31, 0x20, 0x16, 0x6b, 0x3a, 0xae, 0xcd, 0x6c, 0x94, 0x97, 0x47, 0xa7, 0xad, 0xd6, 0xbb,
-	0xb6, 0xdd, 0xed, 0x13, 0x1d, 0x3b, 0x54, 0xc7, 0x96, 0x65, 0x33, 0xcc, 0xa8, 0x6d, 0x79, 0x41,
-	0xa2, 0xba, 0x26, 0x6f, 0xc5, 0xa9, 0xed, 0x9f, 0xe8, 0x8c, 0x0e, 0x88, 0xc7, 0xf0, 0xc0, 0x09,
-	0x12, 0x34, 0x02, 0xcb, 0x3b, 0xd8, 0x35, 0x0f, 0xac, 0x13, 0xdb, 0x1d, 0x88, 0xa7, 0x68, 0x0d,
-	0x0a, 0x1d, 0xec, 0x9a, 0x2d, 0xcb, 0x1f, 0xb4, 0x89, 0x5b, 0x53, 0xd6, 0x95, 0x8d, 0xbc, 0x01,
-	0x3c, 0x74, 0x28, 0x22, 0xa8, 0x04, 0xf3, 0x9d, 0xe1, 0xb0, 0x36, 0x27, 0x2e, 0xf8, 0x4f, 0xfe,
-	0x84, 0x9c, 0x3b, 0xd4, 0xbd, 0x68, 0x99, 0x98, 0x91, 0xda, 0x7c, 0xf0, 0x24, 0x08, 0xed, 0x62,
-	0x46, 0xb4, 0x77, 0x50, 0x36, 0x48, 0x97, 0x7a, 0x8c, 0x93, 0x19, 0xe4, 0xd4, 0x27, 0x1e, 0x43,
-	0x7b, 0x50, 0x12, 0x44, 0x74, 0x44, 0x2e, 0xd8, 0x0a, 0x5b, 0x6a, 0x33, 0x12, 0xd8, 0x1c, 0x2b,
-	0xcf, 0x58, 0xee, 0x24, 0x03, 0xda, 0x4b, 0x40, 0x71, 0x6c, 0xcf, 0xb1, 0x2d, 0x8f, 0xa0, 0x06,
-	0x88, 0x92, 0x5b, 0xcc, 0xee, 0x11, 0x4b, 0x8a, 0xc8, 0xf3, 0xc8, 0x11, 0x0f, 0xa0, 0x0a, 0x64,
-	0xa8, 0xd7, 0xb2, 0x7b, 0x42, 0x45, 0xce, 0x58, 0xa0, 0xde, 0xeb, 0x9e, 0xf6, 0x5b, 0x01, 0xf4,
-	0x26, 0x20, 0x8e, 0x1b, 0x72, 0x09, 0xd4, 0x3d, 0x28, 0xba, 0xc4, 0x23, 0xee, 0x50, 0x64, 0xb7,
-	0xa8, 0x29, 0x30, 0x33, 0xc6, 0x52, 0x2c, 0x7a, 0x60, 0xa2, 0x27, 0x90, 0xe3, 0xe6, 0xf0, 0x06,
-	0x08, 0x83, 0xb8, 0xca, 0xa0, 0x3b, 0xcd, 0xb0, 0x3b, 0xcd, 0xa3, 0xb0, 0x3b, 0x46, 0x94, 0x8b,
-	0x56, 0x60, 0x11, 0x0f, 0x6c, 0xdf, 0x62, 0xb5, 0x05, 0x01, 0x2b, 0x4f, 0xdc, 0x73, 0xea, 0xb5,
-	0x3a, 0xd8, 0xea, 0x90, 0x3e, 0x31, 0x6b, 0x19, 0xa1, 0x03, 0xa8, 0xb7, 0x23, 0x23, 0x5a, 0x17,
-	0xaa, 0x7b, 0xe7, 0xa4, 0xe3, 0x33, 0x22, 0x35, 0x85, 0xbe, 0x1f, 0x42, 0x45, 0xda, 0x9b, 0x62,
-	0x7d, 0x23, 0x66, 0xfd, 0xa4, 0x17, 0x06, 0x72, 0x26, 0x62, 0xda, 0x2b, 0x58, 0x19, 0x27, 0x1a,
-	0x35, 0x21, 0x62, 0x32, 0x43, 0xe7, 0x42, 0x04, 0x33, 0xbd, 0x09, 0xdb, 0x70, 0x23, 0x90, 0x30,
-	0x56, 0xf5, 0x6c, 0x2c, 0xed, 0x21, 0x54, 0xc7, 0x9e, 0xc9, 0x1a, 0x22, 0x12, 0x25, 0x46, 0xf2,
-	0x14, 0x6a, 0x2f, 0xfc, 0x7e, 0xef, 0x4a, 0x44, 0xf3, 0x49, 0xa2, 0x6d, 0x58, 0x4d, 0x79, 0x2a,
-	0xc9, 0x6a, 0x90, 0x35, 0x49, 0x9f, 0x30, 0x12, 0x54, 0x98, 0x31, 0xc2, 0xa3, 0xf6, 0x1c, 0xea,
-	0xfb, 0x84, 0xa5, 0x38, 0x7a, 0x35, 0x79, 0xdf, 0x14, 0x68, 0x4c, 0x79, 0x2f, 0xa9, 0xaf, 0xb9,
-	0xab, 0xe9, 0xcd, 0xa9, 0x40, 0xf9, 0xc0, 0xa2, 0x8c, 0xe2, 0x3e, 0xfd, 0x44, 0x64, 0xe9, 0xda,
-	0x03, 0x40, 0xf1, 0xe0, 0x2c, 0xdf, 0x11, 0x94, 0xf6, 0x09, 0xb7, 0xcb, 0xef, 0x87, 0x7e, 0x6b,
-	0xbf, 0x14, 0xc8, 0x1a, 0xf8, 0x6c, 0x17, 0x33, 0x7c, 0xed, 0x22, 0xd2, 0x56, 0xcc, 0xdc, 0xff,
-	0xaf, 0x98, 0x63, 0x28, 0xc7, 0xca, 0x96, 0x02, 0x1f, 0x41, 0xce, 0xc5, 0x67, 0x7c, 0xe3, 0x61,
-	0xf1, 0x95, 0x14, 0xb6, 0x50, 0x0c, 0x53, 0x2a, 0x32, 0xb2, 0xae, 0x94, 0x96, 0xe6, 0xe7, 0xd6,
-	0x8f, 0x45, 0x28, 0x4a, 0x29, 0x6f, 0x89, 0x3b, 0xa4, 0x1d, 0x82, 0xde, 0x03, 0x8c, 0xd6, 0x19,
-	0xaa, 0xc7, 0x21, 0xc7, 0x37, 0xa8, 0xda, 0x98, 0x72, 0x1b, 0x54, 0xa8, 0x95, 0xbe, 0xfe, 0xf9,
-	0xfb, 0x73, 0x0e, 0xb4, 0x8c, 0xce, 0x05, 0x3d, 0x53, 0x36, 0xd1, 0x47, 0x28, 0x26, 0x47, 0x15,
-	0xad, 0xc7, 0x20, 0x52, 0xd7, 0x85, 0x7a, 0x7b, 0x46, 0x86, 0x24, 0xaa, 0x08, 0xa2, 0x25, 0x2d,
-	0x17, 0xfe, 0x4f, 0x71, 0xae, 0x53, 0x58, 0x4a, 0x0c, 0x09, 0x5a, 0x4b, 0x58, 0x3e, 0x39, 0x79,
-	0xea, 0xfa, 0xf4, 0x04, 0x49, 0xd4, 0x10, 0x44, 0x37, 0x37, 0xab, 0x21, 0x91, 0xfe, 0x79, 0x34,
-	0x35, 0x5f, 0xd0, 0x05, 0x94, 0x27, 0x66, 0x13, 0xdd, 0x89, 0xa1, 0x4e, 0x1b, 0x7a, 0xf5, 0xee,
-	0xec, 0x24, 0x49, 0xbf, 0x2a, 0xe8, 0x2b, 0x5a, 0x31, 0xa2, 0x6f, 0xb5, 0xfd, 0x7e, 0x8f, 0xab,
-	0xfd, 0xae, 0x40, 0x35, 0x75, 0x40, 0xd1, 0xfd, 0x18, 0xf4, 0xac, 0x15, 0xa0, 0x6e, 0x5c, 0x9e,
-	0x98, 0xb4, 0x01, 0x4d, 0xb1, 0xe1, 0x03, 0xc0, 0x68, 0x20, 0x13, 0x9f, 0xd0, 0xc4, 0xf0, 0x26,
-	0x3e, 0xa1, 0xc9, 0x29, 0x8e, 0x3a, 0x5b, 0xd0, 0xe9, 0x08, 0xf1, 0x18, 0xf2, 0xd1, 0x38, 0xa0,
-	0x5b, 0xc9, 0xaa, 0x13, 0xb3, 0xad, 0xd6, 0xd3, 0x2f, 0x25, 0xf8, 0xb2, 0x00, 0xcf, 0xa3, 0xac,
-	0xee, 0x8a, 0x8b, 0xf6, 0xa2, 0xf8, 0x27, 0x7c, 0xfc, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xaf, 0xe9,
-	0x76, 0xa4, 0xf2, 0x08, 0x00, 0x00,
+	// 2409 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x5f, 0x6f, 0xdb, 0xc8,
+	0x11, 0x3f, 0xea, 0x8f, 0x25, 0x8d, 0xa2, 0x3f, 0x5e, 0xff, 0x93, 0x15, 0xbb, 0x49, 0x98, 0xb6,
+	0x97, 0x33, 0x52, 0x29, 0x70, 0x93, 0x16, 0xb9, 0x43, 0x1f, 0x1c, 0x5b, 0xf1, 0xa9, 0x97, 0x4b,
+	0x02, 0xca, 0xc9, 0xb5, 0xb8, 0xa2, 0x02, 0x45, 0xae, 0x1d, 0xd6, 0x14, 0xc9, 0x5b, 0x2e, 0xed,
+	0x28, 0x87, 0xeb, 0x43, 0x51, 0xa0, 0xe8, 0x43, 0x81, 0x02, 0x7d, 0x28, 0xfa, 0xd0, 0xf7, 0x3e,
+	0xf4, 0x5b, 0xb4, 0x5f, 0xa0, 0xed, 0x17, 0xb8, 0x87, 0xa2, 0x5f, 0xa2, 0x2f, 0xc5, 0xfe, 0x21,
+	0x45, 0x52, 0x94, 0xe4, 0xa4, 0xf7, 0xc6, 0x9d, 0xfd, 0xed, 0xcc, 0xec, 0xec, 0xcc, 0xec, 0xec,
+	0x10, 0x9a, 0xde, 0xa8, 0xeb, 0xe9, 0x93, 0x31, 0x76, 0x68, 0xc7, 0x23, 0x2e, 0x75, 0x51, 0x45,
+	0x0e, 0xbd, 0x51, 0x7b, 0xe7, 0xcc, 0x75, 0xcf, 0x6c, 0xdc, 0xd5, 0x3d, 0xab, 0xab, 0x3b, 0x8e,
+	0x4b, 0x75, 0x6a, 0xb9, 0x8e, 0x2f, 0x80, 0xed, 0xed, 0xd8, 0xec, 0x2b, 0x4a, 0xbd, 0x91, 0x6b,
+	0x4e, 0xe4, 0xd4, 0x0d, 0x39, 0xc5, 0x47, 0xa3, 0xe0, 0xb4, 0x4b, 0xad, 0x31, 0xf6, 0xa9, 0x3e,
+	0xf6, 0x04, 0x40, 0xc5, 0xd0, 0x38, 0xd4, 0x89, 0xd9, 0x77, 0x4e, 0x5d, 0x32, 0xe6, 0x5c, 0xd1,
+	0x0d, 0xa8, 0x1a, 0x3a, 0x31, 0x87, 0x4e, 0x30, 0x1e, 0x61, 0xd2, 0x52, 0x6e, 0x2a, 0x77, 0x2a,
+	0x1a, 0x30, 0xd2, 0x53, 0x4e, 0x41, 0x4d, 0xc8, 0x1b, 0x17, 0x17, 0xad, 0x1c, 0x9f, 0x60, 0x9f,
+	0x6c, 0x09, 0x7e, 0xed, 0x59, 0x64, 0x32, 0x34, 0x75, 0x8a, 0x5b, 0x79, 0xb1, 0x44, 0x90, 0x8e,
+	0x74, 0x8a, 0xd5, 0xbf, 0x2a, 0xb0, 0xaa, 0xe1, 0x33, 0xcb, 0xa7, 0x4c, 0x9a, 0x86, 0xbf, 0x08,
+	0xb0, 0x4f, 0x51, 0x0f, 0x9a, 0x5c, 0x92, 0x35, 0x95, 0xce, 0xc5, 0x55, 0xf7, 0xdb, 0x9d, 0x68,
+	0xf3, 0x9d, 0x94, 0x7e, 0x5a, 0xc3, 0x98, 0x55, 0x98, 0x52, 0x7b, 0xe8, 0x63, 0xc3, 0x75, 0x4c,
+	0x9f, 0xeb, 0x95, 0xd7, 0x80, 0x52, 0x7b, 0x20, 0x28, 0xa8, 0x0b, 0xc5, 0xc0, 0xd7, 0xcf, 0x84,
+	0x62, 0xf5, 0xfd, 0xed, 0x14, 0xf3, 0x13, 0xf7, 0x1c, 0x3b, 0x2f, 0x18, 0x40, 0x13, 0x38, 0xf5,
+	0x6b, 0x05, 0x50, 0x5c, 0x5d, 0xdf, 0x73, 0x1d, 0x1f, 0xa3, 0x5d, 0xe0, 0x66, 0x18, 0x52, 0xb6,
+	0x40, 0x1a, 0xa6, 0x62, 0x84, 0x1c, 0xd0, 0x1a, 0x14, 0x2d, 0x7f, 0xe8, 0x9e, 0x73, 0x0d, 0xca,
+	0x5a, 0xc1, 0xf2, 0x9f, 0x9d, 0xa3, 0x75, 0x28, 0x8e, 0x88, 0xee, 0x98, 0xd2, 0x28, 0x62, 0xc0,
+	0xa8, 0xb6, 0xee, 0xd3, 0xfb, 0xad, 0x82, 0xa0, 0xf2, 0x01, 0x7a, 0x08, 0xc2, 0x66, 0xd8, 0x1f,
+	0xea, 0xb4, 0x55, 0x94, 0x96, 0x10, 0x47, 0xd8, 0x09, 0x8f, 0xb0, 0x73, 0x12, 0x1e, 0xa1, 0x56,
+	0x91, 0xe8, 0x03, 0x3a, 0xdd, 0xe2, 0xca, 0x15, 0xb7, 0xf8, 0x43, 0xd8, 0xd4, 0xf0, 0x85, 0x7b,
+	0x8e, 0xa3, 0xe9, 0xf0, 0x54, 0x16, 0xef, 0x52, 0xed, 0xc0, 0xd6, 0xcc, 0x42, 0x69, 0x9f, 0xc8,
+	0x00, 0xca, 0xd4, 0x00, 0xaa, 0x01, 0x1b, 0x4f, 0xa4, 0x21, 0x39, 0xda, 0x0f, 0xe5, 0xdc, 0x80,
+	0xea, 0x18, 0x13, 0xe3, 0x95, 0xee, 0xd0, 0xa1, 0x65, 0x86, 0x7e, 0x16, 0x92, 0xfa, 0x26, 0xfa,
+	0x00, 0x9a, 0x96, 0x63, 0xd8, 0x81, 0x89, 0x87, 0x96, 0xa3, 0x1b, 0xd4, 0xba, 0xc0, 0xd2, 0xb4,
+	0x0d, 0x49, 0xef, 0x4b, 0xb2, 0xfa, 0xcf, 0x1c, 0x54, 0x22, 0x09, 0xcb, 0xce, 0x29, 0x25, 0x38,
+	0x37, 0x23, 0xf8, 0x6d, 0xce, 0x2c, 0x32, 0x7c, 0xf1, 0x6a, 0x86, 0x47, 0xfb, 0xb0, 0xe2, 0x53,
+	0x9d, 0x06, 0xbe, 0x3c, 0xaa, 0x76, 0xd6, 0x8a, 0x01, 0x47, 0x68, 0x12, 0xc9, 0x1c, 0xc3, 0x20,
+	0x58, 0xa7, 0xd8, 0x64, 0x8e, 0x51, 0x5a, 0xee, 0x18, 0x12, 0x7d, 0x40, 0x53, 0x3e, 0x55, 0x7e,
+	0x0b, 0x9f, 0x52, 0x4d, 0xd8, 0x4c, 0x9f, 0x9c, 0x3c, 0xe8, 0x07, 0x50, 0x9d, 0x1a, 0xd8, 0x6f,
+	0x29, 0x37, 0xf3, 0x77, 0xaa, 0xfb, 0xeb, 0x59, 0x1b, 0x11, 0x89, 0x43, 0x2c, 0xcf, 0x0c, 0x10,
+	0xf5, 0x23, 0x28, 0x7e, 0xea, 0x3a, 0x78, 0x82, 0xda, 0x50, 0x36, 0x02, 0x42, 0xb0, 0x63, 0x4c,
+	0xe4, 0x99, 0x45, 0x63, 0xb4, 0x09, 0x2b, 0xfa, 0xd8, 0x0d, 0x1c, 0x2a, 0xa3, 0x5b, 0x8e, 0xd4,
+	0x3f, 0xe6, 0x00, 0x3d, 0x17, 0x52, 0xe3, 0x19, 0x61, 0x89, 0x03, 0x7c, 0x07, 0xea, 0x04, 0xfb,
+	0x98, 0x5c, 0x70, 0x74, 0xe8, 0x03, 0x45, 0xad, 0x16, 0xa3, 0xf6, 0x4d, 0xf4, 0x03, 0x28, 0xb3,
+	0x74, 0xc6, 0x52, 0x66, 0x2b, 0xbf, 0xd4, 0x70, 0x11, 0x36, 0xa6, 0x6c, 0x81, 0xb3, 0x95, 0x23,
+	0xe6, 0x77, 0x96, 0x3f, 0x34, 0x74, 0xc7, 0xc0, 0x36, 0x36, 0xb9, 0xc3, 0x94, 0x35, 0xb0, 0xfc,
+	0x43, 0x49, 0x41, 0xdf, 0x85, 0xa2, 0x47, 0x2c, 0x43, 0x04, 0x71, 0x75, 0xbf, 0x19, 0x33, 0x28,
+	0x37, 0x91, 0x26, 0xa6, 0x91, 0x0a, 0x79, 0xaa, 0xbf, 0x6e, 0x95, 0xe6, 0xa0, 0xd8, 0xa4, 0xfa,
+	0x7b, 0x05, 0x36, 0x7a, 0xaf, 0xb1, 0x11, 0x50, 0x2c, 0x0d, 0x14, 0xc6, 0xdd, 0x53, 0x58, 0x93,
+	0x2b, 0x32, 0x12, 0xef, 0x6e, 0x8c, 0xdb, 0xac, 0x61, 0x35, 0xe4, 0xcd, 0x1a, 0xfb, 0x7d, 0x68,
+	0x58, 0x26, 0x1e, 0x7b, 0x2e, 0x65, 0x47, 0x35, 0x3c, 0xc7, 0x13, 0x19, 0x52, 0xf5, 0x18, 0xf9,
+	0x13, 0x3c, 0x51, 0x9f, 0xc0, 0x66, 0x5a, 0xa3, 0x69, 0x62, 0x8d, 0x54, 0x0a, 0x33, 0x41, 0x78,
+	0xfd, 0xf5, 0xcd, 0x6c, 0xbf, 0x79, 0x00, 0xeb, 0xc2, 0x70, 0xa9, 0xed, 0x2d, 0xe6, 0xa5, 0xde,
+	0x85, 0x8d, 0xd4, 0xb2, 0x45, 0xc9, 0xeb, 0x21, 0xb4, 0x1e, 0x05, 0xf6, 0xf9, 0x95, 0x04, 0xe5,
+	0x93, 0x82, 0x1e, 0xc0, 0x76, 0xc6, 0x52, 0x29, 0xac, 0x05, 0x25, 0x13, 0xdb, 0x98, 0x62, 0xa1,
+	0x61, 0x51, 0x0b, 0x87, 0xea, 0x8f, 0x60, 0xe7, 0x18, 0xd3, 0x0c, 0xd3, 0x5f, 0x6d, 0x7b, 0xbf,
+	0x56, 0x60, 0x77, 0xce, 0x7a, 0x29, 0xfa, 0x9b, 0x3e, 0xfe, 0xcc, 0xc3, 0xb9, 0x0f, 0xab, 0x7d,
+	0xc7, 0xa2, 0x96, 0x6e, 0x5b, 0x6f, 0xf0, 0x55, 0x13, 0xbe, 0xfa, 0x01, 0xa0, 0xf8, 0xaa, 0x45,
+	0x07, 0xf3, 0x97, 0x1c, 0x34, 0x8f, 0x31, 0x33, 0x68, 0x60, 0x47, 0x27, 0x72, 0x1d, 0x2a, 0x9e,
+	0x7e, 0x86, 0x87, 0xbe, 0xf5, 0x06, 0x4b, 0xbb, 0x96, 0x19, 0x61, 0x60, 0xbd, 0xe1, 0x51, 0x69,
+	0x04, 0xc4, 0x77, 0x89, 0xf4, 0x4e, 0x39, 0x42, 0x1d, 0x58, 0x4b, 0x26, 0x83, 0xe1, 0x29, 0x71,
+	0xc7, 0x3c, 0xe0, 0x8b, 0xda, 0x6a, 0x22, 0x23, 0x3c, 0x26, 0xee, 0x18, 0xed, 0xc1, 0x6a, 0x0a,
+	0x4f, 0x5d, 0x19, 0xe8, 0x8d, 0x04, 0xfa, 0xc4, 0x45, 0xf7, 0xa0, 0xe8, 0x5b, 0x8e, 0x81, 0xaf,
+	0x70, 0x97, 0x0b, 0x20, 0x5b, 0x11, 0x38, 0xd4, 0xb2, 0x5b, 0x2b, 0xcb, 0x57, 0x70, 0x60, 0xda,
+	0xaa, 0xa5, 0x19, 0xab, 0x7e, 0xad, 0x40, 0x49, 0xd3, 0x2f, 0x8f, 0x74, 0xaa, 0x7f, 0xe3, 0x87,
+	0x9f, 0x55, 0xc1, 0xe5, 0xde, 0xbe, 0x82, 0x4b, 0x3a, 0x75, 0x3e, 0x1d, 0xff, 0xa9, 0x2d, 0x16,
+	0x66, 0xb6, 0x38, 0x81, 0xe6, 0x51, 0xe0, 0xd9, 0x96, 0xa1, 0x47, 0xb9, 0x25, 0x23, 0xc9, 0x2b,
+	0x59, 0x49, 0x3e, 0x29, 0x3a, 0x77, 0x33, 0xbf, 0x50, 0x74, 0x7e, 0x46, 0xf4, 0x3f, 0x14, 0x58,
+	0x8d, 0x39, 0xa2, 0xf4, 0xd9, 0xef, 0x41, 0x99, 0xe8, 0x97, 0xac, 0x1a, 0xd6, 0xe5, 0xed, 0x88,
+	0x62, 0xf6, 0x90, 0xa7, 0xa1, 0x95, 0x88, 0xf8, 0xc8, 0xae, 0x1c, 0x3f, 0x02, 0x30, 0xc3, 0x4d,
+	0xf9, 0xad, 0x3c, 0xe7, 0x72, 0x3d, 0xc6, 0x25, 0xbd, 0x63, 0x2d, 0x06, 0x67, 0x97, 0x29, 0xc1,
+	0x9e, 0xad, 0x4f, 0xb0, 0x29, 0x9d, 0x33, 0x1a, 0xb3, 0x3d, 0x39, 0xf8, 0x35, 0x1d, 0xca, 0x70,
+	0x28, 0x8a, 0x3d, 0x31, 0xd2, 0x21, 0xa7, 0xa8, 0xbf, 0x84, 0x9a, 0xd8, 0xcf, 0x20, 0x18, 0x8f,
+	0x75, 0x32, 0x61, 0xa5, 0x8f, 0xc1, 0x2f, 0x34, 0x61, 0x42, 0x31, 0x48, 0x29, 0x98, 0x7b, 0x77,
+	0x05, 0xf3, 0x49, 0x05, 0xd5, 0x37, 0xb0, 0x31, 0xa0, 0x04, 0xeb, 0x63, 0xa1, 0x85, 0x3f, 0xc7,
+	0xac, 0xca, 0x32, 0xb3, 0xee, 0x43, 0xc9, 0x17, 0x3b, 0x90, 0x4e, 0xd9, 0x8a, 0xa3, 0xe3, 0x3b,
+	0xd4, 0x42, 0xa0, 0x7a, 0x17, 0x36, 0x07, 0x98, 0x3e, 0xd6, 0x03, 0x9b, 0x3e, 0x27, 0xee, 0xa9,
+	0x65, 0x47, 0xe9, 0x0b, 0x41, 0xc1, 0xd1, 0xc7, 0x58, 0xe6, 0x2d, 0xfe, 0xad, 0xb6, 0x60, 0xf3,
+	0x38, 0x13, 0xad, 0x7e, 0x0e, 0xeb, 0x49, 0xb2, 0xdc, 0x42, 0x06, 0x17, 0x66, 0x0b, 0x4f, 0xc0,
+	0x7c, 0xe9, 0x81, 0xd1, 0x78, 0xea, 0x1a, 0xf9, 0x58, 0xf6, 0x7b, 0x04, 0x9b, 0xe2, 0x79, 0x82,
+	0xc9, 0x67, 0x78, 0xf4, 0xca, 0x75, 0xcf, 0x43, 0x25, 0x9b, 0x90, 0x0f, 0x88, 0x2d, 0xb9, 0xb3,
+	0x4f, 0x96, 0xf7, 0x7c, 0x6c, 0x10, 0x4c, 0xc3, 0xbc, 0x27, 0x46, 0xea, 0x63, 0xd8, 0x9a, 0xe1,
+	0x21, 0x75, 0x9c, 0x2e, 0x51, 0xe2, 0x4b, 0xb2, 0x53, 0xfd, 0xdf, 0x73, 0xd0, 0x90, 0x0c, 0x8e,
+	0xb0, 0x6d, 0x5d, 0x60, 0x32, 0x41, 0xdb, 0x50, 0xc6, 0x17, 0x89, 0x2b, 0xaa, 0xc4, 0xc7, 0x22,
+	0xde, 0xc4, 0x14, 0x9d, 0x78, 0x58, 0xaa, 0x54, 0xe1, 0x94, 0x93, 0x89, 0x87, 0x43, 0xfd, 0xf3,
+	0x53, 0xfd, 0x5b, 0x50, 0xd2, 0x29, 0xc5, 0x63, 0x2f, 0x2c, 0xa7, 0xc2, 0x21, 0xf3, 0x63, 0x51,
+	0x1f, 0x0f, 0x0d, 0xd7, 0x14, 0x39, 0xb6, 0xa8, 0x81, 0x20, 0x1d, 0xba, 0x26, 0x66, 0x6e, 0x8b,
+	0x09, 0x71, 0x09, 0x4f, 0xa6, 0x15, 0x4d, 0x0c, 0x12, 0x65, 0x5d, 0xe9, 0x2d, 0xca, 0xba, 0x1d,
+	0xa8, 0x98, 0x62, 0x83, 0xd8, 0xe4, 0x85, 0x74, 0x59, 0x9b, 0x12, 0xd2, 0x89, 0xa2, 0x32, 0xf3,
+	0xa8, 0x48, 0x26, 0x1a, 0x48, 0x5f, 0xdc, 0x0f, 0x61, 0x87, 0x15, 0xdb, 0x49, 0x4b, 0x5a, 0x38,
+	0x7a, 0x2d, 0xcd, 0x37, 0xa9, 0xea, 0xc1, 0xee, 0x9c, 0xa5, 0xf2, 0x3c, 0x3f, 0x04, 0x30, 0x23,
+	0xaa, 0xcc, 0x47, 0xf1, 0xfc, 0x9c, 0x3a, 0x3e, 0x2d, 0x86, 0xce, 0x3e, 0xf3, 0xdf, 0xe5, 0x60,
+	0x6b, 0x80, 0x29, 0xb5, 0xb1, 0xa8, 0x6a, 0x3c, 0x97, 0xc4, 0x2f, 0x61, 0x66, 0x32, 0x71, 0x8b,
+	0xca, 0x3a, 0x9e, 0x11, 0xf8, 0xe5, 0xb9, 0x05, 0x25, 0x3e, 0x49, 0xdd, 0xd0, 0x1b, 0xd9, 0xf0,
+	0xc4, 0x45, 0x3d, 0x28, 0x9f, 0x11, 0x37, 0xf0, 0x86, 0xa3, 0x89, 0x7c, 0xa5, 0xef, 0xc5, 0x14,
+	0x9c, 0x23, 0xab, 0x73, 0xcc, 0x96, 0x3c, 0x9a, 0x68, 0xa5, 0x33, 0xf1, 0xb1, 0xf4, 0xa6, 0x40,
+	0x5d, 0x58, 0x11, 0xd7, 0x8e, 0x7c, 0xaf, 0x6d, 0xc5, 0xa4, 0xf4, 0x5e, 0x33, 0xde, 0x8f, 0xf9,
+	0xb4, 0x26, 0x61, 0xea, 0x6d, 0x28, 0x49, 0x29, 0xa8, 0x04, 0xf9, 0xa3, 0x83, 0x9f, 0x36, 0xdf,
+	0x43, 0x0d, 0xa8, 0x6a, 0xbd, 0x41, 0x4f, 0x7b, 0x79, 0x70, 0xd2, 0x7f, 0xf6, 0xb4, 0xa9, 0xa8,
+	0xff, 0xc9, 0x41, 0x2d, 0xa6, 0xa3, 0x7b, 0xb9, 0xfc, 0x71, 0x8b, 0xa0, 0xc0, 0x7b, 0x25, 0xc2,
+	0x0c, 0xfc, 0x3b, 0xe3, 0xca, 0xca, 0x67, 0x5d, 0x59, 0xb7, 0xa1, 0x66, 0xe8, 0x1e, 0x0d, 0x08,
+	0x1e, 0x1a, 0xd1, 0x33, 0x23, 0xaf, 0x5d, 0x93, 0xc4, 0x43, 0x46, 0x63, 0xbc, 0x42, 0x90, 0x7c,
+	0x8c, 0x14, 0x39, 0x2a, 0x5c, 0x7a, 0xc0, 0x89, 0xe8, 0x16, 0x5c, 0x13, 0x0f, 0x12, 0xc9, 0x6a,
+	0x85, 0x83, 0xaa, 0x82, 0x26, 0x38, 0x71, 0x71, 0x1c, 0x22, 0x19, 0x95, 0x42, 0x71, 0x8c, 0x38,
+	0xe5, 0x43, 0xf0, 0x69, 0xe0, 0x98, 0x92, 0x4f, 0x59, 0xf0, 0x11, 0xb4, 0x88, 0x8f, 0x84, 0x48,
+	0x3e, 0x15, 0xc1, 0x47, 0x10, 0x25, 0x9f, 0x5d, 0x00, 0x07, 0xd3, 0x10, 0x01, 0x1c, 0x51, 0x71,
+	0x30, 0x15, 0xd3, 0xea, 0x21, 0xd4, 0x9e, 0x60, 0xf3, 0x0c, 0x93, 0x47, 0xba, 0xcd, 0xc4, 0xf3,
+	0xec, 0x60, 0x4c, 0xef, 0xa6, 0x8a, 0x16, 0x0e, 0xd9, 0xcc, 0x48, 0x80, 0xe4, 0x9b, 0x31, 0x1c,
+	0xaa, 0x7f, 0x53, 0xa0, 0x35, 0xeb, 0x51, 0x32, 0x56, 0xee, 0x42, 0x81, 0xb8, 0x97, 0x61, 0x94,
+	0xb4, 0xb2, 0x9d, 0xd0, 0xbd, 0xd4, 0x38, 0x0a, 0x75, 0xa0, 0x48, 0x5d, 0xaa, 0xdb, 0x19, 0xf7,
+	0x4b, 0x12, 0x2e, 0x60, 0xe8, 0x3e, 0x94, 0xa5, 0x16, 0xe1, 0x8d, 0x1e, 0x5f, 0x92, 0xd8, 0x9a,
+	0x16, 0x21, 0xa7, 0x31, 0x58, 0x88, 0xc5, 0xe0, 0x9f, 0x15, 0x58, 0x13, 0x1e, 0x2b, 0x96, 0xfd,
+	0x7f, 0xf1, 0xb7, 0xac, 0xce, 0x89, 0x05, 0x4e, 0xe1, 0x6a, 0x81, 0xf3, 0xa7, 0x1c, 0x54, 0x85,
+	0x66, 0x3d, 0x87, 0x92, 0x09, 0x73, 0x48, 0x4a, 0x74, 0xc7, 0xd7, 0x8d, 0xd0, 0xb9, 0x85, 0x72,
+	0xb5, 0x18, 0x55, 0xc4, 0x45, 0xec, 0x66, 0xe0, 0xdf, 0xcb, 0xca, 0xc3, 0xd9, 0xb0, 0x29, 0x64,
+	0x85, 0x4d, 0x6a, 0x8b, 0xc5, 0x99, 0x2d, 0xc6, 0x7c, 0x69, 0x25, 0xe9, 0x4b, 0xd3, 0x17, 0x7d,
+	0x29, 0xde, 0x7e, 0x48, 0x5c, 0x25, 0xe5, 0xab, 0x5f, 0x25, 0xea, 0xc7, 0xb0, 0x9e, 0x3c, 0x3a,
+	0xe9, 0x7c, 0xf7, 0xa0, 0x84, 0x1d, 0x1a, 0xcb, 0xd2, 0x9b, 0x33, 0xde, 0xc1, 0x8d, 0xa9, 0x85,
+	0xb0, 0xbd, 0x2e, 0xd4, 0x93, 0x6d, 0x26, 0x54, 0x83, 0xca, 0xa7, 0x2f, 0x9e, 0x9c, 0xf4, 0x87,
+	0x2f, 0x06, 0xbd, 0xe6, 0x7b, 0xa8, 0x0e, 0x30, 0xe8, 0x3f, 0x3d, 0x7e, 0xd2, 0xe3, 0x63, 0x65,
+	0xef, 0x00, 0x1a, 0xd1, 0x02, 0xd1, 0x65, 0x42, 0x00, 0x2b, 0x07, 0x87, 0x27, 0xfd, 0x97, 0x0c,
+	0x5e, 0x85, 0x52, 0xef, 0x27, 0xcf, 0xfb, 0x5a, 0xef, 0xa8, 0xa9, 0xb0, 0x81, 0xd6, 0x7b, 0xf9,
+	0xec, 0x93, 0xde, 0x51, 0x33, 0x87, 0xca, 0x50, 0x78, 0x31, 0xe8, 0x1d, 0x35, 0xf3, 0x7b, 0xb7,
+	0xe0, 0x5a, 0xfc, 0xc4, 0xd9, 0xcc, 0x8f, 0x07, 0xcf, 0x9e, 0x36, 0xdf, 0x63, 0x19, 0xf2, 0x70,
+	0xf0, 0xb2, 0xa9, 0xec, 0xff, 0xb7, 0x0e, 0x75, 0x59, 0xf5, 0x0d, 0x30, 0xb9, 0x60, 0x4d, 0x8b,
+	0xcf, 0x01, 0xa6, 0x2d, 0x55, 0xb4, 0x93, 0xa8, 0xc4, 0x52, 0x8d, 0xe1, 0xf6, 0xee, 0x9c, 0x59,
+	0x61, 0x26, 0xb5, 0xf9, 0xab, 0x7f, 0xfd, 0xfb, 0x0f, 0x39, 0x50, 0x8b, 0x5d, 0xf6, 0x90, 0xf8,
+	0x50, 0xd9, 0x43, 0x14, 0x1a, 0xa9, 0xa6, 0x24, 0xba, 0x95, 0xe0, 0x91, 0xd5, 0xe9, 0x6c, 0xab,
+	0x8b, 0x20, 0x52, 0x56, 0x9b, 0xcb, 0x5a, 0xdf, 0x43, 0x5c, 0x56, 0xf7, 0xcb, 0x69, 0x5f, 0xe9,
+	0x2b, 0x64, 0x42, 0x3d, 0xd9, 0x20, 0x43, 0x37, 0xe3, 0xe7, 0x95, 0xd5, 0xf5, 0x6c, 0xdf, 0x5a,
+	0x80, 0x90, 0x22, 0x6b, 0x5c, 0x64, 0x09, 0x89, 0xed, 0xa1, 0x5f, 0x40, 0x3d, 0xd9, 0x36, 0x49,
+	0x48, 0xc9, 0xec, 0xf1, 0xb4, 0x6f, 0x2d, 0x40, 0x48, 0x29, 0x6b, 0x5c, 0x4a, 0x4d, 0x2d, 0x87,
+	0xbf, 0x1d, 0x98, 0x1d, 0xbf, 0x80, 0x5a, 0xa2, 0x61, 0x81, 0x6e, 0x24, 0x9e, 0x71, 0xb3, 0x5d,
+	0x90, 0xf6, 0xcd, 0xf9, 0x00, 0x29, 0x68, 0x97, 0x0b, 0xda, 0xda, 0xdb, 0x08, 0x05, 0x75, 0xbf,
+	0x9c, 0x46, 0xf3, 0x57, 0x68, 0x02, 0xab, 0x33, 0x7d, 0x12, 0x74, 0x3b, 0xc6, 0x75, 0x5e, 0x03,
+	0xa6, 0xfd, 0xed, 0xc5, 0x20, 0x29, 0x7e, 0x9b, 0x8b, 0x5f, 0x53, 0xeb, 0x91, 0xf8, 0xe1, 0x28,
+	0xb0, 0xcf, 0xd9, 0x6e, 0x7f, 0xab, 0xc0, 0x46, 0x66, 0xb3, 0x04, 0xbd, 0x1f, 0x63, 0xbd, 0xa8,
+	0x1d, 0xd3, 0xbe, 0xb3, 0x1c, 0x98, 0x34, 0x03, 0x9a, 0x63, 0x86, 0x9f, 0x03, 0x4c, 0x7b, 0x1f,
+	0x89, 0xf0, 0x98, 0x69, 0xa4, 0xb4, 0x77, 0xe7, 0xcc, 0xa6, 0x4e, 0xb6, 0xda, 0xb5, 0xa6, 0x1c,
+	0x3f, 0x83, 0x4a, 0xf4, 0x4c, 0x45, 0xd7, 0x93, 0x5a, 0x27, 0xba, 0x28, 0xed, 0x9d, 0xec, 0x49,
+	0xc9, 0xbc, 0xc1, 0x99, 0x57, 0x50, 0xa9, 0x4b, 0x04, 0xaf, 0x57, 0x50, 0x4b, 0x3c, 0xd6, 0x16,
+	0x33, 0x8f, 0xbb, 0x4b, 0xe6, 0x1b, 0x4f, 0xdd, 0xe4, 0x02, 0x9a, 0xa8, 0x2e, 0x05, 0x74, 0x7d,
+	0x0e, 0xbb, 0xa7, 0xa0, 0x31, 0x34, 0x44, 0x32, 0x88, 0x5e, 0x2c, 0xa9, 0x20, 0xcf, 0x7a, 0x11,
+	0xb5, 0xd5, 0x45, 0x90, 0x99, 0x58, 0xb8, 0x14, 0x33, 0xcc, 0x3b, 0x7e, 0xa3, 0x88, 0x3f, 0x17,
+	0x33, 0x75, 0x75, 0xc2, 0x3b, 0x16, 0x15, 0xed, 0xed, 0x3b, 0xcb, 0x81, 0x52, 0x83, 0xeb, 0x5c,
+	0x83, 0x0d, 0xb4, 0x16, 0x6a, 0xd0, 0x8d, 0xd5, 0xe0, 0x3e, 0xac, 0x1d, 0x63, 0x9a, 0x2e, 0x59,
+	0x90, 0xba, 0xbc, 0x42, 0x6e, 0xdf, 0x5e, 0x88, 0x49, 0x6e, 0x1f, 0x55, 0xbb, 0x7e, 0x04, 0x41,
+	0x0e, 0xeb, 0xd6, 0x32, 0xd8, 0x3b, 0xc9, 0x5d, 0x0f, 0xef, 0x41, 0xdd, 0xb3, 0x3a, 0x1f, 0x53,
+	0xea, 0x3d, 0x72, 0xcd, 0x49, 0x98, 0x4c, 0x11, 0x8a, 0x09, 0xea, 0x62, 0x2e, 0x05, 0xfd, 0x2c,
+	0xbc, 0x55, 0xc4, 0x3d, 0x87, 0xbe, 0x35, 0x53, 0x60, 0x24, 0xea, 0x9c, 0x39, 0x12, 0xa6, 0xde,
+	0x63, 0x73, 0x74, 0xc8, 0xfd, 0x02, 0x1a, 0xa9, 0x67, 0x7d, 0xc2, 0x77, 0xb2, 0x9f, 0xfc, 0xed,
+	0x78, 0xf6, 0xcb, 0x7a, 0xcd, 0xab, 0x37, 0xb8, 0xb8, 0x6d, 0x75, 0xbd, 0xab, 0x9b, 0x63, 0xcb,
+	0xe9, 0x9e, 0x32, 0xd0, 0x50, 0x3e, 0xde, 0x99, 0x13, 0xf9, 0xd0, 0x38, 0x5e, 0x20, 0xf7, 0xf8,
+	0x1d, 0xe5, 0xee, 0x70, 0xb9, 0x9b, 0x28, 0x53, 0xee, 0x68, 0x85, 0x17, 0x1f, 0xdf, 0xff, 0xdf,
+	0x00, 0x21, 0x94, 0xe4, 0x3a, 0x54, 0x1e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -850,6 +2684,10 @@ const _ = grpc.SupportPackageIsVersion4
 type PaymentServiceClient interface {
 	//クレジットカードのトークン発行(非保持化対応)
 	RegistCard(ctx context.Context, in *RegistCardRequest, opts ...grpc.CallOption) (*RegistCardResponse, error)
+	//カードトークンを失効させる
+	RevokeCardToken(ctx context.Context, in *RevokeCardTokenRequest, opts ...grpc.CallOption) (*RevokeCardTokenResponse, error)
+	//カードトークンの一覧を取得する
+	ListCardTokens(ctx context.Context, in *ListCardTokensRequest, opts ...grpc.CallOption) (*ListCardTokensResponse, error)
 	//決済を行う
 	ExecutePayment(ctx context.Context, in *ExecutePaymentRequest, opts ...grpc.CallOption) (*ExecutePaymentResponse, error)
 	//決済をキャンセルする
@@ -862,6 +2700,22 @@ type PaymentServiceClient interface {
 	Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error)
 	//ベンチマーカー用結果取得API
 	GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*GetResultResponse, error)
+	//ベンチマーカー用結果取得API(1件ずつストリームで返す)
+	StreamResults(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (PaymentService_StreamResultsClient, error)
+	//決済の状態が変化したときに通知するWebhookを登録する(urlが空の場合は登録を解除する)
+	RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*RegisterWebhookResponse, error)
+	//Webhookの配信履歴を取得する
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	//日ごと、予約ごとの精算レポートを取得する(台帳から集計する)
+	GetSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*SettlementReportResponse, error)
+	//精算レポートをCSVまたはJSONで出力する
+	ExportSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	//台帳の仕訳をCSVまたはJSONで出力する
+	ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	//障害注入プロファイルを切り替える(空文字で無効化)
+	SetFaultProfile(ctx context.Context, in *SetFaultProfileRequest, opts ...grpc.CallOption) (*FaultProfileResponse, error)
+	//障害注入プロファイルを取得する
+	GetFaultProfile(ctx context.Context, in *GetFaultProfileRequest, opts ...grpc.CallOption) (*FaultProfileResponse, error)
 }
 
 type paymentServiceClient struct {
@@ -881,6 +2735,24 @@ func (c *paymentServiceClient) RegistCard(ctx context.Context, in *RegistCardReq
 	return out, nil
 }
 
+func (c *paymentServiceClient) RevokeCardToken(ctx context.Context, in *RevokeCardTokenRequest, opts ...grpc.CallOption) (*RevokeCardTokenResponse, error) {
+	out := new(RevokeCardTokenResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/RevokeCardToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListCardTokens(ctx context.Context, in *ListCardTokensRequest, opts ...grpc.CallOption) (*ListCardTokensResponse, error) {
+	out := new(ListCardTokensResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/ListCardTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *paymentServiceClient) ExecutePayment(ctx context.Context, in *ExecutePaymentRequest, opts ...grpc.CallOption) (*ExecutePaymentResponse, error) {
 	out := new(ExecutePaymentResponse)
 	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/ExecutePayment", in, out, opts...)
@@ -935,10 +2807,109 @@ func (c *paymentServiceClient) GetResult(ctx context.Context, in *GetResultReque
 	return out, nil
 }
 
+func (c *paymentServiceClient) StreamResults(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (PaymentService_StreamResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PaymentService_serviceDesc.Streams[0], "/paymentpb.PaymentService/StreamResults", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &paymentServiceStreamResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PaymentService_StreamResultsClient interface {
+	Recv() (*StreamResultsResponse, error)
+	grpc.ClientStream
+}
+
+type paymentServiceStreamResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *paymentServiceStreamResultsClient) Recv() (*StreamResultsResponse, error) {
+	m := new(StreamResultsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *paymentServiceClient) RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*RegisterWebhookResponse, error) {
+	out := new(RegisterWebhookResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/RegisterWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*SettlementReportResponse, error) {
+	out := new(SettlementReportResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/GetSettlementReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExportSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/ExportSettlementReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/ExportLedger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SetFaultProfile(ctx context.Context, in *SetFaultProfileRequest, opts ...grpc.CallOption) (*FaultProfileResponse, error) {
+	out := new(FaultProfileResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/SetFaultProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetFaultProfile(ctx context.Context, in *GetFaultProfileRequest, opts ...grpc.CallOption) (*FaultProfileResponse, error) {
+	out := new(FaultProfileResponse)
+	err := c.cc.Invoke(ctx, "/paymentpb.PaymentService/GetFaultProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentServiceServer is the server API for PaymentService service.
 type PaymentServiceServer interface {
 	//クレジットカードのトークン発行(非保持化対応)
 	RegistCard(context.Context, *RegistCardRequest) (*RegistCardResponse, error)
+	//カードトークンを失効させる
+	RevokeCardToken(context.Context, *RevokeCardTokenRequest) (*RevokeCardTokenResponse, error)
+	//カードトークンの一覧を取得する
+	ListCardTokens(context.Context, *ListCardTokensRequest) (*ListCardTokensResponse, error)
 	//決済を行う
 	ExecutePayment(context.Context, *ExecutePaymentRequest) (*ExecutePaymentResponse, error)
 	//決済をキャンセルする
@@ -951,6 +2922,22 @@ type PaymentServiceServer interface {
 	Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error)
 	//ベンチマーカー用結果取得API
 	GetResult(context.Context, *GetResultRequest) (*GetResultResponse, error)
+	//ベンチマーカー用結果取得API(1件ずつストリームで返す)
+	StreamResults(*GetResultRequest, PaymentService_StreamResultsServer) error
+	//決済の状態が変化したときに通知するWebhookを登録する(urlが空の場合は登録を解除する)
+	RegisterWebhook(context.Context, *RegisterWebhookRequest) (*RegisterWebhookResponse, error)
+	//Webhookの配信履歴を取得する
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	//日ごと、予約ごとの精算レポートを取得する(台帳から集計する)
+	GetSettlementReport(context.Context, *SettlementReportRequest) (*SettlementReportResponse, error)
+	//精算レポートをCSVまたはJSONで出力する
+	ExportSettlementReport(context.Context, *SettlementReportRequest) (*httpbody.HttpBody, error)
+	//台帳の仕訳をCSVまたはJSONで出力する
+	ExportLedger(context.Context, *ExportLedgerRequest) (*httpbody.HttpBody, error)
+	//障害注入プロファイルを切り替える(空文字で無効化)
+	SetFaultProfile(context.Context, *SetFaultProfileRequest) (*FaultProfileResponse, error)
+	//障害注入プロファイルを取得する
+	GetFaultProfile(context.Context, *GetFaultProfileRequest) (*FaultProfileResponse, error)
 }
 
 // UnimplementedPaymentServiceServer can be embedded to have forward compatible implementations.
@@ -960,6 +2947,12 @@ type UnimplementedPaymentServiceServer struct {
 func (*UnimplementedPaymentServiceServer) RegistCard(ctx context.Context, req *RegistCardRequest) (*RegistCardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegistCard not implemented")
 }
+func (*UnimplementedPaymentServiceServer) RevokeCardToken(ctx context.Context, req *RevokeCardTokenRequest) (*RevokeCardTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeCardToken not implemented")
+}
+func (*UnimplementedPaymentServiceServer) ListCardTokens(ctx context.Context, req *ListCardTokensRequest) (*ListCardTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCardTokens not implemented")
+}
 func (*UnimplementedPaymentServiceServer) ExecutePayment(ctx context.Context, req *ExecutePaymentRequest) (*ExecutePaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecutePayment not implemented")
 }
@@ -978,6 +2971,30 @@ func (*UnimplementedPaymentServiceServer) Initialize(ctx context.Context, req *I
 func (*UnimplementedPaymentServiceServer) GetResult(ctx context.Context, req *GetResultRequest) (*GetResultResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetResult not implemented")
 }
+func (*UnimplementedPaymentServiceServer) StreamResults(req *GetResultRequest, srv PaymentService_StreamResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamResults not implemented")
+}
+func (*UnimplementedPaymentServiceServer) RegisterWebhook(ctx context.Context, req *RegisterWebhookRequest) (*RegisterWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhook not implemented")
+}
+func (*UnimplementedPaymentServiceServer) ListWebhookDeliveries(ctx context.Context, req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (*UnimplementedPaymentServiceServer) GetSettlementReport(ctx context.Context, req *SettlementReportRequest) (*SettlementReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSettlementReport not implemented")
+}
+func (*UnimplementedPaymentServiceServer) ExportSettlementReport(ctx context.Context, req *SettlementReportRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSettlementReport not implemented")
+}
+func (*UnimplementedPaymentServiceServer) ExportLedger(ctx context.Context, req *ExportLedgerRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportLedger not implemented")
+}
+func (*UnimplementedPaymentServiceServer) SetFaultProfile(ctx context.Context, req *SetFaultProfileRequest) (*FaultProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaultProfile not implemented")
+}
+func (*UnimplementedPaymentServiceServer) GetFaultProfile(ctx context.Context, req *GetFaultProfileRequest) (*FaultProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaultProfile not implemented")
+}
 
 func RegisterPaymentServiceServer(s *grpc.Server, srv PaymentServiceServer) {
 	s.RegisterService(&_PaymentService_serviceDesc, srv)
@@ -1001,6 +3018,42 @@ func _PaymentService_RegistCard_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_RevokeCardToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCardTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RevokeCardToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/RevokeCardToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RevokeCardToken(ctx, req.(*RevokeCardTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListCardTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCardTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListCardTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/ListCardTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListCardTokens(ctx, req.(*ListCardTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PaymentService_ExecutePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExecutePaymentRequest)
 	if err := dec(in); err != nil {
@@ -1109,6 +3162,153 @@ func _PaymentService_GetResult_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_StreamResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetResultRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PaymentServiceServer).StreamResults(m, &paymentServiceStreamResultsServer{stream})
+}
+
+type PaymentService_StreamResultsServer interface {
+	Send(*StreamResultsResponse) error
+	grpc.ServerStream
+}
+
+type paymentServiceStreamResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *paymentServiceStreamResultsServer) Send(m *StreamResultsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PaymentService_RegisterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RegisterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/RegisterWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RegisterWebhook(ctx, req.(*RegisterWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetSettlementReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettlementReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetSettlementReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/GetSettlementReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetSettlementReport(ctx, req.(*SettlementReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExportSettlementReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettlementReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExportSettlementReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/ExportSettlementReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExportSettlementReport(ctx, req.(*SettlementReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExportLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExportLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/ExportLedger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExportLedger(ctx, req.(*ExportLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SetFaultProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFaultProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SetFaultProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/SetFaultProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SetFaultProfile(ctx, req.(*SetFaultProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetFaultProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFaultProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetFaultProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/paymentpb.PaymentService/GetFaultProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetFaultProfile(ctx, req.(*GetFaultProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PaymentService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "paymentpb.PaymentService",
 	HandlerType: (*PaymentServiceServer)(nil),
@@ -1117,6 +3317,14 @@ var _PaymentService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RegistCard",
 			Handler:    _PaymentService_RegistCard_Handler,
 		},
+		{
+			MethodName: "RevokeCardToken",
+			Handler:    _PaymentService_RevokeCardToken_Handler,
+		},
+		{
+			MethodName: "ListCardTokens",
+			Handler:    _PaymentService_ListCardTokens_Handler,
+		},
 		{
 			MethodName: "ExecutePayment",
 			Handler:    _PaymentService_ExecutePayment_Handler,
@@ -1141,7 +3349,41 @@ var _PaymentService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetResult",
 			Handler:    _PaymentService_GetResult_Handler,
 		},
+		{
+			MethodName: "RegisterWebhook",
+			Handler:    _PaymentService_RegisterWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _PaymentService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "GetSettlementReport",
+			Handler:    _PaymentService_GetSettlementReport_Handler,
+		},
+		{
+			MethodName: "ExportSettlementReport",
+			Handler:    _PaymentService_ExportSettlementReport_Handler,
+		},
+		{
+			MethodName: "ExportLedger",
+			Handler:    _PaymentService_ExportLedger_Handler,
+		},
+		{
+			MethodName: "SetFaultProfile",
+			Handler:    _PaymentService_SetFaultProfile_Handler,
+		},
+		{
+			MethodName: "GetFaultProfile",
+			Handler:    _PaymentService_GetFaultProfile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamResults",
+			Handler:       _PaymentService_StreamResults_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb/payment.proto",
 }
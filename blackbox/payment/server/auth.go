@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"payment/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader はAPIキーを渡すHTTPヘッダ(gRPCのメタデータ)です
+const APIKeyHeader = "x-api-key"
+
+// 管理キーでのみ呼び出せるRPC
+var adminRPCs = map[string]bool{
+	"Initialize":      true,
+	"GetResult":       true,
+	"StreamResults":   true,
+	"SetFaultProfile": true,
+	"GetFaultProfile": true,
+}
+
+// 公開キー(publishable_key)で呼び出せるRPC
+var publishableRPCs = map[string]bool{
+	"RegistCard": true,
+}
+
+// 認証された呼び出し元
+type principal struct {
+	merchantID  string
+	admin       bool
+	publishable bool
+}
+
+type principalKey struct{}
+
+func principalFromContext(ctx context.Context) *principal {
+	p, _ := ctx.Value(principalKey{}).(*principal)
+	return p
+}
+
+//呼び出し元のマーチャントID(認証が無効な場合や管理キーの場合は空文字)
+func merchantFromContext(ctx context.Context) string {
+	if p := principalFromContext(ctx); p != nil {
+		return p.merchantID
+	}
+	return ""
+}
+
+//呼び出し元がownerのデータにアクセスできるか. ownerが空文字のデータ(管理キーで作成したもの)は共有される
+func canAccess(ctx context.Context, owner string) bool {
+	p := principalFromContext(ctx)
+	return p == nil || p.admin || owner == "" || p.merchantID == owner
+}
+
+// APIキーを検証する
+type authenticator struct {
+	adminKey string
+	keys     map[string]*principal
+}
+
+func newAuthenticator(adminKey string, merchants []config.Merchant) *authenticator {
+	a := &authenticator{adminKey: adminKey, keys: map[string]*principal{}}
+	for _, m := range merchants {
+		a.keys[m.APIKey] = &principal{merchantID: m.ID}
+		if m.PublishableKey != "" {
+			a.keys[m.PublishableKey] = &principal{merchantID: m.ID, publishable: true}
+		}
+	}
+	return a
+}
+
+func (a *authenticator) enabled() bool {
+	return a.adminKey != "" || len(a.keys) > 0
+}
+
+//メタデータからAPIキーを取り出す(x-api-key または Authorization: Bearer)
+func apiKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(APIKeyHeader); len(v) > 0 {
+		return v[0]
+	}
+	if v := md.Get("authorization"); len(v) > 0 && strings.HasPrefix(v[0], "Bearer ") {
+		return strings.TrimPrefix(v[0], "Bearer ")
+	}
+	return ""
+}
+
+//rpcの呼び出し元を認証し、呼び出し元を設定したコンテキストを返す
+func (a *authenticator) authenticate(ctx context.Context, rpc string) (context.Context, error) {
+	if !a.enabled() {
+		return ctx, nil
+	}
+
+	key := apiKeyFromContext(ctx)
+	if key == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "API Key Required")
+	}
+	if a.adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(a.adminKey)) == 1 {
+		return context.WithValue(ctx, principalKey{}, &principal{admin: true}), nil
+	}
+
+	p, ok := a.keys[key]
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid API Key")
+	}
+	if adminRPCs[rpc] || (p.publishable && !publishableRPCs[rpc]) {
+		return nil, status.Errorf(codes.PermissionDenied, "Permission Denied")
+	}
+	return context.WithValue(ctx, principalKey{}, p), nil
+}
+
+// WithAuth はマーチャントごとのAPIキーと管理キーによる認証を設定します. どちらも未設定の場合は認証しません
+func WithAuth(adminKey string, merchants []config.Merchant) ServerOption {
+	return func(s *Server) {
+		s.auth = newAuthenticator(adminKey, merchants)
+	}
+}
+
+// AuthInterceptor はAPIキーを検証するインターセプタです
+func (s *Server) AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isHealthCheck(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, err := s.auth.authenticate(ctx, rpcName(info.FullMethod))
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// 認証済みのコンテキストを返すServerStream
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamAuthInterceptor はストリーミングRPCのAPIキーを検証するインターセプタです
+func (s *Server) StreamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthCheck(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx, err := s.auth.authenticate(ss.Context(), rpcName(info.FullMethod))
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
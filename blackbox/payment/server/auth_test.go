@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	s, err := NewNetworkServer(WithAuth("admin", []config.Merchant{
+		{ID: "team1", APIKey: "sk_team1", PublishableKey: "pk_team1"},
+		{ID: "team2", APIKey: "sk_team2"},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor), grpc.StreamInterceptor(s.StreamAuthInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), APIKeyHeader, key)
+	}
+	card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+	expectCode := func(t *testing.T, err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Fatalf("Failed. Expected:%s but %s\n", code, status.Code(err))
+		}
+	}
+
+	t.Run("Authentication", func(t *testing.T) {
+		_, err := c.RegistCard(context.Background(), &pb.RegistCardRequest{CardInformation: card})
+		expectCode(t, err, codes.Unauthenticated)
+		_, err = c.RegistCard(withKey("unknown"), &pb.RegistCardRequest{CardInformation: card})
+		expectCode(t, err, codes.Unauthenticated)
+		_, err = c.Initialize(withKey("sk_team1"), &pb.InitializeRequest{})
+		expectCode(t, err, codes.PermissionDenied)
+		_, err = c.GetResult(withKey("sk_team1"), &pb.GetResultRequest{})
+		expectCode(t, err, codes.PermissionDenied)
+		stream, err := c.StreamResults(withKey("sk_team1"), &pb.GetResultRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		expectCode(t, err, codes.PermissionDenied)
+		_, err = c.Initialize(withKey("admin"), &pb.InitializeRequest{})
+		expectCode(t, err, codes.OK)
+
+		bearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer admin")
+		_, err = c.GetResult(bearer, &pb.GetResultRequest{})
+		expectCode(t, err, codes.OK)
+	})
+
+	var team1Payment string
+	t.Run("Isolation", func(t *testing.T) {
+		// 公開キーはカード登録にのみ使える
+		r, err := c.RegistCard(withKey("pk_team1"), &pb.RegistCardRequest{CardInformation: card})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := r.CardToken
+		_, err = c.ExecutePayment(withKey("pk_team1"), &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 100}})
+		expectCode(t, err, codes.PermissionDenied)
+
+		// 他のマーチャントのカードトークンは使えない
+		_, err = c.ExecutePayment(withKey("sk_team2"), &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 100}})
+		expectCode(t, err, codes.NotFound)
+
+		pr, err := c.ExecutePayment(withKey("sk_team1"), &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 100}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		team1Payment = pr.PaymentId
+
+		// 他のマーチャントの決済は参照もキャンセルもできない
+		_, err = c.GetPaymentInformation(withKey("sk_team2"), &pb.GetPaymentInformationRequest{PaymentId: team1Payment})
+		expectCode(t, err, codes.NotFound)
+		_, err = c.CancelPayment(withKey("sk_team2"), &pb.CancelPaymentRequest{PaymentId: team1Payment})
+		expectCode(t, err, codes.NotFound)
+		br, err := c.BulkCancelPayment(withKey("sk_team2"), &pb.BulkCancelPaymentRequest{PaymentId: []string{team1Payment}})
+		if err != nil || br.Deleted != 0 {
+			t.Fatalf("unexpected bulk cancel: %+v %v", br, err)
+		}
+		gr, err := c.GetPaymentInformation(withKey("sk_team1"), &pb.GetPaymentInformationRequest{PaymentId: team1Payment})
+		if err != nil || gr.PaymentInformation.IsCanceled {
+			t.Fatalf("unexpected payment: %+v %v", gr, err)
+		}
+
+		// 予約IDが同じでもマーチャントが異なれば多重課金ではない
+		r2, err := c.RegistCard(withKey("sk_team2"), &pb.RegistCardRequest{CardInformation: card})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.ExecutePayment(withKey("sk_team2"), &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: r2.CardToken, ReservationId: 1, Amount: 100}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := c.GetResult(withKey("admin"), &pb.GetResultRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.RawData) != 2 || len(res.Duplicates) != 0 {
+			t.Fatalf("unexpected result: %d payments, %d duplicates", len(res.RawData), len(res.Duplicates))
+		}
+		res, err = c.GetResult(withKey("admin"), &pb.GetResultRequest{MerchantId: "team1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.RawData) != 1 || res.RawData[0].PaymentId != team1Payment || res.RawData[0].MerchantId != "team1" {
+			t.Fatalf("unexpected result: %+v", res.RawData)
+		}
+
+		// 精算レポートは自身のマーチャントの分のみ集計される
+		sr, err := c.GetSettlementReport(withKey("sk_team1"), &pb.SettlementReportRequest{MerchantId: "team2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sr.Rows) != 1 || sr.Rows[0].MerchantId != "team1" || sr.Total.CaptureAmount != 100 {
+			t.Fatalf("unexpected settlement report: %+v", sr)
+		}
+		sr, err = c.GetSettlementReport(withKey("admin"), &pb.SettlementReportRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sr.Rows) != 2 || sr.Total.CaptureAmount != 200 {
+			t.Fatalf("unexpected settlement report: %+v", sr)
+		}
+	})
+
+	t.Run("InitializeMerchant", func(t *testing.T) {
+		_, err := c.Initialize(withKey("admin"), &pb.InitializeRequest{MerchantId: "team1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := c.GetResult(withKey("admin"), &pb.GetResultRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.RawData) != 1 || res.RawData[0].MerchantId != "team2" {
+			t.Fatalf("unexpected result: %+v", res.RawData)
+		}
+	})
+}
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	if key, ok := incomingHeaderMatcher("X-Api-Key"); !ok || key != APIKeyHeader {
+		t.Fatalf("Failed. Expected:%s but %s\n", APIKeyHeader, key)
+	}
+	if _, ok := incomingHeaderMatcher("X-Unknown"); ok {
+		t.Fatal("should not be forwarded")
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Brand はカードブランドです
+type Brand struct {
+	Name      string
+	Lengths   []int // カード番号の桁数
+	CvvLength int   // CVVの桁数
+
+	ranges [][2]string // IINの範囲(先頭の桁を両端を含めて比較する)
+}
+
+// Brands は対応しているカードブランドです
+var Brands = []*Brand{
+	{Name: "visa", Lengths: []int{13, 16, 19}, CvvLength: 3, ranges: [][2]string{{"4", "4"}}},
+	{Name: "mastercard", Lengths: []int{16}, CvvLength: 3, ranges: [][2]string{{"51", "55"}, {"2221", "2720"}}},
+	{Name: "amex", Lengths: []int{15}, CvvLength: 4, ranges: [][2]string{{"34", "34"}, {"37", "37"}}},
+	{Name: "jcb", Lengths: []int{16, 17, 18, 19}, CvvLength: 3, ranges: [][2]string{{"3528", "3589"}}},
+	{Name: "diners", Lengths: []int{14, 15, 16, 17, 18, 19}, CvvLength: 3, ranges: [][2]string{{"300", "305"}, {"36", "36"}, {"38", "39"}}},
+	{Name: "discover", Lengths: []int{16, 17, 18, 19}, CvvLength: 3, ranges: [][2]string{{"6011", "6011"}, {"644", "649"}, {"65", "65"}}},
+}
+
+// DetectBrand はカード番号の先頭の桁からブランドを判定します. 判定できない場合はnilを返します
+func DetectBrand(number string) *Brand {
+	for _, brand := range Brands {
+		for _, r := range brand.ranges {
+			if len(number) < len(r[0]) {
+				continue
+			}
+			prefix := number[:len(r[0])]
+			if r[0] <= prefix && prefix <= r[1] {
+				return brand
+			}
+		}
+	}
+	return nil
+}
+
+// 決済の拒否理由
+const (
+	DeclineInsufficientFunds = "insufficient_funds"
+	DeclineExpiredCard       = "expired_card"
+	DeclineStolenCard        = "stolen_card"
+)
+
+// 決済時に必ず拒否されるテスト用カード番号
+var declineCards = map[string]string{
+	"4000000000009995": DeclineInsufficientFunds,
+	"4000000000000069": DeclineExpiredCard,
+	"4000000000009979": DeclineStolenCard,
+}
+
+var declineMessages = map[string]string{
+	DeclineInsufficientFunds: "Insufficient funds",
+	DeclineExpiredCard:       "Card has expired",
+	DeclineStolenCard:        "Card reported stolen",
+}
+
+//テスト用カード番号であれば拒否理由を返す
+func declineReason(number string) (string, bool) {
+	reason, ok := declineCards[number]
+	return reason, ok
+}
+
+// CardDeclined はカードが拒否された場合のPreconditionFailureのTypeです. Subjectに拒否理由が入ります
+const CardDeclined = "CARD_DECLINED"
+
+//拒否理由をPreconditionFailureの詳細付きのエラーにする
+func declineError(reason string) error {
+	st := status.New(codes.FailedPrecondition, "Card Declined: "+reason)
+	detailed, err := st.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: CardDeclined, Subject: reason, Description: declineMessages[reason]},
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	pb "payment/pb"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// カードトークンを決済に使えない理由(PreconditionFailureのType)
+const (
+	CardTokenExpired = "CARD_TOKEN_EXPIRED"
+	CardTokenRevoked = "CARD_TOKEN_REVOKED"
+	CardTokenUsed    = "CARD_TOKEN_USED"
+)
+
+var cardTokenErrors = map[pb.CardTokenStatus]string{
+	pb.CardTokenStatus_EXPIRED: CardTokenExpired,
+	pb.CardTokenStatus_REVOKED: CardTokenRevoked,
+	pb.CardTokenStatus_USED:    CardTokenUsed,
+}
+
+var cardTokenMessages = map[pb.CardTokenStatus]string{
+	pb.CardTokenStatus_EXPIRED: "Card_Token Expired",
+	pb.CardTokenStatus_REVOKED: "Card_Token Revoked",
+	pb.CardTokenStatus_USED:    "Card_Token Already Used",
+}
+
+// カードトークンの状態
+type cardToken struct {
+	createdAt time.Time
+	expiresAt time.Time // ゼロ値の場合は期限なし
+	usage     pb.CardTokenUsage
+	revoked   bool
+	used      bool
+}
+
+func (t *cardToken) status(now time.Time) pb.CardTokenStatus {
+	switch {
+	case t.revoked:
+		return pb.CardTokenStatus_REVOKED
+	case t.used:
+		return pb.CardTokenStatus_USED
+	case !t.expiresAt.IsZero() && !now.Before(t.expiresAt):
+		return pb.CardTokenStatus_EXPIRED
+	}
+	return pb.CardTokenStatus_ACTIVE
+}
+
+// WithCardTokenTTL はRegistCardでttl_secondsが指定されなかった場合のカードトークンの有効期間を設定します(0の場合は期限なし)
+func WithCardTokenTTL(ttl time.Duration) ServerOption {
+	return func(s *Server) {
+		s.cardTokenTTL = ttl
+	}
+}
+
+//カードトークンを作る(s.muを取得して呼び出すこと)
+func (s *Server) newCardToken(req *pb.RegistCardRequest, now time.Time) *cardToken {
+	t := &cardToken{createdAt: now, usage: req.Usage}
+	ttl := s.cardTokenTTL
+	if req.TtlSeconds > 0 {
+		ttl = time.Duration(req.TtlSeconds) * time.Second
+	}
+	if ttl > 0 {
+		t.expiresAt = now.Add(ttl)
+	}
+	return t
+}
+
+func cardTokenError(token string, st pb.CardTokenStatus) error {
+	s := status.New(codes.FailedPrecondition, cardTokenMessages[st])
+	detailed, err := s.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: cardTokenErrors[st], Subject: token, Description: cardTokenMessages[st]},
+		},
+	})
+	if err != nil {
+		return s.Err()
+	}
+	return detailed.Err()
+}
+
+//カードトークンが決済に使えるか確認する. SINGLE_USEのトークンは使用済みにする(s.muを取得して呼び出すこと)
+func (s *Server) useCardToken(token string, now time.Time) error {
+	t, ok := s.cardTokens[token]
+	if !ok {
+		return nil
+	}
+	if st := t.status(now); st != pb.CardTokenStatus_ACTIVE {
+		return cardTokenError(token, st)
+	}
+	if t.usage == pb.CardTokenUsage_SINGLE_USE {
+		t.used = true
+	}
+	return nil
+}
+
+//カードトークンを失効させる
+func (s *Server) RevokeCardToken(ctx context.Context, req *pb.RevokeCardTokenRequest) (*pb.RevokeCardTokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.cardTokens[req.CardToken]
+	if !ok || !canAccess(ctx, s.CardMerchantMap[req.CardToken]) {
+		log.Println("Card_Token Not Found")
+		return &pb.RevokeCardTokenResponse{IsOk: false}, status.Errorf(codes.NotFound, "Card_Token Not Found")
+	}
+	t.revoked = true
+	return &pb.RevokeCardTokenResponse{IsOk: true}, nil
+}
+
+//カードトークンの一覧を作成日時の順に取得する
+func (s *Server) ListCardTokens(ctx context.Context, req *pb.ListCardTokensRequest) (*pb.ListCardTokensResponse, error) {
+	merchantID := req.MerchantId
+	if p := principalFromContext(ctx); p != nil && !p.admin {
+		merchantID = p.merchantID
+	}
+	now := time.Now()
+
+	s.mu.RLock()
+	type entry struct {
+		createdAt time.Time
+		token     *pb.CardToken
+	}
+	entries := []entry{}
+	for token, t := range s.cardTokens {
+		owner := s.CardMerchantMap[token]
+		if merchantID != "" && owner != merchantID {
+			continue
+		}
+		st := t.status(now)
+		if st != pb.CardTokenStatus_ACTIVE && !req.IncludeInactive {
+			continue
+		}
+		card := s.CardInfoMap[token]
+		ct := &pb.CardToken{CardToken: token, MerchantId: owner, Usage: t.usage, Status: st}
+		if b := DetectBrand(card.CardNumber); b != nil {
+			ct.Brand = b.Name
+		}
+		if len(card.CardNumber) >= 4 {
+			ct.Last4 = card.CardNumber[len(card.CardNumber)-4:]
+		}
+		ct.CreatedAt, _ = ptypes.TimestampProto(t.createdAt)
+		if !t.expiresAt.IsZero() {
+			ct.ExpiresAt, _ = ptypes.TimestampProto(t.expiresAt)
+		}
+		entries = append(entries, entry{createdAt: t.createdAt, token: ct})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].createdAt.Equal(entries[j].createdAt) {
+			return entries[i].createdAt.Before(entries[j].createdAt)
+		}
+		return entries[i].token.CardToken < entries[j].token.CardToken
+	})
+	tokens := make([]*pb.CardToken, 0, len(entries))
+	for _, e := range entries {
+		tokens = append(tokens, e.token)
+	}
+	return &pb.ListCardTokensResponse{CardTokens: tokens, IsOk: true}, nil
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "payment/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//エラーのPreconditionFailureのTypeを取り出す
+func preconditionType(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if pf, ok := d.(*errdetails.PreconditionFailure); ok && len(pf.Violations) > 0 {
+			return pf.Violations[0].Type
+		}
+	}
+	return ""
+}
+
+func TestCardTokenLifecycle(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	// 登録時の有効期限の検証を省いて、期限切れのカードを登録できるようにする
+	s, err := NewNetworkServer(WithCardValidators(ValidateCardNumber), WithCardTokenTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+	ctx := context.Background()
+
+	card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+	regist := func(t *testing.T, req *pb.RegistCardRequest) *pb.RegistCardResponse {
+		t.Helper()
+		r, err := c.RegistCard(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	pay := func(token string, reservationID int32) error {
+		_, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{
+			CardToken:     token,
+			ReservationId: reservationID,
+			Amount:        1000,
+		}})
+		return err
+	}
+	expectRejected := func(t *testing.T, err error, code codes.Code, typ string) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Fatalf("Failed. Expected:%s but %s\n", code, status.Code(err))
+		}
+		if got := preconditionType(err); got != typ {
+			t.Fatalf("Failed. Expected:%s but %s\n", typ, got)
+		}
+	}
+
+	t.Run("TTL", func(t *testing.T) {
+		r := regist(t, &pb.RegistCardRequest{CardInformation: card})
+		if r.ExpiresAt == nil {
+			t.Fatal("expires_at is not set")
+		}
+		r = regist(t, &pb.RegistCardRequest{CardInformation: card, TtlSeconds: 60})
+		if r.ExpiresAt == nil {
+			t.Fatal("expires_at is not set")
+		}
+		if d := time.Until(time.Unix(r.ExpiresAt.Seconds, 0)); d <= 0 || time.Minute < d {
+			t.Fatalf("Failed. Expected expiry within a minute but %s\n", d)
+		}
+		if err := pay(r.CardToken, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		s.mu.Lock()
+		s.cardTokens[r.CardToken].expiresAt = time.Now().Add(-time.Second)
+		s.mu.Unlock()
+		expectRejected(t, pay(r.CardToken, 2), codes.FailedPrecondition, CardTokenExpired)
+		// 期限切れの前に成功した決済の再送は同じ結果を返す
+		if err := pay(r.CardToken, 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("SingleUse", func(t *testing.T) {
+		r := regist(t, &pb.RegistCardRequest{CardInformation: card, Usage: pb.CardTokenUsage_SINGLE_USE})
+		if r.Usage != pb.CardTokenUsage_SINGLE_USE {
+			t.Fatalf("Failed. Expected:%s but %s\n", pb.CardTokenUsage_SINGLE_USE, r.Usage)
+		}
+		if err := pay(r.CardToken, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := pay(r.CardToken, 10); err != nil {
+			t.Fatalf("replay of single use token: %s", err)
+		}
+		expectRejected(t, pay(r.CardToken, 11), codes.FailedPrecondition, CardTokenUsed)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		r := regist(t, &pb.RegistCardRequest{CardInformation: card})
+		if _, err := c.RevokeCardToken(ctx, &pb.RevokeCardTokenRequest{CardToken: r.CardToken}); err != nil {
+			t.Fatal(err)
+		}
+		expectRejected(t, pay(r.CardToken, 20), codes.FailedPrecondition, CardTokenRevoked)
+
+		_, err := c.RevokeCardToken(ctx, &pb.RevokeCardTokenRequest{CardToken: "unknown"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("ExpiredCard", func(t *testing.T) {
+		r := regist(t, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "01/19"}})
+		expectRejected(t, pay(r.CardToken, 30), codes.FailedPrecondition, CardDeclined)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		active, err := c.ListCardTokens(ctx, &pb.ListCardTokensRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		all, err := c.ListCardTokens(ctx, &pb.ListCardTokensRequest{IncludeInactive: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// TTLで1件期限切れ、SingleUseで1件使用済み、Revokeで1件失効
+		if len(all.CardTokens) != 5 || len(active.CardTokens) != 2 {
+			t.Fatalf("Failed. Expected 5 tokens (2 active) but %d (%d active)\n", len(all.CardTokens), len(active.CardTokens))
+		}
+		counts := map[pb.CardTokenStatus]int{}
+		for _, ct := range all.CardTokens {
+			counts[ct.Status]++
+			if ct.Brand != "visa" || ct.Last4 != "1111" {
+				t.Fatalf("Failed. Expected visa/1111 but %s/%s\n", ct.Brand, ct.Last4)
+			}
+		}
+		for st, want := range map[pb.CardTokenStatus]int{
+			pb.CardTokenStatus_ACTIVE:  2,
+			pb.CardTokenStatus_EXPIRED: 1,
+			pb.CardTokenStatus_USED:    1,
+			pb.CardTokenStatus_REVOKED: 1,
+		} {
+			if counts[st] != want {
+				t.Errorf("%s: Expected:%d but %d\n", st, want, counts[st])
+			}
+		}
+	})
+}
+
+func TestCardTokenErrorsOverREST(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	// 登録時の有効期限の検証を省いて、期限切れのカードを登録できるようにする
+	s, err := NewNetworkServer(WithCardValidators(ValidateCardNumber))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gw, err := newGateway(ctx, lis.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(gw)
+	defer ts.Close()
+
+	do := func(t *testing.T, method, path, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		res := map[string]interface{}{}
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, res
+	}
+	regist := func(t *testing.T, expiryDate string) string {
+		t.Helper()
+		code, res := do(t, "POST", "/card", fmt.Sprintf(`{"card_information": {"card_number": "4111111111111111", "cvv": "123", "expiry_date": %q}}`, expiryDate))
+		if code != http.StatusOK {
+			t.Fatalf("Failed. Expected:%d but %d %v\n", http.StatusOK, code, res)
+		}
+		return res["card_token"].(string)
+	}
+	pay := func(t *testing.T, token string, reservationID int) map[string]interface{} {
+		t.Helper()
+		code, res := do(t, "POST", "/payment", fmt.Sprintf(`{"payment_information": {"card_token": %q, "reservation_id": %d, "amount": 1000}}`, token, reservationID))
+		if code != http.StatusBadRequest {
+			t.Fatalf("Failed. Expected:%d but %d %v\n", http.StatusBadRequest, code, res)
+		}
+		return res
+	}
+
+	expiredToken := regist(t, "11/50")
+	s.mu.Lock()
+	s.cardTokens[expiredToken].expiresAt = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+
+	revokedToken := regist(t, "11/50")
+	if code, res := do(t, "DELETE", "/card/"+revokedToken, ""); code != http.StatusOK {
+		t.Fatalf("Failed. Expected:%d but %d %v\n", http.StatusOK, code, res)
+	}
+
+	expiredCard := regist(t, "01/19")
+
+	// どれもHTTPステータスは400だが、reasonで区別できる
+	tests := []struct {
+		name        string
+		token       string
+		reason      string
+		declineCode string
+	}{
+		{"ExpiredToken", expiredToken, CardTokenExpired, ""},
+		{"RevokedToken", revokedToken, CardTokenRevoked, ""},
+		{"ExpiredCard", expiredCard, CardDeclined, DeclineExpiredCard},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := pay(t, tt.token, i+1)
+			if res["reason"] != tt.reason {
+				t.Fatalf("Failed. Expected:%s but %v\n", tt.reason, res["reason"])
+			}
+			if declineCode, _ := res["decline_code"].(string); declineCode != tt.declineCode {
+				t.Fatalf("Failed. Expected:%q but %q\n", tt.declineCode, declineCode)
+			}
+			if _, ok := res["details"]; !ok {
+				t.Fatal("details should be kept")
+			}
+		})
+	}
+
+	// 理由のないエラーにはreasonを付けない
+	code, res := do(t, "POST", "/payment", `{"payment_information": {"card_token": "unknown", "reservation_id": 100, "amount": 1000}}`)
+	if code != http.StatusNotFound {
+		t.Fatalf("Failed. Expected:%d but %d\n", http.StatusNotFound, code)
+	}
+	if _, ok := res["reason"]; ok {
+		t.Fatalf("unexpected reason: %v", res)
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 有効なプロファイルに従ってRPCに障害を注入する
+type faultInjector struct {
+	mu       sync.RWMutex
+	profiles map[string]config.FaultProfile
+	active   string
+
+	rndMu sync.Mutex
+	rnd   *rand.Rand
+}
+
+func newFaultInjector() *faultInjector {
+	return &faultInjector{
+		profiles: map[string]config.FaultProfile{},
+		rnd:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// 障害を注入しないRPC(障害注入の管理API)
+var faultExemptRPCs = map[string]bool{
+	"SetFaultProfile": true,
+	"GetFaultProfile": true,
+}
+
+// 有効なプロファイルからrpcの設定を取り出す
+func (f *faultInjector) fault(rpc string) (config.Fault, bool) {
+	if faultExemptRPCs[rpc] {
+		return config.Fault{}, false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.active == "" {
+		return config.Fault{}, false
+	}
+	fault, ok := f.profiles[f.active][rpc]
+	return fault, ok
+}
+
+func (f *faultInjector) activate(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.profiles[name]; name != "" && !ok {
+		return status.Errorf(codes.NotFound, "Fault Profile Not Found: %s", name)
+	}
+	f.active = name
+	return nil
+}
+
+func (f *faultInjector) current() (string, []string) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.profiles))
+	for name := range f.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return f.active, names
+}
+
+// 確率rateでtrueを返す
+func (f *faultInjector) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.rndMu.Lock()
+	defer f.rndMu.Unlock()
+	return f.rnd.Float64() < rate
+}
+
+// パーセンタイルで指定された分布から遅延を1つ取り出す
+func (f *faultInjector) sample(p config.Percentiles) time.Duration {
+	if p.P99 <= 0 {
+		return 0
+	}
+	max := p.Max
+	if max == 0 {
+		max = p.P99
+	}
+
+	f.rndMu.Lock()
+	u := f.rnd.Float64()
+	f.rndMu.Unlock()
+
+	lerp := func(from, to time.Duration, lo, hi float64) time.Duration {
+		return from + time.Duration(float64(to-from)*(u-lo)/(hi-lo))
+	}
+	switch {
+	case u < 0.5:
+		return lerp(0, p.P50, 0, 0.5)
+	case u < 0.9:
+		return lerp(p.P50, p.P90, 0.5, 0.9)
+	case u < 0.99:
+		return lerp(p.P90, p.P99, 0.9, 0.99)
+	default:
+		return lerp(p.P99, max, 0.99, 1)
+	}
+}
+
+func (f *faultInjector) intercept(ctx context.Context, rpc string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	fault, ok := f.fault(rpc)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	if err := wait(ctx, f.sample(fault.Latency)); err != nil {
+		return nil, err
+	}
+	if f.hit(fault.ErrorRate) {
+		log.Printf("Fault injected: %s returns %s\n", rpc, fault.Code())
+		return nil, status.Errorf(fault.Code(), "Fault Injected")
+	}
+	if f.hit(fault.ServerErrorRate) {
+		code := codes.Internal
+		if f.hit(0.5) {
+			code = codes.Unavailable
+		}
+		log.Printf("Fault injected: %s returns %s\n", rpc, code)
+		return nil, status.Errorf(code, "Fault Injected")
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil || !f.hit(fault.TimeoutAfterCommitRate) {
+		return resp, err
+	}
+	// 処理は確定させたまま、クライアントにはタイムアウトしたように見せる
+	log.Printf("Fault injected: %s timed out after commit\n", rpc)
+	if err := wait(ctx, fault.Timeout); err != nil {
+		return nil, err
+	}
+	return nil, status.Errorf(codes.DeadlineExceeded, "Fault Injected")
+}
+
+// WithFaultProfiles は障害注入プロファイルを設定し、activeを有効にします(空文字なら無効)
+func WithFaultProfiles(profiles map[string]config.FaultProfile, active string) ServerOption {
+	return func(s *Server) {
+		s.fault.mu.Lock()
+		s.fault.profiles = make(map[string]config.FaultProfile, len(profiles))
+		for name, p := range profiles {
+			s.fault.profiles[name] = p
+		}
+		s.fault.mu.Unlock()
+		if err := s.fault.activate(active); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// FaultInterceptor は有効な障害注入プロファイルに従ってRPCに障害を注入するインターセプタです
+func (s *Server) FaultInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return s.fault.intercept(ctx, rpcName(info.FullMethod), req, handler)
+}
+
+//障害注入プロファイルを切り替える(空文字で無効化)
+func (s *Server) SetFaultProfile(ctx context.Context, req *pb.SetFaultProfileRequest) (*pb.FaultProfileResponse, error) {
+	if err := s.fault.activate(req.Name); err != nil {
+		log.Println(err.Error())
+		return &pb.FaultProfileResponse{IsOk: false}, err
+	}
+	log.Printf("Fault profile: %q\n", req.Name)
+	name, profiles := s.fault.current()
+	return &pb.FaultProfileResponse{Name: name, Profiles: profiles, IsOk: true}, nil
+}
+
+//障害注入プロファイルを取得する
+func (s *Server) GetFaultProfile(ctx context.Context, req *pb.GetFaultProfileRequest) (*pb.FaultProfileResponse, error) {
+	name, profiles := s.fault.current()
+	return &pb.FaultProfileResponse{Name: name, Profiles: profiles, IsOk: true}, nil
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFaultSample(t *testing.T) {
+	f := newFaultInjector()
+	p := config.Percentiles{P50: 10 * time.Millisecond, P90: 20 * time.Millisecond, P99: 30 * time.Millisecond, Max: 40 * time.Millisecond}
+	under50 := 0
+	for i := 0; i < 1000; i++ {
+		d := f.sample(p)
+		if d < 0 || 40*time.Millisecond < d {
+			t.Fatalf("Failed. Expected:0ms-40ms but %s\n", d)
+		}
+		if d <= 10*time.Millisecond {
+			under50++
+		}
+	}
+	if under50 < 400 || 600 < under50 {
+		t.Fatalf("Failed. Expected: about half of samples under p50 but %d/1000\n", under50)
+	}
+	if d := f.sample(config.Percentiles{}); d != 0 {
+		t.Fatalf("Failed. Expected:0 but %s\n", d)
+	}
+}
+
+func TestFaultInterceptor(t *testing.T) {
+	s, err := NewNetworkServer(WithFaultProfiles(map[string]config.FaultProfile{
+		"down": {
+			"ExecutePayment": {ErrorRate: 1, ErrorCode: "Unavailable"},
+		},
+		"timeout": {
+			"ExecutePayment": {TimeoutAfterCommitRate: 1},
+		},
+	}, ""))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		ExpiryDate: "11/50",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/paymentpb.PaymentService/ExecutePayment"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.ExecutePayment(ctx, req.(*pb.ExecutePaymentRequest))
+	}
+	execute := func(reservationID int32) error {
+		req := &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: reservationID, Amount: 1000}}
+		_, err := s.UnaryInterceptor(ctx, req, info, handler)
+		return err
+	}
+	payments := func() int {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return len(s.PayInfoMap)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		if err := execute(1); err != nil {
+			t.Fatal(err)
+		}
+		if payments() != 1 {
+			t.Fatalf("Failed. Expected:1 but %d\n", payments())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		if _, err := s.SetFaultProfile(ctx, &pb.SetFaultProfileRequest{Name: "down"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := execute(2); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.Unavailable, status.Code(err))
+		}
+		if payments() != 1 {
+			t.Fatalf("payment should not be committed: %d\n", payments())
+		}
+	})
+
+	t.Run("TimeoutAfterCommit", func(t *testing.T) {
+		if _, err := s.SetFaultProfile(ctx, &pb.SetFaultProfileRequest{Name: "timeout"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := execute(3); status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.DeadlineExceeded, status.Code(err))
+		}
+		if payments() != 2 {
+			t.Fatalf("payment should be committed: %d\n", payments())
+		}
+	})
+
+	t.Run("SwitchProfile", func(t *testing.T) {
+		_, err := s.SetFaultProfile(ctx, &pb.SetFaultProfileRequest{Name: "missing"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.NotFound, status.Code(err))
+		}
+		r, err := s.GetFaultProfile(ctx, &pb.GetFaultProfileRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Name != "timeout" || len(r.Profiles) != 2 {
+			t.Fatalf("unexpected fault profile: %+v", r)
+		}
+
+		if _, err := s.SetFaultProfile(ctx, &pb.SetFaultProfileRequest{Name: ""}); err != nil {
+			t.Fatal(err)
+		}
+		if err := execute(4); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
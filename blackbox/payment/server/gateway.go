@@ -2,23 +2,32 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	_ "net/http/pprof"
+	"strings"
 
-	"payment/config"
 	pb "payment/pb"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
-func newGateway(c config.Config, ctx context.Context, opts ...runtime.ServeMuxOption) (http.Handler, error) {
+//grpcAddrのgRPCサーバへ中継するREST APIと/healthz、/metrics(metricsがnilでなければ)、/debug/pprof/のハンドラ. ctxがキャンセルされると接続を閉じる
+func newGateway(ctx context.Context, grpcAddr string, metrics http.Handler, opts ...runtime.ServeMuxOption) (http.Handler, error) {
 	opts = []runtime.ServeMuxOption{
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		// google.api.HttpBodyを返すRPC(CSVの出力など)はボディをそのまま返す
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{OrigName: true, EmitDefaults: true},
+		}),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithProtoErrorHandler(httpError),
 	}
 	mux := runtime.NewServeMux(opts...)
 	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
-	conn, err := grpc.Dial(c.GrpcPort, dialOpts...)
+	conn, err := grpc.Dial(grpcAddr, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -27,18 +36,70 @@ func newGateway(c config.Config, ctx context.Context, opts ...runtime.ServeMuxOp
 		return nil, err
 	}
 
-	return mux, nil
+	root := http.NewServeMux()
+	root.Handle("/", mux)
+	root.Handle("/healthz", healthzHandler(conn))
+	if metrics != nil {
+		root.Handle("/metrics", metrics)
+	}
+	// net/http/pprofがhttp.DefaultServeMuxに登録したハンドラ
+	root.Handle("/debug/pprof/", http.DefaultServeMux)
+	return root, nil
 }
 
-func StartGRPCGateway(c config.Config, opts ...runtime.ServeMuxOption) error {
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+//X-Api-Keyヘッダをメタデータとしてそのまま渡す
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, APIKeyHeader) {
+		return APIKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
 
-	gw, err := newGateway(c, ctx, opts...)
-	if err != nil {
-		return err
+//エラーの理由. REST APIでは同じHTTPステータスになるエラーを、ボディのreasonとdecline_codeで区別できるようにする
+//PreconditionFailureのTypeをreasonに、カードが拒否された場合はその理由をdecline_codeにする
+func errorReason(err error) (reason, declineCode string) {
+	for _, d := range status.Convert(err).Details() {
+		pf, ok := d.(*errdetails.PreconditionFailure)
+		if !ok || len(pf.Violations) == 0 {
+			continue
+		}
+		v := pf.Violations[0]
+		if v.Type == CardDeclined {
+			return v.Type, v.Subject
+		}
+		return v.Type, ""
 	}
+	return "", ""
+}
+
+//REST APIのエラー. grpc-gatewayのデフォルトのボディにreasonとdecline_codeを加える
+func httpError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	reason, declineCode := errorReason(err)
+	if reason != "" {
+		marshaler = &reasonMarshaler{Marshaler: marshaler, reason: reason, declineCode: declineCode}
+	}
+	runtime.DefaultHTTPError(ctx, mux, marshaler, w, r, err)
+}
 
-	return http.ListenAndServe(c.HttpPort, gw)
+//エラーのボディにreasonとdecline_codeを加えるMarshaler
+type reasonMarshaler struct {
+	runtime.Marshaler
+	reason      string
+	declineCode string
+}
+
+func (m *reasonMarshaler) Marshal(v interface{}) ([]byte, error) {
+	b, err := m.Marshaler.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return b, nil
+	}
+	body["reason"], _ = json.Marshal(m.reason)
+	if m.declineCode != "" {
+		body["decline_code"], _ = json.Marshal(m.declineCode)
+	}
+	return json.Marshal(body)
 }
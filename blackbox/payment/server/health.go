@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ServiceName はヘルスチェックで使うサービス名です
+const ServiceName = "paymentpb.PaymentService"
+
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus(ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return h
+}
+
+// Health はgRPCのヘルスチェックプロトコル(grpc.health.v1.Health)のサーバを返します
+func (s *Server) Health() *health.Server {
+	return s.health
+}
+
+//gRPCのヘルスチェックの結果を返す/healthz
+func healthzHandler(conn *grpc.ClientConn) http.HandlerFunc {
+	client := healthpb.NewHealthClient(conn)
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: ServiceName})
+		if err != nil || res.Status != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("unavailable\n"))
+			return
+		}
+		w.Write([]byte("ok\n"))
+	}
+}
+
+//ヘルスチェックのRPCは認証しない
+func isHealthCheck(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/")
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+//インターセプタを先頭から順に適用する1つのインターセプタにまとめる
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, h)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+//インターセプタを先頭から順に適用する1つのインターセプタにまとめる
+func chainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, h)
+			}
+		}
+		return next(srv, ss)
+	}
+}
+
+// UnaryInterceptor はメトリクスの記録、認証、遅延の注入、障害の注入を順に行うインターセプタです
+func (s *Server) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return chainUnaryInterceptors(s.MetricsInterceptor, s.AuthInterceptor, s.LatencyInterceptor, s.FaultInterceptor)(ctx, req, info, handler)
+}
+
+// StreamInterceptor はストリーミングRPCのメトリクスの記録、認証を順に行うインターセプタです
+func (s *Server) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return chainStreamInterceptors(s.StreamMetricsInterceptor, s.StreamAuthInterceptor)(srv, ss, info, handler)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"payment/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCごとに設定された分布に従って遅延を注入する
+type latencyInjector struct {
+	mu        sync.RWMutex
+	latencies map[string]config.Latency
+
+	rndMu sync.Mutex
+	rnd   *rand.Rand
+}
+
+func newLatencyInjector(latencies map[string]config.Latency) *latencyInjector {
+	l := &latencyInjector{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	l.set(latencies)
+	return l
+}
+
+func (l *latencyInjector) set(latencies map[string]config.Latency) {
+	m := make(map[string]config.Latency, len(latencies))
+	for rpc, latency := range latencies {
+		m[rpc] = latency
+	}
+	l.mu.Lock()
+	l.latencies = m
+	l.mu.Unlock()
+}
+
+// rpcに注入する遅延を分布から1つ取り出す
+func (l *latencyInjector) sample(rpc string) time.Duration {
+	l.mu.RLock()
+	latency, ok := l.latencies[rpc]
+	l.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+
+	l.rndMu.Lock()
+	defer l.rndMu.Unlock()
+
+	var d time.Duration
+	switch latency.Distribution {
+	case config.DistributionConstant:
+		d = latency.Duration
+	case config.DistributionUniform:
+		d = latency.Min
+		if latency.Max > latency.Min {
+			d += time.Duration(l.rnd.Int63n(int64(latency.Max - latency.Min)))
+		}
+	case config.DistributionNormal:
+		d = latency.Duration + time.Duration(l.rnd.NormFloat64()*float64(latency.Stddev))
+	case config.DistributionExponential:
+		d = time.Duration(l.rnd.ExpFloat64() * float64(latency.Duration))
+	}
+
+	if d < latency.Min {
+		d = latency.Min
+	}
+	if latency.Max > 0 && d > latency.Max {
+		d = latency.Max
+	}
+	return d
+}
+
+// rpcの遅延分だけ待つ. 待っている間にctxが終了した場合はエラーを返す
+func (l *latencyInjector) inject(ctx context.Context, rpc string) error {
+	return wait(ctx, l.sample(rpc))
+}
+
+// dだけ待つ. 待っている間にctxが終了した場合はエラーを返す
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+		}
+		return status.Error(codes.Canceled, ctx.Err().Error())
+	}
+}
+
+// "/paymentpb.PaymentService/CancelPayment" からRPC名を取り出す
+func rpcName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// SetLatency はRPCごとに注入する遅延を差し替えます
+func (s *Server) SetLatency(latencies map[string]config.Latency) {
+	s.latency.set(latencies)
+}
+
+// LatencyInterceptor は設定された遅延をRPCの処理前に注入するインターセプタです
+func (s *Server) LatencyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.latency.inject(ctx, rpcName(info.FullMethod)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
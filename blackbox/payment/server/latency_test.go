@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLatencySample(t *testing.T) {
+	l := newLatencyInjector(map[string]config.Latency{
+		"Constant":    {Distribution: config.DistributionConstant, Duration: 100 * time.Millisecond},
+		"Uniform":     {Distribution: config.DistributionUniform, Min: 10 * time.Millisecond, Max: 20 * time.Millisecond},
+		"Normal":      {Distribution: config.DistributionNormal, Duration: 50 * time.Millisecond, Stddev: 100 * time.Millisecond, Max: 80 * time.Millisecond},
+		"Exponential": {Distribution: config.DistributionExponential, Duration: 10 * time.Millisecond, Min: 5 * time.Millisecond},
+	})
+
+	for i := 0; i < 100; i++ {
+		if d := l.sample("Constant"); d != 100*time.Millisecond {
+			t.Fatalf("Failed. Expected:100ms but %s\n", d)
+		}
+		if d := l.sample("Uniform"); d < 10*time.Millisecond || 20*time.Millisecond < d {
+			t.Fatalf("Failed. Expected:10ms-20ms but %s\n", d)
+		}
+		if d := l.sample("Normal"); d < 0 || 80*time.Millisecond < d {
+			t.Fatalf("Failed. Expected:0ms-80ms but %s\n", d)
+		}
+		if d := l.sample("Exponential"); d < 5*time.Millisecond {
+			t.Fatalf("Failed. Expected:>=5ms but %s\n", d)
+		}
+		if d := l.sample("NotConfigured"); d != 0 {
+			t.Fatalf("Failed. Expected:0 but %s\n", d)
+		}
+	}
+}
+
+func TestLatencyInterceptor(t *testing.T) {
+	s, err := NewNetworkServer(WithLatency(map[string]config.Latency{
+		"CancelPayment": {Distribution: config.DistributionConstant, Duration: 200 * time.Millisecond},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/paymentpb.PaymentService/CancelPayment"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	t.Run("Delay", func(t *testing.T) {
+		start := time.Now()
+		if _, err := s.LatencyInterceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Fatalf("Failed. Expected:>=200ms but %s\n", elapsed)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := s.LatencyInterceptor(ctx, nil, info, handler)
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.DeadlineExceeded, status.Code(err))
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pb "payment/pb"
+
+	"github.com/rs/xid"
+)
+
+// 台帳の勘定科目
+const (
+	AccountCardReceivable  = "card_receivable"  // カード会社から受け取る金額
+	AccountMerchantPayable = "merchant_payable" // マーチャントに支払う金額
+)
+
+// 仕訳の種類
+const (
+	TransactionCapture = "capture" // 決済
+	TransactionCancel  = "cancel"  // 決済と同じ日の取消
+	TransactionRefund  = "refund"  // 決済より後の日の返金
+)
+
+// 精算日を決めるタイムゾーン
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+type posting struct {
+	account string
+	amount  int64 // 借方を正、貸方を負とする
+}
+
+// 1件の取引の仕訳. postingsの合計は常に0になる
+type ledgerTransaction struct {
+	id            string
+	typ           string
+	paymentID     string
+	reservationID int32
+	merchantID    string
+	datetime      time.Time
+	postings      []posting
+}
+
+// 決済とキャンセルを複式簿記で記録する台帳
+type ledger struct {
+	mu           sync.RWMutex
+	transactions []*ledgerTransaction
+}
+
+func newLedger() *ledger {
+	return &ledger{}
+}
+
+//借方debitと貸方creditにamountを記帳する
+func (l *ledger) post(typ, debit, credit string, payment pb.PaymentInformation, paymentID, merchantID string, at time.Time) {
+	amount := int64(payment.Amount)
+	tx := &ledgerTransaction{
+		id:            "txn_" + xid.New().String(),
+		typ:           typ,
+		paymentID:     paymentID,
+		reservationID: payment.ReservationId,
+		merchantID:    merchantID,
+		datetime:      at,
+		postings: []posting{
+			{account: debit, amount: amount},
+			{account: credit, amount: -amount},
+		},
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.transactions = append(l.transactions, tx)
+}
+
+//決済を記帳する
+func (l *ledger) capture(payment pb.PaymentInformation, paymentID, merchantID string, at time.Time) {
+	l.post(TransactionCapture, AccountCardReceivable, AccountMerchantPayable, payment, paymentID, merchantID, at)
+}
+
+//キャンセルを記帳する. 決済と同じ日であれば取消、後の日であれば返金になる
+func (l *ledger) cancel(payment pb.PaymentInformation, paymentID, merchantID string, capturedAt, at time.Time) {
+	typ := TransactionRefund
+	if settlementDate(capturedAt) == settlementDate(at) {
+		typ = TransactionCancel
+	}
+	l.post(typ, AccountMerchantPayable, AccountCardReceivable, payment, paymentID, merchantID, at)
+}
+
+//merchantIDの仕訳を削除する. merchantIDが空文字の場合はすべて削除する
+func (l *ledger) reset(merchantID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if merchantID == "" {
+		l.transactions = nil
+		return
+	}
+	transactions := l.transactions[:0]
+	for _, tx := range l.transactions {
+		if tx.merchantID != merchantID {
+			transactions = append(transactions, tx)
+		}
+	}
+	l.transactions = transactions
+}
+
+//条件に合う仕訳を記帳順に返す
+func (l *ledger) query(f ledgerFilter) []*ledgerTransaction {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	transactions := []*ledgerTransaction{}
+	for _, tx := range l.transactions {
+		if f.match(tx) {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions
+}
+
+// 仕訳の絞り込み条件. 空文字の項目は制限しない
+type ledgerFilter struct {
+	merchantID string
+	dateFrom   string
+	dateTo     string
+}
+
+func newLedgerFilter(merchantID, dateFrom, dateTo string) (ledgerFilter, error) {
+	for _, d := range []string{dateFrom, dateTo} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseInLocation("2006-01-02", d, jst); err != nil {
+			return ledgerFilter{}, fmt.Errorf("Invalid Date: %s", d)
+		}
+	}
+	return ledgerFilter{merchantID: merchantID, dateFrom: dateFrom, dateTo: dateTo}, nil
+}
+
+func (f ledgerFilter) match(tx *ledgerTransaction) bool {
+	if f.merchantID != "" && tx.merchantID != f.merchantID {
+		return false
+	}
+	date := settlementDate(tx.datetime)
+	if f.dateFrom != "" && date < f.dateFrom {
+		return false
+	}
+	if f.dateTo != "" && date > f.dateTo {
+		return false
+	}
+	return true
+}
+
+//精算日(YYYY-MM-DD, JST)
+func settlementDate(t time.Time) string {
+	return t.In(jst).Format("2006-01-02")
+}
+
+//仕訳から勘定ごとの残高を求める
+func balances(transactions []*ledgerTransaction) map[string]int64 {
+	b := map[string]int64{
+		AccountCardReceivable:  0,
+		AccountMerchantPayable: 0,
+	}
+	for _, tx := range transactions {
+		for _, p := range tx.postings {
+			b[p.account] += p.amount
+		}
+	}
+	return b
+}
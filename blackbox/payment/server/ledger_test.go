@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	pb "payment/pb"
+)
+
+func TestLedgerCancelOrRefund(t *testing.T) {
+	l := newLedger()
+	captured := time.Date(2019, 10, 25, 23, 0, 0, 0, jst)
+	payment := pb.PaymentInformation{ReservationId: 1, Amount: 1000}
+	l.capture(payment, "p1", "", captured)
+	l.cancel(payment, "p1", "", captured, captured.Add(30*time.Minute))
+	l.capture(payment, "p2", "", captured)
+	l.cancel(payment, "p2", "", captured, captured.Add(2*time.Hour))
+
+	transactions := l.query(ledgerFilter{})
+	types := []string{}
+	for _, tx := range transactions {
+		types = append(types, tx.typ)
+	}
+	if strings.Join(types, ",") != "capture,cancel,capture,refund" {
+		t.Fatalf("unexpected transactions: %v", types)
+	}
+	for account, balance := range balances(transactions) {
+		if balance != 0 {
+			t.Fatalf("%s is not balanced: %d", account, balance)
+		}
+	}
+
+	f, err := newLedgerFilter("", "2019-10-26", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(l.query(f)); n != 1 {
+		t.Fatalf("Failed. Expected:%d but %d\n", 1, n)
+	}
+	if _, err := newLedgerFilter("", "2019/10/26", ""); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestSettlementReport(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	card, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentIDs := []string{}
+	for i, amount := range []int32{1000, 2000, 3000} {
+		res, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: card.CardToken, ReservationId: int32(i + 1), Amount: amount}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paymentIDs = append(paymentIDs, res.PaymentId)
+	}
+	if _, err := s.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: paymentIDs[1]}); err != nil {
+		t.Fatal(err)
+	}
+	// 2回目のキャンセルは記帳されない
+	if _, err := s.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: paymentIDs[1]}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.GetSettlementReport(ctx, &pb.SettlementReportRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := report.Total
+	if total.CaptureCount != 3 || total.CaptureAmount != 6000 || total.CancelCount != 1 || total.CancelAmount != 2000 || total.NetAmount != 4000 {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+	if len(report.Rows) != 1 || report.Rows[0].Date != settlementDate(time.Now()) {
+		t.Fatalf("unexpected rows: %+v", report.Rows)
+	}
+	for _, b := range report.Balances {
+		if b.Account == AccountMerchantPayable && -b.Balance != total.NetAmount {
+			t.Fatalf("ledger is inconsistent with report: %d %d", b.Balance, total.NetAmount)
+		}
+	}
+
+	report, err = s.GetSettlementReport(ctx, &pb.SettlementReportRequest{GroupBy: pb.SettlementReportRequest_RESERVATION})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Rows) != 3 || report.Rows[1].ReservationId != 2 || report.Rows[1].NetAmount != 0 {
+		t.Fatalf("unexpected rows: %+v", report.Rows)
+	}
+
+	body, err := s.ExportSettlementReport(ctx, &pb.SettlementReportRequest{GroupBy: pb.SettlementReportRequest_RESERVATION, Format: pb.ExportFormat_CSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(body.Data))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || records[4][0] != "total" || records[4][9] != "4000" {
+		t.Fatalf("unexpected csv: %v", records)
+	}
+
+	body, err = s.ExportLedger(ctx, &pb.ExportLedgerRequest{Format: pb.ExportFormat_JSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ledger struct {
+		Entries []struct {
+			Account string `json:"account"`
+			Amount  string `json:"amount"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(body.Data, &ledger); err != nil {
+		t.Fatal(err)
+	}
+	if body.ContentType != "application/json" || len(ledger.Entries) != 8 {
+		t.Fatalf("unexpected ledger: %s %+v", body.ContentType, ledger)
+	}
+
+	if _, err := s.Initialize(ctx, &pb.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = s.GetSettlementReport(ctx, &pb.SettlementReportRequest{})
+	if report.Total.CaptureCount != 0 || len(report.Rows) != 0 {
+		t.Fatalf("ledger is not cleared: %+v", report)
+	}
+}
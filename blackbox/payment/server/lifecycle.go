@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// 終了時に処理中のリクエストを待つ時間のデフォルト
+const defaultShutdownTimeout = 10 * time.Second
+
+// ShutdownHook は終了時、処理中のリクエストがすべて終わった後に呼ばれる関数です
+type ShutdownHook func(ctx context.Context) error
+
+// WithShutdownHook は終了時に呼ぶ関数を追加します
+func WithShutdownHook(hook ShutdownHook) ServerOption {
+	return func(s *Server) {
+		s.shutdownHooks = append(s.shutdownHooks, hook)
+	}
+}
+
+// WithSnapshotFile は終了時に決済とカードの情報をpathに書き出します
+func WithSnapshotFile(path string) ServerOption {
+	return func(s *Server) {
+		if path == "" {
+			return
+		}
+		s.shutdownHooks = append(s.shutdownHooks, func(ctx context.Context) error {
+			return s.flushSnapshot(path)
+		})
+	}
+}
+
+//REST APIからgRPCに接続するアドレス. 0.0.0.0や::で待ち受けている場合はループバックアドレスに接続する
+func gatewayDialAddr(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return addr.String()
+	}
+	port := strconv.Itoa(tcpAddr.Port)
+	if tcpAddr.IP.To4() == nil {
+		return net.JoinHostPort("::1", port)
+	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// Serve はcの設定でgRPCとREST APIを提供します. ctxがキャンセルされると新しい接続の受け付けをやめ、
+// 処理中のリクエストを待ってからShutdownを呼んで終了します
+func Serve(ctx context.Context, c config.Config, s *Server) error {
+	g := grpc.NewServer(
+		grpc.UnaryInterceptor(s.UnaryInterceptor),
+		grpc.StreamInterceptor(s.StreamInterceptor),
+	)
+	pb.RegisterPaymentServiceServer(g, s)
+	healthpb.RegisterHealthServer(g, s.Health())
+
+	httpLis, err := net.Listen("tcp", c.HttpPort)
+	if err != nil {
+		return err
+	}
+	var (
+		grpcLis net.Listener
+		m       cmux.CMux
+	)
+	if c.SinglePort {
+		// HTTP/2でcontent-typeがapplication/grpcの接続をgRPCに、それ以外をREST APIに振り分ける
+		m = cmux.New(httpLis)
+		grpcLis = m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+		httpLis = m.Match(cmux.Any())
+	} else {
+		grpcLis, err = net.Listen("tcp", c.GrpcPort)
+		if err != nil {
+			httpLis.Close()
+			return err
+		}
+	}
+
+	gwCtx, gwCancel := context.WithCancel(context.Background())
+	defer gwCancel()
+	gw, err := newGateway(gwCtx, gatewayDialAddr(grpcLis.Addr()), s.MetricsHandler())
+	if err != nil {
+		httpLis.Close()
+		grpcLis.Close()
+		return err
+	}
+	hs := &http.Server{Handler: gw}
+
+	errc := make(chan error, 3)
+	go func() {
+		errc <- g.Serve(grpcLis)
+	}()
+	go func() {
+		if err := hs.Serve(httpLis); err != http.ErrServerClosed {
+			errc <- err
+		}
+	}()
+	if m != nil {
+		go func() {
+			errc <- m.Serve()
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err = <-errc:
+		log.Printf("server stopped: %s\n", err)
+	}
+
+	timeout := c.ShutdownTimeout
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Println("Shutting down...")
+	// ヘルスチェックを先に失敗させて、新しいリクエストが来ないようにする
+	s.health.Shutdown()
+	// REST APIはgRPCを呼び出すので先に止める
+	if err := hs.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shutdown HTTP server: %s\n", err)
+	}
+	gwCancel()
+	stopped := make(chan struct{})
+	go func() {
+		g.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Println("Timed out waiting for RPCs to finish")
+		g.Stop()
+	}
+
+	if herr := s.Shutdown(shutdownCtx); herr != nil && err == nil {
+		err = herr
+	}
+	return err
+}
+
+// Shutdown はヘルスチェックをNOT_SERVINGにして、WithShutdownHookなどで登録された関数を順に呼びます
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+	var err error
+	for _, hook := range s.shutdownHooks {
+		if herr := hook(ctx); herr != nil {
+			log.Printf("shutdown hook failed: %s\n", herr)
+			if err == nil {
+				err = herr
+			}
+		}
+	}
+	return err
+}
+
+// 終了時に書き出す決済とカードの情報
+type snapshot struct {
+	CreatedAt        time.Time                        `json:"created_at"`
+	Payments         map[string]pb.PaymentInformation `json:"payments"`
+	Cards            map[string]pb.CardInformation    `json:"cards"`
+	PaymentMerchants map[string]string                `json:"payment_merchants,omitempty"`
+	CardMerchants    map[string]string                `json:"card_merchants,omitempty"`
+}
+
+// WriteSnapshot は決済とカードの情報をJSONでwに書き出します
+func (s *Server) WriteSnapshot(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return json.NewEncoder(w).Encode(&snapshot{
+		CreatedAt:        time.Now(),
+		Payments:         s.PayInfoMap,
+		Cards:            s.CardInfoMap,
+		PaymentMerchants: s.PaymentMerchantMap,
+		CardMerchants:    s.CardMerchantMap,
+	})
+}
+
+//書き出し途中のファイルが残らないように、一時ファイルに書いてからpathに置き換える
+func (s *Server) flushSnapshot(path string) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.WriteSnapshot(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	log.Printf("Snapshot written: %s\n", path)
+	return nil
+}
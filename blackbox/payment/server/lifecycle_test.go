@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+)
+
+//空いているポートを探す
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func TestServeSinglePort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "payment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	snapshotFile := filepath.Join(dir, "snapshot.json")
+	c := config.Config{HttpPort: freeAddr(t), SinglePort: true, ShutdownTimeout: 5 * time.Second}
+	hooked := false
+	s, err := NewNetworkServer(
+		WithSnapshotFile(snapshotFile),
+		WithShutdownHook(func(ctx context.Context) error {
+			hooked = true
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, c, s)
+	}()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get("http://" + c.HttpPort + "/healthz")
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Failed. Expected:%d but %d\n", http.StatusOK, resp.StatusCode)
+	}
+
+	// 同じポートでgRPCも受け付ける
+	conn, err := grpc.Dial(c.HttpPort, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+	r, err := pb.NewPaymentServiceClient(conn).RegistCard(context.Background(), &pb.RegistCardRequest{CardInformation: card})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+	if !hooked {
+		t.Fatal("shutdown hook was not called")
+	}
+
+	f, err := os.Open(snapshotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var snap snapshot
+	if err := json.NewDecoder(f).Decode(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snap.Cards[r.CardToken]; !ok {
+		t.Fatalf("snapshot does not contain card token %s", r.CardToken)
+	}
+}
+
+func TestGatewayDialAddr(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		want string
+	}{
+		{&net.TCPAddr{IP: net.IPv4zero, Port: 5001}, "127.0.0.1:5001"},
+		{&net.TCPAddr{IP: net.IPv6unspecified, Port: 5001}, "[::1]:5001"},
+		{&net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 5001}, "192.0.2.1:5001"},
+	}
+	for _, tt := range tests {
+		if got := gatewayDialAddr(tt.addr); got != tt.want {
+			t.Fatalf("Failed. Expected:%s but %s\n", tt.want, got)
+		}
+	}
+}
+
+func TestServeWildcardAddr(t *testing.T) {
+	//0.0.0.0で待ち受けても、REST APIからgRPCに接続できる
+	wildcard := func(addr string) string {
+		_, port, _ := net.SplitHostPort(addr)
+		return net.JoinHostPort("0.0.0.0", port)
+	}
+	c := config.Config{HttpPort: wildcard(freeAddr(t)), GrpcPort: wildcard(freeAddr(t)), ShutdownTimeout: 5 * time.Second}
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, c, s)
+	}()
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	_, port, _ := net.SplitHostPort(c.HttpPort)
+	url := "http://127.0.0.1:" + port + "/card"
+	body := `{"card_information": {"card_number": "4111111111111111", "cvv": "123", "expiry_date": "11/50"}}`
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Post(url, "application/json", strings.NewReader(body))
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("Failed. Expected:%d but %d %s\n", http.StatusOK, resp.StatusCode, b)
+	}
+	var r struct {
+		CardToken string `json:"card_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.CardToken == "" {
+		t.Fatal("card token is empty")
+	}
+}
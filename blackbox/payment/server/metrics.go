@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RPCごとのリクエスト数、レイテンシ、エラーコードを集計する
+type metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	inflight *prometheus.GaugeVec
+}
+
+func newMetrics(s *Server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "payment",
+			Name:      "grpc_requests_total",
+			Help:      "Number of RPCs handled, by method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "payment",
+			Name:      "grpc_request_duration_seconds",
+			Help:      "RPC latency, including injected latency and faults.",
+			Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method"}),
+		inflight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "payment",
+			Name:      "grpc_requests_in_flight",
+			Help:      "Number of RPCs currently being handled.",
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		m.inflight,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "payment",
+			Name:      "payments",
+			Help:      "Number of payments in memory.",
+		}, func() float64 {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return float64(len(s.PayInfoMap))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "payment",
+			Name:      "cards",
+			Help:      "Number of card tokens in memory.",
+		}, func() float64 {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return float64(len(s.CardInfoMap))
+		}),
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+//rpcの処理を計測する
+func (m *metrics) observe(rpc string, handle func() error) error {
+	m.inflight.WithLabelValues(rpc).Inc()
+	defer m.inflight.WithLabelValues(rpc).Dec()
+
+	start := time.Now()
+	err := handle()
+	m.latency.WithLabelValues(rpc).Observe(time.Since(start).Seconds())
+	m.requests.WithLabelValues(rpc, status.Code(err).String()).Inc()
+	return err
+}
+
+// MetricsInterceptor はRPCごとのリクエスト数、レイテンシ、エラーコードを記録するインターセプタです
+func (s *Server) MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := s.metrics.observe(rpcName(info.FullMethod), func() error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// StreamMetricsInterceptor はストリーミングRPCのリクエスト数、処理時間、エラーコードを記録するインターセプタです
+func (s *Server) StreamMetricsInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.metrics.observe(rpcName(info.FullMethod), func() error {
+		return handler(srv, ss)
+	})
+}
+
+// MetricsHandler はPrometheus形式でメトリクスを返すハンドラです
+func (s *Server) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsAndHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	s, err := NewNetworkServer(WithAuth("admin", []config.Merchant{
+		{ID: "team1", APIKey: "sk_team1"},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor), grpc.StreamInterceptor(s.StreamInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	healthpb.RegisterHealthServer(g, s.Health())
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+
+	t.Run("Health", func(t *testing.T) {
+		// APIキーなしでも確認できる
+		r, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: ServiceName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("Failed. Expected:SERVING but %s\n", r.Status)
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+		_, err := c.RegistCard(context.Background(), &pb.RegistCardRequest{CardInformation: card})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.Unauthenticated, status.Code(err))
+		}
+		ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyHeader, "sk_team1")
+		if _, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card}); err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		s.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		b, err := ioutil.ReadAll(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := string(b)
+		for _, want := range []string{
+			`payment_grpc_requests_total{code="OK",method="RegistCard"} 1`,
+			`payment_grpc_requests_total{code="Unauthenticated",method="RegistCard"} 1`,
+			`payment_grpc_request_duration_seconds_count{method="RegistCard"} 2`,
+			`payment_cards 1`,
+			`payment_payments 0`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("metrics does not contain %q", want)
+			}
+		}
+	})
+}
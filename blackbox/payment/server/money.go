@@ -0,0 +1,117 @@
+package server
+
+import (
+	"math"
+	"strings"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CurrencyJPY は決済金額(amount)と精算に使う通貨です
+const CurrencyJPY = "JPY"
+
+// WithCurrencies は円以外で決済を受け付ける通貨と換算規則を設定します
+func WithCurrencies(currencies map[string]config.Currency) ServerOption {
+	return func(s *Server) {
+		s.currencies = make(map[string]config.Currency, len(currencies))
+		for code, c := range currencies {
+			s.currencies[strings.ToUpper(code)] = c
+		}
+	}
+}
+
+//通貨の換算規則. JPYの場合はfalseを返す
+func (s *Server) currency(code string) (config.Currency, bool, error) {
+	if code == CurrencyJPY {
+		return config.Currency{}, false, nil
+	}
+	c, ok := s.currencies[code]
+	if !ok {
+		return c, false, status.Errorf(codes.InvalidArgument, "Unsupported Currency: %s", code)
+	}
+	return c, true, nil
+}
+
+//端数を丸める. roundは0.5を0から遠い方へ丸める(webappと同じ)
+func roundAmount(v float64, rounding string) int64 {
+	switch rounding {
+	case config.RoundingFloor:
+		return int64(math.Floor(v))
+	case config.RoundingCeil:
+		return int64(math.Ceil(v))
+	}
+	return int64(math.Round(v))
+}
+
+//円の金額を通貨の最小単位に換算する. webappが運賃を換算するのと同じ式と端数処理を使う
+func fromJPY(jpy int64, c config.Currency) int64 {
+	return roundAmount(float64(jpy)/c.JPYPerUnit*math.Pow10(c.Exponent), c.Rounding)
+}
+
+//通貨の最小単位での金額を円に換算する(端数は四捨五入). 円の金額が指定されなかった場合にのみ使う
+func toJPY(amount int64, c config.Currency) int64 {
+	return int64(math.Round(float64(amount) * c.JPYPerUnit / math.Pow10(c.Exponent)))
+}
+
+//priceとtaxを検証し、記録する決済情報を返す
+//priceがJPY以外の場合、amount(円)が指定されていればそれを換算した金額がpriceと一致することを確かめる
+//amountが省略された場合は、priceを円に換算した金額をamountとする
+func (s *Server) normalizePrice(p *pb.PaymentInformation) (pb.PaymentInformation, error) {
+	pay := pb.PaymentInformation{
+		CardToken:     p.CardToken,
+		ReservationId: p.ReservationId,
+		Amount:        p.Amount,
+	}
+	if p.Price == nil {
+		if p.Tax != nil {
+			return pay, status.Errorf(codes.InvalidArgument, "Tax requires Price")
+		}
+		pay.Price = &pb.Money{Currency: CurrencyJPY, Amount: int64(p.Amount)}
+		return pay, nil
+	}
+
+	price := &pb.Money{Currency: strings.ToUpper(p.Price.Currency), Amount: p.Price.Amount}
+	if price.Currency == "" {
+		price.Currency = CurrencyJPY
+	}
+	if price.Amount < 0 {
+		return pay, status.Errorf(codes.InvalidArgument, "Invalid Price")
+	}
+	c, foreign, err := s.currency(price.Currency)
+	if err != nil {
+		return pay, err
+	}
+	switch {
+	case p.Amount == 0:
+		amount := price.Amount
+		if foreign {
+			amount = toJPY(price.Amount, c)
+		}
+		if amount > math.MaxInt32 {
+			return pay, status.Errorf(codes.InvalidArgument, "Invalid Price")
+		}
+		pay.Amount = int32(amount)
+	case !foreign && int64(p.Amount) != price.Amount, foreign && fromJPY(int64(p.Amount), c) != price.Amount:
+		return pay, status.Errorf(codes.InvalidArgument, "Amount and Price mismatch")
+	}
+	pay.Price = price
+
+	if p.Tax != nil {
+		tax := &pb.Money{Currency: strings.ToUpper(p.Tax.Currency), Amount: p.Tax.Amount}
+		if tax.Currency == "" {
+			tax.Currency = price.Currency
+		}
+		if tax.Currency != price.Currency {
+			return pay, status.Errorf(codes.InvalidArgument, "Tax and Price currency mismatch")
+		}
+		if tax.Amount < 0 || price.Amount < tax.Amount {
+			return pay, status.Errorf(codes.InvalidArgument, "Invalid Tax")
+		}
+		pay.Tax = tax
+	}
+	return pay, nil
+}
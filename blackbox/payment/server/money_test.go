@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExecutePaymentPrice(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	s, err := NewNetworkServer(WithCurrencies(map[string]config.Currency{"usd": {JPYPerUnit: 150, Exponent: 2}}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+	ctx := context.Background()
+
+	r, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range []struct {
+		name   string
+		amount int32
+		price  *pb.Money
+		tax    *pb.Money
+		code   codes.Code
+		want   int32
+		wantPr *pb.Money
+	}{
+		{name: "AmountOnly", amount: 1000, want: 1000, wantPr: &pb.Money{Currency: "JPY", Amount: 1000}},
+		{name: "JPY", amount: 1100, price: &pb.Money{Currency: "JPY", Amount: 1100}, tax: &pb.Money{Amount: 100}, want: 1100, wantPr: &pb.Money{Currency: "JPY", Amount: 1100}},
+		{name: "PriceOnly", price: &pb.Money{Amount: 2000}, want: 2000, wantPr: &pb.Money{Currency: "JPY", Amount: 2000}},
+		// 1234セント = 12.34ドル = 1851円
+		{name: "USD", price: &pb.Money{Currency: "usd", Amount: 1234}, tax: &pb.Money{Currency: "USD", Amount: 112}, want: 1851, wantPr: &pb.Money{Currency: "USD", Amount: 1234}},
+		// 円の運賃とそれを換算した価格を指定すると、円の運賃がそのまま記録される
+		{name: "USDWithAmount", amount: 1851, price: &pb.Money{Currency: "USD", Amount: 1234}, want: 1851, wantPr: &pb.Money{Currency: "USD", Amount: 1234}},
+		{name: "USDMismatch", amount: 1851, price: &pb.Money{Currency: "USD", Amount: 1235}, code: codes.InvalidArgument},
+		{name: "Mismatch", amount: 1000, price: &pb.Money{Currency: "JPY", Amount: 1100}, code: codes.InvalidArgument},
+		{name: "Unsupported", price: &pb.Money{Currency: "EUR", Amount: 100}, code: codes.InvalidArgument},
+		{name: "TaxTooLarge", price: &pb.Money{Amount: 100}, tax: &pb.Money{Amount: 101}, code: codes.InvalidArgument},
+		{name: "TaxCurrency", price: &pb.Money{Currency: "USD", Amount: 100}, tax: &pb.Money{Currency: "JPY", Amount: 10}, code: codes.InvalidArgument},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{
+				CardToken:     r.CardToken,
+				ReservationId: int32(i + 1),
+				Amount:        tc.amount,
+				Price:         tc.price,
+				Tax:           tc.tax,
+			}})
+			if status.Code(err) != tc.code {
+				t.Fatalf("Failed. Expected:%s but %s\n", tc.code, status.Code(err))
+			}
+			if err != nil {
+				return
+			}
+			pay, err := c.GetPaymentInformation(ctx, &pb.GetPaymentInformationRequest{PaymentId: res.PaymentId})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := pay.PaymentInformation
+			if p.Amount != tc.want {
+				t.Fatalf("Failed. Expected amount:%d but %d\n", tc.want, p.Amount)
+			}
+			if p.Price.GetCurrency() != tc.wantPr.Currency || p.Price.GetAmount() != tc.wantPr.Amount {
+				t.Fatalf("Failed. Expected price:%v but %v\n", tc.wantPr, p.Price)
+			}
+			if tc.tax != nil && p.Tax.GetAmount() != tc.tax.Amount {
+				t.Fatalf("Failed. Expected tax:%d but %d\n", tc.tax.Amount, p.Tax.GetAmount())
+			}
+		})
+	}
+}
+
+// webappの money_test.go の TestPricingMatchesPaymentService と同じ換算規則と運賃
+var webappFares = []struct {
+	rounding string
+	fare     int32 // 円の運賃
+	price    int64 // webappが換算した価格(セント)
+}{
+	{"", 2500, 1675},
+	{"", 1250, 838},
+	{"", 1001, 671},
+	{"", 12345, 8271},
+	{config.RoundingFloor, 1001, 670},
+	{config.RoundingCeil, 12345, 8272},
+}
+
+func TestNormalizePriceMatchesWebappFare(t *testing.T) {
+	for _, tc := range webappFares {
+		s, err := NewNetworkServer(WithCurrencies(map[string]config.Currency{"USD": {JPYPerUnit: 149.25, Exponent: 2, Rounding: tc.rounding}}))
+		if err != nil {
+			t.Fatalf("failed to create new server:%s", err)
+		}
+		price := &pb.Money{Currency: "USD", Amount: tc.price}
+		pay, err := s.normalizePrice(&pb.PaymentInformation{Amount: tc.fare, Price: price})
+		if err != nil {
+			t.Fatalf("%d yen (%s): %s", tc.fare, tc.rounding, err)
+		}
+		if pay.Amount != tc.fare {
+			t.Fatalf("Failed. Expected:%d but %d\n", tc.fare, pay.Amount)
+		}
+
+		// 換算の端数の扱いが違えば一致しない
+		price = &pb.Money{Currency: "USD", Amount: tc.price + 1}
+		if _, err := s.normalizePrice(&pb.PaymentInformation{Amount: tc.fare, Price: price}); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.InvalidArgument, status.Code(err))
+		}
+	}
+
+	// 円の運賃を省略すると価格から逆算するので、運賃と一致するとは限らない(838セント = 1250.7円)
+	s, err := NewNetworkServer(WithCurrencies(map[string]config.Currency{"USD": {JPYPerUnit: 149.25, Exponent: 2}}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	pay, err := s.normalizePrice(&pb.PaymentInformation{Price: &pb.Money{Currency: "USD", Amount: 838}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pay.Amount != 1251 {
+		t.Fatalf("Failed. Expected:1251 but %d\n", pay.Amount)
+	}
+}
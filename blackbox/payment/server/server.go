@@ -2,17 +2,24 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
 	"log"
 	_ "net/http/pprof"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"payment/config"
 	pb "payment/pb"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	uuid "github.com/nu7hatch/gouuid"
 	"github.com/rs/xid"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/status"
 )
 
@@ -25,21 +32,149 @@ var rawDataPool = sync.Pool{
 	},
 }
 
+//決済とキャンセルを直列化するロックの数
+const paymentLockStripes = 256
+
+// 冪等キーに紐づく決済の記録
+type idempotencyRecord struct {
+	PaymentID string
+	Amount    int32
+}
+
 type Server struct {
-	PayInfoMap  map[string]pb.PaymentInformation
-	CardInfoMap map[string]pb.CardInformation
-	mu          sync.RWMutex
-	cancelLock  sync.RWMutex
+	PayInfoMap     map[string]pb.PaymentInformation
+	CardInfoMap    map[string]pb.CardInformation
+	IdempotencyMap map[string]idempotencyRecord
+	// 決済ID、カードトークンを所有するマーチャントID(管理キーまたは認証なしで作成したものは含まない)
+	PaymentMerchantMap map[string]string
+	CardMerchantMap    map[string]string
+	// 決済IDの昇順の一覧. 結果をソートせずに決済IDの順で取り出すために使う
+	paymentIDs []string
+	// 予約ごとの有効な(キャンセルされていない)決済IDと、そのうち決済が2件以上ある予約
+	// 多重課金を全件を読まずに判定するために使う
+	activeCharges map[reservationKey][]string
+	multiCharged  map[reservationKey]struct{}
+	replayed      int32
+	cardTokens    map[string]*cardToken
+	cardTokenTTL  time.Duration
+	currencies    map[string]config.Currency
+	mu            sync.RWMutex
+
+	// 決済ID(決済では冪等キーまたはカードトークン)のハッシュで選ぶロック. 決済の数によらず一定のメモリで、異なる決済はほぼ並行して処理できる
+	paymentLocks [paymentLockStripes]sync.Mutex
+
+	latency    *latencyInjector
+	fault      *faultInjector
+	validators []CardValidator
+	auth       *authenticator
+	webhook    *webhookDispatcher
+	ledger     *ledger
+	metrics    *metrics
+	health     *health.Server
+
+	shutdownHooks []ShutdownHook
+}
+
+type ServerOption func(s *Server)
+
+// WithLatency はRPCごとに注入する遅延を設定します
+func WithLatency(latencies map[string]config.Latency) ServerOption {
+	return func(s *Server) {
+		s.latency.set(latencies)
+	}
+}
+
+// WithCardValidators はカード登録時の検証ステップを差し替えます
+func WithCardValidators(validators ...CardValidator) ServerOption {
+	return func(s *Server) {
+		s.validators = validators
+	}
 }
 
-func NewNetworkServer() (*Server, error) {
+func NewNetworkServer(opts ...ServerOption) (*Server, error) {
 	ns := &Server{
-		PayInfoMap:  make(map[string]pb.PaymentInformation, 1000000),
-		CardInfoMap: make(map[string]pb.CardInformation, 1000000),
+		PayInfoMap:         make(map[string]pb.PaymentInformation, 1000000),
+		CardInfoMap:        make(map[string]pb.CardInformation, 1000000),
+		IdempotencyMap:     make(map[string]idempotencyRecord, 1000000),
+		PaymentMerchantMap: make(map[string]string),
+		CardMerchantMap:    make(map[string]string),
+		cardTokens:         make(map[string]*cardToken, 1000000),
+		activeCharges:      make(map[reservationKey][]string),
+		multiCharged:       make(map[reservationKey]struct{}),
+		latency:            newLatencyInjector(nil),
+		fault:              newFaultInjector(),
+		validators:         DefaultCardValidators,
+		auth:               newAuthenticator("", nil),
+		webhook:            newWebhookDispatcher(config.Webhook{}),
+		ledger:             newLedger(),
+		health:             newHealthServer(),
+	}
+	ns.metrics = newMetrics(ns)
+	for _, opt := range opts {
+		opt(ns)
 	}
 	return ns, nil
 }
 
+//キーに対応するロック. 同じキーには常に同じロックを返す
+func (s *Server) paymentLock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &s.paymentLocks[h.Sum32()%paymentLockStripes]
+}
+
+//決済をキャンセル済みにする. 決済が存在しない(呼び出し元のものでない)場合はfalseを返す
+func (s *Server) cancelPayment(ctx context.Context, paymentID string) bool {
+	l := s.paymentLock(paymentID)
+	l.Lock()
+	defer l.Unlock()
+
+	s.mu.RLock()
+	paydata, ok := s.PayInfoMap[paymentID]
+	owner := s.PaymentMerchantMap[paymentID]
+	s.mu.RUnlock()
+	if !ok || !canAccess(ctx, owner) {
+		return false
+	}
+	if paydata.IsCanceled {
+		return true
+	}
+
+	paydata.IsCanceled = true
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 読み出してからInitializeされていた場合は書き戻さない
+	if _, ok := s.PayInfoMap[paymentID]; !ok {
+		return false
+	}
+	s.PayInfoMap[paymentID] = paydata
+	s.removeActiveCharge(reservationKey{owner, paydata.ReservationId}, paymentID)
+	capturedAt, _ := ptypes.Timestamp(paydata.Datetime)
+	s.ledger.cancel(paydata, paymentID, owner, capturedAt, time.Now())
+
+	data := webhookData{PaymentID: paymentID, ReservationID: paydata.ReservationId, Amount: paydata.Amount}
+	s.webhook.dispatch(owner, EventPaymentCanceled, data)
+	data.RefundID = "re_" + xid.New().String()
+	s.webhook.dispatch(owner, EventRefundCreated, data)
+	return true
+}
+
+//冪等キーを決定する(未指定の場合はカードトークンと予約IDから生成). マーチャントごとに別の名前空間になる
+func idempotencyKey(merchantID string, req *pb.ExecutePaymentRequest) string {
+	key := req.IdempotencyKey
+	if key == "" {
+		if req.PaymentInformation.ReservationId == 0 {
+			// 予約IDがない決済は再送を判定できない
+			return ""
+		}
+		key = fmt.Sprintf("%s:%d", req.PaymentInformation.CardToken, req.PaymentInformation.ReservationId)
+	}
+	if merchantID != "" {
+		return merchantID + "/" + key
+	}
+	return key
+}
+
 //クレジットカードのトークン発行(非保持化対応)
 func (s *Server) RegistCard(ctx context.Context, req *pb.RegistCardRequest) (*pb.RegistCardResponse, error) {
 	done := make(chan *pb.RegistCardResponse, 1)
@@ -53,6 +188,10 @@ func (s *Server) RegistCard(ctx context.Context, req *pb.RegistCardRequest) (*pb
 		err := s.ValidateCardInformation(req)
 		if err != nil {
 			log.Println(err.Error())
+			if verr, ok := err.(*ValidationError); ok {
+				ec <- verr.GRPCStatus().Err()
+				return
+			}
 			ec <- status.Errorf(codes.InvalidArgument, err.Error())
 			return
 		}
@@ -70,9 +209,31 @@ func (s *Server) RegistCard(ctx context.Context, req *pb.RegistCardRequest) (*pb
 			Cvv:        req.CardInformation.Cvv,
 			ExpiryDate: req.CardInformation.ExpiryDate,
 		}
+		if merchantID := merchantFromContext(ctx); merchantID != "" {
+			s.CardMerchantMap[id.String()] = merchantID
+		}
+		token := s.newCardToken(req, time.Now())
+		s.cardTokens[id.String()] = token
 		s.mu.Unlock()
 
-		done <- &pb.RegistCardResponse{CardToken: id.String(), IsOk: true}
+		var expiresAt *timestamp.Timestamp
+		if !token.expiresAt.IsZero() {
+			expiresAt, _ = ptypes.TimestampProto(token.expiresAt)
+		}
+
+		brand := ""
+		if b := DetectBrand(req.CardInformation.CardNumber); b != nil {
+			brand = b.Name
+		}
+		cardNumber := req.CardInformation.CardNumber
+		done <- &pb.RegistCardResponse{
+			CardToken: id.String(),
+			IsOk:      true,
+			Brand:     brand,
+			Last4:     cardNumber[len(cardNumber)-4:],
+			ExpiresAt: expiresAt,
+			Usage:     token.usage,
+		}
 	}()
 	select {
 	case r := <-done:
@@ -92,34 +253,108 @@ func (s *Server) ExecutePayment(ctx context.Context, req *pb.ExecutePaymentReque
 			ec <- status.Errorf(codes.InvalidArgument, "Invalid POST data")
 			return
 		}
+		pay, err := s.normalizePrice(req.PaymentInformation)
+		if err != nil {
+			log.Println(err.Error())
+			ec <- err
+			return
+		}
+
+		merchantID := merchantFromContext(ctx)
+		key := idempotencyKey(merchantID, req)
+		cardToken := req.PaymentInformation.CardToken
+
+		// 同じ冪等キー(冪等キーがない場合はカードトークン)の決済だけを直列化する
+		lockKey := key
+		if lockKey == "" {
+			lockKey = cardToken
+		}
+		l := s.paymentLock(lockKey)
+		l.Lock()
+		defer l.Unlock()
 
 		s.mu.RLock()
-		_, ok := s.CardInfoMap[req.PaymentInformation.CardToken]
+		card, ok := s.CardInfoMap[cardToken]
+		owner := s.CardMerchantMap[cardToken]
+		rec, replayed := s.IdempotencyMap[key]
 		s.mu.RUnlock()
-		if ok {
-			date, err := ptypes.TimestampProto(time.Now())
-			if err != nil {
-				log.Println(err.Error())
-				ec <- err
+		if !ok || !canAccess(ctx, owner) {
+			log.Println("Card_Token Not Found")
+			ec <- status.Errorf(codes.NotFound, "Card_Token Not Found")
+			return
+		}
+		if reason, ok := declineReason(card.CardNumber); ok {
+			log.Printf("Card Declined: %s\n", reason)
+			ec <- declineError(reason)
+			return
+		}
+
+		if key != "" && replayed {
+			if rec.Amount != pay.Amount {
+				log.Printf("Idempotency key reused with different amount: key=%s\n", key)
+				ec <- status.Errorf(codes.FailedPrecondition, "Idempotency key reused with different amount")
 				return
 			}
-			guid := xid.New()
-
 			s.mu.Lock()
-			s.PayInfoMap[guid.String()] = pb.PaymentInformation{
-				CardToken:     req.PaymentInformation.CardToken,
-				ReservationId: req.PaymentInformation.ReservationId,
-				Datetime:      date,
-				Amount:        req.PaymentInformation.Amount,
-				IsCanceled:    false,
-			}
+			s.replayed++
 			s.mu.Unlock()
+			done <- &pb.ExecutePaymentResponse{PaymentId: rec.PaymentID, IsOk: true}
+			return
+		}
+
+		// 登録後に有効期限が過ぎたカードは決済できない
+		if err := ValidateExpiryDate(&card); err != nil {
+			log.Printf("Card Declined: %s\n", DeclineExpiredCard)
+			ec <- declineError(DeclineExpiredCard)
+			return
+		}
+
+		now := time.Now()
+		pay.Datetime, err = ptypes.TimestampProto(now)
+		if err != nil {
+			log.Println(err.Error())
+			ec <- err
+			return
+		}
+		guid := xid.New()
 
-			done <- &pb.ExecutePaymentResponse{PaymentId: guid.String(), IsOk: true}
+		s.mu.Lock()
+		// 読み出してからInitializeされていた場合は決済しない
+		if _, ok := s.CardInfoMap[cardToken]; !ok {
+			s.mu.Unlock()
+			log.Println("Card_Token Not Found")
+			ec <- status.Errorf(codes.NotFound, "Card_Token Not Found")
+			return
+		}
+		// 使い捨てのトークンは異なる冪等キーの決済と取り合うので、使用済みにするのは書き込みと同じロックの中で行う
+		if err := s.useCardToken(cardToken, now); err != nil {
+			s.mu.Unlock()
+			log.Println(err.Error())
+			ec <- err
 			return
 		}
-		log.Println("Card_Token Not Found")
-		ec <- status.Errorf(codes.NotFound, "Card_Token Not Found")
+		s.PayInfoMap[guid.String()] = pay
+		s.addPaymentID(guid.String())
+		s.addActiveCharge(reservationKey{merchantID, pay.ReservationId}, guid.String())
+		if merchantID != "" {
+			s.PaymentMerchantMap[guid.String()] = merchantID
+		}
+		if key != "" {
+			s.IdempotencyMap[key] = idempotencyRecord{
+				PaymentID: guid.String(),
+				Amount:    pay.Amount,
+			}
+		}
+		s.mu.Unlock()
+
+		s.ledger.capture(pay, guid.String(), merchantID, now)
+		s.webhook.dispatch(merchantID, EventPaymentSucceeded, webhookData{
+			PaymentID:     guid.String(),
+			ReservationID: req.PaymentInformation.ReservationId,
+			Amount:        pay.Amount,
+		})
+
+		done <- &pb.ExecutePaymentResponse{PaymentId: guid.String(), IsOk: true}
 	}()
 	select {
 	case r := <-done:
@@ -133,18 +368,8 @@ func (s *Server) ExecutePayment(ctx context.Context, req *pb.ExecutePaymentReque
 func (s *Server) CancelPayment(ctx context.Context, req *pb.CancelPaymentRequest) (*pb.CancelPaymentResponse, error) {
 	done := make(chan struct{}, 1)
 	ec := make(chan error, 1)
-	s.cancelLock.Lock()
-	defer s.cancelLock.Unlock()
 	go func() {
-		s.mu.RLock()
-		paydata, ok := s.PayInfoMap[req.PaymentId]
-		s.mu.RUnlock()
-		time.Sleep(1 * time.Second)
-		if ok {
-			s.mu.Lock()
-			paydata.IsCanceled = true
-			s.PayInfoMap[req.PaymentId] = paydata
-			s.mu.Unlock()
+		if s.cancelPayment(ctx, req.PaymentId) {
 			done <- struct{}{}
 			return
 		}
@@ -163,40 +388,17 @@ func (s *Server) CancelPayment(ctx context.Context, req *pb.CancelPaymentRequest
 //バルクで決済をキャンセルする
 func (s *Server) BulkCancelPayment(ctx context.Context, req *pb.BulkCancelPaymentRequest) (*pb.BulkCancelPaymentResponse, error) {
 	done := make(chan int32, 1)
-	ec := make(chan int32, 1)
-	s.cancelLock.Lock()
-	defer s.cancelLock.Unlock()
 	go func() {
-		s.mu.Lock()
-		if len(req.PaymentId) < 1 {
-			ec <- 0
-			return
-		}
-
 		var i int32
 		for _, v := range req.PaymentId {
-			paydata, ok := s.PayInfoMap[v]
-			if ok {
-				paydata.IsCanceled = true
-				s.PayInfoMap[v] = paydata
-			} else {
-				i--
+			if s.cancelPayment(ctx, v) {
+				i++
 			}
-			i++
 		}
 		done <- i
 	}()
-	select {
-	case num := <-done:
-		s.mu.Unlock()
-		time.Sleep(time.Second * 1)
-		return &pb.BulkCancelPaymentResponse{Deleted: num}, nil
-	case num := <-ec:
-		s.mu.Unlock()
-		time.Sleep(time.Second * 1)
-		return &pb.BulkCancelPaymentResponse{Deleted: num}, nil
-	}
-	return nil, nil
+	num := <-done
+	return &pb.BulkCancelPaymentResponse{Deleted: num}, nil
 }
 
 //決済情報を取得する
@@ -206,8 +408,9 @@ func (s *Server) GetPaymentInformation(ctx context.Context, req *pb.GetPaymentIn
 	go func() {
 		s.mu.RLock()
 		id, ok := s.PayInfoMap[req.PaymentId]
+		owner := s.PaymentMerchantMap[req.PaymentId]
 		s.mu.RUnlock()
-		if ok {
+		if ok && canAccess(ctx, owner) {
 			done <- &pb.GetPaymentInformationResponse{PaymentInformation: &id, IsOk: true}
 			return
 		}
@@ -229,11 +432,29 @@ func (s *Server) Initialize(ctx context.Context, req *pb.InitializeRequest) (*pb
 	ec := make(chan error, 1)
 	go func() {
 		s.mu.Lock()
+		defer s.mu.Unlock()
+		if req.MerchantId != "" {
+			s.initializeMerchant(req.MerchantId)
+			s.webhook.clearHistory(req.MerchantId)
+			s.ledger.reset(req.MerchantId)
+			done <- struct{}{}
+			return
+		}
 		s.PayInfoMap = nil
 		s.CardInfoMap = nil
+		s.IdempotencyMap = nil
 		s.PayInfoMap = make(map[string]pb.PaymentInformation, 1000000)
 		s.CardInfoMap = make(map[string]pb.CardInformation, 1000000)
-		s.mu.Unlock()
+		s.IdempotencyMap = make(map[string]idempotencyRecord, 1000000)
+		s.PaymentMerchantMap = make(map[string]string)
+		s.CardMerchantMap = make(map[string]string)
+		s.cardTokens = make(map[string]*cardToken, 1000000)
+		s.paymentIDs = nil
+		s.activeCharges = make(map[reservationKey][]string)
+		s.multiCharged = make(map[reservationKey]struct{})
+		s.replayed = 0
+		s.webhook.clearHistory("")
+		s.ledger.reset("")
 		done <- struct{}{}
 	}()
 	select {
@@ -244,35 +465,268 @@ func (s *Server) Initialize(ctx context.Context, req *pb.InitializeRequest) (*pb
 	}
 }
 
+// GetResultRequestの条件
+type resultFilter struct {
+	cursor          string
+	reservationFrom int32
+	reservationTo   int32
+	since           time.Time
+	until           time.Time
+	merchantID      string
+}
+
+func newResultFilter(req *pb.GetResultRequest) (*resultFilter, error) {
+	f := &resultFilter{
+		cursor:          req.Cursor,
+		reservationFrom: req.ReservationIdFrom,
+		reservationTo:   req.ReservationIdTo,
+		merchantID:      req.MerchantId,
+	}
+	if req.Since != nil {
+		since, err := ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid since: %s", err)
+		}
+		f.since = since
+	}
+	if req.Until != nil {
+		until, err := ptypes.Timestamp(req.Until)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid until: %s", err)
+		}
+		f.until = until
+	}
+	return f, nil
+}
+
+func (f *resultFilter) match(v pb.PaymentInformation, merchantID string) bool {
+	if f.merchantID != "" && merchantID != f.merchantID {
+		return false
+	}
+	if f.reservationFrom != 0 && v.ReservationId < f.reservationFrom {
+		return false
+	}
+	if f.reservationTo != 0 && v.ReservationId > f.reservationTo {
+		return false
+	}
+	if f.since.IsZero() && f.until.IsZero() {
+		return true
+	}
+	t, err := ptypes.Timestamp(v.Datetime)
+	if err != nil {
+		return false
+	}
+	if !f.since.IsZero() && t.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !t.Before(f.until) {
+		return false
+	}
+	return true
+}
+
+// 結果として返す決済
+type resultEntry struct {
+	paymentID  string
+	merchantID string
+	payment    pb.PaymentInformation
+	card       pb.CardInformation
+}
+
+func (e *resultEntry) fill(rawData *pb.RawData) {
+	rawData.PaymentId = e.paymentID
+	rawData.MerchantId = e.merchantID
+	rawData.PaymentInformation.CardToken = e.payment.CardToken
+	rawData.PaymentInformation.ReservationId = e.payment.ReservationId
+	rawData.PaymentInformation.Datetime = e.payment.Datetime
+	rawData.PaymentInformation.Amount = e.payment.Amount
+	rawData.PaymentInformation.IsCanceled = e.payment.IsCanceled
+	rawData.PaymentInformation.Price = e.payment.Price
+	rawData.PaymentInformation.Tax = e.payment.Tax
+
+	rawData.CardInformation.CardNumber = e.card.CardNumber
+	rawData.CardInformation.Cvv = e.card.Cvv
+	rawData.CardInformation.ExpiryDate = e.card.ExpiryDate
+}
+
+//決済IDを昇順の一覧に加える(s.muを取得して呼び出すこと)
+//xidは発行順に増加するので、通常は末尾に追加するだけで済む
+func (s *Server) addPaymentID(paymentID string) {
+	n := len(s.paymentIDs)
+	if n == 0 || s.paymentIDs[n-1] < paymentID {
+		s.paymentIDs = append(s.paymentIDs, paymentID)
+		return
+	}
+	i := sort.SearchStrings(s.paymentIDs, paymentID)
+	s.paymentIDs = append(s.paymentIDs, "")
+	copy(s.paymentIDs[i+1:], s.paymentIDs[i:])
+	s.paymentIDs[i] = paymentID
+}
+
+//決済IDがafterより後の決済を最大limit件調べ、条件に合うものを決済IDの昇順でentriesに追加する
+//調べた最後の決済IDと、それより後に決済が残っているかを返す
+func (s *Server) readResults(f *resultFilter, after string, limit int, entries []*resultEntry) ([]*resultEntry, string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := 0
+	if after != "" {
+		i = sort.Search(len(s.paymentIDs), func(i int) bool {
+			return s.paymentIDs[i] > after
+		})
+	}
+	end := len(s.paymentIDs)
+	if limit > 0 && i+limit < end {
+		end = i + limit
+	}
+	for _, id := range s.paymentIDs[i:end] {
+		v := s.PayInfoMap[id]
+		merchantID := s.PaymentMerchantMap[id]
+		if !f.match(v, merchantID) {
+			continue
+		}
+		entries = append(entries, &resultEntry{paymentID: id, merchantID: merchantID, payment: v, card: s.CardInfoMap[v.CardToken]})
+	}
+	if i == end {
+		return entries, after, false
+	}
+	return entries, s.paymentIDs[end-1], end < len(s.paymentIDs)
+}
+
+func (s *Server) replayedCount() int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replayed
+}
+
+//予約に対する有効な決済を記録する(s.muを取得して呼び出すこと)
+func (s *Server) addActiveCharge(k reservationKey, paymentID string) {
+	if k.reservationID == 0 {
+		return
+	}
+	s.activeCharges[k] = append(s.activeCharges[k], paymentID)
+	if len(s.activeCharges[k]) > 1 {
+		s.multiCharged[k] = struct{}{}
+	}
+}
+
+//キャンセルされた決済を予約に対する有効な決済から除く(s.muを取得して呼び出すこと)
+func (s *Server) removeActiveCharge(k reservationKey, paymentID string) {
+	ids := s.activeCharges[k]
+	for i, id := range ids {
+		if id == paymentID {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	switch len(ids) {
+	case 0:
+		delete(s.activeCharges, k)
+	case 1:
+		s.activeCharges[k] = ids
+		delete(s.multiCharged, k)
+	default:
+		s.activeCharges[k] = ids
+	}
+}
+
+//条件に合う決済のうち、同一予約に対する有効な決済が複数あるものを列挙する
+//有効な決済が2件以上ある予約だけを調べるので、決済の件数によらず軽い
+func (s *Server) findDuplicates(f *resultFilter) []*pb.DuplicatePayment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := newDuplicateFinder()
+	for k := range s.multiCharged {
+		for _, id := range s.activeCharges[k] {
+			v := s.PayInfoMap[id]
+			if !f.match(v, k.merchantID) {
+				continue
+			}
+			d.add(&resultEntry{paymentID: id, merchantID: k.merchantID, payment: v})
+		}
+	}
+	return d.duplicates()
+}
+
+//マーチャントのデータのみ削除する(s.muを取得して呼び出すこと)
+func (s *Server) initializeMerchant(merchantID string) {
+	for id, owner := range s.PaymentMerchantMap {
+		if owner == merchantID {
+			delete(s.PayInfoMap, id)
+			delete(s.PaymentMerchantMap, id)
+		}
+	}
+	ids := s.paymentIDs[:0]
+	for _, id := range s.paymentIDs {
+		if _, ok := s.PayInfoMap[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	s.paymentIDs = ids
+	for k := range s.activeCharges {
+		if k.merchantID == merchantID {
+			delete(s.activeCharges, k)
+			delete(s.multiCharged, k)
+		}
+	}
+	for token, owner := range s.CardMerchantMap {
+		if owner == merchantID {
+			delete(s.CardInfoMap, token)
+			delete(s.CardMerchantMap, token)
+			delete(s.cardTokens, token)
+		}
+	}
+	for key := range s.IdempotencyMap {
+		if strings.HasPrefix(key, merchantID+"/") {
+			delete(s.IdempotencyMap, key)
+		}
+	}
+}
+
 //ベンチマーカー用結果取得API
 func (s *Server) GetResult(ctx context.Context, req *pb.GetResultRequest) (*pb.GetResultResponse, error) {
 	done := make(chan *pb.GetResultResponse, 1)
 	ec := make(chan error, 1)
 	go func() {
-		log.Printf("Card count: %d\n", len(s.CardInfoMap))
-		log.Printf("Payment count: %d\n", len(s.PayInfoMap))
+		f, err := newResultFilter(req)
+		if err != nil {
+			ec <- err
+			return
+		}
+		limit := int(req.PageSize)
+		if limit < 0 {
+			limit = 0
+		}
+		// cursorより後の決済をページが埋まるまで読み出す. 条件に合わない決済もlimitに数えるので、1回で埋まるとは限らない
+		entries := []*resultEntry{}
+		last, more := f.cursor, true
+		for more && (limit == 0 || len(entries) < limit) {
+			n := 0
+			if limit > 0 {
+				n = limit - len(entries)
+			}
+			entries, last, more = s.readResults(f, last, n, entries)
+		}
+		nextCursor := ""
+		if more {
+			nextCursor = last
+		}
+		// 多重課金はページに関係なく条件に合う決済全体から判定する
+		duplicates := s.findDuplicates(f)
 
-		raw := []*pb.RawData{}
-		s.mu.RLock()
-		for _, v := range s.PayInfoMap {
-			rawData := getRawData()
-			defer putRawData(rawData)
-
-			t := v.CardToken
-			rawData.PaymentInformation.CardToken = t
-			rawData.PaymentInformation.ReservationId = v.ReservationId
-			rawData.PaymentInformation.Datetime = v.Datetime
-			rawData.PaymentInformation.Amount = v.Amount
-			rawData.PaymentInformation.IsCanceled = v.IsCanceled
-
-			rawData.CardInformation.CardNumber = s.CardInfoMap[t].CardNumber
-			rawData.CardInformation.Cvv = s.CardInfoMap[t].Cvv
-			rawData.CardInformation.ExpiryDate = s.CardInfoMap[t].ExpiryDate
+		// レスポンスが送信されるまで参照されるため、プールは使わない
+		raw := make([]*pb.RawData, 0, len(entries))
+		for _, e := range entries {
+			rawData := &pb.RawData{
+				PaymentInformation: &pb.PaymentInformation{},
+				CardInformation:    &pb.CardInformation{},
+			}
+			e.fill(rawData)
 			raw = append(raw, rawData)
 		}
-		s.mu.RUnlock()
 
-		done <- &pb.GetResultResponse{RawData: raw, IsOk: true}
+		done <- &pb.GetResultResponse{RawData: raw, IsOk: true, Duplicates: duplicates, Replayed: s.replayedCount(), NextCursor: nextCursor}
 	}()
 	select {
 	case r := <-done:
@@ -282,6 +736,84 @@ func (s *Server) GetResult(ctx context.Context, req *pb.GetResultRequest) (*pb.G
 	}
 }
 
+//StreamResultsで一度にロックを取って読み出す決済の数
+const streamBatchSize = 1000
+
+//ベンチマーカー用結果取得API(1件ずつストリームで返す)
+//全件を読み込まずに、決済IDの昇順で少しずつ読み出しながら送信する
+func (s *Server) StreamResults(req *pb.GetResultRequest, stream pb.PaymentService_StreamResultsServer) error {
+	f, err := newResultFilter(req)
+	if err != nil {
+		return err
+	}
+
+	var count int32
+	entries := make([]*resultEntry, 0, streamBatchSize)
+	last, more := f.cursor, true
+	for more {
+		entries, last, more = s.readResults(f, last, streamBatchSize, entries[:0])
+		for _, e := range entries {
+			rawData := getRawData()
+			e.fill(rawData)
+			// Sendはシリアライズが終わるまで戻らないので、直後にプールへ返してよい
+			err := stream.Send(&pb.StreamResultsResponse{RawData: rawData})
+			putRawData(rawData)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+	}
+
+	// 多重課金はcursorに関係なく条件に合う決済全体から判定する
+	return stream.Send(&pb.StreamResultsResponse{Summary: &pb.ResultSummary{
+		Count:      count,
+		Duplicates: s.findDuplicates(f),
+		Replayed:   s.replayedCount(),
+	}})
+}
+
+//予約IDはマーチャントごとに別のものとして扱う
+type reservationKey struct {
+	merchantID    string
+	reservationID int32
+}
+
+//決済を1件ずつ受け取って多重課金を探す
+type duplicateFinder struct {
+	byReservation map[reservationKey][]string
+}
+
+func newDuplicateFinder() *duplicateFinder {
+	return &duplicateFinder{byReservation: map[reservationKey][]string{}}
+}
+
+func (d *duplicateFinder) add(e *resultEntry) {
+	if e.payment.IsCanceled || e.payment.ReservationId == 0 {
+		return
+	}
+	k := reservationKey{e.merchantID, e.payment.ReservationId}
+	d.byReservation[k] = append(d.byReservation[k], e.paymentID)
+}
+
+func (d *duplicateFinder) duplicates() []*pb.DuplicatePayment {
+	duplicates := []*pb.DuplicatePayment{}
+	for k, ids := range d.byReservation {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Strings(ids)
+		duplicates = append(duplicates, &pb.DuplicatePayment{ReservationId: k.reservationID, PaymentId: ids, MerchantId: k.merchantID})
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].MerchantId != duplicates[j].MerchantId {
+			return duplicates[i].MerchantId < duplicates[j].MerchantId
+		}
+		return duplicates[i].ReservationId < duplicates[j].ReservationId
+	})
+	return duplicates
+}
+
 func init() {
 	for i := 0; i < 1000000; i++ {
 		putRawData(getRawData())
@@ -2,13 +2,22 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	pb "payment/pb"
 
+	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 /*
@@ -51,9 +60,9 @@ func TestServer(t *testing.T) {
 	t.Run("RegistCard", func(t *testing.T) {
 		ctx := context.Background()
 		card := &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "123",
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		r, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
 		if err != nil {
@@ -148,7 +157,7 @@ func TestServer(t *testing.T) {
 		card := &pb.CardInformation{
 			CardNumber: "1234567", //invalid
 			Cvv:        "123",
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		r, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
@@ -157,9 +166,9 @@ func TestServer(t *testing.T) {
 		t.Logf("%#v", r)
 
 		card = &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "12", //invalid
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		r, err = c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
@@ -168,7 +177,7 @@ func TestServer(t *testing.T) {
 		t.Logf("%#v", r)
 
 		card = &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "123",
 			ExpiryDate: "01/18", //invalid
 		}
@@ -249,15 +258,16 @@ func TestServer(t *testing.T) {
 	})
 
 	cardlist := make([]pb.CardInformation, 3)
+	cardnumbers := []string{"4111111111111111", "5555555555554444", "3530111333300000"}
 	tokenlist := make([]string, 3)
 	t.Log(len(cardlist))
 	t.Run("[Ex]RegistCard for GetResult", func(t *testing.T) {
 		ctx := context.Background()
 		for i, _ := range cardlist {
 			card := pb.CardInformation{
-				CardNumber: strconv.Itoa(i + 11111111),
+				CardNumber: cardnumbers[i],
 				Cvv:        strconv.Itoa(i + 111),
-				ExpiryDate: "11/22",
+				ExpiryDate: "11/50",
 			}
 			cardlist[i] = card
 
@@ -370,3 +380,444 @@ func TestServer(t *testing.T) {
 		}
 	})
 }
+
+func TestIdempotentExecutePayment(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		ExpiryDate: "11/50",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := r.CardToken
+
+	var payid string
+	t.Run("Replay returns original payment", func(t *testing.T) {
+		pay := &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 9800}
+		first, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.PaymentId != second.PaymentId {
+			t.Fatalf("Failed. Expected:%s but %s\n", first.PaymentId, second.PaymentId)
+		}
+		payid = first.PaymentId
+	})
+
+	t.Run("Replay with different amount", func(t *testing.T) {
+		pay := &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 1000}
+		_, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.FailedPrecondition, status.Code(err))
+		}
+	})
+
+	t.Run("Explicit idempotency key", func(t *testing.T) {
+		pay := &pb.PaymentInformation{CardToken: token, ReservationId: 1, Amount: 9800}
+		r, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay, IdempotencyKey: "retry-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.PaymentId == payid {
+			t.Fatal("should be a new payment")
+		}
+	})
+
+	t.Run("GetResult reports duplicates", func(t *testing.T) {
+		r, err := s.GetResult(ctx, &pb.GetResultRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.RawData) != 2 {
+			t.Fatalf("Failed. Expected:2 but %d\n", len(r.RawData))
+		}
+		if r.Replayed != 1 {
+			t.Fatalf("Failed. Expected:1 but %d\n", r.Replayed)
+		}
+		if len(r.Duplicates) != 1 || r.Duplicates[0].ReservationId != 1 || len(r.Duplicates[0].PaymentId) != 2 {
+			t.Fatalf("Failed. Unexpected duplicates: %#v\n", r.Duplicates)
+		}
+	})
+
+	t.Run("Canceled payment is not a duplicate", func(t *testing.T) {
+		if _, err := s.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: payid}); err != nil {
+			t.Fatal(err)
+		}
+		r, err := s.GetResult(ctx, &pb.GetResultRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.RawData) != 2 || len(r.Duplicates) != 0 {
+			t.Fatalf("unexpected result: %d payments, %d duplicates", len(r.RawData), len(r.Duplicates))
+		}
+	})
+}
+
+func TestConcurrentCancelPayment(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		ExpiryDate: "11/50",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payids := make([]string, 100)
+	for i := range payids {
+		pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: int32(i + 1), Amount: 9800}
+		r, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payids[i] = r.PaymentId
+	}
+
+	var wg sync.WaitGroup
+	for _, payid := range payids {
+		wg.Add(2)
+		go func(payid string) {
+			defer wg.Done()
+			if _, err := s.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: payid}); err != nil {
+				t.Error(err)
+			}
+		}(payid)
+		go func(payid string) {
+			defer wg.Done()
+			if _, err := s.BulkCancelPayment(ctx, &pb.BulkCancelPaymentRequest{PaymentId: []string{payid}}); err != nil {
+				t.Error(err)
+			}
+		}(payid)
+	}
+	wg.Wait()
+
+	for _, payid := range payids {
+		r, err := s.GetPaymentInformation(ctx, &pb.GetPaymentInformationRequest{PaymentId: payid})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.PaymentInformation.IsCanceled {
+			t.Fatalf("payment %s should be canceled", payid)
+		}
+	}
+}
+
+func TestConcurrentExecutePayment(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+	card := &pb.CardInformation{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		ExpiryDate: "11/50",
+	}
+
+	t.Run("Same idempotency key", func(t *testing.T) {
+		r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payids := make([]string, 50)
+		var wg sync.WaitGroup
+		for i := range payids {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: 1, Amount: 9800}
+				res, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				payids[i] = res.PaymentId
+			}(i)
+		}
+		wg.Wait()
+		for _, payid := range payids {
+			if payid != payids[0] {
+				t.Fatalf("retries should return the same payment: %s and %s", payids[0], payid)
+			}
+		}
+		if s.replayedCount() != int32(len(payids)-1) {
+			t.Fatalf("Failed. Expected:%d but %d\n", len(payids)-1, s.replayedCount())
+		}
+	})
+
+	t.Run("Single use token with different keys", func(t *testing.T) {
+		r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card, Usage: pb.CardTokenUsage_SINGLE_USE})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+			ok int
+		)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: int32(i + 100), Amount: 9800}
+				if _, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay}); err == nil {
+					mu.Lock()
+					ok++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+		if ok != 1 {
+			t.Fatalf("single use token should be charged once but %d\n", ok)
+		}
+	})
+}
+
+func TestGetResultPagination(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	g := grpc.NewServer()
+	defer g.Stop()
+
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+	ctx := context.Background()
+
+	r, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		ExpiryDate: "11/50",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 10; i++ {
+		pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: int32(i), Amount: 1000}
+		if _, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 予約10に対する多重課金
+	pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: 10, Amount: 1000}
+	if _, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay, IdempotencyKey: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Pagination", func(t *testing.T) {
+		seen := map[string]bool{}
+		cursor := ""
+		pages := 0
+		for {
+			r, err := c.GetResult(ctx, &pb.GetResultRequest{PageSize: 4, Cursor: cursor})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			if len(r.Duplicates) != 1 {
+				t.Fatalf("Failed. Expected:1 duplicates but %d\n", len(r.Duplicates))
+			}
+			for _, v := range r.RawData {
+				if seen[v.PaymentId] {
+					t.Fatalf("payment %s is returned twice", v.PaymentId)
+				}
+				seen[v.PaymentId] = true
+			}
+			if r.NextCursor == "" {
+				break
+			}
+			cursor = r.NextCursor
+		}
+		if len(seen) != 11 || pages != 3 {
+			t.Fatalf("Failed. Expected:11 payments in 3 pages but %d in %d\n", len(seen), pages)
+		}
+	})
+
+	t.Run("PaginationWithFilter", func(t *testing.T) {
+		// 条件に合わない決済を読み飛ばしてもページはpage_size件まで埋まる
+		ids := []int32{}
+		cursor := ""
+		pages := 0
+		for {
+			r, err := c.GetResult(ctx, &pb.GetResultRequest{PageSize: 2, Cursor: cursor, ReservationIdFrom: 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			if r.NextCursor != "" && len(r.RawData) != 2 {
+				t.Fatalf("Failed. Expected:2 payments in a page but %d\n", len(r.RawData))
+			}
+			for _, v := range r.RawData {
+				ids = append(ids, v.PaymentInformation.ReservationId)
+			}
+			if r.NextCursor == "" {
+				break
+			}
+			cursor = r.NextCursor
+		}
+		if fmt.Sprint(ids) != "[7 8 9 10 10]" || pages != 3 {
+			t.Fatalf("Failed. Expected:[7 8 9 10 10] in 3 pages but %v in %d\n", ids, pages)
+		}
+	})
+
+	t.Run("FilterByReservationID", func(t *testing.T) {
+		r, err := c.GetResult(ctx, &pb.GetResultRequest{ReservationIdFrom: 3, ReservationIdTo: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.RawData) != 3 || len(r.Duplicates) != 0 || r.NextCursor != "" {
+			t.Fatalf("unexpected result: %d payments, %d duplicates", len(r.RawData), len(r.Duplicates))
+		}
+		for _, v := range r.RawData {
+			if v.PaymentInformation.ReservationId < 3 || 5 < v.PaymentInformation.ReservationId {
+				t.Fatalf("unexpected reservation_id: %d", v.PaymentInformation.ReservationId)
+			}
+		}
+	})
+
+	t.Run("FilterByTime", func(t *testing.T) {
+		future, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+		r, err := c.GetResult(ctx, &pb.GetResultRequest{Since: future})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.RawData) != 0 {
+			t.Fatalf("Failed. Expected:0 but %d\n", len(r.RawData))
+		}
+		r, err = c.GetResult(ctx, &pb.GetResultRequest{Until: future})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.RawData) != 11 {
+			t.Fatalf("Failed. Expected:11 but %d\n", len(r.RawData))
+		}
+	})
+
+	t.Run("StreamResults", func(t *testing.T) {
+		stream, err := c.StreamResults(ctx, &pb.GetResultRequest{ReservationIdFrom: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		var summary *pb.ResultSummary
+		for {
+			r, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Summary != nil {
+				summary = r.Summary
+				continue
+			}
+			if r.RawData.PaymentInformation.ReservationId < 5 || r.RawData.CardInformation.CardNumber != "4111111111111111" {
+				t.Fatalf("unexpected raw data: %+v", r.RawData)
+			}
+			count++
+		}
+		if count != 7 {
+			t.Fatalf("Failed. Expected:7 but %d\n", count)
+		}
+		if summary == nil || summary.Count != 7 || len(summary.Duplicates) != 1 || summary.Duplicates[0].ReservationId != 10 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+	})
+
+	t.Run("StreamResultsAfterCursor", func(t *testing.T) {
+		all, err := c.GetResult(ctx, &pb.GetResultRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cursor := all.RawData[3].PaymentId
+		stream, err := c.StreamResults(ctx, &pb.GetResultRequest{Cursor: cursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := []string{}
+		var summary *pb.ResultSummary
+		for {
+			r, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Summary != nil {
+				summary = r.Summary
+				continue
+			}
+			ids = append(ids, r.RawData.PaymentId)
+		}
+		if len(ids) != 7 || ids[0] != all.RawData[4].PaymentId || !sort.StringsAreSorted(ids) {
+			t.Fatalf("payments after the cursor should be streamed in order: %v", ids)
+		}
+		// 多重課金はcursorより前の決済も含めて判定する
+		if summary == nil || summary.Count != 7 || len(summary.Duplicates) != 1 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+	})
+}
+
+func TestReadResultsInBatches(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	for _, id := range []string{"b", "d", "a", "e", "c"} {
+		s.PayInfoMap[id] = pb.PaymentInformation{ReservationId: 1}
+		s.addPaymentID(id)
+	}
+	if strings.Join(s.paymentIDs, "") != "abcde" {
+		t.Fatalf("payment ids should be sorted: %v", s.paymentIDs)
+	}
+
+	f := &resultFilter{}
+	ids := ""
+	last, more := "", true
+	for batches := 0; more; batches++ {
+		if batches > 3 {
+			t.Fatal("too many batches")
+		}
+		var entries []*resultEntry
+		entries, last, more = s.readResults(f, last, 2, nil)
+		for _, e := range entries {
+			ids += e.paymentID
+		}
+	}
+	if ids != "abcde" {
+		t.Fatalf("Failed. Expected:abcde but %s\n", ids)
+	}
+}
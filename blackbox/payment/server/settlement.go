@@ -0,0 +1,228 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"log"
+	"sort"
+	"strconv"
+
+	pb "payment/pb"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var settlementCSVHeader = []string{
+	"merchant_id", "date", "reservation_id",
+	"capture_count", "capture_amount",
+	"cancel_count", "cancel_amount",
+	"refund_count", "refund_amount",
+	"net_amount",
+}
+
+var ledgerCSVHeader = []string{
+	"transaction_id", "type", "payment_id", "reservation_id", "merchant_id", "account", "amount", "datetime",
+}
+
+//呼び出し元が参照できるマーチャントの仕訳の絞り込み条件を作る
+func settlementFilter(ctx context.Context, merchantID, dateFrom, dateTo string) (ledgerFilter, error) {
+	if p := principalFromContext(ctx); p != nil && !p.admin {
+		merchantID = p.merchantID
+	}
+	f, err := newLedgerFilter(merchantID, dateFrom, dateTo)
+	if err != nil {
+		return f, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return f, nil
+}
+
+//仕訳を集計して精算レポートを作る
+func (s *Server) settlementReport(ctx context.Context, req *pb.SettlementReportRequest) (*pb.SettlementReportResponse, error) {
+	f, err := settlementFilter(ctx, req.MerchantId, req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	transactions := s.ledger.query(f)
+
+	type key struct {
+		merchantID    string
+		date          string
+		reservationID int32
+	}
+	rows := map[key]*pb.SettlementRow{}
+	total := &pb.SettlementRow{}
+	for _, tx := range transactions {
+		k := key{merchantID: tx.merchantID}
+		if req.GroupBy == pb.SettlementReportRequest_RESERVATION {
+			k.reservationID = tx.reservationID
+		} else {
+			k.date = settlementDate(tx.datetime)
+		}
+		row, ok := rows[k]
+		if !ok {
+			row = &pb.SettlementRow{MerchantId: k.merchantID, Date: k.date, ReservationId: k.reservationID}
+			rows[k] = row
+		}
+		for _, r := range []*pb.SettlementRow{row, total} {
+			addTransaction(r, tx)
+		}
+	}
+
+	res := &pb.SettlementReportResponse{Rows: make([]*pb.SettlementRow, 0, len(rows)), Total: total, IsOk: true}
+	for _, row := range rows {
+		res.Rows = append(res.Rows, row)
+	}
+	sort.Slice(res.Rows, func(i, j int) bool {
+		a, b := res.Rows[i], res.Rows[j]
+		if a.MerchantId != b.MerchantId {
+			return a.MerchantId < b.MerchantId
+		}
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.ReservationId < b.ReservationId
+	})
+
+	b := balances(transactions)
+	accounts := make([]string, 0, len(b))
+	for account := range b {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	for _, account := range accounts {
+		res.Balances = append(res.Balances, &pb.LedgerBalance{Account: account, Balance: b[account]})
+	}
+	return res, nil
+}
+
+//マーチャントへの支払額(merchant_payable)の増減から行を集計する
+func addTransaction(row *pb.SettlementRow, tx *ledgerTransaction) {
+	for _, p := range tx.postings {
+		if p.account != AccountMerchantPayable {
+			continue
+		}
+		switch tx.typ {
+		case TransactionCapture:
+			row.CaptureCount++
+			row.CaptureAmount -= p.amount
+		case TransactionCancel:
+			row.CancelCount++
+			row.CancelAmount += p.amount
+		case TransactionRefund:
+			row.RefundCount++
+			row.RefundAmount += p.amount
+		}
+		row.NetAmount -= p.amount
+	}
+}
+
+//protoメッセージをJSONのHttpBodyにする
+func jsonBody(m proto.Message) (*httpbody.HttpBody, error) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{OrigName: true, EmitDefaults: true}).Marshal(&buf, m); err != nil {
+		return nil, err
+	}
+	return &httpbody.HttpBody{ContentType: "application/json", Data: buf.Bytes()}, nil
+}
+
+//レコードをCSVのHttpBodyにする
+func csvBody(records [][]string) (*httpbody.HttpBody, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return &httpbody.HttpBody{ContentType: "text/csv; charset=utf-8", Data: buf.Bytes()}, nil
+}
+
+func settlementRecord(row *pb.SettlementRow) []string {
+	reservationID := ""
+	if row.ReservationId != 0 {
+		reservationID = strconv.Itoa(int(row.ReservationId))
+	}
+	return []string{
+		row.MerchantId, row.Date, reservationID,
+		strconv.FormatInt(row.CaptureCount, 10), strconv.FormatInt(row.CaptureAmount, 10),
+		strconv.FormatInt(row.CancelCount, 10), strconv.FormatInt(row.CancelAmount, 10),
+		strconv.FormatInt(row.RefundCount, 10), strconv.FormatInt(row.RefundAmount, 10),
+		strconv.FormatInt(row.NetAmount, 10),
+	}
+}
+
+//日ごと、予約ごとの精算レポートを取得する(台帳から集計する)
+func (s *Server) GetSettlementReport(ctx context.Context, req *pb.SettlementReportRequest) (*pb.SettlementReportResponse, error) {
+	res, err := s.settlementReport(ctx, req)
+	if err != nil {
+		log.Println(err.Error())
+		return &pb.SettlementReportResponse{IsOk: false}, err
+	}
+	return res, nil
+}
+
+//精算レポートをCSVまたはJSONで出力する
+func (s *Server) ExportSettlementReport(ctx context.Context, req *pb.SettlementReportRequest) (*httpbody.HttpBody, error) {
+	res, err := s.settlementReport(ctx, req)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, err
+	}
+	if req.Format == pb.ExportFormat_JSON {
+		return jsonBody(res)
+	}
+
+	records := [][]string{settlementCSVHeader}
+	for _, row := range res.Rows {
+		records = append(records, settlementRecord(row))
+	}
+	total := settlementRecord(res.Total)
+	total[0] = "total"
+	records = append(records, total)
+	return csvBody(records)
+}
+
+//台帳の仕訳をCSVまたはJSONで出力する
+func (s *Server) ExportLedger(ctx context.Context, req *pb.ExportLedgerRequest) (*httpbody.HttpBody, error) {
+	f, err := settlementFilter(ctx, req.MerchantId, req.DateFrom, req.DateTo)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, err
+	}
+
+	entries := []*pb.LedgerEntry{}
+	for _, tx := range s.ledger.query(f) {
+		datetime, err := ptypes.TimestampProto(tx.datetime)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range tx.postings {
+			entries = append(entries, &pb.LedgerEntry{
+				TransactionId: tx.id,
+				Type:          tx.typ,
+				PaymentId:     tx.paymentID,
+				ReservationId: tx.reservationID,
+				MerchantId:    tx.merchantID,
+				Account:       p.account,
+				Amount:        p.amount,
+				Datetime:      datetime,
+			})
+		}
+	}
+	if req.Format == pb.ExportFormat_JSON {
+		return jsonBody(&pb.ExportLedgerResponse{Entries: entries})
+	}
+
+	records := [][]string{ledgerCSVHeader}
+	for _, e := range entries {
+		records = append(records, []string{
+			e.TransactionId, e.Type, e.PaymentId, strconv.Itoa(int(e.ReservationId)), e.MerchantId,
+			e.Account, strconv.FormatInt(e.Amount, 10), ptypes.TimestampString(e.Datetime),
+		})
+	}
+	return csvBody(records)
+}
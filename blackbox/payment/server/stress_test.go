@@ -0,0 +1,235 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	pb "payment/pb"
+
+	"google.golang.org/grpc"
+)
+
+// ストレステストで期待する決済の状態
+type expectedPayment struct {
+	reservationID int32
+	amount        int32
+	canceled      bool
+}
+
+// ストレステストで作成した決済を記録する
+type stressLedger struct {
+	mu       sync.Mutex
+	tokens   []string
+	payments map[string]*expectedPayment
+	ids      []string
+	replayed int32
+}
+
+func (l *stressLedger) addToken(token string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = append(l.tokens, token)
+}
+
+func (l *stressLedger) randomToken(r *rand.Rand) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tokens[r.Intn(len(l.tokens))]
+}
+
+func (l *stressLedger) addPayment(id string, p *expectedPayment) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.payments[id] = p
+	l.ids = append(l.ids, id)
+}
+
+//重複しないn件以下の決済IDを選ぶ
+func (l *stressLedger) randomPayments(r *rand.Rand, n int) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.ids) == 0 {
+		return nil
+	}
+	picked := map[string]bool{}
+	ids := []string{}
+	for i := 0; i < n; i++ {
+		id := l.ids[r.Intn(len(l.ids))]
+		if !picked[id] {
+			picked[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (l *stressLedger) markCanceled(ids ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		l.payments[id].canceled = true
+	}
+}
+
+/*
+	RegistCard, ExecutePayment(再送を含む), CancelPayment, BulkCancelPayment, GetPaymentInformation を並行に呼び出し、
+	・成功した決済がすべてGetResultに含まれる(失われていない)
+	・キャンセルに成功した決済だけがキャンセル済みになっている
+	・GetResultの件数、再送の件数が呼び出し側で数えたものと一致する
+	ことを確認する. -raceで実行することを想定している
+*/
+func TestStress(t *testing.T) {
+	workers, iterations := 32, 200
+	if testing.Short() {
+		workers, iterations = 8, 50
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor), grpc.StreamInterceptor(s.StreamInterceptor))
+	defer g.Stop()
+	pb.RegisterPaymentServiceServer(g, s)
+	go g.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pb.NewPaymentServiceClient(conn)
+	ctx := context.Background()
+
+	l := &stressLedger{payments: map[string]*expectedPayment{}}
+	card := &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}
+	r, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.addToken(r.CardToken)
+
+	var reservationID int32
+	var wg sync.WaitGroup
+	errc := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for i := 0; i < iterations; i++ {
+				if err := stressStep(ctx, c, l, rnd, &reservationID); err != nil {
+					errc <- err
+					return
+				}
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		t.Fatal(err)
+	}
+
+	res, err := c.GetResult(ctx, &pb.GetResultRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.RawData) != len(l.payments) {
+		t.Fatalf("Failed. Expected:%d payments but %d\n", len(l.payments), len(res.RawData))
+	}
+	if res.Replayed != l.replayed {
+		t.Fatalf("Failed. Expected:%d replayed but %d\n", l.replayed, res.Replayed)
+	}
+	if len(res.Duplicates) != 0 {
+		t.Fatalf("Failed. Expected no duplicates but %d\n", len(res.Duplicates))
+	}
+	for _, raw := range res.RawData {
+		want, ok := l.payments[raw.PaymentId]
+		if !ok {
+			t.Fatalf("unexpected payment: %s", raw.PaymentId)
+		}
+		got := raw.PaymentInformation
+		if got.ReservationId != want.reservationID || got.Amount != want.amount {
+			t.Fatalf("payment %s: Expected reservation=%d amount=%d but reservation=%d amount=%d\n",
+				raw.PaymentId, want.reservationID, want.amount, got.ReservationId, got.Amount)
+		}
+		if got.IsCanceled != want.canceled {
+			t.Fatalf("payment %s: Expected is_canceled=%t but %t\n", raw.PaymentId, want.canceled, got.IsCanceled)
+		}
+	}
+}
+
+//ランダムに選んだ操作を1つ行い、結果を記録する
+func stressStep(ctx context.Context, c pb.PaymentServiceClient, l *stressLedger, r *rand.Rand, reservationID *int32) error {
+	switch n := r.Intn(10); {
+	case n < 1:
+		card := &pb.CardInformation{CardNumber: "5555555555554444", Cvv: "123", ExpiryDate: "11/50"}
+		res, err := c.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: card})
+		if err != nil {
+			return err
+		}
+		l.addToken(res.CardToken)
+	case n < 5:
+		pay := &pb.PaymentInformation{
+			CardToken:     l.randomToken(r),
+			ReservationId: atomic.AddInt32(reservationID, 1),
+			Amount:        int32(r.Intn(10000) + 1),
+		}
+		res, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		if err != nil {
+			return err
+		}
+		l.addPayment(res.PaymentId, &expectedPayment{reservationID: pay.ReservationId, amount: pay.Amount})
+		// 同じ予約への再送は同じ決済IDになる
+		if r.Intn(4) == 0 {
+			replay, err := c.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+			if err != nil {
+				return err
+			}
+			if replay.PaymentId != res.PaymentId {
+				return fmt.Errorf("replayed payment: Expected:%s but %s", res.PaymentId, replay.PaymentId)
+			}
+			l.mu.Lock()
+			l.replayed++
+			l.mu.Unlock()
+		}
+	case n < 7:
+		ids := l.randomPayments(r, 1)
+		if len(ids) == 0 {
+			return nil
+		}
+		if _, err := c.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: ids[0]}); err != nil {
+			return err
+		}
+		l.markCanceled(ids...)
+	case n < 8:
+		ids := l.randomPayments(r, 3)
+		res, err := c.BulkCancelPayment(ctx, &pb.BulkCancelPaymentRequest{PaymentId: ids})
+		if err != nil {
+			return err
+		}
+		if int(res.Deleted) != len(ids) {
+			return fmt.Errorf("bulk cancel: Expected:%d but %d", len(ids), res.Deleted)
+		}
+		l.markCanceled(ids...)
+	default:
+		ids := l.randomPayments(r, 1)
+		if len(ids) == 0 {
+			return nil
+		}
+		if _, err := c.GetPaymentInformation(ctx, &pb.GetPaymentInformationRequest{PaymentId: ids[0]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,42 +1,128 @@
 package server
 
 import (
-	pb "payment/pb"
-	"time"
+	"regexp"
 	"strconv"
 	"strings"
-	"errors"
-	"regexp"
+	"time"
+
+	pb "payment/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CardValidator はカード情報の検証ステップです. 検証に失敗した場合はエラーを返します
+type CardValidator func(card *pb.CardInformation) error
+
+// DefaultCardValidators はカード登録時に順に実行される検証ステップです
+var DefaultCardValidators = []CardValidator{
+	ValidateCardNumber,
+	ValidateLuhn,
+	ValidateBrand,
+	ValidateCvv,
+	ValidateExpiryDate,
+}
+
+// ValidationError はカード情報の項目ごとの検証エラーです
+type ValidationError struct {
+	Field       string
+	Description string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Description
+}
+
+// GRPCStatus は検証エラーをBadRequestの詳細付きのステータスに変換します
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Description)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: e.Field, Description: e.Description},
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+func invalid(field, description string) error {
+	return &ValidationError{Field: field, Description: description}
+}
+
+var (
+	cardNumberRegexp = regexp.MustCompile("^[0-9]+$")
+	cvvRegexp        = regexp.MustCompile("^[0-9]+$")
+	expiryRegexp     = regexp.MustCompile("^[0-9]{2}/[0-9]{2}$")
 )
 
 func (s *Server) ValidateCardInformation(req *pb.RegistCardRequest) error {
-	card := pb.CardInformation{
-		CardNumber: req.CardInformation.CardNumber,
-		Cvv:        req.CardInformation.Cvv,
-		ExpiryDate: req.CardInformation.ExpiryDate,
+	for _, validate := range s.validators {
+		if err := validate(req.CardInformation); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if len(card.CardNumber) != 8 {
-		return errors.New("Invalid CardNumber Length")
+// ValidateCardNumber はカード番号が13桁から19桁の数字であることを検証します
+func ValidateCardNumber(card *pb.CardInformation) error {
+	if len(card.CardNumber) < 13 || 19 < len(card.CardNumber) {
+		return invalid("card_number", "Invalid CardNumber Length")
 	}
-	if len(card.Cvv) != 3 {
-		return errors.New("Invalid Cvv Length")
+	if !cardNumberRegexp.MatchString(card.CardNumber) {
+		return invalid("card_number", "Invalid CardNumber")
 	}
-	if len(card.ExpiryDate) != 5 {
-		return errors.New("Invalid ExpiryDate length")
+	return nil
+}
+
+// ValidateLuhn はカード番号のチェックディジット(Luhn)を検証します
+func ValidateLuhn(card *pb.CardInformation) error {
+	if !luhn(card.CardNumber) {
+		return invalid("card_number", "Invalid CardNumber Checksum")
+	}
+	return nil
+}
+
+// ValidateBrand はカードブランドを判定し、ブランドごとの桁数を検証します
+func ValidateBrand(card *pb.CardInformation) error {
+	brand := DetectBrand(card.CardNumber)
+	if brand == nil {
+		return invalid("card_number", "Unsupported Card Brand")
 	}
+	for _, l := range brand.Lengths {
+		if len(card.CardNumber) == l {
+			return nil
+		}
+	}
+	return invalid("card_number", "Invalid CardNumber Length")
+}
 
-	cardnum := regexp.MustCompile("^[0-9]{8}$")
-	if !cardnum.MatchString(card.CardNumber) {
-		return errors.New("Invalid CardNumber")
+// ValidateCvv はカードブランドに応じた桁数(American Expressは4桁、その他は3桁)のCVVであることを検証します
+func ValidateCvv(card *pb.CardInformation) error {
+	cvvLength := 3
+	if brand := DetectBrand(card.CardNumber); brand != nil {
+		cvvLength = brand.CvvLength
 	}
-	cvvnum := regexp.MustCompile("^[0-9]{3}$")
-	if !cvvnum.MatchString(card.Cvv) {
-		return errors.New("Invalid Cvv")
+	if len(card.Cvv) != cvvLength {
+		return invalid("cvv", "Invalid Cvv Length")
 	}
-	expdate := regexp.MustCompile("^[0-9]{2}/[0-9]{2}$")
-	if !expdate.MatchString(card.ExpiryDate) {
-		return errors.New("Invalid ExpiryDate")
+	if !cvvRegexp.MatchString(card.Cvv) {
+		return invalid("cvv", "Invalid Cvv")
+	}
+	return nil
+}
+
+// ValidateExpiryDate は有効期限(MM/YY)が正しく、期限切れでないことを検証します
+func ValidateExpiryDate(card *pb.CardInformation) error {
+	if len(card.ExpiryDate) != 5 {
+		return invalid("expiry_date", "Invalid ExpiryDate length")
+	}
+	if !expiryRegexp.MatchString(card.ExpiryDate) {
+		return invalid("expiry_date", "Invalid ExpiryDate")
 	}
 
 	mmyy := strings.Split(card.ExpiryDate, "/")
@@ -45,22 +131,40 @@ func (s *Server) ValidateCardInformation(req *pb.RegistCardRequest) error {
 		return err
 	}
 	y := strconv.Itoa(time.Now().UTC().Year())
-	if err != nil {
-		return err
-	}
 	year, err := strconv.Atoi(y[:2] + mmyy[1])
 	if err != nil {
 		return err
 	}
 
 	if month < 1 || 12 < month {
-		return errors.New("Invalid month.")
+		return invalid("expiry_date", "Invalid month.")
 	}
 	if year < time.Now().UTC().Year() {
-		return errors.New("Credit card has expired.")
+		return invalid("expiry_date", "Credit card has expired.")
 	}
 	if year == time.Now().UTC().Year() && month < int(time.Now().UTC().Month()) {
-		return errors.New("Credit card has expired.")
+		return invalid("expiry_date", "Credit card has expired.")
 	}
 	return nil
 }
+
+//Luhnアルゴリズムでチェックディジットを検証する
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if d < 0 || 9 < d {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
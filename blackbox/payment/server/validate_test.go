@@ -1,9 +1,14 @@
 package server
 
 import (
+	"context"
 	"testing"
 
 	pb "payment/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestValidator(t *testing.T) {
@@ -14,9 +19,9 @@ func TestValidator(t *testing.T) {
 
 	t.Run("ValidateCard with correct parameters", func(t *testing.T) {
 		card := &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "123",
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
 		if err != nil {
@@ -29,7 +34,7 @@ func TestValidator(t *testing.T) {
 		card := &pb.CardInformation{
 			CardNumber: "1", //less
 			Cvv:        "123",
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
@@ -37,14 +42,14 @@ func TestValidator(t *testing.T) {
 		}
 		t.Logf("%#v", err)
 
-		card.CardNumber = "123456789" //over
+		card.CardNumber = "41111111111111111111" //over
 		err = s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
 			t.Fatal("should fail")
 		}
 		t.Logf("%#v", err)
 
-		card.CardNumber = "1234567A" //out of number
+		card.CardNumber = "411111111111111A" //out of number
 		err = s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
 			t.Fatal("should fail")
@@ -54,9 +59,9 @@ func TestValidator(t *testing.T) {
 
 	t.Run("ValidateCard with invalid cvv", func(t *testing.T) {
 		card := &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "1", //less
-			ExpiryDate: "11/22",
+			ExpiryDate: "11/50",
 		}
 		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
 		if err == nil {
@@ -81,7 +86,7 @@ func TestValidator(t *testing.T) {
 
 	t.Run("ValidateCard with invalid ExpiryDate", func(t *testing.T) {
 		card := &pb.CardInformation{
-			CardNumber: "12345678",
+			CardNumber: "4111111111111111",
 			Cvv:        "123",
 			ExpiryDate: "01/15", //past
 		}
@@ -107,3 +112,119 @@ func TestValidator(t *testing.T) {
 	})
 
 }
+
+func TestBrandAndLuhn(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+
+	brands := map[string]string{
+		"4111111111111111":    "visa",
+		"4222222222222":       "visa",
+		"5555555555554444":    "mastercard",
+		"2223003122003222":    "mastercard",
+		"378282246310005":     "amex",
+		"3530111333300000":    "jcb",
+		"30569309025904":      "diners",
+		"6011111111111117":    "discover",
+		"6011000990139424":    "discover",
+		"4111111111111111110": "visa",
+	}
+	for number, expected := range brands {
+		brand := DetectBrand(number)
+		if brand == nil || brand.Name != expected {
+			t.Fatalf("Failed. %s Expected:%s but %+v\n", number, expected, brand)
+		}
+		cvv := "123"
+		if brand.CvvLength == 4 {
+			cvv = "1234"
+		}
+		card := &pb.CardInformation{CardNumber: number, Cvv: cvv, ExpiryDate: "11/50"}
+		if err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card}); err != nil {
+			t.Fatalf("%s: %s", number, err)
+		}
+	}
+
+	t.Run("Luhn", func(t *testing.T) {
+		card := &pb.CardInformation{CardNumber: "4111111111111112", Cvv: "123", ExpiryDate: "11/50"}
+		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
+		if err == nil {
+			t.Fatal("should fail")
+		}
+		if verr, ok := err.(*ValidationError); !ok || verr.Field != "card_number" {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Amex requires 4 digit cvv", func(t *testing.T) {
+		card := &pb.CardInformation{CardNumber: "378282246310005", Cvv: "123", ExpiryDate: "11/50"}
+		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
+		if verr, ok := err.(*ValidationError); !ok || verr.Field != "cvv" {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Unsupported brand", func(t *testing.T) {
+		card := &pb.CardInformation{CardNumber: "9999999999999995", Cvv: "123", ExpiryDate: "11/50"}
+		err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card})
+		if err == nil {
+			t.Fatal("should fail")
+		}
+	})
+
+	t.Run("Pluggable validators", func(t *testing.T) {
+		s, err := NewNetworkServer(WithCardValidators(ValidateCardNumber))
+		if err != nil {
+			t.Fatalf("failed to create new server:%s", err)
+		}
+		card := &pb.CardInformation{CardNumber: "9999999999999999", Cvv: "1", ExpiryDate: "01/15"}
+		if err := s.ValidateCardInformation(&pb.RegistCardRequest{CardInformation: card}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDeclineCards(t *testing.T) {
+	s, err := NewNetworkServer()
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	declines := map[string]string{
+		"4000000000009995": DeclineInsufficientFunds,
+		"4000000000000069": DeclineExpiredCard,
+		"4000000000009979": DeclineStolenCard,
+	}
+	for number, reason := range declines {
+		r, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{
+			CardNumber: number,
+			Cvv:        "123",
+			ExpiryDate: "11/50",
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Brand != "visa" || r.Last4 != number[12:] {
+			t.Fatalf("unexpected response: %+v", r)
+		}
+
+		pay := &pb.PaymentInformation{CardToken: r.CardToken, ReservationId: 1, Amount: 1000}
+		_, err = s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: pay})
+		st := status.Convert(err)
+		if st.Code() != codes.FailedPrecondition {
+			t.Fatalf("Failed. Expected:%s but %s\n", codes.FailedPrecondition, st.Code())
+		}
+		if len(st.Details()) != 1 {
+			t.Fatalf("Failed. Expected:1 detail but %d\n", len(st.Details()))
+		}
+		failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+		if !ok || failure.Violations[0].Subject != reason {
+			t.Fatalf("unexpected details: %#v", st.Details())
+		}
+	}
+	if len(s.PayInfoMap) != 0 {
+		t.Fatalf("declined payments should not be recorded: %d", len(s.PayInfoMap))
+	}
+}
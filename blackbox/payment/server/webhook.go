@@ -0,0 +1,283 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/rs/xid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Webhookで通知するイベントの種類
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentCanceled  = "payment.canceled"
+	EventRefundCreated    = "refund.created"
+)
+
+// Webhookのリクエストヘッダ
+const (
+	SignatureHeader = "X-Payment-Signature" // t=<UNIX時刻>,v1=<HMAC-SHA256(secret, "<UNIX時刻>.<body>")の16進表記>
+	EventIDHeader   = "X-Payment-Event-Id"
+)
+
+// 配信履歴として保持する件数
+const maxWebhookDeliveries = 10000
+
+type webhookEvent struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Created int64       `json:"created"`
+	Data    webhookData `json:"data"`
+}
+
+type webhookData struct {
+	PaymentID     string `json:"payment_id"`
+	ReservationID int32  `json:"reservation_id"`
+	Amount        int32  `json:"amount"`
+	RefundID      string `json:"refund_id,omitempty"`
+	MerchantID    string `json:"merchant_id,omitempty"`
+}
+
+type webhookEndpoint struct {
+	url    string
+	secret string
+}
+
+// 決済の状態変化をマーチャントごとに登録されたURLへ配信する
+type webhookDispatcher struct {
+	mu        sync.RWMutex
+	endpoints map[string]webhookEndpoint
+
+	logMu      sync.Mutex
+	deliveries []*pb.WebhookDelivery
+
+	client *http.Client
+	conf   config.Webhook
+}
+
+func newWebhookDispatcher(conf config.Webhook) *webhookDispatcher {
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = 5
+	}
+	if conf.InitialBackoff <= 0 {
+		conf.InitialBackoff = 500 * time.Millisecond
+	}
+	if conf.MaxBackoff <= 0 {
+		conf.MaxBackoff = 30 * time.Second
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 5 * time.Second
+	}
+	return &webhookDispatcher{
+		endpoints: map[string]webhookEndpoint{},
+		client:    &http.Client{Timeout: conf.Timeout},
+		conf:      conf,
+	}
+}
+
+// SignWebhook はWebhookのボディに対する署名ヘッダの値を返します
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func (d *webhookDispatcher) register(merchantID, u, secret string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if u == "" {
+		delete(d.endpoints, merchantID)
+		return
+	}
+	d.endpoints[merchantID] = webhookEndpoint{url: u, secret: secret}
+}
+
+//merchantIDのWebhookにイベントを非同期で配信する. Webhookが登録されていなければ何もしない
+func (d *webhookDispatcher) dispatch(merchantID, eventType string, data webhookData) {
+	d.mu.RLock()
+	ep, ok := d.endpoints[merchantID]
+	d.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	data.MerchantID = merchantID
+	ev := &webhookEvent{
+		ID:      "evt_" + xid.New().String(),
+		Type:    eventType,
+		Created: time.Now().Unix(),
+		Data:    data,
+	}
+	go d.deliver(ep, ev)
+}
+
+//2xxが返るまで指数バックオフで再送する
+func (d *webhookDispatcher) deliver(ep webhookEndpoint, ev *webhookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	backoff := d.conf.InitialBackoff
+	for attempt := 1; attempt <= d.conf.MaxAttempts; attempt++ {
+		code, err := d.post(ep, ev, body)
+		delivered := err == nil && 200 <= code && code < 300
+		d.record(ep, ev, attempt, code, err, delivered)
+		if delivered {
+			return
+		}
+		if attempt == d.conf.MaxAttempts {
+			log.Printf("Webhook delivery failed: event=%s url=%s\n", ev.ID, ep.url)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > d.conf.MaxBackoff {
+			backoff = d.conf.MaxBackoff
+		}
+	}
+}
+
+func (d *webhookDispatcher) post(ep webhookEndpoint, ev *webhookEvent, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, ep.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, ev.ID)
+	req.Header.Set(SignatureHeader, SignWebhook(ep.secret, time.Now().Unix(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (d *webhookDispatcher) record(ep webhookEndpoint, ev *webhookEvent, attempt, code int, err error, delivered bool) {
+	datetime, _ := ptypes.TimestampProto(time.Now())
+	delivery := &pb.WebhookDelivery{
+		EventId:    ev.ID,
+		EventType:  ev.Type,
+		Url:        ep.url,
+		Attempt:    int32(attempt),
+		StatusCode: int32(code),
+		Datetime:   datetime,
+		Delivered:  delivered,
+		MerchantId: ev.Data.MerchantID,
+		PaymentId:  ev.Data.PaymentID,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	d.logMu.Lock()
+	defer d.logMu.Unlock()
+	d.deliveries = append(d.deliveries, delivery)
+	if len(d.deliveries) > maxWebhookDeliveries {
+		d.deliveries = d.deliveries[len(d.deliveries)-maxWebhookDeliveries:]
+	}
+}
+
+//配信履歴を取り出す. merchantIDが空文字でなければそのマーチャントのもののみ返す
+func (d *webhookDispatcher) history(merchantID, eventID string) []*pb.WebhookDelivery {
+	d.logMu.Lock()
+	defer d.logMu.Unlock()
+	deliveries := []*pb.WebhookDelivery{}
+	for _, delivery := range d.deliveries {
+		if merchantID != "" && delivery.MerchantId != merchantID {
+			continue
+		}
+		if eventID != "" && delivery.EventId != eventID {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+//配信履歴を削除する. merchantIDが空文字の場合はすべて削除する
+func (d *webhookDispatcher) clearHistory(merchantID string) {
+	d.logMu.Lock()
+	defer d.logMu.Unlock()
+	if merchantID == "" {
+		d.deliveries = nil
+		return
+	}
+	deliveries := d.deliveries[:0]
+	for _, delivery := range d.deliveries {
+		if delivery.MerchantId != merchantID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	d.deliveries = deliveries
+}
+
+// WithWebhook はWebhookの配信設定を指定します
+func WithWebhook(conf config.Webhook) ServerOption {
+	return func(s *Server) {
+		s.webhook = newWebhookDispatcher(conf)
+	}
+}
+
+//決済の状態が変化したときに通知するWebhookを登録する(urlが空の場合は登録を解除する)
+func (s *Server) RegisterWebhook(ctx context.Context, req *pb.RegisterWebhookRequest) (*pb.RegisterWebhookResponse, error) {
+	merchantID := merchantFromContext(ctx)
+	if req.Url == "" {
+		s.webhook.register(merchantID, "", "")
+		return &pb.RegisterWebhookResponse{IsOk: true}, nil
+	}
+
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		log.Printf("Invalid Webhook URL: %s\n", req.Url)
+		return &pb.RegisterWebhookResponse{IsOk: false}, status.Errorf(codes.InvalidArgument, "Invalid Webhook URL")
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			log.Println(err.Error())
+			return &pb.RegisterWebhookResponse{IsOk: false}, status.Errorf(codes.Internal, "Internal Error, Generate Secret")
+		}
+	}
+
+	s.webhook.register(merchantID, req.Url, secret)
+	log.Printf("Webhook registered: merchant=%q url=%s\n", merchantID, req.Url)
+	return &pb.RegisterWebhookResponse{Secret: secret, IsOk: true}, nil
+}
+
+//Webhookの配信履歴を取得する
+func (s *Server) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	deliveries := s.webhook.history(merchantFromContext(ctx), req.EventId)
+	return &pb.ListWebhookDeliveriesResponse{Deliveries: deliveries, IsOk: true}, nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"payment/config"
+	pb "payment/pb"
+)
+
+func TestWebhook(t *testing.T) {
+	var mu sync.Mutex
+	events := []webhookEvent{}
+	failures := 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sig := r.Header.Get(SignatureHeader)
+		parts := strings.SplitN(strings.TrimPrefix(sig, "t="), ",", 2)
+		ts, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || SignWebhook("whsec_test", ts, body) != sig {
+			t.Errorf("invalid signature: %s", sig)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		// 最初の配信は失敗させて再送されることを確認する
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var ev webhookEvent
+		if err := json.Unmarshal(body, &ev); err != nil {
+			t.Error(err)
+		}
+		if ev.ID != r.Header.Get(EventIDHeader) {
+			t.Errorf("event id mismatch: %s %s", ev.ID, r.Header.Get(EventIDHeader))
+		}
+		events = append(events, ev)
+	}))
+	defer ts.Close()
+
+	s, err := NewNetworkServer(WithWebhook(config.Webhook{InitialBackoff: 10 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create new server:%s", err)
+	}
+	ctx := context.Background()
+
+	if _, err := s.RegisterWebhook(ctx, &pb.RegisterWebhookRequest{Url: "ftp://example.com"}); err == nil {
+		t.Fatal("should fail")
+	}
+	res, err := s.RegisterWebhook(ctx, &pb.RegisterWebhookRequest{Url: ts.URL, Secret: "whsec_test"})
+	if err != nil || res.Secret != "whsec_test" {
+		t.Fatalf("failed to register webhook: %v", err)
+	}
+
+	card, err := s.RegistCard(ctx, &pb.RegistCardRequest{CardInformation: &pb.CardInformation{CardNumber: "4111111111111111", Cvv: "123", ExpiryDate: "11/50"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pay, err := s.ExecutePayment(ctx, &pb.ExecutePaymentRequest{PaymentInformation: &pb.PaymentInformation{CardToken: card.CardToken, ReservationId: 1, Amount: 1000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CancelPayment(ctx, &pb.CancelPaymentRequest{PaymentId: pay.PaymentId}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 配信履歴は受信側の応答後に記録されるので、履歴で配信完了を待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, d := range deliveries.Deliveries {
+			if d.Delivered {
+				n++
+			}
+		}
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook events are not delivered: %d", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	types := map[string]webhookEvent{}
+	for _, ev := range events {
+		types[ev.Type] = ev
+	}
+	for _, typ := range []string{EventPaymentSucceeded, EventPaymentCanceled, EventRefundCreated} {
+		ev, ok := types[typ]
+		if !ok {
+			t.Fatalf("%s is not delivered", typ)
+		}
+		if ev.Data.PaymentID != pay.PaymentId || ev.Data.ReservationID != 1 || ev.Data.Amount != 1000 {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	}
+	if types[EventRefundCreated].Data.RefundID == "" {
+		t.Fatal("refund_id is empty")
+	}
+
+	deliveries, err := s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries.Deliveries) != 4 {
+		t.Fatalf("Failed. Expected:%d but %d\n", 4, len(deliveries.Deliveries))
+	}
+	var retried *pb.WebhookDelivery
+	for _, d := range deliveries.Deliveries {
+		if !d.Delivered {
+			retried = d
+		}
+	}
+	if retried == nil || retried.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("unexpected deliveries: %+v", deliveries.Deliveries)
+	}
+	deliveries, err = s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{EventId: retried.EventId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries.Deliveries) != 2 || !deliveries.Deliveries[1].Delivered || deliveries.Deliveries[1].Attempt != 2 {
+		t.Fatalf("unexpected deliveries: %+v", deliveries.Deliveries)
+	}
+
+	if _, err := s.Initialize(ctx, &pb.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, _ = s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{})
+	if len(deliveries.Deliveries) != 0 {
+		t.Fatalf("deliveries are not cleared: %d", len(deliveries.Deliveries))
+	}
+}
@@ -129,7 +129,7 @@ class ApiService {
       /*
       data =
       {
-          card_number: "12345678",
+          card_number: "4111111111111111",
           cvv: "123",
           expiry_date: "12/22"
       }
@@ -137,7 +137,11 @@ class ApiService {
 
       return await this.httpService.get('/api/settings').then(function(res){
         var paymentService = new HttpService(res.payment_api)
-        return paymentService.post('/card', data).then(function(res){
+        var config = {}
+        if (res.payment_publishable_key) {
+          config.headers = { 'X-Api-Key': res.payment_publishable_key }
+        }
+        return paymentService.post('/card', data, config).then(function(res){
           return res
         })
       });
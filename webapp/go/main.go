@@ -87,6 +87,9 @@ type Reservation struct {
 	Adult         int        `json:"adult" db:"adult"`
 	Child         int        `json:"child" db:"child"`
 	Amount        int        `json:"amount" db:"amount"`
+	Currency      string     `json:"currency" db:"currency"`
+	TaxAmount     int        `json:"tax_amount" db:"tax_amount"`
+	AmountJPY     int        `json:"-" db:"amount_jpy"` // 換算前の円の運賃
 }
 
 type SeatReservation struct {
@@ -136,6 +139,7 @@ type TrainSearchResponse struct {
 	ArrivalTime      string            `json:"arrival_time"`
 	SeatAvailability map[string]string `json:"seat_availability"`
 	Fare             map[string]int    `json:"seat_fare"`
+	FareDetail       map[string]Money  `json:"seat_fare_detail"` // 通貨と消費税額つきの運賃
 }
 
 type User struct {
@@ -169,6 +173,7 @@ type RequestSeat struct {
 type TrainReservationResponse struct {
 	ReservationId int64 `json:"reservation_id"`
 	Amount        int   `json:"amount"`
+	Price         Money `json:"price"`
 	IsOk          bool  `json:"is_ok"`
 }
 
@@ -182,9 +187,17 @@ type ReservationPaymentResponse struct {
 }
 
 type PaymentInformationRequest struct {
-	CardToken     string `json:"card_token"`
-	ReservationId int    `json:"reservation_id"`
-	Amount        int    `json:"amount"`
+	CardToken     string        `json:"card_token"`
+	ReservationId int           `json:"reservation_id"`
+	Amount        int           `json:"amount"`
+	Price         *PaymentMoney `json:"price,omitempty"`
+	Tax           *PaymentMoney `json:"tax,omitempty"`
+}
+
+// 決済サービスに送る通貨つきの金額
+type PaymentMoney struct {
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
 }
 
 type PaymentInformation struct {
@@ -204,6 +217,7 @@ type ReservationResponse struct {
 	CarNumber     int               `json:"car_number"`
 	SeatClass     string            `json:"seat_class"`
 	Amount        int               `json:"amount"`
+	Price         Money             `json:"price"`
 	Adult         int               `json:"adult"`
 	Child         int               `json:"child"`
 	Departure     string            `json:"departure"`
@@ -222,7 +236,8 @@ type CancelPaymentInformationResponse struct {
 }
 
 type Settings struct {
-	PaymentAPI string `json:"payment_api"`
+	PaymentAPI            string `json:"payment_api"`
+	PaymentPublishableKey string `json:"payment_publishable_key,omitempty"`
 }
 
 type InitializeResponse struct {
@@ -684,17 +699,23 @@ func trainSearchHandler(w http.ResponseWriter, r *http.Request) {
 			}
 			nonReservedFare = nonReservedFare*adult + nonReservedFare/2*child
 
-			fareInformation := map[string]int{
+			fareInformation := map[string]int{}
+			fareDetail := map[string]Money{}
+			for seatClass, fare := range map[string]int{
 				"premium":        premiumFare,
 				"premium_smoke":  premiumFare,
 				"reserved":       reservedFare,
 				"reserved_smoke": reservedFare,
 				"non_reserved":   nonReservedFare,
+			} {
+				price := pricing.price(fare)
+				fareInformation[seatClass] = price.Amount
+				fareDetail[seatClass] = price
 			}
 
 			trainSearchResponseList = append(trainSearchResponseList, TrainSearchResponse{
 				train.TrainClass, train.TrainName, train.StartStation, train.LastStation,
-				fromStation.Name, toStation.Name, departure, arrival, seatAvailability, fareInformation,
+				fromStation.Name, toStation.Name, departure, arrival, seatAvailability, fareInformation, fareDetail,
 			})
 
 			if len(trainSearchResponseList) >= 10 {
@@ -1474,6 +1495,7 @@ func trainReservationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	sumFare := (req.Adult * fare) + (req.Child*fare)/2
 	fmt.Println("SUMFARE")
+	price := pricing.price(sumFare)
 
 	// userID取得。ログインしてないと怒られる。
 	user, errCode, errMsg := getUser(r)
@@ -1485,7 +1507,7 @@ func trainReservationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//予約ID発行と予約情報登録
-	query = "INSERT INTO `reservations` (`user_id`, `date`, `train_class`, `train_name`, `departure`, `arrival`, `status`, `payment_id`, `adult`, `child`, `amount`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	query = "INSERT INTO `reservations` (`user_id`, `date`, `train_class`, `train_name`, `departure`, `arrival`, `status`, `payment_id`, `adult`, `child`, `amount`, `currency`, `tax_amount`, `amount_jpy`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 	result, err := tx.Exec(
 		query,
 		user.ID,
@@ -1498,6 +1520,9 @@ func trainReservationHandler(w http.ResponseWriter, r *http.Request) {
 		"a",
 		req.Adult,
 		req.Child,
+		price.Amount,
+		price.Currency,
+		price.Tax,
 		sumFare,
 	)
 	if err != nil {
@@ -1536,7 +1561,8 @@ func trainReservationHandler(w http.ResponseWriter, r *http.Request) {
 
 	rr := TrainReservationResponse{
 		ReservationId: id,
-		Amount:        sumFare,
+		Amount:        price.Amount,
+		Price:         price,
 		IsOk:          true,
 	}
 	response, err := json.Marshal(rr)
@@ -1620,7 +1646,17 @@ func reservationPaymentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 決済する
-	payInfo := PaymentInformationRequest{req.CardToken, req.ReservationId, reservation.Amount}
+	// JPY以外の場合、決済サービスは円の運賃(Amount)を同じ規則で換算した金額がPriceと一致するか確かめる
+	payInfo := PaymentInformationRequest{
+		CardToken:     req.CardToken,
+		ReservationId: req.ReservationId,
+		Amount:        reservation.AmountJPY,
+		Price:         &PaymentMoney{reservation.Currency, reservation.Amount},
+		Tax:           &PaymentMoney{reservation.Currency, reservation.TaxAmount},
+	}
+	if reservation.Currency == currencyJPY {
+		payInfo.Amount = reservation.Amount
+	}
 	j, err := json.Marshal(PaymentInformation{PayInfo: payInfo})
 	if err != nil {
 		tx.Rollback()
@@ -1634,7 +1670,19 @@ func reservationPaymentHandler(w http.ResponseWriter, r *http.Request) {
 		payment_api = "http://payment:5000"
 	}
 
-	resp, err := http.Post(payment_api+"/payment", "application/json", bytes.NewBuffer(j))
+	paymentReq, err := http.NewRequest("POST", payment_api+"/payment", bytes.NewBuffer(j))
+	if err != nil {
+		tx.Rollback()
+		errorResponse(w, http.StatusInternalServerError, "HTTPリクエストの作成に失敗しました")
+		log.Println(err.Error())
+		return
+	}
+	paymentReq.Header.Set("Content-Type", "application/json")
+	// 決済サービスでAPIキーによる認証が有効な場合
+	if apiKey := os.Getenv("PAYMENT_API_KEY"); apiKey != "" {
+		paymentReq.Header.Set("X-Api-Key", apiKey)
+	}
+	resp, err := http.DefaultClient.Do(paymentReq)
 	if err != nil {
 		tx.Rollback()
 		errorResponse(w, resp.StatusCode, "HTTP POSTに失敗しました")
@@ -1831,6 +1879,7 @@ func makeReservationResponse(reservation Reservation) (ReservationResponse, erro
 	reservationResponse.ReservationId = reservation.ReservationId
 	reservationResponse.Date = reservation.Date.Format("2006/01/02")
 	reservationResponse.Amount = reservation.Amount
+	reservationResponse.Price = Money{reservation.Currency, reservation.Amount, reservation.TaxAmount}
 	reservationResponse.Adult = reservation.Adult
 	reservationResponse.Child = reservation.Child
 	reservationResponse.Departure = reservation.Departure
@@ -2009,6 +2058,9 @@ func userReservationCancelHandler(w http.ResponseWriter, r *http.Request) {
 			log.Println(err.Error())
 			return
 		}
+		if apiKey := os.Getenv("PAYMENT_API_KEY"); apiKey != "" {
+			req.Header.Set("X-Api-Key", apiKey)
+		}
 		resp, err := client.Do(req)
 		if err != nil {
 			tx.Rollback()
@@ -2082,6 +2134,10 @@ func initializeHandler(w http.ResponseWriter, r *http.Request) {
 	dbx.Exec("TRUNCATE reservations")
 	dbx.Exec("TRUNCATE users")
 
+	if err := registerPaymentWebhook(); err != nil {
+		log.Println(err.Error())
+	}
+
 	resp := InitializeResponse{
 		availableDays,
 		"golang",
@@ -2098,6 +2154,8 @@ func settingsHandler(w http.ResponseWriter, r *http.Request) {
 
 	settings := Settings{
 		PaymentAPI: payment_api,
+		// ブラウザからのカード登録に使う、決済サービスの公開キー
+		PaymentPublishableKey: os.Getenv("PAYMENT_PUBLISHABLE_KEY"),
 	}
 
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
@@ -2112,6 +2170,11 @@ func main() {
 	// MySQL関連のお膳立て
 	var err error
 
+	pricing, err = loadPricing()
+	if err != nil {
+		log.Fatalf("invalid pricing: %s", err.Error())
+	}
+
 	host := os.Getenv("MYSQL_HOSTNAME")
 	if host == "" {
 		host = "127.0.0.1"
@@ -2175,6 +2238,9 @@ func main() {
 	mux.HandleFunc(pat.Get("/api/user/reservations/:item_id"), userReservationResponseHandler)
 	mux.HandleFunc(pat.Post("/api/user/reservations/:item_id/cancel"), userReservationCancelHandler)
 
+	// 決済サービスからのWebhook
+	mux.HandleFunc(pat.Post("/api/payment/webhook"), paymentWebhookHandler)
+
 	fmt.Println(banner)
 	err = http.ListenAndServe(":8000", mux)
 
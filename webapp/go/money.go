@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 運賃の計算に使う通貨
+const currencyJPY = "JPY"
+
+// Money は通貨つきの金額です. Amountは通貨の最小単位(JPYは円、USDはセント)での税込金額、Taxはそのうちの消費税額です
+type Money struct {
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
+	Tax      int    `json:"tax"`
+}
+
+// CurrencyRule は円から他の通貨への換算規則です. 決済サービスの currencies と同じ項目と意味を持ちます
+type CurrencyRule struct {
+	JPYPerUnit float64 `json:"jpy_per_unit"` // 主単位(USDなら1ドル)あたりの円
+	Exponent   int     `json:"exponent"`     // 最小単位の桁数(USDなら2)
+	Rounding   string  `json:"rounding"`     // 端数の扱い(round, floor, ceil). 省略時はround(0.5は0から遠い方へ)
+}
+
+// Pricing は料金の表示と決済に使う通貨と税率です
+type Pricing struct {
+	Currency string
+	TaxRate  float64 // 運賃に含まれる消費税の税率
+	Rules    map[string]CurrencyRule
+}
+
+var pricing = Pricing{
+	Currency: currencyJPY,
+	TaxRate:  0.10,
+	Rules:    map[string]CurrencyRule{},
+}
+
+// ISUTRAIN_CURRENCY, ISUTRAIN_TAX_RATE, ISUTRAIN_CURRENCY_RULES から料金の設定を読み込む
+// 例: ISUTRAIN_CURRENCY=USD ISUTRAIN_CURRENCY_RULES='{"USD": {"jpy_per_unit": 149.25, "exponent": 2}}'
+func loadPricing() (Pricing, error) {
+	p := Pricing{Currency: currencyJPY, TaxRate: 0.10, Rules: map[string]CurrencyRule{}}
+
+	if s := os.Getenv("ISUTRAIN_CURRENCY_RULES"); s != "" {
+		rules := map[string]CurrencyRule{}
+		if err := json.Unmarshal([]byte(s), &rules); err != nil {
+			return p, fmt.Errorf("ISUTRAIN_CURRENCY_RULES: %s", err)
+		}
+		for code, rule := range rules {
+			if rule.JPYPerUnit <= 0 || rule.Exponent < 0 || 4 < rule.Exponent {
+				return p, fmt.Errorf("ISUTRAIN_CURRENCY_RULES: invalid rule for %s", code)
+			}
+			switch rule.Rounding {
+			case "", "round", "floor", "ceil":
+			default:
+				return p, fmt.Errorf("ISUTRAIN_CURRENCY_RULES: unknown rounding for %s: %s", code, rule.Rounding)
+			}
+			p.Rules[strings.ToUpper(code)] = rule
+		}
+	}
+	if s := os.Getenv("ISUTRAIN_CURRENCY"); s != "" {
+		p.Currency = strings.ToUpper(s)
+	}
+	if _, ok := p.Rules[p.Currency]; p.Currency != currencyJPY && !ok {
+		return p, fmt.Errorf("ISUTRAIN_CURRENCY: no conversion rule for %s", p.Currency)
+	}
+	if s := os.Getenv("ISUTRAIN_TAX_RATE"); s != "" {
+		rate, err := strconv.ParseFloat(s, 64)
+		if err != nil || rate < 0 || 1 < rate {
+			return p, fmt.Errorf("ISUTRAIN_TAX_RATE: invalid tax rate: %s", s)
+		}
+		p.TaxRate = rate
+	}
+	return p, nil
+}
+
+// 円の金額(税込)を設定された通貨に換算し、含まれる消費税額を求める
+func (p Pricing) price(jpy int) Money {
+	m := Money{Currency: p.Currency, Amount: jpy}
+	if rule, ok := p.Rules[p.Currency]; ok && p.Currency != currencyJPY {
+		// 決済サービスは同じ式で換算した金額と一致するか確かめる
+		v := float64(jpy) / rule.JPYPerUnit * math.Pow10(rule.Exponent)
+		switch rule.Rounding {
+		case "floor":
+			v = math.Floor(v)
+		case "ceil":
+			v = math.Ceil(v)
+		default:
+			v = math.Round(v)
+		}
+		m.Amount = int(v)
+	}
+	// 内税: 税込金額 × 税率 / (1 + 税率) の端数を切り捨てる
+	m.Tax = int(math.Floor(float64(m.Amount)*p.TaxRate/(1+p.TaxRate) + 1e-9))
+	return m
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPricingPrice(t *testing.T) {
+	p := Pricing{Currency: currencyJPY, TaxRate: 0.10, Rules: map[string]CurrencyRule{}}
+	if m := p.price(1100); m != (Money{"JPY", 1100, 100}) {
+		t.Fatalf("failed test %#v", m)
+	}
+	if m := p.price(5555); m != (Money{"JPY", 5555, 505}) {
+		t.Fatalf("failed test %#v", m)
+	}
+
+	p.Currency = "USD"
+	p.Rules["USD"] = CurrencyRule{JPYPerUnit: 149.25, Exponent: 2}
+	// 1000円 = 6.70ドル
+	if m := p.price(1000); m != (Money{"USD", 670, 60}) {
+		t.Fatalf("failed test %#v", m)
+	}
+	p.Rules["USD"] = CurrencyRule{JPYPerUnit: 148, Exponent: 2, Rounding: "floor"}
+	if m := p.price(1001); m.Amount != 676 {
+		t.Fatalf("failed test %#v", m)
+	}
+}
+
+func TestLoadPricing(t *testing.T) {
+	defer os.Unsetenv("ISUTRAIN_CURRENCY")
+	defer os.Unsetenv("ISUTRAIN_CURRENCY_RULES")
+	defer os.Unsetenv("ISUTRAIN_TAX_RATE")
+
+	p, err := loadPricing()
+	if err != nil || p.Currency != currencyJPY || p.TaxRate != 0.10 {
+		t.Fatalf("failed test %#v %v", p, err)
+	}
+
+	os.Setenv("ISUTRAIN_CURRENCY", "usd")
+	if _, err := loadPricing(); err == nil {
+		t.Fatal("should fail without conversion rule")
+	}
+	os.Setenv("ISUTRAIN_CURRENCY_RULES", `{"USD": {"jpy_per_unit": 149.25, "exponent": 2}}`)
+	os.Setenv("ISUTRAIN_TAX_RATE", "0.08")
+	p, err = loadPricing()
+	if err != nil || p.Currency != "USD" || p.TaxRate != 0.08 || p.Rules["USD"].Exponent != 2 {
+		t.Fatalf("failed test %#v %v", p, err)
+	}
+
+	os.Setenv("ISUTRAIN_TAX_RATE", "-1")
+	if _, err := loadPricing(); err == nil {
+		t.Fatal("should fail with negative tax rate")
+	}
+}
+
+// 決済サービスの money_test.go の TestNormalizePriceMatchesWebappFare と同じ換算規則と運賃
+// 決済サービスは円の運賃をこの価格に換算できるか確かめて決済する
+func TestPricingMatchesPaymentService(t *testing.T) {
+	for _, tc := range []struct {
+		rounding string
+		fare     int
+		price    int
+	}{
+		{"", 2500, 1675},
+		{"", 1250, 838},
+		{"", 1001, 671},
+		{"", 12345, 8271},
+		{"floor", 1001, 670},
+		{"ceil", 12345, 8272},
+	} {
+		p := Pricing{Currency: "USD", TaxRate: 0.10, Rules: map[string]CurrencyRule{
+			"USD": {JPYPerUnit: 149.25, Exponent: 2, Rounding: tc.rounding},
+		}}
+		if m := p.price(tc.fare); m.Amount != tc.price {
+			t.Fatalf("failed test %d yen (%s): %#v", tc.fare, tc.rounding, m)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 決済サービスからのWebhookの署名の有効期間
+const paymentWebhookTolerance = 5 * time.Minute
+
+type PaymentWebhookEvent struct {
+	ID      string             `json:"id"`
+	Type    string             `json:"type"`
+	Created int64              `json:"created"`
+	Data    PaymentWebhookData `json:"data"`
+}
+
+type PaymentWebhookData struct {
+	PaymentId     string `json:"payment_id"`
+	ReservationId int    `json:"reservation_id"`
+	Amount        int64  `json:"amount"`
+	RefundId      string `json:"refund_id"`
+}
+
+type PaymentWebhookRequest struct {
+	Url    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// X-Payment-Signature: t=<UNIX時刻>,v1=<HMAC-SHA256(secret, "<UNIX時刻>.<body>")> を検証する
+func verifyPaymentWebhookSignature(secret, header string, body []byte, now time.Time) error {
+	var timestamp string
+	signatures := []string{}
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("署名のタイムスタンプが不正です")
+	}
+	d := now.Sub(time.Unix(t, 0))
+	if d > paymentWebhookTolerance || d < -paymentWebhookTolerance {
+		return errors.New("署名の有効期限が切れています")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for _, s := range signatures {
+		sig, err := hex.DecodeString(s)
+		if err == nil && hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return errors.New("署名が一致しません")
+}
+
+func paymentWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	/*
+		決済サービスからのWebhookを受け取り、予約の状態を決済の状態に合わせる
+	*/
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		errorResponse(w, http.StatusNotFound, "Webhookが設定されていません")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "リクエストの読み込みに失敗しました")
+		log.Println(err.Error())
+		return
+	}
+	err = verifyPaymentWebhookSignature(secret, r.Header.Get("X-Payment-Signature"), body, time.Now())
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		log.Println(err.Error())
+		return
+	}
+
+	event := PaymentWebhookEvent{}
+	err = json.Unmarshal(body, &event)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "JSON parseに失敗しました")
+		log.Println(err.Error())
+		return
+	}
+
+	switch event.Type {
+	case "payment.succeeded":
+		// コミット処理のレスポンスを受け取れなかった予約を確定させる
+		_, err = dbx.Exec(
+			"UPDATE reservations SET status=?, payment_id=? WHERE reservation_id=? AND status=?",
+			"done",
+			event.Data.PaymentId,
+			event.Data.ReservationId,
+			"requesting",
+		)
+	case "payment.canceled", "refund.created":
+		_, err = dbx.Exec(
+			"UPDATE reservations SET status=? WHERE reservation_id=? AND payment_id=?",
+			"rejected",
+			event.Data.ReservationId,
+			event.Data.PaymentId,
+		)
+	default:
+		// 未知のイベントは受け取るだけにする
+		log.Printf("unknown webhook event: %s", event.Type)
+	}
+	if err != nil {
+		// 500を返して再送してもらう
+		errorResponse(w, http.StatusInternalServerError, "予約情報の更新に失敗しました")
+		log.Println(err.Error())
+		return
+	}
+
+	messageResponse(w, "ok")
+}
+
+// PAYMENT_WEBHOOK_URL が設定されていれば決済サービスにWebhookを登録する
+func registerPaymentWebhook() error {
+	webhookURL := os.Getenv("PAYMENT_WEBHOOK_URL")
+	if webhookURL == "" {
+		return nil
+	}
+
+	payment_api := os.Getenv("PAYMENT_API")
+	if payment_api == "" {
+		payment_api = "http://payment:5000"
+	}
+
+	j, err := json.Marshal(PaymentWebhookRequest{Url: webhookURL, Secret: os.Getenv("PAYMENT_WEBHOOK_SECRET")})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", payment_api+"/webhook", bytes.NewBuffer(j))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := os.Getenv("PAYMENT_API_KEY"); apiKey != "" {
+		req.Header.Set("X-Api-Key", apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Webhookの登録に失敗しました: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// 決済サービスの SignWebhook と同じ形式の X-Payment-Signature を作る
+func signPaymentWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifyPaymentWebhookSignature(t *testing.T) {
+	now := time.Unix(1577836800, 0)
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"payment_id":"p1","reservation_id":1,"amount":1000}}`)
+	header := signPaymentWebhook(testWebhookSecret, now.Unix(), body)
+
+	if err := verifyPaymentWebhookSignature(testWebhookSecret, header, body, now); err != nil {
+		t.Fatalf("valid signature should be accepted: %s", err)
+	}
+	// 有効期間内であれば時刻のずれは許容する
+	if err := verifyPaymentWebhookSignature(testWebhookSecret, header, body, now.Add(paymentWebhookTolerance-time.Second)); err != nil {
+		t.Fatalf("signature within tolerance should be accepted: %s", err)
+	}
+	// シークレットのローテーション中は複数のv1のどれかが一致すればよい
+	rotated := header + ",v1=" + hex.EncodeToString([]byte("old signature"))
+	if err := verifyPaymentWebhookSignature(testWebhookSecret, rotated, body, now); err != nil {
+		t.Fatalf("one matching v1 should be accepted: %s", err)
+	}
+
+	tamperedBody := bytes.Replace(body, []byte("1000"), []byte("9999"), 1)
+	tamperedSig := header[:len(header)-1] + "0"
+	if tamperedSig == header {
+		tamperedSig = header[:len(header)-1] + "1"
+	}
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+	}{
+		{"expired", testWebhookSecret, header, body, now.Add(paymentWebhookTolerance + time.Second)},
+		{"future", testWebhookSecret, header, body, now.Add(-paymentWebhookTolerance - time.Second)},
+		{"tampered body", testWebhookSecret, header, tamperedBody, now},
+		{"tampered signature", testWebhookSecret, tamperedSig, body, now},
+		{"wrong secret", "whsec_other", header, body, now},
+		{"no timestamp", testWebhookSecret, header[len("t=1577836800,"):], body, now},
+		{"empty", testWebhookSecret, "", body, now},
+	}
+	for _, tt := range tests {
+		if err := verifyPaymentWebhookSignature(tt.secret, tt.header, tt.body, tt.now); err == nil {
+			t.Fatalf("%s: should be rejected", tt.name)
+		}
+	}
+}
+
+func TestPaymentWebhookHandler(t *testing.T) {
+	defer os.Unsetenv("PAYMENT_WEBHOOK_SECRET")
+
+	post := func(header string, body []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/payment/webhook", bytes.NewReader(body))
+		if header != "" {
+			r.Header.Set("X-Payment-Signature", header)
+		}
+		w := httptest.NewRecorder()
+		paymentWebhookHandler(w, r)
+		return w
+	}
+
+	// 未知のイベントはDBを更新せずに受け取る
+	body := []byte(`{"id":"evt_1","type":"payment.unknown","data":{"payment_id":"p1","reservation_id":1}}`)
+	now := time.Now().Unix()
+
+	os.Unsetenv("PAYMENT_WEBHOOK_SECRET")
+	if w := post(signPaymentWebhook(testWebhookSecret, now, body), body); w.Code != http.StatusNotFound {
+		t.Fatalf("webhook without secret should be 404: %d", w.Code)
+	}
+
+	os.Setenv("PAYMENT_WEBHOOK_SECRET", testWebhookSecret)
+	if w := post(signPaymentWebhook(testWebhookSecret, now, body), body); w.Code != http.StatusOK {
+		t.Fatalf("valid webhook should be accepted: %d %s", w.Code, w.Body.String())
+	}
+
+	expired := now - int64((paymentWebhookTolerance + time.Minute).Seconds())
+	tampered := []byte(`{"id":"evt_1","type":"payment.unknown","data":{"payment_id":"p2","reservation_id":1}}`)
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+	}{
+		{"expired", signPaymentWebhook(testWebhookSecret, expired, body), body},
+		{"tampered body", signPaymentWebhook(testWebhookSecret, now, body), tampered},
+		{"wrong secret", signPaymentWebhook("whsec_other", now, body), body},
+		{"no signature", "", body},
+	}
+	for _, tt := range tests {
+		if w := post(tt.header, tt.body); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: should be 400 but %d", tt.name, w.Code)
+		}
+	}
+
+	// 署名が正しくてもJSONとして読めなければ400
+	broken := []byte(`{"id":`)
+	if w := post(signPaymentWebhook(testWebhookSecret, now, broken), broken); w.Code != http.StatusBadRequest {
+		t.Fatalf("broken json should be 400: %d", w.Code)
+	}
+}
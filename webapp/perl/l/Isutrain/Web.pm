@@ -182,9 +182,13 @@ get '/api/settings' => sub {
 
     my $payment_api = $ENV{PAYMENT_API} // $DEFAULT_PAYMENT_API;
 
-    $c->render_json({
+    my $settings = {
         payment_api => $payment_api,
-    });
+    };
+    # ブラウザからのカード登録に使う、決済サービスの公開キー
+    $settings->{payment_publishable_key} = $ENV{PAYMENT_PUBLISHABLE_KEY} if $ENV{PAYMENT_PUBLISHABLE_KEY};
+
+    $c->render_json($settings);
 };
 
 #mux.HandleFunc(pat.Get("/api/stations"), getStationsHandler)
@@ -1268,6 +1272,8 @@ post '/api/train/reservation/commit' => [qw/allow_json_request/] => sub {
 
     my $req = HTTP::Request->new(POST => $payment_api . "/payment");
     $req->header("Content-Type", "application/json");
+    # 決済サービスでAPIキーによる認証が有効な場合
+    $req->header("X-Api-Key", $ENV{PAYMENT_API_KEY}) if $ENV{PAYMENT_API_KEY};
     $req->content($json);
 
     my $ua  = LWP::UserAgent->new(
@@ -1582,6 +1588,7 @@ post '/api/user/reservations/{item_id:\d+}/cancel' => sub {
 
         my $req = HTTP::Request->new(DELETE => $payment_api . "/payment/".$reservation->{payment_id});
         $req->header("Content-Type", "application/json");
+        $req->header("X-Api-Key", $ENV{PAYMENT_API_KEY}) if $ENV{PAYMENT_API_KEY};
         $req->content($json);
 
         my $ua  = LWP::UserAgent->new(
@@ -70,6 +70,16 @@ class Service
         ];
     }
 
+    // 決済サービスでAPIキーによる認証が有効な場合
+    private function paymentHeaders(): array
+    {
+        $apiKey = Environment::get('PAYMENT_API_KEY', '');
+        if ($apiKey === '') {
+            return [];
+        }
+        return ['X-Api-Key' => $apiKey];
+    }
+
     private function checkAvailableDate(DateTime $date): bool
     {
         $base = new DateTime('2020-01-01 00:00:00');
@@ -1351,6 +1361,7 @@ class Service
         try {
             $r = $http_client->post($payment_api . '/payment', [
                 'json' => $payInfo,
+                'headers' => $this->paymentHeaders(),
             ]);
         } catch (RequestException $e) {
             $this->dbh->rollBack();
@@ -1481,6 +1492,7 @@ class Service
                 try {
                     $r = $http_client->delete($payment_api . sprintf("/payment/%s", $reservation['payment_id']), [
                         'json' => $payInfo,
+                        'headers' => $this->paymentHeaders(),
                         'timeout' => 10,
                     ]);
                 } catch (RequestException $e) {
@@ -1612,6 +1624,12 @@ class Service
 
     public function settingsHandler(Request $request, Response $response, array $args)
     {
-        return $response->withJson(["payment_api" => Environment::get('PAYMENT_API', 'http://localhost:5000')]);
+        $settings = ["payment_api" => Environment::get('PAYMENT_API', 'http://localhost:5000')];
+        // ブラウザからのカード登録に使う、決済サービスの公開キー
+        $publishableKey = Environment::get('PAYMENT_PUBLISHABLE_KEY', '');
+        if ($publishableKey !== '') {
+            $settings["payment_publishable_key"] = $publishableKey;
+        }
+        return $response->withJson($settings);
     }
 }
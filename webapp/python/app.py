@@ -82,6 +82,13 @@ def handle_http_exception(error):
 def message_response(message):
     return flask.jsonify({'is_error': False, 'message': message})
 
+def payment_headers():
+    # 決済サービスでAPIキーによる認証が有効な場合
+    api_key = os.getenv('PAYMENT_API_KEY', '')
+    if api_key:
+        return {"X-Api-Key": api_key}
+    return {}
+
 def check_available_date(date):
     d = datetime.datetime(2020, 1, 1) + datetime.timedelta(days=AvailableDays)
     if d.date() <= date:
@@ -958,7 +965,7 @@ def post_commit():
                     "reservation_id": reservation["reservation_id"],
                     "amount": reservation["amount"],
                 }
-            })
+            }, headers=payment_headers())
 
             if res.status_code != 200:
                 raise HttpException(requests.codes['internal_server_error'], "決済に失敗しました。カードトークンや支払いIDが間違っている可能性があります")
@@ -1105,7 +1112,7 @@ def post_user_reservation_cancel(item_id):
 
                 payment_api = os.getenv('PAYMENT_API', 'http://payment:5000')
 
-                res = requests.delete(payment_api+"/payment/" + reservation["payment_id"])
+                res = requests.delete(payment_api+"/payment/" + reservation["payment_id"], headers=payment_headers())
 
                 if res.status_code != 200:
                     raise HttpException(requests.codes['internal_server_error'], "決済のキャンセルに失敗しました")
@@ -1132,9 +1139,14 @@ def post_user_reservation_cancel(item_id):
 
 @app.route("/api/settings", methods=["GET"])
 def get_settings():
-    return flask.jsonify({
+    settings = {
         "payment_api": os.getenv('PAYMENT_API', 'http://localhost:5000'),
-    })
+    }
+    # ブラウザからのカード登録に使う、決済サービスの公開キー
+    publishable_key = os.getenv('PAYMENT_PUBLISHABLE_KEY', '')
+    if publishable_key:
+        settings["payment_publishable_key"] = publishable_key
+    return flask.jsonify(settings)
 
 
 @app.route("/initialize", methods=["POST"])
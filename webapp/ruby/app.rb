@@ -239,8 +239,13 @@ module Isutrain
     get '/api/settings' do
       payment_api = ENV['PAYMENT_API'] || 'http://127.0.0.1:5000'
 
+      settings = { payment_api: payment_api }
+      # ブラウザからのカード登録に使う、決済サービスの公開キー
+      publishable_key = ENV['PAYMENT_PUBLISHABLE_KEY']
+      settings[:payment_publishable_key] = publishable_key unless publishable_key.nil? || publishable_key.empty?
+
       content_type :json
-      { payment_api: payment_api }.to_json
+      settings.to_json
     end
 
     get '/api/stations' do
@@ -1278,6 +1283,8 @@ __EOF
           payment_information: pay_info
         }.to_json
         req['Content-Type'] = 'application/json'
+        # 決済サービスでAPIキーによる認証が有効な場合
+        req['X-Api-Key'] = ENV['PAYMENT_API_KEY'] unless ENV['PAYMENT_API_KEY'].nil? || ENV['PAYMENT_API_KEY'].empty?
 
         http = Net::HTTP.new(uri.host, uri.port)
         http.use_ssl = uri.scheme == 'https'
@@ -1483,6 +1490,8 @@ __EOF
           payment_id: reservation[:payment_id]
         }.to_json
         req['Content-Type'] = 'application/json'
+        # 決済サービスでAPIキーによる認証が有効な場合
+        req['X-Api-Key'] = ENV['PAYMENT_API_KEY'] unless ENV['PAYMENT_API_KEY'].nil? || ENV['PAYMENT_API_KEY'].empty?
 
         http = Net::HTTP.new(uri.host, uri.port)
         http.use_ssl = uri.scheme == 'https'
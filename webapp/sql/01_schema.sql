@@ -27,7 +27,10 @@ CREATE TABLE `reservations` (
   `payment_id` varchar(100) NOT NULL,
   `adult` int NOT NULL,
   `child` int NOT NULL,
-  `amount` bigint NOT NULL
+  `amount` bigint NOT NULL,
+  `currency` varchar(3) NOT NULL DEFAULT 'JPY',
+  `tax_amount` bigint NOT NULL DEFAULT 0,
+  `amount_jpy` bigint NOT NULL DEFAULT 0
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 DROP TABLE IF EXISTS `seat_master`;